@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -10,8 +11,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/tinkerbelle-io/tb-manage/internal/agent"
 	"github.com/tinkerbelle-io/tb-manage/internal/auth"
+	"github.com/tinkerbelle-io/tb-manage/internal/commands"
 	"github.com/tinkerbelle-io/tb-manage/internal/config"
+	"github.com/tinkerbelle-io/tb-manage/internal/insights"
+	"github.com/tinkerbelle-io/tb-manage/internal/iot"
 	"github.com/tinkerbelle-io/tb-manage/internal/logging"
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+	"github.com/tinkerbelle-io/tb-manage/internal/signing"
+	"github.com/tinkerbelle-io/tb-manage/internal/terminal"
 	"github.com/tinkerbelle-io/tb-manage/internal/upload"
 )
 
@@ -19,19 +26,49 @@ var (
 	flagClusterID           string
 	flagIdleTimeout         time.Duration
 	flagScanInterval        time.Duration
+	flagScanTimeout         time.Duration
 	flagDaemonProfile       string
 	flagGatewayURL          string
 	flagSaaSURL             string
 	flagPermissions         []string
 	flagMaxSessions         int
 	flagExcludeNamespaces   []string
+	flagSkipNamespaces      []string
+	flagScanNamespaces      []string
+	flagClusterNameLabel    string
+	flagLocalAPIAddr        string
+	flagIoTCacheTTL         time.Duration
+	flagIPv6EgressCheck     bool
 	flagMaxRemediations     int
 	flagRemediationCooldown time.Duration
 	flagDryRun              bool
 	flagSkipUpload          bool
 	flagShellCommand        string
+	flagScrubOutput         bool
+	flagScrubPatterns       []string
+	flagPTYEnvDeny          []string
+	flagPTYEnvAllow         []string
+	flagSequenceFile        string
+	flagImageInventory      bool
 	flagAuditLog            string
 	flagPublicKey           string
+	flagOriginPolicy        map[string]string
+	flagHelmChartDrift      bool
+	flagDeprecatedAPITarget string
+	flagSkipDeprecatedAPI   bool
+	flagLogSampling         bool
+	flagKubeletCertProbe    bool
+	flagPKIDirs             []string
+	flagAnalyzerSummary     bool
+	flagAnalyzerConcurrency int
+	flagCronJobFailureRuns  int
+	flagK8sQPS              float32
+	flagK8sBurst            int
+	flagNSConcurrency       int
+	flagSigningKey          string
+	flagSigningCert         string
+	flagTOTPSecret          string
+	flagTOTPActions         []string
 )
 
 var daemonCmd = &cobra.Command{
@@ -53,28 +90,88 @@ Either or both can be specified.`,
 func init() {
 	daemonCmd.Flags().StringVar(&flagClusterID, "cluster-id", "", "Cluster identifier")
 	daemonCmd.Flags().DurationVar(&flagIdleTimeout, "idle-timeout", 30*time.Minute, "Terminal session idle timeout")
-	daemonCmd.Flags().DurationVar(&flagScanInterval, "scan-interval", 5*time.Minute, "Scan interval (e.g., 5m, 30s)")
+	daemonCmd.Flags().DurationVar(&flagScanInterval, "scan-interval", 5*time.Minute, "Scan interval (e.g., 5m, 30s); 0 scans once (env: SCAN_INTERVAL_SECONDS)")
+	daemonCmd.Flags().DurationVar(&flagScanTimeout, "scan-timeout", 0, "Abort a scan cycle after this long and upload the partial result (0 = no limit)")
 	daemonCmd.Flags().StringVar(&flagDaemonProfile, "profile", "standard", "Scan profile: minimal, standard, full")
 	daemonCmd.Flags().StringVar(&flagGatewayURL, "gateway", "", "Gateway WebSocket URL for terminal sessions (env: TB_GATEWAY_URL)")
 	daemonCmd.Flags().StringVar(&flagSaaSURL, "saas-url", "", "SaaS base URL for upload (env: TB_URL, defaults to --url)")
 	daemonCmd.Flags().StringSliceVar(&flagPermissions, "permissions", []string{"scan"}, "Agent permissions: scan, terminal")
 	daemonCmd.Flags().IntVar(&flagMaxSessions, "max-sessions", 10, "Maximum concurrent terminal sessions")
 	daemonCmd.Flags().StringSliceVar(&flagExcludeNamespaces, "exclude-namespaces", nil, "Comma-separated namespaces to exclude from k8s scanning (env: EXCLUDE_NAMESPACES)")
+	daemonCmd.Flags().StringSliceVar(&flagSkipNamespaces, "skip-namespaces", nil, "Comma-separated namespaces or globs skipped in addition to --exclude-namespaces or the defaults (env: TB_SKIP_NAMESPACES)")
+	daemonCmd.Flags().StringSliceVar(&flagScanNamespaces, "scan-namespaces", nil, "Comma-separated namespaces or globs to limit k8s scanning to (env: TB_SCAN_NAMESPACES)")
+	daemonCmd.Flags().StringVar(&flagClusterNameLabel, "cluster-name-label", scanner.DefaultClusterNameLabel, "Label on the kube-system namespace or nodes that names the cluster (env: CLUSTER_NAME_LABEL)")
+	daemonCmd.Flags().StringVar(&flagLocalAPIAddr, "local-api-addr", "", "Serve the latest scan results read-only over HTTP on this address (e.g. :9464; binds to localhost unless a host is given)")
+	daemonCmd.Flags().BoolVar(&flagIPv6EgressCheck, "ipv6-egress-check", false, "Check IPv6 Internet reachability during network scans (dials 2001:4860:4860::8888)")
+	daemonCmd.Flags().DurationVar(&flagIoTCacheTTL, "iot-cache-ttl", iot.DefaultCacheTTL, "Reuse each IoT provider's discovered devices for this long between scans (0 = query every scan)")
 	daemonCmd.Flags().IntVar(&flagMaxRemediations, "max-remediations-per-hour", 10, "Circuit breaker: max auto-remediations per hour")
 	daemonCmd.Flags().DurationVar(&flagRemediationCooldown, "remediation-cooldown", 30*time.Minute, "Per-resource cooldown between remediations")
 	daemonCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Remediation dry-run mode (log actions without executing)")
 	daemonCmd.Flags().BoolVar(&flagSkipUpload, "skip-upload", false, "Skip host scan upload (controller mode — DaemonSet handles host reporting)")
 	daemonCmd.Flags().StringVar(&flagAuditLog, "audit-log", "", "Custom audit log path (default: ~/.tb-manage/audit.log on macOS, /var/log/tb-manage/audit.log on Linux)")
 	daemonCmd.Flags().StringVar(&flagPublicKey, "public-key", "", "Ed25519 public key for command signature verification (hex or base64, env: TB_PUBLIC_KEY)")
+	daemonCmd.Flags().StringToStringVar(&flagOriginPolicy, "origin-policy", nil, "Actions each signing origin may issue, e.g. saas-api=*,automation=pty.resize|session.close; unlisted origins are denied unless *= is given")
+	daemonCmd.Flags().StringVar(&flagSigningKey, "signing-key", "", "Private key file to self-check at startup and hourly (env: TB_SIGNING_KEY)")
+	daemonCmd.Flags().StringVar(&flagSigningCert, "signing-cert", "", "X.509 PEM or SSH certificate to check for upcoming expiry (env: TB_SIGNING_CERT)")
+	daemonCmd.Flags().StringVar(&flagTOTPSecret, "totp-secret", "", "Base32 TOTP secret; when set, destructive commands must carry a valid totp_code parameter (env: TB_TOTP_SECRET)")
+	daemonCmd.Flags().StringSliceVar(&flagTOTPActions, "totp-actions", nil, "Actions gated by --totp-secret (default: delete_deployment,delete_pvc,drain_node,delete_job,delete_completed_jobs)")
+	daemonCmd.Flags().BoolVar(&flagHelmChartDrift, "helm-chart-drift", false, "Flag Flux HelmReleases whose chart is behind the latest version in their HelmRepository (fetches index.yaml)")
+	daemonCmd.Flags().StringVar(&flagDeprecatedAPITarget, "deprecated-api-target", "", "Kubernetes version (e.g. 1.29) to check for objects written through APIs removed by then (default: the release after the cluster's)")
+	daemonCmd.Flags().BoolVar(&flagSkipDeprecatedAPI, "skip-deprecated-api", false, "Disable the deprecated API check (lists workloads through the dynamic client)")
+	daemonCmd.Flags().BoolVar(&flagLogSampling, "log-sampling", false, "Sample recent logs of crashlooping/unready pods and attach error counts to insights (reads pod logs)")
+	daemonCmd.Flags().BoolVar(&flagKubeletCertProbe, "kubelet-cert-probe", false, "Flag kubelet serving certificates expiring within 30 days (dials each node's kubelet port)")
+	daemonCmd.Flags().StringSliceVar(&flagPKIDirs, "pki-dir", []string{insights.DefaultPKIDir}, "Directories of control-plane certificates to check for expiry; missing directories are skipped (empty = off)")
+	daemonCmd.Flags().IntVar(&flagAnalyzerConcurrency, "analyzer-concurrency", insights.DefaultAnalyzerConcurrency, "Maximum analyzer runs (one analyzer in one namespace) in flight at once")
+	daemonCmd.Flags().IntVar(&flagCronJobFailureRuns, "cronjob-failure-runs", insights.DefaultBrokenCronJobRuns, "Finished runs in a row that must have failed before a CronJob is flagged as broken")
+	daemonCmd.Flags().Float32Var(&flagK8sQPS, "k8s-qps", scanner.DefaultK8sQPS, "Kubernetes API requests per second during cluster scans")
+	daemonCmd.Flags().IntVar(&flagK8sBurst, "k8s-burst", scanner.DefaultK8sBurst, "Kubernetes API request burst during cluster scans")
+	daemonCmd.Flags().IntVar(&flagNSConcurrency, "namespace-concurrency", scanner.DefaultNamespaceConcurrency, "Namespaces scanned at once; halved automatically while the API server answers 429 Too Many Requests")
+	daemonCmd.Flags().BoolVar(&flagAnalyzerSummary, "report-analyzer-summary", false, "Include per-analyzer status, insight count and duration in insight reports")
 	daemonCmd.Flags().StringVar(&flagShellCommand, "shell-command", "", "Custom shell command for PTY sessions (e.g., 'nsenter -t 1 -m -u -i -n -- /bin/bash')")
+	daemonCmd.Flags().BoolVar(&flagScrubOutput, "scrub-output", false, "Mask secrets (AWS keys, JWTs, password= values) in terminal output with ***")
+	daemonCmd.Flags().StringArrayVar(&flagScrubPatterns, "scrub-pattern", nil, "Regexp for --scrub-output, repeatable; replaces the built-in list. A capture group masks only the group")
+	daemonCmd.Flags().StringArrayVar(&flagPTYEnvDeny, "pty-env-deny", nil, "Glob of environment variable names kept out of terminal sessions, repeatable; replaces the built-in list (TB_TOKEN, TB_SECRET, *_TOKEN, *_SECRET, *_PASSWORD, *_KEY)")
+	daemonCmd.Flags().StringArrayVar(&flagPTYEnvAllow, "pty-env-allow", nil, "Glob of environment variable names passed to terminal sessions even when denied, repeatable")
+	daemonCmd.Flags().StringVar(&flagSequenceFile, "sequence-file", "", "State file numbering scans across restarts (default /var/lib/tb-manage/scan-sequence, ~/.tb-manage/scan-sequence on macOS)")
+	daemonCmd.Flags().BoolVar(&flagImageInventory, "image-inventory", false, "Include a deduplicated list of running images with digests in cluster scans")
 	rootCmd.AddCommand(daemonCmd)
 }
 
 func runDaemon(cmd *cobra.Command, args []string) error {
 	logging.Setup(flagLogLevel)
 
+	agentCfg, err := resolveDaemonConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if agentCfg == nil {
+		return cmd.Help()
+	}
+
+	effective := newEffectiveConfig(*agentCfg)
+	if data, err := json.Marshal(effective); err == nil {
+		slog.Info("effective configuration", "config", string(data))
+	}
+	if agentCfg.ScanConfig != nil {
+		agentCfg.ScanConfig.EffectiveConfig = effective
+	}
+
+	return agent.New(*agentCfg).Run(context.Background())
+}
+
+// resolveDaemonConfig resolves the agent configuration from flags, env and
+// the config file (flag > env > config file > default). It returns nil
+// when no mode of operation is configured.
+func resolveDaemonConfig(cmd *cobra.Command) (*agent.Config, error) {
 	// Load config file for defaults (permissions, etc.)
-	cfg, _ := config.Load(flagConfig)
+	cfg, err := config.Load(flagConfig)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	redact := upload.RedactRules{Remove: cfg.Redact.Remove, Hash: cfg.Redact.Hash, HashKey: cfg.Redact.HashKey}
+	if err := redact.Validate(); err != nil {
+		return nil, err
+	}
 
 	// Resolve values: flag > env > config file > default
 	token := resolveToken()
@@ -96,9 +193,15 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		identity = cfg.Identity
 	}
 
-	// Need at least one mode of operation: token, multi-upstream, or host-key identity
-	if token == "" && resolveUpstreams() == "" && identity != "ssh-host-key" {
-		return cmd.Help()
+	upstreams, err := loadUpstreams()
+	if err != nil {
+		return nil, err
+	}
+
+	// Need at least one mode of operation: token, multi-upstream, host-key
+	// identity, or scanning for the local API alone
+	if token == "" && len(upstreams) == 0 && identity != "ssh-host-key" && flagLocalAPIAddr == "" {
+		return nil, nil
 	}
 
 	// Merge permissions: flag overrides config file
@@ -112,17 +215,75 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 	if !cmd.Flags().Changed("exclude-namespaces") && cfg != nil && len(cfg.ExcludeNamespaces) > 0 {
 		excludeNS = cfg.ExcludeNamespaces
 	}
+	skipNS := flagSkipNamespaces
+	if !cmd.Flags().Changed("skip-namespaces") && cfg != nil {
+		skipNS = cfg.SkipNamespaces
+	}
+	includeNS := flagScanNamespaces
+	if !cmd.Flags().Changed("scan-namespaces") && cfg != nil {
+		includeNS = cfg.ScanNamespaces
+	}
+	clusterNameLabel := flagClusterNameLabel
+	if !cmd.Flags().Changed("cluster-name-label") && cfg != nil && cfg.ClusterNameLabel != "" {
+		clusterNameLabel = cfg.ClusterNameLabel
+	}
 
-	// Build scan loop config
-	var scanCfg *agent.ScanLoopConfig
+	interval, err := resolveScanInterval(cmd.Flags().Changed("scan-interval"), flagScanInterval, cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	// Multi-upstream via TB_UPSTREAMS takes priority
-	if upstreamsJSON := resolveUpstreams(); upstreamsJSON != "" {
-		upstreams, err := upload.ParseUpstreams(upstreamsJSON)
-		if err != nil {
-			return fmt.Errorf("parse TB_UPSTREAMS: %w", err)
-		}
+	totp, err := resolveTOTPPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	originPolicy, err := signing.ParseOriginPolicy(flagOriginPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build scan loop config: the same scan settings in every mode, which
+	// differ only in where results are sent
+	scanSettings := agent.ScanLoopConfig{
+		Profile:                flagDaemonProfile,
+		Interval:               interval,
+		ScanTimeout:            flagScanTimeout,
+		Version:                rootCmd.Version,
+		ExcludeNamespaces:      excludeNS,
+		SkipNamespaces:         skipNS,
+		IncludeNamespaces:      includeNS,
+		ClusterNameLabel:       clusterNameLabel,
+		LocalAPIAddr:           flagLocalAPIAddr,
+		SequencePath:           flagSequenceFile,
+		IoTCacheTTL:            flagIoTCacheTTL,
+		IPv6EgressCheck:        flagIPv6EgressCheck,
+		HelmChartDrift:         flagHelmChartDrift,
+		DeprecatedAPITarget:    flagDeprecatedAPITarget,
+		SkipDeprecatedAPI:      flagSkipDeprecatedAPI,
+		LogSampling:            flagLogSampling,
+		KubeletCertProbe:       flagKubeletCertProbe,
+		PKIDirs:                flagPKIDirs,
+		ReportAnalyzerSummary:  flagAnalyzerSummary,
+		AnalyzerConcurrency:    flagAnalyzerConcurrency,
+		BrokenCronJobRuns:      flagCronJobFailureRuns,
+		K8sQPS:                 flagK8sQPS,
+		K8sBurst:               flagK8sBurst,
+		NamespaceConcurrency:   flagNSConcurrency,
+		ImageInventory:         flagImageInventory,
+		Redact:                 redact,
+		Enrich:                 upload.Enrichment{Static: cfg.Enrich.Static, Command: cfg.Enrich.Command},
+		MaxPayloadBytes:        cfg.MaxPayloadBytes,
+		TOTP:                   totp,
+		SkipUpload:             flagSkipUpload,
+		MaxRemediationsPerHour: flagMaxRemediations,
+		RemediationCooldown:    flagRemediationCooldown,
+		DryRun:                 flagDryRun,
+	}
+	var scanCfg *agent.ScanLoopConfig
 
+	// Multi-upstream via --upstreams-file or TB_UPSTREAMS takes priority
+	if len(upstreams) > 0 {
 		// Safety: at most 1 upstream may have "remediate" permission (prevents split-brain)
 		remediateCount := 0
 		for _, u := range upstreams {
@@ -133,39 +294,24 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 			}
 		}
 		if remediateCount > 1 {
-			return fmt.Errorf("at most 1 upstream may have 'remediate' permission (found %d) — prevents split-brain remediation", remediateCount)
+			return nil, fmt.Errorf("at most 1 upstream may have 'remediate' permission (found %d) — prevents split-brain remediation", remediateCount)
 		}
 
-		scanCfg = &agent.ScanLoopConfig{
-			Profile:                flagDaemonProfile,
-			Interval:               flagScanInterval,
-			Upstreams:              upstreams,
-			Version:                rootCmd.Version,
-			ExcludeNamespaces:      excludeNS,
-			SkipUpload:             flagSkipUpload,
-			MaxRemediationsPerHour: flagMaxRemediations,
-			RemediationCooldown:    flagRemediationCooldown,
-			DryRun:                 flagDryRun,
-		}
+		scanCfg = &scanSettings
+		scanCfg.Upstreams = upstreams
 	} else if saasURL != "" {
 		anonKey := resolveAnonKey()
 		if anonKey == "" && cfg != nil && cfg.AnonKey != "" {
 			anonKey = cfg.AnonKey
 		}
-		scanCfg = &agent.ScanLoopConfig{
-			Profile:                flagDaemonProfile,
-			Interval:               flagScanInterval,
-			UploadURL:              saasURL,
-			Token:                  token,
-			AnonKey:                anonKey,
-			IdentityMode:           identity,
-			Version:                rootCmd.Version,
-			ExcludeNamespaces:      excludeNS,
-			SkipUpload:             flagSkipUpload,
-			MaxRemediationsPerHour: flagMaxRemediations,
-			RemediationCooldown:    flagRemediationCooldown,
-			DryRun:                 flagDryRun,
-		}
+		scanCfg = &scanSettings
+		scanCfg.UploadURL = saasURL
+		scanCfg.Token = token
+		scanCfg.AnonKey = anonKey
+		scanCfg.IdentityMode = identity
+	} else if flagLocalAPIAddr != "" {
+		// No SaaS: scan only to serve results on the local API
+		scanCfg = &scanSettings
 	}
 
 	// Parse shell command if provided
@@ -174,18 +320,38 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		shellCmd = strings.Fields(flagShellCommand)
 	}
 
+	var scrubber *terminal.Scrubber
+	if flagScrubOutput {
+		patterns := flagScrubPatterns
+		if len(patterns) == 0 {
+			patterns = terminal.DefaultScrubPatterns
+		}
+		if scrubber, err = terminal.NewScrubber(patterns); err != nil {
+			return nil, err
+		}
+	}
+
+	denyEnv := flagPTYEnvDeny
+	if len(denyEnv) == 0 {
+		denyEnv = terminal.DefaultEnvDenyPatterns
+	}
+	envFilter, err := terminal.NewEnvFilter(denyEnv, flagPTYEnvAllow)
+	if err != nil {
+		return nil, err
+	}
+
 	// Load SSH host key if identity mode is ssh-host-key
 	var hostIdentity *auth.HostIdentity
 	if identity == "ssh-host-key" {
 		hi, err := auth.LoadHostKey("")
 		if err != nil {
-			return fmt.Errorf("load host key for gateway auth: %w", err)
+			return nil, fmt.Errorf("load host key for gateway auth: %w", err)
 		}
 		hostIdentity = hi
 		slog.Info("loaded SSH host key for gateway auth", "fingerprint", hi.Fingerprint)
 	}
 
-	a := agent.New(agent.Config{
+	return &agent.Config{
 		WSURL:        gatewayURL,
 		Token:        token,
 		ClusterID:    flagClusterID,
@@ -194,14 +360,31 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		Permissions:  permissions,
 		MaxSessions:  flagMaxSessions,
 		ShellCommand:       shellCmd,
+		OutputScrubber:     scrubber,
+		EnvFilter:          envFilter,
 		TokenInURLFallback: cfg.TokenInURLFallback,
 		AuditLogPath:       flagAuditLog,
 		PublicKey:          resolvePublicKey(),
+		OriginPolicy:       originPolicy,
 		IdentityMode:       identity,
 		HostIdentity:       hostIdentity,
-	})
+		SigningKeyPath:     resolveSigningKey(),
+		SigningCertPath:    resolveSigningCert(),
+	}, nil
+}
 
-	return a.Run(context.Background())
+// resolveScanInterval returns the scan interval: --scan-interval flag >
+// SCAN_INTERVAL_SECONDS / config file > flag default. Zero means one-shot;
+// negative values are rejected.
+func resolveScanInterval(flagChanged bool, flagValue time.Duration, cfg *config.Config) (time.Duration, error) {
+	interval := flagValue
+	if !flagChanged && cfg != nil {
+		interval = cfg.ScanInterval
+	}
+	if interval < 0 {
+		return 0, fmt.Errorf("scan interval must be >= 0 (0 = one-shot), got %s", interval)
+	}
+	return interval, nil
 }
 
 // resolveSaaSURL returns the SaaS URL for uploading scan results.
@@ -254,3 +437,36 @@ func resolvePublicKey() string {
 	}
 	return resolveEnv("TB_PUBLIC_KEY")
 }
+
+// resolveSigningKey returns the signing private key path from flag or env.
+func resolveSigningKey() string {
+	if flagSigningKey != "" {
+		return flagSigningKey
+	}
+	return resolveEnv("TB_SIGNING_KEY")
+}
+
+// resolveTOTPPolicy builds the second-factor policy from flag or env.
+// It returns nil when no secret is configured.
+func resolveTOTPPolicy() (*commands.TOTPPolicy, error) {
+	secret := flagTOTPSecret
+	if secret == "" {
+		secret = resolveEnv("TB_TOTP_SECRET")
+	}
+	if secret == "" {
+		return nil, nil
+	}
+	policy, err := commands.NewTOTPPolicy(secret, flagTOTPActions)
+	if err != nil {
+		return nil, fmt.Errorf("totp: %w", err)
+	}
+	return policy, nil
+}
+
+// resolveSigningCert returns the signing certificate path from flag or env.
+func resolveSigningCert() string {
+	if flagSigningCert != "" {
+		return flagSigningCert
+	}
+	return resolveEnv("TB_SIGNING_CERT")
+}
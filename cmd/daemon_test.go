@@ -1,6 +1,15 @@
 package cmd
 
-import "testing"
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/config"
+)
 
 func TestValidateGatewayURL(t *testing.T) {
 	tests := []struct {
@@ -28,3 +37,136 @@ func TestValidateGatewayURL(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveScanInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string // SCAN_INTERVAL_SECONDS, "" = unset
+		flagChanged bool
+		flagValue   time.Duration
+		want        time.Duration
+		wantErr     bool
+	}{
+		{"default", "", false, 5 * time.Minute, 5 * time.Minute, false},
+		{"env zero is one-shot", "0", false, 5 * time.Minute, 0, false},
+		{"env seconds", "90", false, 5 * time.Minute, 90 * time.Second, false},
+		{"env negative rejected", "-5", false, 5 * time.Minute, 0, true},
+		{"flag zero is one-shot", "", true, 0, 0, false},
+		{"flag overrides env", "0", true, time.Minute, time.Minute, false},
+		{"flag negative rejected", "", true, -time.Second, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SCAN_INTERVAL_SECONDS", tt.env)
+			cfg, err := config.Load("")
+			if err != nil {
+				t.Fatalf("config.Load: %v", err)
+			}
+			got, err := resolveScanInterval(tt.flagChanged, tt.flagValue, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveScanInterval() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveScanInterval() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigLoadInvalidScanInterval(t *testing.T) {
+	t.Setenv("SCAN_INTERVAL_SECONDS", "5m")
+	if _, err := config.Load(""); err == nil {
+		t.Fatal("expected error for non-integer SCAN_INTERVAL_SECONDS")
+	}
+}
+
+func TestEffectiveConfigPrecedenceAndRedaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	file := `token: file-token-000000000000
+url: https://file.example.com
+permissions: [scan]
+cluster_name_label: file-label
+enrich:
+  command: cmdb-lookup --api-key enrich-secret-0000
+`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TB_TOKEN", "env-token-000000000000")
+	t.Setenv("TB_URL", "https://env.example.com")
+	t.Setenv("TB_ANON_KEY", "env-anon-key-0000abcd")
+	t.Setenv("CLUSTER_NAME_LABEL", "env-label")
+
+	prevConfig, prevToken, prevPerms := flagConfig, flagToken, flagPermissions
+	t.Cleanup(func() {
+		flagConfig, flagToken, flagPermissions = prevConfig, prevToken, prevPerms
+		daemonCmd.Flags().Lookup("permissions").Changed = false
+	})
+	flagConfig = path
+	flagToken = "flag-token-00000000wxyz"
+	if err := daemonCmd.Flags().Set("permissions", "scan,terminal"); err != nil {
+		t.Fatal(err)
+	}
+
+	agentCfg, err := resolveDaemonConfig(daemonCmd)
+	if err != nil {
+		t.Fatalf("resolveDaemonConfig: %v", err)
+	}
+	if agentCfg == nil {
+		t.Fatal("resolveDaemonConfig returned no config")
+	}
+	ec := newEffectiveConfig(*agentCfg)
+
+	if ec.Mode != "single-upstream" {
+		t.Errorf("Mode = %q, want single-upstream", ec.Mode)
+	}
+	// flag > env > file
+	if ec.Token != "****wxyz" {
+		t.Errorf("Token = %q, want the flag token masked", ec.Token)
+	}
+	if got := strings.Join(ec.Permissions, ","); got != "scan,terminal" {
+		t.Errorf("Permissions = %q, want flag value", got)
+	}
+	// env > file
+	if ec.SaaSURL != "https://env.example.com" {
+		t.Errorf("SaaSURL = %q, want env value", ec.SaaSURL)
+	}
+	if ec.ClusterNameLabel != "env-label" {
+		t.Errorf("ClusterNameLabel = %q, want env value", ec.ClusterNameLabel)
+	}
+
+	data, err := json.Marshal(ec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"flag-token", "env-token", "file-token", "env-anon-key", "enrich-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("effective config leaks %q: %s", secret, data)
+		}
+	}
+}
+
+func TestResolveDaemonConfigLocalAPIOnly(t *testing.T) {
+	t.Setenv("TB_TOKEN", "")
+	t.Setenv("TB_URL", "")
+	t.Setenv("TB_UPSTREAMS", "")
+	prevConfig, prevAddr := flagConfig, flagLocalAPIAddr
+	t.Cleanup(func() { flagConfig, flagLocalAPIAddr = prevConfig, prevAddr })
+	flagConfig = filepath.Join(t.TempDir(), "missing.yaml")
+	flagLocalAPIAddr = "127.0.0.1:9464"
+
+	agentCfg, err := resolveDaemonConfig(daemonCmd)
+	if err != nil {
+		t.Fatalf("resolveDaemonConfig: %v", err)
+	}
+	if agentCfg == nil || agentCfg.ScanConfig == nil {
+		t.Fatal("expected a scan loop serving the local API without a token or upstreams")
+	}
+	if ec := newEffectiveConfig(*agentCfg); ec.Mode != "local-api-only" {
+		t.Errorf("Mode = %q, want local-api-only", ec.Mode)
+	}
+	if agentCfg.ScanConfig.UploadURL != "" || len(agentCfg.ScanConfig.Upstreams) > 0 {
+		t.Errorf("local-API-only config should not upload: %+v", agentCfg.ScanConfig)
+	}
+}
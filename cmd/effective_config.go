@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/agent"
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+)
+
+// effectiveConfig is the daemon's fully-resolved configuration, logged once
+// at startup and served by the local API at GET /config, so support can
+// see which flag, env or config file values won. Secrets are masked.
+type effectiveConfig struct {
+	Mode                string              `json:"mode"` // multi-upstream, single-upstream, local-api-only, terminal-only
+	Profile             string              `json:"profile,omitempty"`
+	ScanInterval        string              `json:"scan_interval,omitempty"`
+	ScanTimeout         string              `json:"scan_timeout,omitempty"`
+	Permissions         []string            `json:"permissions"`
+	Identity            string              `json:"identity"`
+	Token               string              `json:"token,omitempty"`
+	AnonKey             string              `json:"anon_key,omitempty"`
+	SaaSURL             string              `json:"saas_url,omitempty"`
+	GatewayURL          string              `json:"gateway_url,omitempty"`
+	LocalAPIAddr        string              `json:"local_api_addr,omitempty"`
+	Upstreams           []effectiveUpstream `json:"upstreams,omitempty"`
+	ExcludeNamespaces   []string            `json:"exclude_namespaces,omitempty"`
+	SkipNamespaces      []string            `json:"skip_namespaces,omitempty"`
+	ScanNamespaces      []string            `json:"scan_namespaces,omitempty"`
+	ClusterNameLabel    string              `json:"cluster_name_label,omitempty"`
+	Scanners            []string            `json:"scanners"`
+	IPv6EgressCheck     bool                `json:"ipv6_egress_check,omitempty"`
+	Analyzers           map[string]bool     `json:"analyzers"` // opt-in analyzers and their state
+	AnalyzerConcurrency int                 `json:"analyzer_concurrency,omitempty"`
+	CronJobFailureRuns  int                 `json:"cronjob_failure_runs,omitempty"`
+	DeprecatedAPITarget string              `json:"deprecated_api_target,omitempty"`
+	K8sQPS              float32             `json:"k8s_qps,omitempty"`
+	K8sBurst            int                 `json:"k8s_burst,omitempty"`
+	NSConcurrency       int                 `json:"namespace_concurrency,omitempty"`
+	ImageInventory      bool                `json:"image_inventory,omitempty"`
+	PKIDirs             []string            `json:"pki_dirs,omitempty"`
+	EnrichFields        []string            `json:"enrich_fields,omitempty"`  // static enrichment keys
+	EnrichCommand       string              `json:"enrich_command,omitempty"` // masked: may carry credentials
+	MaxPayloadBytes     int                 `json:"max_payload_bytes,omitempty"`
+	SkipUpload          bool                `json:"skip_upload,omitempty"`
+	DryRun              bool                `json:"dry_run,omitempty"`
+	SignatureCheck      bool                `json:"signature_check"`
+	OriginPolicy        map[string][]string `json:"origin_policy,omitempty"`
+	TOTPGate            bool                `json:"totp_gate"`
+}
+
+// effectiveUpstream is one upstream in multi-upstream mode.
+type effectiveUpstream struct {
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Token       string   `json:"token,omitempty"`
+	AnonKey     string   `json:"anon_key,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Profile     string   `json:"profile,omitempty"`
+}
+
+// newEffectiveConfig summarizes a resolved agent config with secrets masked.
+func newEffectiveConfig(cfg agent.Config) effectiveConfig {
+	ec := effectiveConfig{
+		Mode:           "terminal-only",
+		Permissions:    cfg.Permissions,
+		Identity:       cfg.IdentityMode,
+		Token:          redactSecret(cfg.Token),
+		GatewayURL:     cfg.WSURL,
+		Scanners:       []string{},
+		Analyzers:      map[string]bool{},
+		SignatureCheck: cfg.PublicKey != "",
+		OriginPolicy:   cfg.OriginPolicy,
+	}
+
+	sc := cfg.ScanConfig
+	if sc == nil {
+		return ec
+	}
+	switch {
+	case len(sc.Upstreams) > 0:
+		ec.Mode = "multi-upstream"
+	case sc.UploadURL != "":
+		ec.Mode = "single-upstream"
+	default:
+		ec.Mode = "local-api-only"
+	}
+	ec.Profile = sc.Profile
+	ec.ScanInterval = sc.Interval.String()
+	if sc.ScanTimeout > 0 {
+		ec.ScanTimeout = sc.ScanTimeout.String()
+	}
+	ec.AnonKey = redactSecret(sc.AnonKey)
+	ec.SaaSURL = sc.UploadURL
+	ec.LocalAPIAddr = sc.LocalAPIAddr
+	for _, u := range sc.Upstreams {
+		ec.Upstreams = append(ec.Upstreams, effectiveUpstream{
+			Name:        u.Name,
+			URL:         u.URL,
+			Token:       redactSecret(u.Token),
+			AnonKey:     redactSecret(u.AnonKey),
+			Permissions: u.Permissions,
+			Profile:     u.Profile,
+		})
+	}
+	ec.ExcludeNamespaces = sc.ExcludeNamespaces
+	ec.SkipNamespaces = sc.SkipNamespaces
+	ec.ScanNamespaces = sc.IncludeNamespaces
+	ec.ClusterNameLabel = sc.ClusterNameLabel
+	if p, err := scanner.ParseProfile(sc.Profile); err == nil {
+		for _, s := range scanner.NewRegistry().ForProfile(p) {
+			ec.Scanners = append(ec.Scanners, s.Name())
+		}
+	}
+	ec.IPv6EgressCheck = sc.IPv6EgressCheck
+	ec.Analyzers["helm_chart_drift"] = sc.HelmChartDrift
+	ec.Analyzers["deprecated_api"] = !sc.SkipDeprecatedAPI
+	ec.DeprecatedAPITarget = sc.DeprecatedAPITarget
+	ec.Analyzers["log_sampling"] = sc.LogSampling
+	ec.Analyzers["analyzer_summary"] = sc.ReportAnalyzerSummary
+	ec.Analyzers["kubelet_cert_probe"] = sc.KubeletCertProbe
+	ec.AnalyzerConcurrency = sc.AnalyzerConcurrency
+	ec.CronJobFailureRuns = sc.BrokenCronJobRuns
+	ec.K8sQPS = sc.K8sQPS
+	ec.K8sBurst = sc.K8sBurst
+	ec.NSConcurrency = sc.NamespaceConcurrency
+	ec.ImageInventory = sc.ImageInventory
+	ec.PKIDirs = sc.PKIDirs
+	for k := range sc.Enrich.Static {
+		ec.EnrichFields = append(ec.EnrichFields, k)
+	}
+	sort.Strings(ec.EnrichFields)
+	ec.EnrichCommand = redactSecret(sc.Enrich.Command)
+	ec.MaxPayloadBytes = sc.MaxPayloadBytes
+	ec.SkipUpload = sc.SkipUpload
+	ec.DryRun = sc.DryRun
+	ec.TOTPGate = sc.TOTP != nil
+	return ec
+}
+
+// redactSecret masks a secret for display. Long secrets keep their last 4
+// characters to tell them apart; "" stays "" so unset values are visible.
+func redactSecret(s string) string {
+	switch {
+	case s == "":
+		return ""
+	case len(s) < 16:
+		return "****"
+	default:
+		return "****" + s[len(s)-4:]
+	}
+}
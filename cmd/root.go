@@ -5,16 +5,18 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/tinkerbelle-io/tb-manage/internal/upload"
 )
 
 var (
 	// Flags
-	flagToken    string
-	flagURL      string
-	flagAnonKey  string
-	flagConfig   string
-	flagLogLevel string
-	flagIdentity string
+	flagToken         string
+	flagURL           string
+	flagAnonKey       string
+	flagConfig        string
+	flagLogLevel      string
+	flagIdentity      string
+	flagUpstreamsFile string
 )
 
 var rootCmd = &cobra.Command{
@@ -32,6 +34,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&flagAnonKey, "anon-key", "", "Supabase anon key for API auth (env: TB_ANON_KEY)")
 	rootCmd.PersistentFlags().StringVar(&flagConfig, "config", "", "Config file path (default: /etc/tb-manage/config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&flagLogLevel, "log-level", "info", "Log level: debug, info, warn, error")
+	rootCmd.PersistentFlags().StringVar(&flagUpstreamsFile, "upstreams-file", "", "JSON file with an array of upstreams, overrides TB_UPSTREAMS (env: TB_UPSTREAMS_FILE)")
 	rootCmd.PersistentFlags().StringVar(&flagIdentity, "identity", "", "Identity mode: token (default), ssh-host-key (env: TB_IDENTITY)")
 }
 
@@ -84,6 +87,30 @@ func resolveUpstreams() string {
 	return os.Getenv("TB_UPSTREAMS")
 }
 
+// resolveUpstreamsFile returns the upstreams file path from flag or environment.
+func resolveUpstreamsFile() string {
+	if flagUpstreamsFile != "" {
+		return flagUpstreamsFile
+	}
+	return os.Getenv("TB_UPSTREAMS_FILE")
+}
+
+// loadUpstreams returns the configured upstreams: the upstreams file if
+// set, else TB_UPSTREAMS. It returns nil when neither is configured.
+func loadUpstreams() ([]upload.Upstream, error) {
+	if path := resolveUpstreamsFile(); path != "" {
+		return upload.LoadUpstreamsFile(path)
+	}
+	if upstreamsJSON := resolveUpstreams(); upstreamsJSON != "" {
+		upstreams, err := upload.ParseUpstreams(upstreamsJSON)
+		if err != nil {
+			return nil, fmt.Errorf("parse TB_UPSTREAMS: %w", err)
+		}
+		return upstreams, nil
+	}
+	return nil, nil
+}
+
 // lookupEnv wraps os.LookupEnv.
 func lookupEnv(key string) (string, bool) {
 	return os.LookupEnv(key)
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tinkerbelle-io/tb-manage/internal/auth"
+	"github.com/tinkerbelle-io/tb-manage/internal/config"
 	"github.com/tinkerbelle-io/tb-manage/internal/logging"
 	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
 	"github.com/tinkerbelle-io/tb-manage/internal/ssh"
@@ -21,8 +24,17 @@ var (
 	flagJSON    bool
 	flagSSH     []string
 	flagUpload  bool
+	flagOutput  string
+	flagOutMode string
+
+	flagVerboseScan bool
+	flagScanImages  bool
 )
 
+// progress reports per-step scan activity on stderr with --verbose-scan.
+// Nil (the default) discards it.
+var progress *scanner.Progress
+
 var scanCmd = &cobra.Command{
 	Use:   "scan",
 	Short: "Run a one-shot infrastructure scan",
@@ -40,18 +52,25 @@ func init() {
 	scanCmd.Flags().BoolVar(&flagJSON, "json", false, "Output as JSON")
 	scanCmd.Flags().StringSliceVar(&flagSSH, "ssh", nil, "Remote hosts to scan via SSH (user@host[:port])")
 	scanCmd.Flags().BoolVar(&flagUpload, "upload", false, "Upload results to TinkerBelle SaaS (requires --token and --url)")
+	scanCmd.Flags().StringVarP(&flagOutput, "output", "o", "", "Write the JSON result to this file instead of stdout")
+	scanCmd.Flags().StringVar(&flagOutMode, "output-mode", "0600", "File permissions for --output (octal)")
+	scanCmd.Flags().BoolVar(&flagVerboseScan, "verbose-scan", false, "Stream per-step scan progress to stderr")
+	scanCmd.Flags().BoolVar(&flagScanImages, "image-inventory", false, "Include a deduplicated list of running images with digests in the cluster scan")
 	rootCmd.AddCommand(scanCmd)
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
 	logging.Setup(flagLogLevel)
+	if flagVerboseScan {
+		progress = scanner.NewProgress(os.Stderr)
+	}
 
 	profile, err := scanner.ParseProfile(flagProfile)
 	if err != nil {
 		return err
 	}
 
-	reg := scanner.NewRegistry()
+	reg := scanner.NewRegistryWithOptions(scanner.RegistryOptions{ImageInventory: flagScanImages})
 	scanners := reg.ForProfile(profile)
 
 	if len(scanners) == 0 {
@@ -72,19 +91,10 @@ func runScan(cmd *cobra.Command, args []string) error {
 
 func runLocalScan(ctx context.Context, scanners []scanner.Scanner, profile scanner.Profile) error {
 	start := time.Now()
-	result := scanner.NewResult()
-	runner := scanner.LocalRunner{}
-
-	for _, s := range scanners {
-		data, scanErr := s.Scan(ctx, runner)
-		if scanErr != nil {
-			slog.Warn("scanner failed", "scanner", s.Name(), "error", scanErr)
-			continue
-		}
-		result.Set(s.Name(), data)
-	}
+	result := runScanners(ctx, scanners, scanner.LocalRunner{}, slog.Default())
 
 	scanner.ApplyTopology(result)
+	scanner.ApplyHealth(result)
 
 	result.Meta.Version = rootCmd.Version
 	result.Meta.DurationMS = int(time.Since(start).Milliseconds())
@@ -125,7 +135,6 @@ func runSSHScan(ctx context.Context, scanners []scanner.Scanner, profile scanner
 		slog.Info("scanning remote host", "target", target.String())
 
 		start := time.Now()
-		result := scanner.NewResult()
 
 		runner, err := ssh.NewRunner(target)
 		if err != nil {
@@ -134,22 +143,20 @@ func runSSHScan(ctx context.Context, scanners []scanner.Scanner, profile scanner
 			continue
 		}
 
+		// Skip K8s scanner for SSH mode (uses client-go, not commands)
+		var remote []scanner.Scanner
 		for _, s := range scanners {
-			// Skip K8s scanner for SSH mode (uses client-go, not commands)
-			if s.Name() == "cluster" {
-				continue
-			}
-			data, scanErr := s.Scan(ctx, runner)
-			if scanErr != nil {
-				slog.Warn("scanner failed", "target", target.String(), "scanner", s.Name(), "error", scanErr)
-				continue
+			if s.Name() != "cluster" {
+				remote = append(remote, s)
 			}
-			result.Set(s.Name(), data)
 		}
+		progress.Printf("scanning %s", target.String())
+		result := runScanners(ctx, remote, runner, slog.With("target", target.String()))
 
 		runner.Close()
 
 		scanner.ApplyTopology(result)
+		scanner.ApplyHealth(result)
 
 		result.Meta.Version = rootCmd.Version
 		result.Meta.DurationMS = int(time.Since(start).Milliseconds())
@@ -166,6 +173,24 @@ func runSSHScan(ctx context.Context, scanners []scanner.Scanner, profile scanner
 		slog.Info("scan complete", "target", target.String(), "duration_ms", result.Meta.DurationMS)
 	}
 
+	// Cloned images and bad VM templates show up as the same MAC on
+	// several hosts, which breaks hardware-based identity
+	if len(results) > 1 {
+		hostIfaces := make(map[string][]scanner.InterfaceInfo)
+		for _, hr := range results {
+			if hr.Result == nil || hr.Result.Network == nil {
+				continue
+			}
+			var netInfo scanner.NetworkInfo
+			if err := json.Unmarshal(hr.Result.Network, &netInfo); err == nil {
+				hostIfaces[hr.Target] = netInfo.Interfaces
+			}
+		}
+		for _, c := range scanner.FindMACCollisions(hostIfaces) {
+			slog.Warn("duplicate MAC address across hosts", "mac", c.MAC, "hosts", c.Hosts)
+		}
+	}
+
 	// Output
 	if len(targets) == 1 && results[0].Error == "" {
 		// Single host: output just the result
@@ -173,6 +198,9 @@ func runSSHScan(ctx context.Context, scanners []scanner.Scanner, profile scanner
 	}
 
 	// Multi-host: output array
+	if flagOutput != "" {
+		return writeOutputFile(flagOutput, flagOutMode, results)
+	}
 	if flagJSON {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -195,20 +223,75 @@ func runSSHScan(ctx context.Context, scanners []scanner.Scanner, profile scanner
 	return nil
 }
 
-func uploadResult(ctx context.Context, result *scanner.Result) error {
-	req := upload.BuildRequest(result)
+// runScanners runs each scanner in turn and collects their output.
+// Failed scanners are logged to log and skipped.
+func runScanners(ctx context.Context, scanners []scanner.Scanner, runner scanner.CommandRunner, log *slog.Logger) *scanner.Result {
+	result := scanner.NewResult()
+	runner = progress.Runner(runner)
 
-	// Multi-upstream mode: TB_UPSTREAMS JSON array
-	if upstreamsJSON := resolveUpstreams(); upstreamsJSON != "" {
-		upstreams, err := upload.ParseUpstreams(upstreamsJSON)
-		if err != nil {
-			return fmt.Errorf("parse TB_UPSTREAMS: %w", err)
+	for _, s := range scanners {
+		progress.Printf("starting %s scan", s.Name())
+		start := time.Now()
+		data, scanErr := s.Scan(ctx, runner)
+		if scanErr != nil {
+			log.Warn("scanner failed", "scanner", s.Name(), "error", scanErr)
+			progress.Printf("%s scan failed: %v", s.Name(), scanErr)
+			continue
 		}
+		result.Set(s.Name(), data)
+		progress.Printf("finished %s scan in %s (%s)", s.Name(), time.Since(start).Round(time.Millisecond), formatBytes(len(data)))
+	}
+	return result
+}
+
+// formatBytes renders a payload size for progress output.
+func formatBytes(n int) string {
+	if n < 1024 {
+		return fmt.Sprintf("%dB", n)
+	}
+	return fmt.Sprintf("%dKB", (n+512)/1024)
+}
+
+func uploadResult(ctx context.Context, result *scanner.Result) error {
+	cfg, err := config.Load(flagConfig)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	rules := upload.RedactRules{Remove: cfg.Redact.Remove, Hash: cfg.Redact.Hash, HashKey: cfg.Redact.HashKey}
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	enrich := upload.Enrichment{Static: cfg.Enrich.Static, Command: cfg.Enrich.Command}
+	extra, err := enrich.Fields(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Multi-upstream mode: --upstreams-file or TB_UPSTREAMS JSON array.
+	// Each upstream gets the result trimmed to its own profile.
+	upstreams, err := loadUpstreams()
+	if err != nil {
+		return err
+	}
+	if len(upstreams) > 0 {
+		progress.Printf("uploading to %d upstream(s)", len(upstreams))
 		mc := upload.NewMultiClient(upstreams)
-		_, err = mc.Upload(ctx, req)
+		_, err = mc.UploadResult(ctx, result, extra, rules, cfg.MaxPayloadBytes)
 		return err
 	}
 
+	req, err := upload.PrepareRequest(result, extra, rules, cfg.MaxPayloadBytes)
+	if err != nil {
+		return err
+	}
+	if req.Meta.Truncated {
+		slog.Warn("payload truncated to fit size limit", "max_bytes", cfg.MaxPayloadBytes, "sections", req.Meta.TruncatedSections)
+	}
+
+	if data, err := json.Marshal(req); err == nil {
+		progress.Printf("uploading %s", formatBytes(len(data)))
+	}
+
 	identity := resolveIdentity()
 	url := resolveURL()
 	anonKey := resolveAnonKey()
@@ -249,6 +332,9 @@ func uploadResult(ctx context.Context, result *scanner.Result) error {
 }
 
 func outputResult(result *scanner.Result) error {
+	if flagOutput != "" {
+		return writeOutputFile(flagOutput, flagOutMode, result)
+	}
 	if flagJSON {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -263,3 +349,42 @@ func outputResult(result *scanner.Result) error {
 	}
 	return nil
 }
+
+// writeOutputFile writes v as indented JSON to path. The file is written to a
+// temp file in the same directory and renamed into place, so readers never
+// see a partial result.
+func writeOutputFile(path, mode string, v interface{}) error {
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return fmt.Errorf("invalid --output-mode %q: %w", mode, err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal result: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after successful rename
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Chmod(os.FileMode(perm)); err != nil {
+		tmp.Close()
+		return fmt.Errorf("chmod %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", tmp.Name(), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename to %s: %w", path, err)
+	}
+
+	slog.Info("wrote scan result", "path", path)
+	return nil
+}
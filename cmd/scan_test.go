@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+)
+
+func TestOutputResultToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.json")
+	flagOutput, flagOutMode, flagJSON = path, "0640", true
+	defer func() { flagOutput, flagOutMode, flagJSON = "", "0600", false }()
+
+	result := scanner.NewResult()
+	result.Set("os", json.RawMessage(`{"name":"linux"}`))
+	result.Meta.Profile = "minimal"
+
+	// Capture stdout to make sure the payload isn't also printed
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	outErr := outputResult(result)
+	os.Stdout = stdout
+	w.Close()
+	printed, _ := io.ReadAll(r)
+
+	if outErr != nil {
+		t.Fatalf("outputResult: %v", outErr)
+	}
+	if len(printed) != 0 {
+		t.Errorf("expected nothing on stdout, got %q", printed)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("output file not created: %v", err)
+	}
+	var got scanner.Result
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output file is not valid JSON: %v", err)
+	}
+	if got.Meta.Profile != "minimal" {
+		t.Errorf("profile = %q, want minimal", got.Meta.Profile)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("mode = %o, want 640", info.Mode().Perm())
+	}
+
+	// No temp files left behind
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected only the output file, found %d entries", len(entries))
+	}
+}
+
+func TestWriteOutputFileInvalidMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.json")
+	if err := writeOutputFile(path, "rw-r--r--", map[string]string{}); err == nil {
+		t.Fatal("expected error for invalid mode")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("file should not be created on invalid mode")
+	}
+}
+
+// fakeScanner runs one command and returns fixed output.
+type fakeScanner struct {
+	name string
+	cmd  string
+	err  error
+}
+
+func (s fakeScanner) Name() string        { return s.name }
+func (s fakeScanner) Platforms() []string { return nil }
+func (s fakeScanner) Scan(ctx context.Context, runner scanner.CommandRunner) (json.RawMessage, error) {
+	if _, err := runner.Run(ctx, s.cmd); err != nil {
+		return nil, err
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return json.RawMessage(`{"ok":true}`), nil
+}
+
+type fakeRunner map[string]string
+
+func (r fakeRunner) Run(_ context.Context, cmd string) ([]byte, error) {
+	out, ok := r[cmd]
+	if !ok {
+		return nil, errors.New("command not found")
+	}
+	return []byte(out), nil
+}
+
+func TestRunScannersVerboseProgress(t *testing.T) {
+	var buf bytes.Buffer
+	progress = scanner.NewProgress(&buf)
+	defer func() { progress = nil }()
+
+	runner := fakeRunner{
+		"lsblk -J": "sda\nsdb\nsdc\nsdd\n",
+		"uname -a": "Linux node-1",
+	}
+	scanners := []scanner.Scanner{
+		fakeScanner{name: "storage", cmd: "lsblk -J"},
+		fakeScanner{name: "host", cmd: "uname -a", err: errors.New("parse failed")},
+		fakeScanner{name: "network", cmd: "ip -j addr"},
+	}
+
+	result := runScanners(context.Background(), scanners, runner, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if len(result.Meta.Phases) != 1 || result.Meta.Phases[0] != "storage" {
+		t.Errorf("phases = %v, want [storage]", result.Meta.Phases)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"starting storage scan",
+		"lsblk returned 4 line(s)",
+		"finished storage scan",
+		"host scan failed: parse failed",
+		"ip failed after",
+		"network scan failed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("progress output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunScannersQuietByDefault(t *testing.T) {
+	result := runScanners(context.Background(),
+		[]scanner.Scanner{fakeScanner{name: "host", cmd: "uname -a"}},
+		fakeRunner{"uname -a": "Linux"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if len(result.Meta.Phases) != 1 {
+		t.Errorf("phases = %v", result.Meta.Phases)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+	"github.com/tinkerbelle-io/tb-manage/internal/schema"
+	"github.com/tinkerbelle-io/tb-manage/internal/upload"
+)
+
+// payloadSchemas are the upload payloads the schema command documents.
+var payloadSchemas = map[string]func() *schema.Schema{
+	"host":    func() *schema.Schema { return schema.Generate("HostScanResult", upload.HostScanResult{}) },
+	"cluster": func() *schema.Schema { return schema.Generate("ClusterScanResult", scanner.ClusterScanResult{}) },
+	"edge-ingest": func() *schema.Schema {
+		return schema.Generate("EdgeIngestRequest", upload.EdgeIngestRequest{},
+			schema.RawField(upload.EdgeIngestRequest{}, "cluster", scanner.ClusterScanResult{}))
+	},
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [host|cluster|edge-ingest]",
+	Short: "Print the JSON Schema of the upload payloads",
+	Long: `Print a JSON Schema (draft 2020-12) generated from the payload structs.
+
+With an argument, print that payload's schema. Without one, print an
+object mapping each payload name to its schema.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	var out any
+	if len(args) == 1 {
+		gen, ok := payloadSchemas[args[0]]
+		if !ok {
+			return fmt.Errorf("unknown payload %q (valid: %s)", args[0], strings.Join(payloadSchemaNames(), ", "))
+		}
+		out = gen()
+	} else {
+		all := make(map[string]*schema.Schema, len(payloadSchemas))
+		for name, gen := range payloadSchemas {
+			all[name] = gen()
+		}
+		out = all
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func payloadSchemaNames() []string {
+	names := make([]string, 0, len(payloadSchemas))
+	for name := range payloadSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+	"github.com/tinkerbelle-io/tb-manage/internal/schema"
+	"github.com/tinkerbelle-io/tb-manage/internal/upload"
+)
+
+func TestPayloadSchemasValidateSample(t *testing.T) {
+	cluster, err := json.Marshal(scanner.ClusterScanResult{
+		Name: "prod",
+		Nodes: []scanner.NodeScanResult{{
+			Name: "node-1", Status: "Ready", Roles: []string{"control-plane"},
+			Version: "v1.31.0", OS: "linux", OSImage: "Ubuntu 24.04",
+		}},
+		Namespaces: []scanner.NamespaceScanResult{{
+			Name:   "default",
+			Labels: map[string]string{"team": "infra"},
+			Workloads: []scanner.WorkloadScanResult{{
+				Name: "api", Namespace: "default", Kind: "Deployment",
+				Containers: []scanner.ContainerInfoK8s{{Name: "api", Image: "api:1.2"}},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := json.Marshal(upload.EdgeIngestRequest{
+		AgentToken: "tok",
+		Host: &upload.HostScanResult{
+			Name: "node-1",
+			Type: "baremetal",
+			System: upload.HostSystem{
+				OS: "linux", Arch: "amd64", CPUCores: 8, MemoryGB: 31.3,
+				LoadAvg: []float64{0.5, 0.4, 0.3},
+			},
+			Network: upload.HostNetwork{
+				Hostname:   "node-1",
+				Interfaces: []upload.HostInterface{{Name: "eth0", IP: "10.0.0.5"}},
+			},
+			Storage: json.RawMessage(`{"filesystems":[]}`),
+		},
+		Cluster: cluster,
+		Meta:    upload.EdgeIngestMeta{Version: "test", DurationMS: 1200, Phases: []string{"host", "cluster"}, SourceHost: "node-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schema.Validate(payloadSchemas["edge-ingest"](), req); err != nil {
+		t.Errorf("edge-ingest sample: %v", err)
+	}
+	if err := schema.Validate(payloadSchemas["cluster"](), cluster); err != nil {
+		t.Errorf("cluster sample: %v", err)
+	}
+
+	var body map[string]json.RawMessage
+	json.Unmarshal(req, &body)
+	if err := schema.Validate(payloadSchemas["host"](), body["host"]); err != nil {
+		t.Errorf("host sample: %v", err)
+	}
+
+	// The cluster section is checked against the cluster scan
+	if err := schema.Validate(payloadSchemas["edge-ingest"](), []byte(`{"agent_token":"tok","cluster":{"name":1},`+
+		`"meta":{"version":"test","duration_ms":0,"phases":[],"source_host":"n"}}`)); err == nil {
+		t.Error("expected an edge-ingest request with a malformed cluster to fail validation")
+	}
+
+	// A host without its required system block must be rejected
+	if err := schema.Validate(payloadSchemas["host"](), []byte(`{"name":"n","type":"vm","network":{"hostname":"n","interfaces":[]}}`)); err == nil {
+		t.Error("expected host without system to fail validation")
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tinkerbelle-io/tb-manage/internal/auth"
+	"github.com/tinkerbelle-io/tb-manage/internal/config"
+	"github.com/tinkerbelle-io/tb-manage/internal/logging"
+	"github.com/tinkerbelle-io/tb-manage/internal/upload"
+)
+
+var testUploadCmd = &cobra.Command{
+	Use:   "test-upload",
+	Short: "Send a synthetic scan to verify edge-ingest connectivity",
+	Long: `Send a minimal synthetic host scan to the configured URL, or to each
+upstream in TB_UPSTREAMS/--upstreams-file, with the same credentials and
+identity headers a real upload uses, and print the HTTP status and
+edge-ingest response. Use it to check connectivity and credentials before
+running the daemon.
+
+The scan is uploaded as host "` + testUploadHost + `" rather than under this
+machine's hostname, so it does not replace the host's real inventory.`,
+	RunE: runTestUpload,
+}
+
+func init() {
+	rootCmd.AddCommand(testUploadCmd)
+}
+
+func runTestUpload(cmd *cobra.Command, args []string) error {
+	logging.Setup(flagLogLevel)
+
+	cfg, err := config.Load(flagConfig)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	// Multi-upstream mode: each upstream is tested with its own credentials
+	upstreams, err := loadUpstreams()
+	if err != nil {
+		return err
+	}
+	if len(upstreams) > 0 {
+		var failed []string
+		for i, u := range upstreams {
+			if i > 0 {
+				fmt.Fprintln(os.Stdout)
+			}
+			fmt.Fprintf(os.Stdout, "Upstream:   %s\n", u.Name)
+			client := upload.NewClient(u.URL, u.Token, u.AnonKey)
+			if err := testUpload(ctx, os.Stdout, u.URL, client, u.Token); err != nil {
+				failed = append(failed, u.Name)
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("test upload failed for %s", strings.Join(failed, ", "))
+		}
+		return nil
+	}
+
+	// Resolve values: flag > env > config file
+	url := resolveURL()
+	if url == "" && cfg != nil {
+		url = cfg.URL
+	}
+	token := resolveToken()
+	if token == "" && cfg != nil {
+		token = cfg.Token
+	}
+	anonKey := resolveAnonKey()
+	if anonKey == "" && cfg != nil {
+		anonKey = cfg.AnonKey
+	}
+	identity := resolveIdentity()
+	if flagIdentity == "" && resolveEnv("TB_IDENTITY") == "" && cfg != nil && cfg.Identity != "" {
+		identity = cfg.Identity
+	}
+
+	if url == "" {
+		return fmt.Errorf("--url/TB_URL required for test-upload")
+	}
+
+	var client *upload.Client
+	if identity == "ssh-host-key" {
+		// Token is optional here; the host key headers identify the node
+		hostID, err := auth.LoadHostKey("")
+		if err != nil {
+			return fmt.Errorf("load host key: %w", err)
+		}
+		client = upload.NewHostKeyClient(url, anonKey, token, hostID)
+	} else {
+		if token == "" {
+			return fmt.Errorf("--token/TB_TOKEN required for test-upload (or use --identity ssh-host-key)")
+		}
+		client = upload.NewClient(url, token, anonKey)
+	}
+	return testUpload(ctx, os.Stdout, url, client, token)
+}
+
+// testUpload posts a synthetic host scan to edge-ingest through client and
+// reports the outcome to w. Retries are turned off, so the first status
+// the server returns is the one shown. A non-200 status is returned as an
+// error after the response body is printed.
+func testUpload(ctx context.Context, w io.Writer, baseURL string, client *upload.Client, token string) error {
+	client.SetMaxRetries(0)
+	fmt.Fprintf(w, "POST %s/functions/v1/edge-ingest\n", baseURL)
+
+	result, err := client.Upload(ctx, syntheticUploadRequest(token))
+	var statusErr *upload.StatusError
+	if errors.As(err, &statusErr) {
+		fmt.Fprintf(w, "Status:     %d %s\n", statusErr.StatusCode, http.StatusText(statusErr.StatusCode))
+		fmt.Fprintf(w, "Error:      %s\n", strings.TrimSpace(statusErr.Body))
+		return fmt.Errorf("test upload failed (HTTP %d)", statusErr.StatusCode)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "Error:      %v\n", err)
+		return err
+	}
+
+	fmt.Fprintf(w, "Status:     %d %s\n", http.StatusOK, http.StatusText(http.StatusOK))
+	fmt.Fprintf(w, "Success:    %s\n", boolStatus(result.Success))
+	fmt.Fprintf(w, "Session:    %s\n", valueOrNA(result.SessionID))
+	fmt.Fprintf(w, "Cluster:    %s\n", valueOrNA(result.ClusterID))
+	fmt.Fprintf(w, "Resources:  %d\n", result.ResourceCount)
+	return nil
+}
+
+// testUploadHost names the synthetic host test-upload reports, so its
+// empty scan is never taken for a real host's.
+const testUploadHost = "tb-manage-test-upload"
+
+// syntheticUploadRequest builds the smallest request edge-ingest accepts:
+// a host with only its name, OS and architecture.
+func syntheticUploadRequest(token string) *upload.EdgeIngestRequest {
+	return &upload.EdgeIngestRequest{
+		AgentToken: token,
+		Host: &upload.HostScanResult{
+			Name: testUploadHost,
+			Type: "baremetal",
+			System: upload.HostSystem{
+				OS:   runtime.GOOS,
+				Arch: runtime.GOARCH,
+			},
+		},
+		Meta: upload.EdgeIngestMeta{
+			Version:    rootCmd.Version,
+			Phases:     []string{"host"},
+			SourceHost: testUploadHost,
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/upload"
+)
+
+func TestTestUpload(t *testing.T) {
+	var got upload.EdgeIngestRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/functions/v1/edge-ingest" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if got.AgentToken != "good-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid agent token"}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"session_id":"sess-1","cluster_id":"clu-1","resource_count":1}`))
+	}))
+	defer srv.Close()
+
+	t.Run("success", func(t *testing.T) {
+		var out bytes.Buffer
+		client := upload.NewClient(srv.URL, "good-token", "anon")
+		if err := testUpload(context.Background(), &out, srv.URL, client, "good-token"); err != nil {
+			t.Fatalf("testUpload: %v\n%s", err, out.String())
+		}
+		for _, want := range []string{"200 OK", "Session:    sess-1", "Cluster:    clu-1", "Resources:  1"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+		// Never reported under the real hostname, which would replace the
+		// host's inventory with an empty scan
+		if got.Host == nil || got.Host.Name != testUploadHost || got.Meta.SourceHost != testUploadHost {
+			t.Errorf("synthetic request host = %+v, meta = %+v, want %s", got.Host, got.Meta, testUploadHost)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		var out bytes.Buffer
+		err := testUpload(context.Background(), &out, srv.URL, upload.NewClient(srv.URL, "bad-token", "anon"), "bad-token")
+		if err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+			t.Fatalf("err = %v, want HTTP 401 failure", err)
+		}
+		for _, want := range []string{"401 Unauthorized", "invalid agent token"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+		if strings.Contains(out.String(), "Session:") {
+			t.Errorf("unexpected response fields on failure:\n%s", out.String())
+		}
+	})
+
+	t.Run("no retry", func(t *testing.T) {
+		var calls int
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+
+		var out bytes.Buffer
+		err := testUpload(context.Background(), &out, failing.URL, upload.NewClient(failing.URL, "good-token", ""), "good-token")
+		if err == nil || calls != 1 {
+			t.Fatalf("err = %v after %d calls, want one failed attempt", err, calls)
+		}
+		if !strings.Contains(out.String(), "503 Service Unavailable") {
+			t.Errorf("output missing status:\n%s", out.String())
+		}
+	})
+}
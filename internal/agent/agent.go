@@ -47,10 +47,16 @@ type Agent struct {
 	hostIdentity       *auth.HostIdentity
 	writeMu     sync.Mutex
 
+	// Gateway reconnect state
+	backoff     *reconnectBackoff
+	connectedAt time.Time
+
 	// Permissions
 	permissions  map[string]bool
 	maxSessions  int
 	shellCommand []string
+	scrubber     *terminal.Scrubber
+	envFilter    *terminal.EnvFilter
 
 	// Audit
 	auditLog *audit.AuditLogger
@@ -58,6 +64,11 @@ type Agent struct {
 	// Signing verification
 	verifier *signing.Verifier
 
+	// Key material self-check
+	keyMaterial    signing.KeyMaterial
+	healthMu       sync.Mutex
+	healthWarnings []string
+
 	// Scan loop
 	scanLoop *ScanLoop
 	log      *slog.Logger
@@ -66,6 +77,9 @@ type Agent struct {
 const (
 	// DefaultMaxSessions is the maximum concurrent terminal sessions per agent.
 	DefaultMaxSessions = 10
+
+	// SelfCheckInterval is how often key material is re-validated.
+	SelfCheckInterval = time.Hour
 )
 
 // Config holds agent configuration.
@@ -78,11 +92,16 @@ type Config struct {
 	Permissions  []string        // e.g., ["terminal", "scan"]
 	MaxSessions  int             // 0 = DefaultMaxSessions
 	ShellCommand       []string // Custom shell command (e.g., ["nsenter", "-t", "1", "-m", "-u", "-i", "-n", "--", "/bin/bash"])
+	OutputScrubber     *terminal.Scrubber // masks secrets in PTY output (nil = off)
+	EnvFilter          *terminal.EnvFilter // env vars PTY sessions inherit (nil = terminal defaults)
 	TokenInURLFallback bool     // DEPRECATED: also send token in URL query param for migration
 	AuditLogPath       string   // Custom audit log path (empty = default)
 	PublicKey          string   // Ed25519 public key for command verification (hex or base64)
+	OriginPolicy       signing.OriginPolicy // actions each signing origin may issue (nil = all)
 	IdentityMode       string            // "token" or "ssh-host-key"
 	HostIdentity       *auth.HostIdentity // SSH host key identity (when IdentityMode == "ssh-host-key")
+	SigningKeyPath     string             // Private key used to sign requests (empty = not checked)
+	SigningCertPath    string             // Certificate for the signing key (empty = not checked)
 }
 
 // New creates a new Agent (does not connect yet).
@@ -118,9 +137,13 @@ func New(cfg Config) *Agent {
 			return nil
 		}
 		verifier = signing.NewVerifier(pubKey)
-		logger.Info("command signature verification enabled")
+		verifier.SetOriginPolicy(cfg.OriginPolicy)
+		logger.Info("command signature verification enabled", "origin_policy", cfg.OriginPolicy != nil)
 	} else {
 		logger.Warn("no public key configured — commands will NOT be verified (insecure)")
+		if cfg.OriginPolicy != nil {
+			logger.Warn("origin policy ignored without a public key")
+		}
 	}
 
 	a := &Agent{
@@ -136,13 +159,24 @@ func New(cfg Config) *Agent {
 		permissions:  perms,
 		maxSessions:  maxSessions,
 		shellCommand: cfg.ShellCommand,
+		scrubber:     cfg.OutputScrubber,
+		envFilter:    cfg.EnvFilter,
 		log:          logger,
 		auditLog:     auditLog,
 		verifier:     verifier,
+		backoff:      newReconnectBackoff(),
+		keyMaterial: signing.KeyMaterial{
+			PublicKey:      cfg.PublicKey,
+			PrivateKeyPath: cfg.SigningKeyPath,
+			CertPath:       cfg.SigningCertPath,
+		},
 	}
 
 	if cfg.ScanConfig != nil {
 		a.scanLoop = NewScanLoop(*cfg.ScanConfig, logger)
+		if cfg.WSURL != "" {
+			a.scanLoop.SetCommandEvents(a.sendMessage)
+		}
 	}
 
 	return a
@@ -165,15 +199,46 @@ func (a *Agent) Run(ctx context.Context) error {
 		}
 	}()
 
+	// SIGHUP triggers an immediate scan; overlapping triggers are coalesced
+	if a.scanLoop != nil {
+		hupCh := make(chan os.Signal, 1)
+		signal.Notify(hupCh, syscall.SIGHUP)
+		go func() {
+			defer signal.Stop(hupCh)
+			for {
+				select {
+				case <-hupCh:
+					a.log.Info("received SIGHUP, triggering scan")
+					go a.scanLoop.Trigger(ctx)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// Validate key material now and periodically so expiring or broken
+	// keys surface before they cause auth failures
+	a.runSelfCheck()
+	go a.selfCheckLoop(ctx)
+
 	// Start scan loop if configured
+	scanDone := make(chan struct{})
 	if a.scanLoop != nil {
-		go a.scanLoop.Run(ctx)
+		go func() {
+			a.scanLoop.Run(ctx)
+			close(scanDone)
+		}()
 	}
 
-	// If no WebSocket URL, run scan-only mode
+	// If no WebSocket URL, run scan-only mode. A one-shot scan loop
+	// returns after its first scan, which ends the agent too.
 	if a.wsURL == "" {
 		a.log.Info("no WebSocket URL configured, running in scan-only mode")
-		<-ctx.Done()
+		select {
+		case <-ctx.Done():
+		case <-scanDone:
+		}
 		return nil
 	}
 
@@ -185,12 +250,7 @@ func (a *Agent) Run(ctx context.Context) error {
 	a.log.Info("connected to gateway", "url", a.wsURL)
 
 	// Send immediate heartbeat so gateway knows our agentId
-	a.sendMessage(protocol.HeartbeatMessage{
-		Type:      protocol.TypeHeartbeat,
-		AgentID:   a.agentID,
-		ClusterID: a.clusterID,
-		Timestamp: time.Now().Unix(),
-	})
+	a.sendMessage(a.heartbeat())
 
 	// Start periodic heartbeat
 	go a.heartbeatLoop(ctx)
@@ -262,31 +322,43 @@ func (a *Agent) connect() error {
 	if err != nil {
 		return err
 	}
+	a.writeMu.Lock()
 	a.conn = conn
+	a.writeMu.Unlock()
+	a.connectedAt = time.Now()
 	return nil
 }
 
+// reconnect re-dials the gateway with exponential backoff and jitter.
+// Terminal sessions are kept open; their output resumes on the new
+// connection once it is established.
 func (a *Agent) reconnect(ctx context.Context) error {
-	backoff := time.Second
-	maxBackoff := 30 * time.Second
+	a.writeMu.Lock()
+	if a.conn != nil {
+		a.conn.Close()
+	}
+	a.writeMu.Unlock()
+
+	if a.backoff == nil {
+		a.backoff = newReconnectBackoff()
+	}
+	a.backoff.Disconnected(time.Since(a.connectedAt))
+
+	for attempt := 1; ; attempt++ {
+		delay := a.backoff.Next()
+		a.log.Info("attempting reconnect", "attempt", attempt, "backoff", delay)
 
-	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(backoff):
+		case <-time.After(delay):
 		}
 
-		a.log.Info("attempting reconnect", "backoff", backoff)
 		if err := a.connect(); err != nil {
-			a.log.Warn("reconnect failed", "error", err, "backoff", backoff)
-			backoff *= 2
-			if backoff > maxBackoff {
-				backoff = maxBackoff
-			}
+			a.log.Warn("reconnect failed", "attempt", attempt, "error", err)
 			continue
 		}
-		a.log.Info("reconnected to gateway")
+		a.log.Info("reconnected to gateway", "attempts", attempt)
 		return nil
 	}
 }
@@ -503,6 +575,8 @@ func (a *Agent) handleSessionOpen(msg protocol.SessionOpenMessage) error {
 				Error:     errMsg,
 			})
 		},
+		a.scrubber,
+		a.envFilter,
 	)
 	if err != nil {
 		a.sendMessage(protocol.SessionErrorMessage{
@@ -622,12 +696,46 @@ func (a *Agent) heartbeatLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			a.sendMessage(protocol.HeartbeatMessage{
-				Type:      protocol.TypeHeartbeat,
-				AgentID:   a.agentID,
-				ClusterID: a.clusterID,
-				Timestamp: time.Now().Unix(),
-			})
+			a.sendMessage(a.heartbeat())
+		}
+	}
+}
+
+func (a *Agent) heartbeat() protocol.HeartbeatMessage {
+	a.healthMu.Lock()
+	warnings := a.healthWarnings
+	a.healthMu.Unlock()
+	return protocol.HeartbeatMessage{
+		Type:      protocol.TypeHeartbeat,
+		AgentID:   a.agentID,
+		ClusterID: a.clusterID,
+		Timestamp: time.Now().Unix(),
+		Warnings:  warnings,
+	}
+}
+
+// runSelfCheck validates the agent's key material, logging any problems and
+// recording them for the next heartbeat.
+func (a *Agent) runSelfCheck() signing.SelfCheckResult {
+	result := signing.CheckKeyMaterial(a.keyMaterial, time.Now())
+	for _, w := range result.Warnings {
+		a.log.Warn("key material self-check", "warning", w)
+	}
+	a.healthMu.Lock()
+	a.healthWarnings = result.Warnings
+	a.healthMu.Unlock()
+	return result
+}
+
+func (a *Agent) selfCheckLoop(ctx context.Context) {
+	ticker := time.NewTicker(SelfCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.runSelfCheck()
 		}
 	}
 }
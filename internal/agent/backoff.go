@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	// Gateway reconnect backoff bounds.
+	reconnectBaseDelay = time.Second
+	reconnectMaxDelay  = 60 * time.Second
+	// reconnectStableAfter is how long a connection must stay up before the
+	// backoff resets, so a gateway that accepts then drops us still backs off.
+	reconnectStableAfter = 2 * time.Minute
+)
+
+// reconnectBackoff computes gateway reconnect delays: exponential growth from
+// base up to max, with equal jitter so agents don't reconnect in lockstep.
+type reconnectBackoff struct {
+	base        time.Duration
+	max         time.Duration
+	stableAfter time.Duration
+	attempt     int
+	rand        func() float64 // returns [0,1); injectable for tests
+}
+
+func newReconnectBackoff() *reconnectBackoff {
+	return &reconnectBackoff{
+		base:        reconnectBaseDelay,
+		max:         reconnectMaxDelay,
+		stableAfter: reconnectStableAfter,
+		rand:        rand.Float64,
+	}
+}
+
+// ceiling returns the un-jittered delay for the current attempt.
+func (b *reconnectBackoff) ceiling() time.Duration {
+	d := b.base
+	for i := 0; i < b.attempt && d < b.max; i++ {
+		d *= 2
+	}
+	if d > b.max {
+		d = b.max
+	}
+	return d
+}
+
+// Next returns the delay before the next attempt and advances the backoff.
+// The delay is uniformly distributed in [ceiling/2, ceiling).
+func (b *reconnectBackoff) Next() time.Duration {
+	d := b.ceiling()
+	b.attempt++
+	half := d / 2
+	return half + time.Duration(b.rand()*float64(d-half))
+}
+
+// Disconnected records that a connection ended after uptime. Connections
+// that stayed up for at least stableAfter reset the backoff.
+func (b *reconnectBackoff) Disconnected(uptime time.Duration) {
+	if uptime >= b.stableAfter {
+		b.attempt = 0
+	}
+}
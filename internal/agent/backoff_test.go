@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectBackoffGrowthAndCap(t *testing.T) {
+	b := newReconnectBackoff()
+	b.rand = func() float64 { return 0.999999 } // delays land just under the ceiling
+
+	want := []time.Duration{1, 2, 4, 8, 16, 32, 60, 60, 60}
+	for i, w := range want {
+		ceiling := w * time.Second
+		got := b.Next()
+		if got < ceiling/2 || got >= ceiling {
+			t.Errorf("attempt %d: delay %s outside [%s, %s)", i, got, ceiling/2, ceiling)
+		}
+	}
+}
+
+func TestReconnectBackoffJitterRange(t *testing.T) {
+	b := newReconnectBackoff()
+	b.attempt = 3 // ceiling 8s
+
+	b.rand = func() float64 { return 0 }
+	if got := b.Next(); got != 4*time.Second {
+		t.Errorf("min jitter delay = %s, want 4s", got)
+	}
+
+	b.attempt = 3
+	b.rand = func() float64 { return 0.5 }
+	if got := b.Next(); got != 6*time.Second {
+		t.Errorf("mid jitter delay = %s, want 6s", got)
+	}
+}
+
+func TestReconnectBackoffReset(t *testing.T) {
+	b := newReconnectBackoff()
+	b.rand = func() float64 { return 0 }
+	for i := 0; i < 5; i++ {
+		b.Next()
+	}
+
+	// A connection that drops quickly keeps backing off
+	b.Disconnected(5 * time.Second)
+	if got := b.Next(); got != 16*time.Second {
+		t.Errorf("after short-lived connection: delay = %s, want 16s", got)
+	}
+
+	// A long-lived connection resets to the base delay
+	b.Disconnected(reconnectStableAfter)
+	if got := b.Next(); got != reconnectBaseDelay/2 {
+		t.Errorf("after stable connection: delay = %s, want %s", got, reconnectBaseDelay/2)
+	}
+}
@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/insights"
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+)
+
+// LocalAPI serves the latest scan results read-only over HTTP, for on-box
+// tooling and debugging without the SaaS. A nil *LocalAPI ignores updates.
+//
+//	GET /inventory  host, network, storage and container phases + meta
+//	GET /cluster    cluster phase
+//	GET /insights   insights from the last analysis
+//	GET /iot        iot phase
+//	GET /power      power phase
+//	GET /config     the agent's effective configuration, secrets masked
+//
+// Scan endpoints return 404 until a scan has produced the data.
+type LocalAPI struct {
+	log *slog.Logger
+
+	mu       sync.RWMutex
+	config   any
+	result   *scanner.Result
+	insights []insights.ClusterInsight
+	analyzed bool
+}
+
+// NewLocalAPI creates an empty LocalAPI.
+func NewLocalAPI(logger *slog.Logger) *LocalAPI {
+	return &LocalAPI{log: logger.With("component", "localapi")}
+}
+
+// SetConfig records the effective configuration served at /config.
+func (a *LocalAPI) SetConfig(cfg any) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.config = cfg
+}
+
+// SetResult records the latest scan result.
+func (a *LocalAPI) SetResult(result *scanner.Result) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.result = result
+}
+
+// SetInsights records the latest insights.
+func (a *LocalAPI) SetInsights(found []insights.ClusterInsight) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.insights = found
+	a.analyzed = true
+}
+
+// Handler returns the HTTP handler for the API.
+func (a *LocalAPI) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /inventory", a.phase(func(r *scanner.Result) any {
+		if r.Host == nil {
+			return nil
+		}
+		return struct {
+			Host       json.RawMessage       `json:"host"`
+			Network    json.RawMessage       `json:"network,omitempty"`
+			Storage    json.RawMessage       `json:"storage,omitempty"`
+			Containers json.RawMessage       `json:"containers,omitempty"`
+			Health     *scanner.HealthReport `json:"health,omitempty"`
+			Meta       scanner.ResultMeta    `json:"meta"`
+		}{r.Host, r.Network, r.Storage, r.Containers, r.Health, r.Meta}
+	}))
+	mux.HandleFunc("GET /cluster", a.phase(func(r *scanner.Result) any { return rawOrNil(r.Cluster) }))
+	mux.HandleFunc("GET /iot", a.phase(func(r *scanner.Result) any { return rawOrNil(r.IoT) }))
+	mux.HandleFunc("GET /power", a.phase(func(r *scanner.Result) any { return rawOrNil(r.Power) }))
+	mux.HandleFunc("GET /insights", func(w http.ResponseWriter, _ *http.Request) {
+		a.mu.RLock()
+		found, analyzed := a.insights, a.analyzed
+		a.mu.RUnlock()
+		if !analyzed {
+			writeJSONError(w, http.StatusNotFound, "no insights analysis yet")
+			return
+		}
+		if found == nil {
+			found = []insights.ClusterInsight{}
+		}
+		writeJSON(w, http.StatusOK, found)
+	})
+	mux.HandleFunc("GET /config", func(w http.ResponseWriter, _ *http.Request) {
+		a.mu.RLock()
+		cfg := a.config
+		a.mu.RUnlock()
+		if cfg == nil {
+			writeJSONError(w, http.StatusNotFound, "no configuration recorded")
+			return
+		}
+		writeJSON(w, http.StatusOK, cfg)
+	})
+	return mux
+}
+
+// phase serves the part of the latest result selected by pick, or 404
+// when there is no result or pick returns nil.
+func (a *LocalAPI) phase(pick func(*scanner.Result) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		a.mu.RLock()
+		result := a.result
+		a.mu.RUnlock()
+
+		var body any
+		if result != nil {
+			body = pick(result)
+		}
+		if body == nil {
+			writeJSONError(w, http.StatusNotFound, "not collected by the last scan")
+			return
+		}
+		writeJSON(w, http.StatusOK, body)
+	}
+}
+
+// Serve listens on addr until ctx is cancelled. An addr without a host
+// (":9090") binds to localhost only; give an explicit host to expose it.
+func (a *LocalAPI) Serve(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              localAPIListenAddr(addr),
+		Handler:           a.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	a.log.Info("local API listening", "addr", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func localAPIListenAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		// Bare port
+		return net.JoinHostPort("127.0.0.1", addr)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+func rawOrNil(raw json.RawMessage) any {
+	if raw == nil {
+		return nil
+	}
+	return raw
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
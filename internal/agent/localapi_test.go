@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func getJSON(t *testing.T, srv *httptest.Server, path string, v any) int {
+	t.Helper()
+	resp, err := http.Get(srv.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%s: Content-Type = %q", path, ct)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("%s: decode: %v", path, err)
+	}
+	return resp.StatusCode
+}
+
+func TestLocalAPIServesLastScan(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	sl := NewScanLoop(ScanLoopConfig{
+		Profile:      "full",
+		Version:      "test",
+		LocalAPIAddr: ":0",
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
+	}, logger)
+	sl.scanners = func(scanner.Profile) []scanner.Scanner {
+		return []scanner.Scanner{
+			stubScanner{name: "host", data: `{"name":"node-1","system":{"os":"linux"}}`},
+			stubScanner{name: "storage", data: `{"filesystems":[]}`},
+			stubScanner{name: "cluster", data: `{"name":"prod","nodes":[{"name":"node-1"}]}`},
+			stubScanner{name: "iot", data: `{"devices":[{"ip":"10.0.0.9"}]}`},
+			stubScanner{name: "power", data: `{"targets":[{"host":"10.0.0.20"}]}`},
+		}
+	}
+	sl.k8sClient = fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "default"},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "api",
+			RestartCount: 12,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}},
+	})
+
+	srv := httptest.NewServer(sl.localAPI.Handler())
+	defer srv.Close()
+
+	// Nothing to serve before the first scan
+	var errBody map[string]string
+	if code := getJSON(t, srv, "/inventory", &errBody); code != http.StatusNotFound || errBody["error"] == "" {
+		t.Errorf("/inventory before scan = %d %v, want 404 with error", code, errBody)
+	}
+
+	sl.runScan(context.Background())
+
+	var inventory struct {
+		Host    map[string]any     `json:"host"`
+		Storage map[string]any     `json:"storage"`
+		Meta    scanner.ResultMeta `json:"meta"`
+	}
+	if code := getJSON(t, srv, "/inventory", &inventory); code != http.StatusOK {
+		t.Fatalf("/inventory = %d", code)
+	}
+	if inventory.Host == nil || inventory.Storage == nil || inventory.Meta.Version != "test" {
+		t.Errorf("/inventory = %+v, want host, storage and meta", inventory)
+	}
+
+	var cluster struct {
+		Name  string           `json:"name"`
+		Nodes []map[string]any `json:"nodes"`
+	}
+	if code := getJSON(t, srv, "/cluster", &cluster); code != http.StatusOK || cluster.Name != "prod" || len(cluster.Nodes) != 1 {
+		t.Errorf("/cluster = %d %+v", code, cluster)
+	}
+
+	var iot struct {
+		Devices []map[string]any `json:"devices"`
+	}
+	if code := getJSON(t, srv, "/iot", &iot); code != http.StatusOK || len(iot.Devices) != 1 {
+		t.Errorf("/iot = %d %+v", code, iot)
+	}
+
+	var power struct {
+		Targets []map[string]any `json:"targets"`
+	}
+	if code := getJSON(t, srv, "/power", &power); code != http.StatusOK || len(power.Targets) != 1 {
+		t.Errorf("/power = %d %+v", code, power)
+	}
+
+	var found []struct {
+		Analyzer   string `json:"analyzer"`
+		TargetName string `json:"target_name"`
+	}
+	if code := getJSON(t, srv, "/insights", &found); code != http.StatusOK {
+		t.Fatalf("/insights = %d", code)
+	}
+	var crashloop bool
+	for _, in := range found {
+		crashloop = crashloop || in.Analyzer == "crashlooping"
+	}
+	if !crashloop {
+		t.Errorf("/insights = %+v, want a crashlooping insight", found)
+	}
+}
+
+func TestLocalAPIMissingPhase(t *testing.T) {
+	api := NewLocalAPI(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+	result := scanner.NewResult()
+	result.Set("host", json.RawMessage(`{"name":"node-1"}`))
+	api.SetResult(result)
+
+	srv := httptest.NewServer(api.Handler())
+	defer srv.Close()
+
+	var body map[string]any
+	if code := getJSON(t, srv, "/cluster", &body); code != http.StatusNotFound {
+		t.Errorf("/cluster without cluster phase = %d, want 404", code)
+	}
+	if code := getJSON(t, srv, "/insights", &body); code != http.StatusNotFound {
+		t.Errorf("/insights before analysis = %d, want 404", code)
+	}
+
+	resp, err := http.Post(srv.URL+"/inventory", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST /inventory = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestLocalAPIServesConfig(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	api := NewLocalAPI(logger)
+	srv := httptest.NewServer(api.Handler())
+	defer srv.Close()
+
+	var body map[string]string
+	if code := getJSON(t, srv, "/config", &body); code != http.StatusNotFound {
+		t.Errorf("/config before SetConfig = %d, want 404", code)
+	}
+
+	api.SetConfig(map[string]string{"mode": "local-api-only", "token": "****"})
+	body = nil
+	if code := getJSON(t, srv, "/config", &body); code != http.StatusOK || body["mode"] != "local-api-only" {
+		t.Errorf("/config = %d %v", code, body)
+	}
+}
+
+func TestLocalAPIListenAddr(t *testing.T) {
+	tests := map[string]string{
+		":9464":          "127.0.0.1:9464",
+		"9464":           "127.0.0.1:9464",
+		"0.0.0.0:9464":   "0.0.0.0:9464",
+		"[::1]:9464":     "[::1]:9464",
+		"localhost:9464": "localhost:9464",
+	}
+	for in, want := range tests {
+		if got := localAPIListenAddr(in); got != want {
+			t.Errorf("localAPIListenAddr(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -2,29 +2,77 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/tinkerbelle-io/tb-manage/internal/auth"
 	"github.com/tinkerbelle-io/tb-manage/internal/commands"
 	"github.com/tinkerbelle-io/tb-manage/internal/insights"
+	"github.com/tinkerbelle-io/tb-manage/internal/protocol"
 	"github.com/tinkerbelle-io/tb-manage/internal/remediation"
 	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
 	"github.com/tinkerbelle-io/tb-manage/internal/upload"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
 // ScanLoopConfig configures the periodic scan loop.
 type ScanLoopConfig struct {
 	Profile           string
-	Interval          time.Duration
-	UploadURL         string            // Supabase base URL for edge-ingest (single mode)
-	Token             string            // agent_token (single mode)
-	AnonKey           string            // Supabase anon key (single mode)
-	IdentityMode      string            // "token" or "ssh-host-key"
-	Upstreams         []upload.Upstream // Multi-upstream mode
-	Version           string            // binary version
-	ExcludeNamespaces []string          // namespaces to skip during k8s scan
+	Interval          time.Duration      // 0 = scan once and return
+	ScanTimeout       time.Duration      // 0 = no limit; otherwise collection + analysis abort and upload partial data
+	UploadURL         string             // Supabase base URL for edge-ingest (single mode)
+	Token             string             // agent_token (single mode)
+	AnonKey           string             // Supabase anon key (single mode)
+	IdentityMode      string             // "token" or "ssh-host-key"
+	Upstreams         []upload.Upstream  // Multi-upstream mode
+	Version           string             // binary version
+	ExcludeNamespaces []string           // namespaces to skip during k8s scan
+	SkipNamespaces    []string           // skipped on top of ExcludeNamespaces (or the defaults); globs allowed
+	IncludeNamespaces []string           // scan only these namespaces ("" = all); globs allowed
+	ClusterNameLabel  string             // label naming the cluster ("" = scanner default)
+	IoTCacheTTL       time.Duration      // reuse each IoT provider's discovery this long (0 = always query)
+	IPv6EgressCheck   bool               // dial an Internet host over IPv6 to confirm egress
+	HelmChartDrift    bool               // compare HelmRelease charts against their repo index (fetches index.yaml)
+	LogSampling       bool               // attach error-line samples from pod logs to crashloop/unready insights
+	KubeletCertProbe  bool               // dial each node's kubelet port to check its serving certificate expiry
+	PKIDirs           []string           // directories of control-plane certificates to check for expiry
+	Redact            upload.RedactRules // payload fields to strip/hash before upload
+	Enrich            upload.Enrichment  // site fields merged into the host payload before redaction
+	MaxPayloadBytes   int                // truncate uploads larger than this (0 = no limit)
+	LocalAPIAddr      string             // serve the latest results read-only over HTTP ("" = off)
+	EffectiveConfig   any                // resolved agent config, served at GET /config on the local API
+
+	// State file numbering scans across restarts ("" = DefaultSequencePath)
+	SequencePath string
+
+	// Kubernetes version checked for objects written through removed APIs
+	// ("" = the release after the cluster's), or skip that check entirely
+	DeprecatedAPITarget string
+	SkipDeprecatedAPI   bool
+
+	// Attach per-analyzer status/duration to insight reports
+	ReportAnalyzerSummary bool
+
+	// Analyzer runs in flight at once (0 = insights.DefaultAnalyzerConcurrency)
+	AnalyzerConcurrency int
+
+	// Failed runs in a row before a CronJob is flagged (0 = insights.DefaultBrokenCronJobRuns)
+	BrokenCronJobRuns int
+
+	// Kubernetes API rate limit (0 = scanner.DefaultK8sQPS/DefaultK8sBurst)
+	// and namespaces scanned at once (0 = scanner.DefaultNamespaceConcurrency)
+	K8sQPS               float32
+	K8sBurst             int
+	NamespaceConcurrency int
+
+	// List distinct running images with digests in cluster scans
+	ImageInventory bool
+
+	// Second-factor gate for destructive commands (nil = off)
+	TOTP *commands.TOTPPolicy
 
 	// Controller mode: skip host scan upload (DaemonSet handles that)
 	SkipUpload bool
@@ -40,6 +88,7 @@ type ScanLoop struct {
 	cfg      ScanLoopConfig
 	log      *slog.Logger
 	uploader upload.Uploader
+	multi    *upload.MultiClient // set in multi-upstream mode, uploads per-upstream profiles
 
 	// Insights
 	insightsEngine   *insights.Engine
@@ -49,13 +98,32 @@ type ScanLoop struct {
 	remediator  *remediation.Remediator
 	remReporter *remediation.Reporter
 
+	// Read-only HTTP view of the latest results (nil when disabled)
+	localAPI *LocalAPI
+
+	// Numbers each scan for the SaaS to order uploads
+	sequence *ScanSequence
+
 	// Commands
 	cmdPollers    []*commands.Poller
 	cmdExecutor   *commands.Executor
 	cmdCompleters []*commands.Completer
 
+	// Relays command progress and results to the gateway (nil = not
+	// connected to one)
+	cmdEvents func(msg any)
+
 	// Shared k8s client (nil until first use, lazy-initialized)
 	k8sClient kubernetes.Interface
+
+	// Single-flight guard: timer and SIGHUP triggers never overlap; a
+	// trigger during an active scan queues at most one follow-up.
+	scanMu      sync.Mutex
+	scanning    bool
+	scanPending bool
+	scan        func(ctx context.Context) // runScan, replaceable in tests
+
+	scanners func(scanner.Profile) []scanner.Scanner // registry lookup, replaceable in tests
 }
 
 // NewScanLoop creates a new scan loop.
@@ -64,9 +132,49 @@ func NewScanLoop(cfg ScanLoopConfig, logger *slog.Logger) *ScanLoop {
 		cfg: cfg,
 		log: logger.With("component", "scanloop"),
 	}
+	sl.scan = sl.runScan
+	seq, err := NewScanSequence(cfg.SequencePath)
+	if err != nil {
+		logger.Error("scan sequence state not durable, numbering seeded from the clock", "path", seq.path, "error", err)
+	}
+	sl.sequence = seq
+	if cfg.LocalAPIAddr != "" {
+		sl.localAPI = NewLocalAPI(logger)
+		sl.localAPI.SetConfig(cfg.EffectiveConfig)
+	}
+	// One registry for the loop's lifetime so scanner caches (IoT) persist
+	// between cycles
+	iotCacheTTL := cfg.IoTCacheTTL
+	if iotCacheTTL == 0 {
+		iotCacheTTL = -1
+	}
+	registryOpts := scanner.RegistryOptions{
+		ExcludeNamespaces: cfg.ExcludeNamespaces,
+		SkipNamespaces:    cfg.SkipNamespaces,
+		IncludeNamespaces: cfg.IncludeNamespaces,
+		ClusterNameLabel:  cfg.ClusterNameLabel,
+		IoTCacheTTL:       iotCacheTTL,
+		IPv6EgressCheck:   cfg.IPv6EgressCheck,
+
+		K8sQPS:               cfg.K8sQPS,
+		K8sBurst:             cfg.K8sBurst,
+		NamespaceConcurrency: cfg.NamespaceConcurrency,
+		ImageInventory:       cfg.ImageInventory,
+	}
+	sl.scanners = scanner.NewRegistryWithOptions(registryOpts).ForProfile
 
 	if len(cfg.Upstreams) > 0 {
-		sl.uploader = upload.NewMultiClient(cfg.Upstreams)
+		// Upstreams without their own profile get the agent's; the scan
+		// itself runs at the widest profile any upstream asks for
+		upstreams := make([]upload.Upstream, len(cfg.Upstreams))
+		copy(upstreams, cfg.Upstreams)
+		for i := range upstreams {
+			if upstreams[i].Profile == "" {
+				upstreams[i].Profile = cfg.Profile
+			}
+		}
+		sl.multi = upload.NewMultiClient(upstreams)
+		sl.uploader = sl.multi
 	} else if cfg.UploadURL != "" && cfg.IdentityMode == "ssh-host-key" {
 		// SSH host key identity: load host key and create host-key client.
 		// Token is passed through for cluster routing (host key = identity, token = cluster).
@@ -81,7 +189,16 @@ func NewScanLoop(cfg ScanLoopConfig, logger *slog.Logger) *ScanLoop {
 	}
 
 	// Initialize insights engine
-	sl.insightsEngine = insights.NewEngine(cfg.ExcludeNamespaces)
+	// Insights cover the same namespaces as the cluster scan
+	sl.insightsEngine = insights.NewEngine(nil)
+	sl.insightsEngine.SetNamespaceFilter(scanner.NamespaceFilter(registryOpts))
+	sl.insightsEngine.SetConcurrency(cfg.AnalyzerConcurrency)
+	if cfg.BrokenCronJobRuns > 0 {
+		sl.insightsEngine.SetAnalyzer(insights.NewBrokenCronJobAnalyzer(cfg.BrokenCronJobRuns))
+	}
+	if cfg.LogSampling {
+		sl.insightsEngine.SetLogSampler(insights.NewLogSampler(insights.LogSampleOptions{}))
+	}
 
 	// Set up per-upstream reporters and command infrastructure
 	for _, u := range cfg.Upstreams {
@@ -118,6 +235,7 @@ func NewScanLoop(cfg ScanLoopConfig, logger *slog.Logger) *ScanLoop {
 
 // Run starts the scan loop. It runs an initial scan immediately, then
 // scans at the configured interval until the context is cancelled.
+// With a zero interval it returns after the initial scan (one-shot).
 func (sl *ScanLoop) Run(ctx context.Context) {
 	sl.log.Info("scan loop starting",
 		"profile", sl.cfg.Profile,
@@ -125,8 +243,21 @@ func (sl *ScanLoop) Run(ctx context.Context) {
 		"upload", sl.uploader != nil,
 	)
 
+	if sl.localAPI != nil {
+		go func() {
+			if err := sl.localAPI.Serve(ctx, sl.cfg.LocalAPIAddr); err != nil {
+				sl.log.Error("local API failed", "addr", sl.cfg.LocalAPIAddr, "error", err)
+			}
+		}()
+	}
+
 	// Initial scan immediately
-	sl.runScan(ctx)
+	sl.requestScan(ctx, "initial")
+
+	if sl.cfg.Interval <= 0 {
+		sl.log.Info("one-shot scan complete")
+		return
+	}
 
 	ticker := time.NewTicker(sl.cfg.Interval)
 	defer ticker.Stop()
@@ -137,8 +268,49 @@ func (sl *ScanLoop) Run(ctx context.Context) {
 			sl.log.Info("scan loop stopped")
 			return
 		case <-ticker.C:
-			sl.runScan(ctx)
+			sl.requestScan(ctx, "timer")
+		}
+	}
+}
+
+// Trigger runs a scan now, e.g. on SIGHUP. If a scan is already running
+// the request is coalesced into a single follow-up scan and Trigger
+// returns immediately.
+func (sl *ScanLoop) Trigger(ctx context.Context) {
+	sl.requestScan(ctx, "manual")
+}
+
+// requestScan runs a scan unless one is in flight. Requests that arrive
+// while scanning set a pending flag; the running caller then performs
+// exactly one more scan. Further requests while a follow-up is already
+// queued are dropped.
+func (sl *ScanLoop) requestScan(ctx context.Context, source string) {
+	sl.scanMu.Lock()
+	if sl.scanning {
+		if sl.scanPending {
+			sl.log.Info("scan already queued, dropping trigger", "source", source)
+		} else {
+			sl.scanPending = true
+			sl.log.Info("scan in progress, queued follow-up", "source", source)
+		}
+		sl.scanMu.Unlock()
+		return
+	}
+	sl.scanning = true
+	sl.scanMu.Unlock()
+
+	for {
+		sl.scan(ctx)
+
+		sl.scanMu.Lock()
+		if !sl.scanPending || ctx.Err() != nil {
+			sl.scanning, sl.scanPending = false, false
+			sl.scanMu.Unlock()
+			return
 		}
+		sl.scanPending = false
+		sl.scanMu.Unlock()
+		sl.log.Info("running queued follow-up scan")
 	}
 }
 
@@ -150,17 +322,24 @@ func (sl *ScanLoop) runScan(ctx context.Context) {
 		sl.log.Error("invalid scan profile", "profile", sl.cfg.Profile, "error", err)
 		return
 	}
+	profile = sl.widestProfile(profile)
 
-	reg := scanner.NewRegistryWithOptions(scanner.RegistryOptions{
-		ExcludeNamespaces: sl.cfg.ExcludeNamespaces,
-	})
-	scanners := reg.ForProfile(profile)
+	scanners := sl.scanners(profile)
 
 	if len(scanners) == 0 {
 		sl.log.Warn("no scanners for profile", "profile", sl.cfg.Profile)
 		return
 	}
 
+	// The scan timeout bounds collection and analysis only; uploads and
+	// reports use ctx so partial data still gets out
+	scanCtx := ctx
+	if sl.cfg.ScanTimeout > 0 {
+		var cancel context.CancelFunc
+		scanCtx, cancel = context.WithTimeout(ctx, sl.cfg.ScanTimeout)
+		defer cancel()
+	}
+
 	start := time.Now()
 	result := scanner.NewResult()
 	runner := scanner.LocalRunner{}
@@ -172,23 +351,36 @@ func (sl *ScanLoop) runScan(ctx context.Context) {
 			sl.log.Info("scan interrupted by shutdown")
 			return
 		}
+		if scanCtx.Err() != nil {
+			break
+		}
 
-		data, scanErr := s.Scan(ctx, runner)
+		data, scanErr := runScanner(scanCtx, s, runner)
 		if scanErr != nil {
 			sl.log.Warn("scanner failed", "scanner", s.Name(), "error", scanErr)
 			continue
 		}
 		result.Set(s.Name(), data)
 	}
+	if ctx.Err() == nil && scanCtx.Err() != nil {
+		result.Meta.Partial = true
+		sl.log.Warn("scan timed out, uploading partial result",
+			"timeout", sl.cfg.ScanTimeout, "phases", result.Meta.Phases)
+	}
 
-	// Apply topology inference
+	// Apply topology inference and health scoring
 	scanner.ApplyTopology(result)
+	scanner.ApplyHealth(result)
 
 	hostname := nodeHostname()
 	result.Meta.Version = sl.cfg.Version
 	result.Meta.DurationMS = int(time.Since(start).Milliseconds())
-	result.Meta.Profile = sl.cfg.Profile
+	result.Meta.Profile = profile.String()
 	result.Meta.SourceHost = hostname
+	result.Meta.GeneratedAt = start.UTC().Format(time.RFC3339)
+	if result.Meta.Sequence, err = sl.sequence.Next(); err != nil {
+		sl.log.Error("failed to persist scan sequence", "error", err)
+	}
 
 	// Override host name — HostScanner runs `hostname` inside the pod which
 	// returns the pod name (e.g., tb-manage-xxxx), not the real node name.
@@ -198,7 +390,9 @@ func (sl *ScanLoop) runScan(ctx context.Context) {
 		"duration_ms", result.Meta.DurationMS,
 		"phases", result.Meta.Phases,
 		"inferred_role", result.Meta.InferredRole,
+		"partial", result.Meta.Partial,
 	)
+	sl.localAPI.SetResult(result)
 
 	// Upload if configured (controller mode skips this — DaemonSet handles host uploads)
 	if sl.uploader != nil && !sl.cfg.SkipUpload {
@@ -212,16 +406,33 @@ func (sl *ScanLoop) runScan(ctx context.Context) {
 		return
 	}
 
-	// Analyze
-	allInsights := sl.insightsEngine.Analyze(ctx, clientset)
-	if len(allInsights) > 0 {
-		sl.log.Info("insights detected", "count", len(allInsights))
-	}
+	// Analyze. A pass cut short by the scan timeout yields an incomplete
+	// insight set; reporting it would resolve every insight the engine did
+	// not get to, so it is kept local and remediation and commands still run.
+	var allInsights []insights.ClusterInsight
+	if scanCtx.Err() != nil {
+		sl.log.Warn("scan timeout reached, skipping insights analysis")
+	} else {
+		var summary []insights.AnalyzerRun
+		allInsights, summary = sl.insightsEngine.AnalyzeWithSummary(scanCtx, clientset)
+		sl.localAPI.SetInsights(allInsights)
+		if len(allInsights) > 0 {
+			sl.log.Info("insights detected", "count", len(allInsights))
+		}
+		if !sl.cfg.ReportAnalyzerSummary {
+			summary = nil
+		}
 
-	// Report insights
-	for _, reporter := range sl.insightReporters {
-		if _, err := reporter.Report(ctx, allInsights); err != nil {
-			sl.log.Warn("insight report failed", "error", err)
+		// Report insights
+		if ctx.Err() == nil && scanCtx.Err() != nil {
+			sl.log.Warn("scan timeout reached during analysis, not reporting partial insights",
+				"count", len(allInsights))
+		} else {
+			for _, reporter := range sl.insightReporters {
+				if _, err := reporter.Report(ctx, allInsights, summary); err != nil {
+					sl.log.Warn("insight report failed", "error", err)
+				}
+			}
 		}
 	}
 
@@ -236,6 +447,12 @@ func (sl *ScanLoop) runScan(ctx context.Context) {
 	}
 
 	// Poll and execute commands
+	sl.runCommands(ctx, clientset)
+}
+
+// runCommands polls every command upstream and executes what it returns,
+// reporting each result back to the upstream it came from.
+func (sl *ScanLoop) runCommands(ctx context.Context, clientset kubernetes.Interface) {
 	for i, poller := range sl.cmdPollers {
 		cmds, err := poller.Poll(ctx)
 		if err != nil {
@@ -248,7 +465,7 @@ func (sl *ScanLoop) runScan(ctx context.Context) {
 
 		executor := sl.getCommandExecutor(clientset)
 		for _, cmd := range cmds {
-			result := executor.Execute(ctx, cmd)
+			result := sl.executeCommand(ctx, executor, cmd)
 			if i < len(sl.cmdCompleters) {
 				if err := sl.cmdCompleters[i].Complete(ctx, cmd.ID, result); err != nil {
 					sl.log.Warn("command completion report failed", "id", cmd.ID, "error", err)
@@ -258,14 +475,112 @@ func (sl *ScanLoop) runScan(ctx context.Context) {
 	}
 }
 
+// SetCommandEvents sends command progress and results to send, which is
+// usually the agent's gateway connection.
+func (sl *ScanLoop) SetCommandEvents(send func(msg any)) {
+	sl.cmdEvents = send
+}
+
+// executeCommand runs cmd, relaying its progress and final result when a
+// gateway is attached.
+func (sl *ScanLoop) executeCommand(ctx context.Context, executor *commands.Executor, cmd commands.Command) commands.CommandResult {
+	var result commands.CommandResult
+	for ev := range executor.ExecuteStream(ctx, cmd) {
+		if ev.Result != nil {
+			result = *ev.Result
+			continue
+		}
+		if sl.cmdEvents != nil {
+			sl.cmdEvents(protocol.CommandProgressMessage{
+				Type:      protocol.TypeCommandProgress,
+				CommandID: cmd.ID,
+				Message:   ev.Progress.Message,
+				Done:      ev.Progress.Done,
+				Total:     ev.Progress.Total,
+			})
+		}
+	}
+	if sl.cmdEvents != nil {
+		sl.cmdEvents(protocol.CommandResultMessage{
+			Type:      protocol.TypeCommandResult,
+			CommandID: cmd.ID,
+			Success:   result.Success,
+			Code:      string(result.Code),
+			Message:   result.Message,
+		})
+	}
+	return result
+}
+
+// runScanner runs s, returning early with ctx's error if ctx ends first.
+// A scanner blocked in an uninterruptible call (e.g. a hung NFS mount) is
+// abandoned rather than holding up the cycle; its result is discarded.
+func runScanner(ctx context.Context, s scanner.Scanner, runner scanner.CommandRunner) (json.RawMessage, error) {
+	type scanResult struct {
+		data json.RawMessage
+		err  error
+	}
+	done := make(chan scanResult, 1)
+	go func() {
+		data, err := s.Scan(ctx, runner)
+		done <- scanResult{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// widestProfile widens profile to cover every upstream's profile so each
+// upstream can be sent the phases it asked for.
+func (sl *ScanLoop) widestProfile(profile scanner.Profile) scanner.Profile {
+	for _, u := range sl.cfg.Upstreams {
+		if u.Profile == "" {
+			continue
+		}
+		if p, err := scanner.ParseProfile(u.Profile); err == nil && p > profile {
+			profile = p
+		}
+	}
+	return profile
+}
+
 // uploadResult sends scan results to edge-ingest.
 func (sl *ScanLoop) uploadResult(ctx context.Context, result *scanner.Result) {
-	req := upload.BuildRequest(result)
-
-	resp, err := sl.uploader.Upload(ctx, req)
+	// A broken enrichment source shouldn't stop inventory from flowing
+	extra, err := sl.cfg.Enrich.Fields(ctx)
 	if err != nil {
-		sl.log.Error("upload failed", "error", err)
-		return
+		sl.log.Warn("payload enrichment failed, uploading without it", "error", err)
+		extra = nil
+	}
+
+	var resp *upload.EdgeIngestResponse
+	if sl.multi != nil {
+		// Per-upstream profiles: the client trims, enriches and redacts each copy
+		resp, err = sl.multi.UploadResult(ctx, result, extra, sl.cfg.Redact, sl.cfg.MaxPayloadBytes)
+		if err != nil {
+			sl.log.Error("upload failed", "error", err)
+			return
+		}
+	} else {
+		req, err := upload.PrepareRequest(result, extra, sl.cfg.Redact, sl.cfg.MaxPayloadBytes)
+		if err != nil {
+			// Never fall back to the unredacted payload
+			sl.log.Error("payload preparation failed, skipping upload", "error", err)
+			return
+		}
+		if req.Meta.Truncated {
+			sl.log.Warn("payload truncated to fit size limit", "max_bytes", sl.cfg.MaxPayloadBytes, "sections", req.Meta.TruncatedSections)
+		}
+
+		resp, err = sl.uploader.Upload(ctx, req)
+		if err != nil {
+			sl.log.Error("upload failed", "error", err)
+			return
+		}
 	}
 
 	sl.log.Info("upload complete",
@@ -298,6 +613,25 @@ func (sl *ScanLoop) getK8sClient() kubernetes.Interface {
 	// Now that we have a clientset, initialize the remediator if configured
 	sl.initRemediator(clientset)
 
+	if dynClient, err := dynamic.NewForConfig(config); err != nil {
+		sl.log.Warn("failed to create k8s dynamic client, deprecated API and helm chart drift checks disabled", "error", err)
+	} else {
+		if !sl.cfg.SkipDeprecatedAPI {
+			// By default checked against the next minor release, ahead of an upgrade
+			sl.insightsEngine.AddAnalyzer(insights.NewDeprecatedAPIAnalyzer(dynClient, sl.cfg.DeprecatedAPITarget))
+		}
+		if sl.cfg.HelmChartDrift {
+			sl.insightsEngine.AddAnalyzer(insights.NewHelmChartDriftAnalyzer(dynClient))
+		}
+	}
+	if sl.cfg.KubeletCertProbe || len(sl.cfg.PKIDirs) > 0 {
+		sl.insightsEngine.AddAnalyzer(insights.NewCertExpiryAnalyzer(insights.CertExpiryOptions{
+			ProbeKubelets: sl.cfg.KubeletCertProbe,
+			PKIDirs:       sl.cfg.PKIDirs,
+			NodeName:      nodeHostname(),
+		}))
+	}
+
 	return clientset
 }
 
@@ -348,6 +682,9 @@ func (sl *ScanLoop) getCommandExecutor(clientset kubernetes.Interface) *commands
 		return sl.cmdExecutor
 	}
 	sl.cmdExecutor = commands.NewExecutor(clientset)
+	if sl.cfg.TOTP != nil {
+		sl.cmdExecutor.SetTOTPPolicy(sl.cfg.TOTP)
+	}
 	return sl.cmdExecutor
 }
 
@@ -2,19 +2,30 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+	"github.com/tinkerbelle-io/tb-manage/internal/upload"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestScanLoopRunsInitialScan(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 
 	sl := NewScanLoop(ScanLoopConfig{
-		Profile:  "minimal",
-		Interval: 1 * time.Hour, // Long interval — we only care about the initial scan
-		Version:  "test",
+		Profile:      "minimal",
+		Interval:     1 * time.Hour, // Long interval — we only care about the initial scan
+		Version:      "test",
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
 	}, logger)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -36,13 +47,37 @@ func TestScanLoopRunsInitialScan(t *testing.T) {
 	}
 }
 
+func TestScanLoopOneShot(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	sl := NewScanLoop(ScanLoopConfig{
+		Profile:      "minimal",
+		Interval:     0,
+		Version:      "test",
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
+	}, logger)
+
+	done := make(chan struct{})
+	go func() {
+		sl.Run(context.Background()) // no deadline: must return on its own
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("one-shot scan loop did not return after the initial scan")
+	}
+}
+
 func TestScanLoopGracefulShutdown(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 
 	sl := NewScanLoop(ScanLoopConfig{
-		Profile:  "minimal",
-		Interval: 100 * time.Millisecond,
-		Version:  "test",
+		Profile:      "minimal",
+		Interval:     100 * time.Millisecond,
+		Version:      "test",
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
 	}, logger)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -72,9 +107,10 @@ func TestScanLoopNoUploadWithoutClient(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 
 	sl := NewScanLoop(ScanLoopConfig{
-		Profile:  "minimal",
-		Interval: 1 * time.Hour,
-		Version:  "test",
+		Profile:      "minimal",
+		Interval:     1 * time.Hour,
+		Version:      "test",
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
 		// No UploadURL or Token — client will be nil
 	}, logger)
 
@@ -99,9 +135,10 @@ func TestScanLoopInvalidProfile(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 
 	sl := NewScanLoop(ScanLoopConfig{
-		Profile:  "nonexistent",
-		Interval: 1 * time.Hour,
-		Version:  "test",
+		Profile:      "nonexistent",
+		Interval:     1 * time.Hour,
+		Version:      "test",
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
 	}, logger)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -116,3 +153,244 @@ func TestScanLoopInvalidProfile(t *testing.T) {
 	<-done
 	// If we got here without panic, the test passes
 }
+
+func TestScanLoopTriggerCoalesces(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	sl := NewScanLoop(ScanLoopConfig{Profile: "minimal", Version: "test"}, logger)
+
+	var (
+		mu        sync.Mutex
+		active    int
+		maxActive int
+		total     int
+	)
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	sl.scan = func(ctx context.Context) {
+		mu.Lock()
+		active++
+		total++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+
+		started <- struct{}{}
+		<-release
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+	}
+
+	ctx := context.Background()
+	done := make(chan struct{})
+	go func() {
+		sl.requestScan(ctx, "timer")
+		close(done)
+	}()
+	<-started
+
+	// Overlapping triggers while the first scan is blocked: all return
+	// immediately and collapse into one follow-up
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sl.Trigger(ctx)
+		}()
+	}
+	wg.Wait()
+
+	close(release)
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxActive != 1 {
+		t.Errorf("max concurrent scans = %d, want 1", maxActive)
+	}
+	if total != 2 {
+		t.Errorf("total scans = %d, want 2 (initial + one coalesced follow-up)", total)
+	}
+	if sl.scanning || sl.scanPending {
+		t.Error("guard state not reset after scans finished")
+	}
+}
+
+// stubScanner returns fixed data, optionally blocking until release is
+// closed (ignoring ctx, like a scanner stuck on a hung mount).
+type stubScanner struct {
+	name    string
+	data    string
+	release chan struct{}
+}
+
+func (s stubScanner) Name() string        { return s.name }
+func (s stubScanner) Platforms() []string { return nil }
+func (s stubScanner) Scan(ctx context.Context, _ scanner.CommandRunner) (json.RawMessage, error) {
+	if s.release != nil {
+		<-s.release
+	}
+	return json.RawMessage(s.data), nil
+}
+
+type captureUploader struct {
+	mu   sync.Mutex
+	reqs []*upload.EdgeIngestRequest
+}
+
+func (u *captureUploader) Upload(_ context.Context, req *upload.EdgeIngestRequest) (*upload.EdgeIngestResponse, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.reqs = append(u.reqs, req)
+	return &upload.EdgeIngestResponse{SessionID: "s"}, nil
+}
+
+func TestScanLoopTimeoutUploadsPartial(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	sl := NewScanLoop(ScanLoopConfig{
+		Profile:      "minimal",
+		Version:      "test",
+		ScanTimeout:  100 * time.Millisecond,
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
+	}, logger)
+
+	release := make(chan struct{})
+	defer close(release)
+	sl.scanners = func(scanner.Profile) []scanner.Scanner {
+		return []scanner.Scanner{
+			stubScanner{name: "host", data: `{"name":"node-1","system":{"os":"linux"}}`},
+			stubScanner{name: "storage", data: `{"filesystems":[]}`, release: release},
+			stubScanner{name: "network", data: `{"interfaces":[]}`},
+		}
+	}
+	uploader := &captureUploader{}
+	sl.uploader = uploader
+
+	start := time.Now()
+	sl.runScan(context.Background())
+	elapsed := time.Since(start)
+
+	if elapsed > 2*time.Second {
+		t.Fatalf("scan cycle took %v, want abort near the 100ms deadline", elapsed)
+	}
+
+	uploader.mu.Lock()
+	defer uploader.mu.Unlock()
+	if len(uploader.reqs) != 1 {
+		t.Fatalf("expected 1 upload, got %d", len(uploader.reqs))
+	}
+	req := uploader.reqs[0]
+	if !req.Meta.Partial {
+		t.Error("timed-out scan should be marked partial")
+	}
+	if len(req.Meta.Phases) != 1 || req.Meta.Phases[0] != "host" {
+		t.Errorf("phases = %v, want only [host] gathered before the timeout", req.Meta.Phases)
+	}
+	if req.Host == nil {
+		t.Error("partial upload should include the host data gathered before the timeout")
+	}
+}
+
+func TestScanLoopTimeoutStillPollsCommands(t *testing.T) {
+	var mu sync.Mutex
+	paths := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	sl := NewScanLoop(ScanLoopConfig{
+		Profile:     "minimal",
+		Version:     "test",
+		ScanTimeout: 100 * time.Millisecond,
+		Upstreams: []upload.Upstream{{
+			URL: srv.URL, Token: "tok", Permissions: []string{"scan", "execute_commands"},
+		}},
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
+	}, logger)
+
+	release := make(chan struct{})
+	defer close(release)
+	sl.scanners = func(scanner.Profile) []scanner.Scanner {
+		return []scanner.Scanner{stubScanner{name: "host", data: `{"name":"node-1"}`, release: release}}
+	}
+	sl.uploader = &captureUploader{}
+	sl.k8sClient = fake.NewSimpleClientset()
+
+	sl.runScan(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if paths["/functions/v1/cluster-commands/poll"] != 1 {
+		t.Errorf("timed-out scan should still poll commands, requests: %v", paths)
+	}
+	for path := range paths {
+		if strings.Contains(path, "insight") {
+			t.Errorf("timed-out scan should not report insights, got request to %s", path)
+		}
+	}
+}
+
+func TestScanLoopNoTimeoutNotPartial(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	sl := NewScanLoop(ScanLoopConfig{
+		Profile:      "minimal",
+		Version:      "test",
+		ScanTimeout:  time.Minute,
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
+	}, logger)
+	sl.scanners = func(scanner.Profile) []scanner.Scanner {
+		return []scanner.Scanner{stubScanner{name: "host", data: `{"name":"node-1","system":{"os":"linux"}}`}}
+	}
+	uploader := &captureUploader{}
+	sl.uploader = uploader
+
+	sl.runScan(context.Background())
+
+	if len(uploader.reqs) != 1 || uploader.reqs[0].Meta.Partial {
+		t.Errorf("complete scan should upload once without partial flag: %+v", uploader.reqs)
+	}
+}
+
+func TestScanLoopNumbersScans(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	cfg := ScanLoopConfig{
+		Profile:      "minimal",
+		Version:      "test",
+		SequencePath: filepath.Join(t.TempDir(), "scan-sequence"),
+	}
+	uploader := &captureUploader{}
+	newLoop := func() *ScanLoop {
+		sl := NewScanLoop(cfg, logger)
+		sl.scanners = func(scanner.Profile) []scanner.Scanner {
+			return []scanner.Scanner{stubScanner{name: "host", data: `{"name":"node-1","system":{"os":"linux"}}`}}
+		}
+		sl.uploader = uploader
+		return sl
+	}
+
+	sl := newLoop()
+	sl.runScan(context.Background())
+	sl.runScan(context.Background())
+	// Simulated restart
+	newLoop().runScan(context.Background())
+
+	if len(uploader.reqs) != 3 {
+		t.Fatalf("expected 3 uploads, got %d", len(uploader.reqs))
+	}
+	for i, req := range uploader.reqs {
+		if want := uint64(i + 1); req.Meta.Sequence != want {
+			t.Errorf("upload %d: sequence = %d, want %d", i, req.Meta.Sequence, want)
+		}
+		if _, err := time.Parse(time.RFC3339, req.Meta.GeneratedAt); err != nil {
+			t.Errorf("upload %d: generated_at %q: %v", i, req.Meta.GeneratedAt, err)
+		}
+	}
+}
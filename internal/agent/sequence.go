@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSequencePath returns the platform-appropriate default location of
+// the scan sequence state file.
+func DefaultSequencePath() string {
+	if runtime.GOOS == "darwin" {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".tb-manage", "scan-sequence")
+	}
+	return "/var/lib/tb-manage/scan-sequence"
+}
+
+// ScanSequence numbers scans with a counter that only goes up, even
+// across restarts, so the SaaS can order uploads that arrive late (retries,
+// spooled payloads). The last number handed out is kept in a state file.
+type ScanSequence struct {
+	mu   sync.Mutex
+	path string
+	last uint64
+}
+
+// NewScanSequence loads the counter from path (DefaultSequencePath when
+// empty). A missing file starts the count at zero. When the file is
+// unreadable or corrupt, or the counter cannot be written back, the count is
+// seeded from the wall clock instead, which stays ahead of any counter a
+// previous run could have reached, and the error is returned so the caller
+// can report that numbering is no longer durable.
+func NewScanSequence(path string) (*ScanSequence, error) {
+	if path == "" {
+		path = DefaultSequencePath()
+	}
+	s := &ScanSequence{path: path}
+	if err := s.load(); err != nil {
+		s.last = uint64(time.Now().Unix())
+		return s, err
+	}
+	// Probe persistence up front so a read-only state directory shows up
+	// at startup rather than as a warning on every scan
+	if err := s.save(); err != nil {
+		s.last = uint64(time.Now().Unix())
+		return s, err
+	}
+	return s, nil
+}
+
+// load reads the last number handed out from the state file.
+func (s *ScanSequence) load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read scan sequence: %w", err)
+	}
+	last, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse scan sequence %s: %w", s.path, err)
+	}
+	s.last = last
+	return nil
+}
+
+// Next returns the next sequence number and persists it. The number is
+// valid even when persisting fails; the error only means a restart may
+// reuse it.
+func (s *ScanSequence) Next() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last++
+	return s.last, s.save()
+}
+
+// save writes the counter via a temporary file so a crash mid-write never
+// leaves a truncated state file behind.
+func (s *ScanSequence) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("save scan sequence: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(s.last, 10)+"\n"), 0600); err != nil {
+		return fmt.Errorf("save scan sequence: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("save scan sequence: %w", err)
+	}
+	return nil
+}
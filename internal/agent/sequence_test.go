@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScanSequencePersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "scan-sequence")
+
+	seq, err := NewScanSequence(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for want := uint64(1); want <= 3; want++ {
+		n, err := seq.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Errorf("Next() = %d, want %d", n, want)
+		}
+	}
+
+	// Restart: a new process picks up where the last one stopped
+	seq, err = NewScanSequence(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := seq.Next(); n != 4 {
+		t.Errorf("Next() after restart = %d, want 4", n)
+	}
+}
+
+func TestScanSequenceCorruptStateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan-sequence")
+	if err := os.WriteFile(path, []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	seq, err := NewScanSequence(path)
+	if err == nil {
+		t.Fatal("expected an error for a corrupt state file")
+	}
+	// Still usable; numbering continues from the clock, ahead of any
+	// counter the lost state could have held
+	if n, _ := seq.Next(); n < uint64(time.Now().Unix()) {
+		t.Errorf("Next() = %d, want a clock-seeded number", n)
+	}
+}
+
+func TestScanSequenceUnwritableStateDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0500); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(dir, 0700)
+	if f, err := os.Create(filepath.Join(dir, "probe")); err == nil {
+		f.Close()
+		t.Skip("directory permissions not enforced (running as root)")
+	}
+
+	seq, err := NewScanSequence(filepath.Join(dir, "scan-sequence"))
+	if err == nil {
+		t.Fatal("expected an error when the state file cannot be written")
+	}
+	if n, _ := seq.Next(); n < uint64(time.Now().Unix()) {
+		t.Errorf("Next() = %d, want a clock-seeded number", n)
+	}
+}
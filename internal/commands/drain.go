@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/podutil"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// drainNode cordons a node and evicts its pods one by one, reporting
+// progress after each. Evictions go through the Eviction API so
+// PodDisruptionBudgets are honoured; a pod whose eviction is refused is
+// left running and the drain carries on with the rest. Like kubectl drain,
+// it refuses to start when the node runs pods without a controller, which
+// nothing would recreate, unless the force parameter is true.
+func (e *Executor) drainNode(ctx context.Context, cmd Command, progress func(CommandProgress)) CommandResult {
+	podList, err := e.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + cmd.TargetName,
+	})
+	if err != nil {
+		return apiFailure(err)
+	}
+	force, _ := cmd.Parameters["force"].(bool)
+	var pods []corev1.Pod
+	var unowned []string
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != cmd.TargetName || !evictable(pod) {
+			continue
+		}
+		if metav1.GetControllerOf(&pod) == nil {
+			unowned = append(unowned, pod.Namespace+"/"+pod.Name)
+		}
+		pods = append(pods, pod)
+	}
+	if len(unowned) > 0 && !force {
+		return CommandResult{
+			Success: false,
+			Code:    CodeInvalidParameter,
+			Message: fmt.Sprintf("node %s runs %d pod(s) without a controller that would not be recreated (%s); set force to evict them", cmd.TargetName, len(unowned), strings.Join(unowned, ", ")),
+			Details: map[string]any{"unowned": unowned},
+		}
+	}
+
+	if result := e.cordonNode(ctx, cmd, true); !result.Success {
+		return result
+	}
+
+	total := len(pods)
+	progress(CommandProgress{Message: fmt.Sprintf("node %s cordoned, evicting %d pods", cmd.TargetName, total), Total: total})
+
+	evicted := 0
+	var failed []string
+	var firstErr error
+	for _, pod := range pods {
+		err := e.clientset.CoreV1().Pods(pod.Namespace).EvictV1(ctx, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			e.log.Warn("eviction failed", "node", cmd.TargetName, "pod", pod.Namespace+"/"+pod.Name, "error", err)
+			failed = append(failed, pod.Namespace+"/"+pod.Name)
+			if firstErr == nil {
+				firstErr = err
+			}
+			progress(CommandProgress{Message: fmt.Sprintf("could not evict %s/%s: %v", pod.Namespace, pod.Name, err), Done: evicted, Total: total})
+			continue
+		}
+		evicted++
+		progress(CommandProgress{Message: fmt.Sprintf("evicted %d/%d pods", evicted, total), Done: evicted, Total: total})
+	}
+
+	details := map[string]any{"evicted": evicted, "total": total}
+	if len(failed) > 0 {
+		details["failed"] = failed
+		return CommandResult{
+			Success: false,
+			Code:    errorCode(firstErr),
+			Message: fmt.Sprintf("Node %s partially drained: evicted %d/%d pods, %d failed", cmd.TargetName, evicted, total, len(failed)),
+			Details: details,
+		}
+	}
+	return CommandResult{
+		Success: true,
+		Code:    CodeOK,
+		Message: fmt.Sprintf("Node %s drained (%d pods evicted)", cmd.TargetName, evicted),
+		Details: details,
+	}
+}
+
+// evictable reports whether a drain should evict pod: a drainable pod (see
+// podutil.IsDrainablePod) that has not finished; finished pods hold no
+// workload.
+func evictable(pod corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	return podutil.IsDrainablePod(pod)
+}
@@ -3,10 +3,16 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -15,6 +21,7 @@ import (
 // Executor runs commands against a Kubernetes cluster.
 type Executor struct {
 	clientset kubernetes.Interface
+	totp      *TOTPPolicy
 	log       *slog.Logger
 }
 
@@ -26,12 +33,48 @@ func NewExecutor(clientset kubernetes.Interface) *Executor {
 	}
 }
 
+// SetTOTPPolicy requires a TOTP second factor for the policy's actions.
+func (e *Executor) SetTOTPPolicy(p *TOTPPolicy) {
+	e.totp = p
+}
+
 // Execute runs a single command and returns the result.
 func (e *Executor) Execute(ctx context.Context, cmd Command) CommandResult {
+	return e.execute(ctx, cmd, func(CommandProgress) {})
+}
+
+// ExecuteStream runs a single command in the background, sending progress
+// updates from long-running actions (drain_node) as they happen. The
+// final event carries the result, after which the channel is closed.
+// Callers must read until then.
+func (e *Executor) ExecuteStream(ctx context.Context, cmd Command) <-chan CommandEvent {
+	events := make(chan CommandEvent)
+	go func() {
+		defer close(events)
+		result := e.execute(ctx, cmd, func(p CommandProgress) {
+			events <- CommandEvent{Progress: &p}
+		})
+		events <- CommandEvent{Result: &result}
+	}()
+	return events
+}
+
+func (e *Executor) execute(ctx context.Context, cmd Command, progress func(CommandProgress)) CommandResult {
 	e.log.Info("executing command",
 		"id", cmd.ID, "action", cmd.Action,
 		"kind", cmd.TargetKind, "ns", cmd.TargetNamespace, "name", cmd.TargetName)
 
+	if e.totp != nil {
+		if err := e.totp.Check(cmd); err != nil {
+			e.log.Warn("command rejected by second-factor policy", "id", cmd.ID, "action", cmd.Action, "error", err)
+			return CommandResult{
+				Success: false,
+				Code:    CodeForbidden,
+				Message: fmt.Sprintf("second factor rejected: %v", err),
+			}
+		}
+	}
+
 	var result CommandResult
 	switch cmd.Action {
 	case "delete_pod":
@@ -39,38 +82,75 @@ func (e *Executor) Execute(ctx context.Context, cmd Command) CommandResult {
 	case "force_delete_pod":
 		result = e.forceDeletePod(ctx, cmd)
 	case "restart_deployment":
-		result = e.restartDeployment(ctx, cmd)
+		result = e.restartWorkload(ctx, cmd, "Deployment")
+	case "restart_statefulset":
+		result = e.restartWorkload(ctx, cmd, "StatefulSet")
+	case "restart_daemonset":
+		result = e.restartWorkload(ctx, cmd, "DaemonSet")
+	case "rollback_deployment":
+		result = e.rollbackDeployment(ctx, cmd)
 	case "scale":
 		result = e.scale(ctx, cmd)
 	case "delete_deployment":
 		result = e.deleteDeployment(ctx, cmd)
 	case "delete_pvc":
 		result = e.deletePVC(ctx, cmd)
+	case "delete_job":
+		result = e.deleteJob(ctx, cmd)
+	case "delete_completed_jobs":
+		result = e.deleteCompletedJobs(ctx, cmd)
 	case "cordon_node":
 		result = e.cordonNode(ctx, cmd, true)
 	case "uncordon_node":
 		result = e.cordonNode(ctx, cmd, false)
+	case "drain_node":
+		result = e.drainNode(ctx, cmd, progress)
 	case "tune_resource_limits":
 		result = e.tuneResourceLimits(ctx, cmd)
 	default:
 		result = CommandResult{
 			Success: false,
+			Code:    CodeUnknownAction,
 			Message: fmt.Sprintf("unknown action: %s", cmd.Action),
 		}
 	}
 
 	e.log.Info("command result",
-		"id", cmd.ID, "success", result.Success, "message", result.Message)
+		"id", cmd.ID, "success", result.Success, "code", result.Code, "message", result.Message)
 	return result
 }
 
+// apiFailure converts a Kubernetes API error into a failed result, mapping
+// the API status reason to a ResultCode.
+func apiFailure(err error) CommandResult {
+	return CommandResult{Success: false, Code: errorCode(err), Message: err.Error()}
+}
+
+func errorCode(err error) ResultCode {
+	switch {
+	case apierrors.IsNotFound(err):
+		return CodeNotFound
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return CodeForbidden
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return CodeConflict
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return CodeInvalidParameter
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	default:
+		return CodeInternal
+	}
+}
+
 func (e *Executor) deletePod(ctx context.Context, cmd Command) CommandResult {
 	err := e.clientset.CoreV1().Pods(cmd.TargetNamespace).Delete(ctx, cmd.TargetName, metav1.DeleteOptions{})
 	if err != nil {
-		return CommandResult{Success: false, Message: err.Error()}
+		return apiFailure(err)
 	}
 	return CommandResult{
 		Success: true,
+		Code:    CodeOK,
 		Message: fmt.Sprintf("Pod %s/%s deleted", cmd.TargetNamespace, cmd.TargetName),
 	}
 }
@@ -81,48 +161,117 @@ func (e *Executor) forceDeletePod(ctx context.Context, cmd Command) CommandResul
 		GracePeriodSeconds: &grace,
 	})
 	if err != nil {
-		return CommandResult{Success: false, Message: err.Error()}
+		return apiFailure(err)
 	}
 	return CommandResult{
 		Success: true,
+		Code:    CodeOK,
 		Message: fmt.Sprintf("Pod %s/%s force-deleted (gracePeriod=0)", cmd.TargetNamespace, cmd.TargetName),
 	}
 }
 
-func (e *Executor) restartDeployment(ctx context.Context, cmd Command) CommandResult {
+// restartWorkload triggers a rolling restart of a Deployment, StatefulSet
+// or DaemonSet the way kubectl rollout restart does: by stamping the pod
+// template with a restartedAt annotation.
+func (e *Executor) restartWorkload(ctx context.Context, cmd Command, kind string) CommandResult {
 	restartedAt := time.Now().UTC().Format(time.RFC3339)
-	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`, restartedAt)
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"%s"}}}}}`, restartedAt))
 
-	_, err := e.clientset.AppsV1().Deployments(cmd.TargetNamespace).Patch(
-		ctx, cmd.TargetName, apitypes.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	var err error
+	switch kind {
+	case "Deployment":
+		_, err = e.clientset.AppsV1().Deployments(cmd.TargetNamespace).Patch(
+			ctx, cmd.TargetName, apitypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = e.clientset.AppsV1().StatefulSets(cmd.TargetNamespace).Patch(
+			ctx, cmd.TargetName, apitypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = e.clientset.AppsV1().DaemonSets(cmd.TargetNamespace).Patch(
+			ctx, cmd.TargetName, apitypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
 	if err != nil {
-		return CommandResult{Success: false, Message: err.Error()}
+		return apiFailure(err)
 	}
 	return CommandResult{
 		Success: true,
-		Message: fmt.Sprintf("Deployment %s/%s restarted (rollout triggered at %s)", cmd.TargetNamespace, cmd.TargetName, restartedAt),
+		Code:    CodeOK,
+		Message: fmt.Sprintf("%s %s/%s restarted (rollout triggered at %s)", kind, cmd.TargetNamespace, cmd.TargetName, restartedAt),
+	}
+}
+
+// revisionAnnotation numbers a Deployment's rollouts; the Deployment
+// controller sets it on the Deployment and on each of its ReplicaSets.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// rollbackDeployment reverts a Deployment's pod template to the one of its
+// previous revision, like kubectl rollout undo. The Deployment controller
+// then rolls the reverted template out as a new revision.
+func (e *Executor) rollbackDeployment(ctx context.Context, cmd Command) CommandResult {
+	deps := e.clientset.AppsV1().Deployments(cmd.TargetNamespace)
+	dep, err := deps.Get(ctx, cmd.TargetName, metav1.GetOptions{})
+	if err != nil {
+		return apiFailure(err)
+	}
+	if dep.Spec.Paused {
+		return CommandResult{Success: false, Code: CodeConflict, Message: fmt.Sprintf("Deployment %s/%s is paused; resume it before rolling back", cmd.TargetNamespace, cmd.TargetName)}
+	}
+	current, _ := strconv.ParseInt(dep.Annotations[revisionAnnotation], 10, 64)
+
+	selector, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
+	if err != nil {
+		return CommandResult{Success: false, Code: CodeInvalidParameter, Message: fmt.Sprintf("invalid selector: %v", err)}
+	}
+	rsList, err := e.clientset.AppsV1().ReplicaSets(cmd.TargetNamespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return apiFailure(err)
+	}
+	// The newest revision before the current one
+	var previous *corev1.PodTemplateSpec
+	var revision int64
+	for i, rs := range rsList.Items {
+		if !metav1.IsControlledBy(&rs, dep) {
+			continue
+		}
+		r, err := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		if err != nil || r >= current || r <= revision {
+			continue
+		}
+		previous, revision = &rsList.Items[i].Spec.Template, r
+	}
+	if previous == nil {
+		return CommandResult{Success: false, Code: CodeNotFound, Message: fmt.Sprintf("Deployment %s/%s has no revision before %d to roll back to", cmd.TargetNamespace, cmd.TargetName, current)}
+	}
+
+	// The ReplicaSet's template carries its pod-template-hash label, which
+	// the Deployment controller adds itself
+	template := previous.DeepCopy()
+	delete(template.Labels, "pod-template-hash")
+	patch, _ := json.Marshal([]map[string]any{{"op": "replace", "path": "/spec/template", "value": template}})
+	if _, err := deps.Patch(ctx, cmd.TargetName, apitypes.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return apiFailure(err)
+	}
+	return CommandResult{
+		Success: true,
+		Code:    CodeOK,
+		Message: fmt.Sprintf("Deployment %s/%s rolled back from revision %d to revision %d", cmd.TargetNamespace, cmd.TargetName, current, revision),
+		Details: map[string]any{"from_revision": current, "revision": revision},
 	}
 }
 
 func (e *Executor) scale(ctx context.Context, cmd Command) CommandResult {
 	replicasRaw, ok := cmd.Parameters["replicas"]
 	if !ok {
-		return CommandResult{Success: false, Message: "missing 'replicas' parameter"}
-	}
-	// JSON numbers decode as float64
-	replicasFloat, ok := replicasRaw.(float64)
-	if !ok {
-		return CommandResult{Success: false, Message: fmt.Sprintf("invalid replicas value: %v", replicasRaw)}
+		return CommandResult{Success: false, Code: CodeInvalidParameter, Message: "missing 'replicas' parameter"}
 	}
-	newReplicas := int32(replicasFloat)
-	if newReplicas < 0 {
-		return CommandResult{Success: false, Message: fmt.Sprintf("invalid replicas value: %d", newReplicas)}
+	newReplicas, err := parseReplicas(replicasRaw)
+	if err != nil {
+		return CommandResult{Success: false, Code: CodeInvalidParameter, Message: err.Error()}
 	}
 
 	// Get current scale
 	scale, err := e.clientset.AppsV1().Deployments(cmd.TargetNamespace).GetScale(ctx, cmd.TargetName, metav1.GetOptions{})
 	if err != nil {
-		return CommandResult{Success: false, Message: err.Error()}
+		return apiFailure(err)
 	}
 	oldReplicas := scale.Spec.Replicas
 
@@ -130,23 +279,65 @@ func (e *Executor) scale(ctx context.Context, cmd Command) CommandResult {
 	scale.Spec.Replicas = newReplicas
 	_, err = e.clientset.AppsV1().Deployments(cmd.TargetNamespace).UpdateScale(ctx, cmd.TargetName, scale, metav1.UpdateOptions{})
 	if err != nil {
-		return CommandResult{Success: false, Message: err.Error()}
+		return apiFailure(err)
 	}
 
 	return CommandResult{
 		Success: true,
+		Code:    CodeOK,
 		Message: fmt.Sprintf("Deployment %s/%s scaled from %d to %d", cmd.TargetNamespace, cmd.TargetName, oldReplicas, newReplicas),
 		Details: map[string]any{"old_replicas": oldReplicas, "new_replicas": newReplicas},
 	}
 }
 
+// parseReplicas converts a replicas parameter to int32. JSON numbers decode
+// as float64 (or json.Number with UseNumber); some clients send strings.
+func parseReplicas(v any) (int32, error) {
+	var f float64
+	switch r := v.(type) {
+	case float64:
+		f = r
+	case int:
+		f = float64(r)
+	case int64:
+		f = float64(r)
+	case json.Number:
+		n, err := r.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid replicas value %q: not a number", r.String())
+		}
+		f = n
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(r), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid replicas value %q: not a number", r)
+		}
+		f = n
+	default:
+		return 0, fmt.Errorf("invalid replicas value %v: expected a number, got %T", v, v)
+	}
+
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		return 0, fmt.Errorf("invalid replicas value %v: not a finite number", v)
+	case f != math.Trunc(f):
+		return 0, fmt.Errorf("invalid replicas value %v: must be a whole number", v)
+	case f < 0:
+		return 0, fmt.Errorf("invalid replicas value %v: must not be negative", v)
+	case f > math.MaxInt32:
+		return 0, fmt.Errorf("invalid replicas value %v: exceeds %d", v, math.MaxInt32)
+	}
+	return int32(f), nil
+}
+
 func (e *Executor) deleteDeployment(ctx context.Context, cmd Command) CommandResult {
 	err := e.clientset.AppsV1().Deployments(cmd.TargetNamespace).Delete(ctx, cmd.TargetName, metav1.DeleteOptions{})
 	if err != nil {
-		return CommandResult{Success: false, Message: err.Error()}
+		return apiFailure(err)
 	}
 	return CommandResult{
 		Success: true,
+		Code:    CodeOK,
 		Message: fmt.Sprintf("Deployment %s/%s deleted", cmd.TargetNamespace, cmd.TargetName),
 	}
 }
@@ -154,20 +345,88 @@ func (e *Executor) deleteDeployment(ctx context.Context, cmd Command) CommandRes
 func (e *Executor) deletePVC(ctx context.Context, cmd Command) CommandResult {
 	err := e.clientset.CoreV1().PersistentVolumeClaims(cmd.TargetNamespace).Delete(ctx, cmd.TargetName, metav1.DeleteOptions{})
 	if err != nil {
-		return CommandResult{Success: false, Message: err.Error()}
+		return apiFailure(err)
 	}
 	return CommandResult{
 		Success: true,
+		Code:    CodeOK,
 		Message: fmt.Sprintf("PVC %s/%s deleted", cmd.TargetNamespace, cmd.TargetName),
 	}
 }
 
+// deleteJob deletes a Job together with its pods. The API's default for
+// Jobs would orphan them.
+func (e *Executor) deleteJob(ctx context.Context, cmd Command) CommandResult {
+	if cmd.TargetNamespace == "" {
+		return CommandResult{Success: false, Code: CodeInvalidParameter, Message: "missing target namespace"}
+	}
+	background := metav1.DeletePropagationBackground
+	err := e.clientset.BatchV1().Jobs(cmd.TargetNamespace).Delete(ctx, cmd.TargetName, metav1.DeleteOptions{PropagationPolicy: &background})
+	if err != nil {
+		return apiFailure(err)
+	}
+	return CommandResult{
+		Success: true,
+		Code:    CodeOK,
+		Message: fmt.Sprintf("Job %s/%s deleted", cmd.TargetNamespace, cmd.TargetName),
+		Details: map[string]any{"deleted": 1},
+	}
+}
+
+// deleteCompletedJobs deletes the Jobs in the target namespace that
+// succeeded longer ago than the required older_than parameter (a duration
+// such as "24h"). Running and failed Jobs are kept.
+func (e *Executor) deleteCompletedJobs(ctx context.Context, cmd Command) CommandResult {
+	if cmd.TargetNamespace == "" {
+		return CommandResult{Success: false, Code: CodeInvalidParameter, Message: "missing target namespace"}
+	}
+	v, _ := cmd.Parameters["older_than"].(string)
+	if v == "" {
+		return CommandResult{Success: false, Code: CodeInvalidParameter, Message: "missing older_than parameter: expected a duration such as 24h"}
+	}
+	olderThan, err := time.ParseDuration(v)
+	if err != nil || olderThan < 0 {
+		return CommandResult{Success: false, Code: CodeInvalidParameter, Message: fmt.Sprintf("invalid older_than value %q: expected a duration such as 24h", v)}
+	}
+
+	jobs := e.clientset.BatchV1().Jobs(cmd.TargetNamespace)
+	list, err := jobs.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return apiFailure(err)
+	}
+	cutoff := time.Now().Add(-olderThan)
+	background := metav1.DeletePropagationBackground
+	var deleted []string
+	for _, job := range list.Items {
+		if job.Status.Succeeded == 0 || job.Status.CompletionTime == nil || job.Status.CompletionTime.After(cutoff) {
+			continue
+		}
+		err := jobs.Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &background})
+		if apierrors.IsNotFound(err) {
+			continue // deleted meanwhile, e.g. by its TTL
+		}
+		if err != nil {
+			result := apiFailure(err)
+			result.Message = fmt.Sprintf("deleted %d job(s), then failed on %s: %v", len(deleted), job.Name, err)
+			result.Details = map[string]any{"deleted": len(deleted), "jobs": deleted}
+			return result
+		}
+		deleted = append(deleted, job.Name)
+	}
+	return CommandResult{
+		Success: true,
+		Code:    CodeOK,
+		Message: fmt.Sprintf("Deleted %d completed job(s) in namespace %s", len(deleted), cmd.TargetNamespace),
+		Details: map[string]any{"deleted": len(deleted), "jobs": deleted},
+	}
+}
+
 func (e *Executor) cordonNode(ctx context.Context, cmd Command, cordon bool) CommandResult {
 	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, cordon)
 	_, err := e.clientset.CoreV1().Nodes().Patch(
 		ctx, cmd.TargetName, apitypes.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
 	if err != nil {
-		return CommandResult{Success: false, Message: err.Error()}
+		return apiFailure(err)
 	}
 
 	action := "cordoned"
@@ -176,6 +435,7 @@ func (e *Executor) cordonNode(ctx context.Context, cmd Command, cordon bool) Com
 	}
 	return CommandResult{
 		Success: true,
+		Code:    CodeOK,
 		Message: fmt.Sprintf("Node %s %s", cmd.TargetName, action),
 	}
 }
@@ -196,61 +456,59 @@ func (e *Executor) tuneResourceLimits(ctx context.Context, cmd Command) CommandR
 
 	// Build a strategic merge patch that sets limits on all containers
 	// We need to get the current spec first to know container names
-	var containers []map[string]any
-
+	var podSpec corev1.PodSpec
 	switch kind {
 	case "Deployment":
 		dep, err := e.clientset.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
 		if err != nil {
-			return CommandResult{Success: false, Message: err.Error()}
-		}
-		for _, c := range dep.Spec.Template.Spec.Containers {
-			memLim := c.Resources.Limits.Memory()
-			cpuLim := c.Resources.Limits.Cpu()
-			if (memLim != nil && !memLim.IsZero()) && (cpuLim != nil && !cpuLim.IsZero()) {
-				continue // already has both limits
-			}
-			patch := map[string]any{"name": c.Name, "resources": map[string]any{"limits": map[string]string{}}}
-			limits := patch["resources"].(map[string]any)["limits"].(map[string]string)
-			if memLim == nil || memLim.IsZero() {
-				limits["memory"] = memLimit
-			}
-			if cpuLim == nil || cpuLim.IsZero() {
-				limits["cpu"] = cpuLimit
-			}
-			containers = append(containers, patch)
+			return apiFailure(err)
 		}
+		podSpec = dep.Spec.Template.Spec
 	case "StatefulSet":
 		sts, err := e.clientset.AppsV1().StatefulSets(ns).Get(ctx, name, metav1.GetOptions{})
 		if err != nil {
-			return CommandResult{Success: false, Message: err.Error()}
-		}
-		for _, c := range sts.Spec.Template.Spec.Containers {
-			memLim := c.Resources.Limits.Memory()
-			cpuLim := c.Resources.Limits.Cpu()
-			if (memLim != nil && !memLim.IsZero()) && (cpuLim != nil && !cpuLim.IsZero()) {
-				continue
-			}
-			patch := map[string]any{"name": c.Name, "resources": map[string]any{"limits": map[string]string{}}}
-			limits := patch["resources"].(map[string]any)["limits"].(map[string]string)
-			if memLim == nil || memLim.IsZero() {
-				limits["memory"] = memLimit
-			}
-			if cpuLim == nil || cpuLim.IsZero() {
-				limits["cpu"] = cpuLimit
-			}
-			containers = append(containers, patch)
+			return apiFailure(err)
 		}
+		podSpec = sts.Spec.Template.Spec
 	case "DaemonSet":
 		ds, err := e.clientset.AppsV1().DaemonSets(ns).Get(ctx, name, metav1.GetOptions{})
 		if err != nil {
-			return CommandResult{Success: false, Message: err.Error()}
+			return apiFailure(err)
 		}
-		for _, c := range ds.Spec.Template.Spec.Containers {
+		podSpec = ds.Spec.Template.Spec
+	default:
+		return CommandResult{Success: false, Code: CodeInvalidParameter, Message: fmt.Sprintf("unsupported kind: %s", kind)}
+	}
+
+	var containers []map[string]any
+	only, _ := cmd.Parameters["container"].(string)
+	if only != "" {
+		// A named container gets the given limits even if it already has
+		// some, so an existing limit can be raised (e.g. after an OOMKill)
+		limits := map[string]string{}
+		if v, ok := cmd.Parameters["memory_limit"].(string); ok && v != "" {
+			limits["memory"] = v
+		}
+		if v, ok := cmd.Parameters["cpu_limit"].(string); ok && v != "" {
+			limits["cpu"] = v
+		}
+		if len(limits) == 0 {
+			return CommandResult{Success: false, Code: CodeInvalidParameter, Message: "memory_limit or cpu_limit is required with container"}
+		}
+		found := false
+		for _, c := range podSpec.Containers {
+			found = found || c.Name == only
+		}
+		if !found {
+			return CommandResult{Success: false, Code: CodeNotFound, Message: fmt.Sprintf("%s %s/%s has no container %q", kind, ns, name, only)}
+		}
+		containers = append(containers, map[string]any{"name": only, "resources": map[string]any{"limits": limits}})
+	} else {
+		for _, c := range podSpec.Containers {
 			memLim := c.Resources.Limits.Memory()
 			cpuLim := c.Resources.Limits.Cpu()
 			if (memLim != nil && !memLim.IsZero()) && (cpuLim != nil && !cpuLim.IsZero()) {
-				continue
+				continue // already has both limits
 			}
 			patch := map[string]any{"name": c.Name, "resources": map[string]any{"limits": map[string]string{}}}
 			limits := patch["resources"].(map[string]any)["limits"].(map[string]string)
@@ -262,13 +520,12 @@ func (e *Executor) tuneResourceLimits(ctx context.Context, cmd Command) CommandR
 			}
 			containers = append(containers, patch)
 		}
-	default:
-		return CommandResult{Success: false, Message: fmt.Sprintf("unsupported kind: %s", kind)}
 	}
 
 	if len(containers) == 0 {
 		return CommandResult{
 			Success: true,
+			Code:    CodeOK,
 			Message: fmt.Sprintf("%s %s/%s already has all resource limits set", kind, ns, name),
 		}
 	}
@@ -295,11 +552,21 @@ func (e *Executor) tuneResourceLimits(ctx context.Context, cmd Command) CommandR
 	}
 
 	if err != nil {
-		return CommandResult{Success: false, Message: err.Error()}
+		return apiFailure(err)
 	}
 
+	if only != "" {
+		limits := containers[0]["resources"].(map[string]any)["limits"].(map[string]string)
+		return CommandResult{
+			Success: true,
+			Code:    CodeOK,
+			Message: fmt.Sprintf("%s %s/%s container %s limits set (%v)", kind, ns, name, only, limits),
+			Details: map[string]any{"patched_containers": 1, "container": only, "limits": limits},
+		}
+	}
 	return CommandResult{
 		Success: true,
+		Code:    CodeOK,
 		Message: fmt.Sprintf("%s %s/%s limits set (cpu=%s, memory=%s) for %d container(s)", kind, ns, name, cpuLimit, memLimit, len(containers)),
 		Details: map[string]any{"patched_containers": len(containers), "cpu_limit": cpuLimit, "memory_limit": memLimit},
 	}
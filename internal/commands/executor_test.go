@@ -2,13 +2,23 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/tinkerbelle-io/tb-manage/internal/insights"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/fake"
 	ktesting "k8s.io/client-go/testing"
 )
@@ -94,6 +104,94 @@ func TestDeletePVC(t *testing.T) {
 	}
 }
 
+func TestDeleteJob(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default"}},
+	)
+	result := NewExecutor(clientset).Execute(context.Background(), Command{
+		ID: "cmd-13", Action: "delete_job",
+		TargetKind: "Job", TargetNamespace: "default", TargetName: "migrate",
+	})
+	if !result.Success || result.Details["deleted"] != 1 {
+		t.Errorf("expected one job deleted: %s %v", result.Message, result.Details)
+	}
+	if _, err := clientset.BatchV1().Jobs("default").Get(context.Background(), "migrate", metav1.GetOptions{}); err == nil {
+		t.Error("job should have been deleted")
+	}
+
+	result = NewExecutor(clientset).Execute(context.Background(), Command{
+		ID: "cmd-16", Action: "delete_job", TargetKind: "Job", TargetName: "migrate",
+	})
+	if result.Success || result.Code != CodeInvalidParameter {
+		t.Errorf("missing namespace: expected invalid_parameter, got %s: %s", result.Code, result.Message)
+	}
+}
+
+func TestDeleteCompletedJobs(t *testing.T) {
+	now := time.Now()
+	job := func(ns, name string, succeeded int32, completed time.Duration) *batchv1.Job {
+		j := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Status:     batchv1.JobStatus{Succeeded: succeeded},
+		}
+		if completed > 0 {
+			at := metav1.NewTime(now.Add(-completed))
+			j.Status.CompletionTime = &at
+		}
+		return j
+	}
+	clientset := fake.NewSimpleClientset(
+		job("default", "old-backup", 1, 48*time.Hour),
+		job("default", "fresh-backup", 1, time.Hour),
+		job("default", "running", 0, 0),
+		job("other", "old-report", 1, 72*time.Hour),
+	)
+
+	result := NewExecutor(clientset).Execute(context.Background(), Command{
+		ID: "cmd-14", Action: "delete_completed_jobs", TargetNamespace: "default",
+		Parameters: map[string]any{"older_than": "24h"},
+	})
+	if !result.Success || result.Details["deleted"] != 1 {
+		t.Fatalf("expected one job deleted: %s %v", result.Message, result.Details)
+	}
+
+	remaining := map[string]bool{}
+	list, err := clientset.BatchV1().Jobs("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, j := range list.Items {
+		remaining[j.Namespace+"/"+j.Name] = true
+	}
+	for _, name := range []string{"default/fresh-backup", "default/running", "other/old-report"} {
+		if !remaining[name] {
+			t.Errorf("%s should have been kept", name)
+		}
+	}
+	if remaining["default/old-backup"] {
+		t.Error("default/old-backup should have been deleted")
+	}
+
+	result = NewExecutor(clientset).Execute(context.Background(), Command{
+		ID: "cmd-15", Action: "delete_completed_jobs", TargetNamespace: "default",
+		Parameters: map[string]any{"older_than": "a while"},
+	})
+	if result.Success || result.Code != CodeInvalidParameter {
+		t.Errorf("expected invalid_parameter, got %s: %s", result.Code, result.Message)
+	}
+
+	// Without older_than nothing is deleted
+	result = NewExecutor(clientset).Execute(context.Background(), Command{
+		ID: "cmd-17", Action: "delete_completed_jobs", TargetNamespace: "default",
+	})
+	if result.Success || result.Code != CodeInvalidParameter {
+		t.Errorf("missing older_than: expected invalid_parameter, got %s: %s", result.Code, result.Message)
+	}
+	if _, err := clientset.BatchV1().Jobs("default").Get(context.Background(), "fresh-backup", metav1.GetOptions{}); err != nil {
+		t.Error("default/fresh-backup should have been kept")
+	}
+}
+
 func TestRestartDeployment(t *testing.T) {
 	clientset := fake.NewSimpleClientset(
 		&appsv1.Deployment{
@@ -120,6 +218,133 @@ func TestRestartDeployment(t *testing.T) {
 	}
 }
 
+func TestRestartStatefulSetAndDaemonSet(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "db"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "db", Image: "postgres:16"}}},
+	}
+	clientset := fake.NewSimpleClientset(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: int32Ptr(1), Template: template},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-exporter", Namespace: "default"},
+			Spec:       appsv1.DaemonSetSpec{Template: template},
+		},
+	)
+	exec := NewExecutor(clientset)
+
+	for _, cmd := range []Command{
+		{ID: "cmd-16", Action: "restart_statefulset", TargetKind: "StatefulSet", TargetNamespace: "default", TargetName: "db"},
+		{ID: "cmd-17", Action: "restart_daemonset", TargetKind: "DaemonSet", TargetNamespace: "default", TargetName: "node-exporter"},
+	} {
+		if result := exec.Execute(context.Background(), cmd); !result.Success {
+			t.Errorf("%s: expected success: %s", cmd.Action, result.Message)
+		}
+	}
+
+	sts, err := clientset.AppsV1().StatefulSets("default").Get(context.Background(), "db", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := clientset.AppsV1().DaemonSets("default").Get(context.Background(), "node-exporter", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for kind, annotations := range map[string]map[string]string{
+		"StatefulSet": sts.Spec.Template.Annotations,
+		"DaemonSet":   ds.Spec.Template.Annotations,
+	} {
+		if _, err := time.Parse(time.RFC3339, annotations["kubectl.kubernetes.io/restartedAt"]); err != nil {
+			t.Errorf("%s: restartedAt annotation = %q, want an RFC3339 time", kind, annotations["kubectl.kubernetes.io/restartedAt"])
+		}
+	}
+
+	result := exec.Execute(context.Background(), Command{
+		ID: "cmd-18", Action: "restart_statefulset",
+		TargetKind: "StatefulSet", TargetNamespace: "default", TargetName: "missing",
+	})
+	if result.Success || result.Code != CodeNotFound {
+		t.Errorf("expected not_found for a missing StatefulSet, got %s: %s", result.Code, result.Message)
+	}
+}
+
+func TestRollbackDeployment(t *testing.T) {
+	isController := true
+	template := func(image, hash string) corev1.PodTemplateSpec {
+		labels := map[string]string{"app": "web"}
+		if hash != "" {
+			labels["pod-template-hash"] = hash
+		}
+		return corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: image}}},
+		}
+	}
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web", Namespace: "default", UID: "web-uid",
+			Annotations: map[string]string{revisionAnnotation: "2"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(2),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: template("web:2.0", ""),
+		},
+	}
+	rs := func(name, revision, image, hash string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "default",
+				Labels:      map[string]string{"app": "web", "pod-template-hash": hash},
+				Annotations: map[string]string{revisionAnnotation: revision},
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "web-uid", Controller: &isController},
+				},
+			},
+			Spec: appsv1.ReplicaSetSpec{Template: template(image, hash)},
+		}
+	}
+	clientset := fake.NewSimpleClientset(dep,
+		rs("web-5d8f7c9b4", "1", "web:1.0", "5d8f7c9b4"),
+		rs("web-7c6b5d4f8", "2", "web:2.0", "7c6b5d4f8"),
+	)
+	exec := NewExecutor(clientset)
+
+	result := exec.Execute(context.Background(), Command{
+		ID: "cmd-11", Action: "rollback_deployment",
+		TargetKind: "Deployment", TargetNamespace: "default", TargetName: "web",
+	})
+	if !result.Success {
+		t.Fatalf("expected success: %s", result.Message)
+	}
+	if result.Details["revision"] != int64(1) {
+		t.Errorf("revision = %v, want 1", result.Details["revision"])
+	}
+
+	got, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image := got.Spec.Template.Spec.Containers[0].Image; image != "web:1.0" {
+		t.Errorf("image = %s, want web:1.0", image)
+	}
+	if _, ok := got.Spec.Template.Labels["pod-template-hash"]; ok {
+		t.Error("pod-template-hash label copied into the deployment template")
+	}
+
+	// Nothing before revision 1
+	clientset = fake.NewSimpleClientset(dep, rs("web-7c6b5d4f8", "2", "web:2.0", "7c6b5d4f8"))
+	result = NewExecutor(clientset).Execute(context.Background(), Command{
+		ID: "cmd-12", Action: "rollback_deployment",
+		TargetKind: "Deployment", TargetNamespace: "default", TargetName: "web",
+	})
+	if result.Success || result.Code != CodeNotFound {
+		t.Errorf("expected not_found without an earlier revision, got %s: %s", result.Code, result.Message)
+	}
+}
+
 func TestCordonNode(t *testing.T) {
 	clientset := fake.NewSimpleClientset(
 		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
@@ -155,6 +380,119 @@ func TestUncordonNode(t *testing.T) {
 	}
 }
 
+func TestDrainNodeStreamsProgress(t *testing.T) {
+	isController := true
+	pod := func(name, node string, owner string) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if owner != "" {
+			p.OwnerReferences = []metav1.OwnerReference{{Kind: owner, Name: "owner", Controller: &isController}}
+		}
+		return p
+	}
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+		pod("web-1", "worker-1", "ReplicaSet"),
+		pod("web-2", "worker-1", "ReplicaSet"),
+		pod("db-0", "worker-1", "StatefulSet"),
+		pod("node-exporter", "worker-1", "DaemonSet"),
+		pod("web-3", "worker-2", "ReplicaSet"),
+	)
+	var evicted []string
+	clientset.PrependReactor("create", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		ev := action.(ktesting.CreateAction).GetObject().(*policyv1.Eviction)
+		evicted = append(evicted, ev.Name)
+		return true, nil, nil
+	})
+	exec := NewExecutor(clientset)
+
+	var progress []CommandProgress
+	var result *CommandResult
+	for ev := range exec.ExecuteStream(context.Background(), Command{
+		ID: "cmd-drain", Action: "drain_node",
+		TargetKind: "Node", TargetName: "worker-1",
+	}) {
+		if result != nil {
+			t.Fatal("event received after the result")
+		}
+		if ev.Progress != nil {
+			progress = append(progress, *ev.Progress)
+		}
+		result = ev.Result
+	}
+
+	if result == nil || !result.Success {
+		t.Fatalf("expected a successful result, got %+v", result)
+	}
+	if len(evicted) != 3 {
+		t.Errorf("evicted %v, want the 3 non-DaemonSet pods on worker-1", evicted)
+	}
+	want := []string{
+		"node worker-1 cordoned, evicting 3 pods",
+		"evicted 1/3 pods",
+		"evicted 2/3 pods",
+		"evicted 3/3 pods",
+	}
+	if len(progress) != len(want) {
+		t.Fatalf("progress = %+v, want %d events", progress, len(want))
+	}
+	for i, p := range progress {
+		if p.Message != want[i] || p.Total != 3 {
+			t.Errorf("progress[%d] = %+v, want %q of 3", i, p, want[i])
+		}
+	}
+
+	node, _ := clientset.CoreV1().Nodes().Get(context.Background(), "worker-1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Error("node should be cordoned")
+	}
+}
+
+func TestDrainNodeRefusesUnownedPods(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: "worker-1"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	)
+	var evicted []string
+	clientset.PrependReactor("create", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		evicted = append(evicted, action.(ktesting.CreateAction).GetObject().(*policyv1.Eviction).Name)
+		return true, nil, nil
+	})
+	exec := NewExecutor(clientset)
+
+	result := exec.Execute(context.Background(), Command{
+		ID: "cmd-drain", Action: "drain_node", TargetKind: "Node", TargetName: "worker-1",
+	})
+	if result.Success || result.Code != CodeInvalidParameter {
+		t.Fatalf("expected invalid_parameter, got %+v", result)
+	}
+	node, _ := clientset.CoreV1().Nodes().Get(context.Background(), "worker-1", metav1.GetOptions{})
+	if node.Spec.Unschedulable || len(evicted) != 0 {
+		t.Fatalf("refused drain should not cordon or evict (unschedulable=%v, evicted=%v)", node.Spec.Unschedulable, evicted)
+	}
+
+	result = exec.Execute(context.Background(), Command{
+		ID: "cmd-drain-force", Action: "drain_node", TargetKind: "Node", TargetName: "worker-1",
+		Parameters: map[string]any{"force": true},
+	})
+	if !result.Success || len(evicted) != 1 {
+		t.Errorf("forced drain: %+v, evicted %v", result, evicted)
+	}
+}
+
 func TestUnknownAction(t *testing.T) {
 	clientset := fake.NewSimpleClientset()
 	exec := NewExecutor(clientset)
@@ -170,6 +508,72 @@ func TestUnknownAction(t *testing.T) {
 	if result.Message != "unknown action: fly_to_moon" {
 		t.Errorf("unexpected message: %s", result.Message)
 	}
+	if result.Code != CodeUnknownAction {
+		t.Errorf("code = %q, want %q", result.Code, CodeUnknownAction)
+	}
+}
+
+func TestResultCodeNotFound(t *testing.T) {
+	exec := NewExecutor(fake.NewSimpleClientset())
+
+	result := exec.Execute(context.Background(), Command{
+		ID: "cmd-nf", Action: "delete_pod",
+		TargetKind: "Pod", TargetNamespace: "default", TargetName: "missing",
+	})
+
+	if result.Success {
+		t.Fatal("deleting a missing pod should fail")
+	}
+	if result.Code != CodeNotFound {
+		t.Errorf("code = %q, want %q", result.Code, CodeNotFound)
+	}
+}
+
+func TestResultCodeForbidden(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	)
+	clientset.PrependReactor("patch", "nodes", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "node-1", errors.New("RBAC denied"))
+	})
+	exec := NewExecutor(clientset)
+
+	result := exec.Execute(context.Background(), Command{
+		ID: "cmd-fb", Action: "cordon_node", TargetKind: "Node", TargetName: "node-1",
+	})
+
+	if result.Success {
+		t.Fatal("forbidden patch should fail")
+	}
+	if result.Code != CodeForbidden {
+		t.Errorf("code = %q, want %q", result.Code, CodeForbidden)
+	}
+}
+
+func TestResultCodeConflictAndOK(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+	)
+	exec := NewExecutor(clientset)
+
+	result := exec.Execute(context.Background(), Command{
+		ID: "cmd-ok", Action: "restart_deployment",
+		TargetKind: "Deployment", TargetNamespace: "default", TargetName: "web",
+	})
+	if !result.Success || result.Code != CodeOK {
+		t.Errorf("result = %+v, want success with code %q", result, CodeOK)
+	}
+
+	clientset.PrependReactor("patch", "deployments", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web", errors.New("object modified"))
+	})
+	result = exec.Execute(context.Background(), Command{
+		ID: "cmd-cf", Action: "restart_deployment",
+		TargetKind: "Deployment", TargetNamespace: "default", TargetName: "web",
+	})
+	if result.Code != CodeConflict {
+		t.Errorf("code = %q, want %q", result.Code, CodeConflict)
+	}
 }
 
 func TestScaleDeployment(t *testing.T) {
@@ -227,6 +631,60 @@ func TestScaleMissingReplicas(t *testing.T) {
 	if result.Success {
 		t.Error("should fail with missing replicas parameter")
 	}
+	if result.Code != CodeInvalidParameter {
+		t.Errorf("code = %q, want %q", result.Code, CodeInvalidParameter)
+	}
+}
+
+func TestScaleReplicasInputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas any
+		wantOK   bool
+		wantErr  string
+	}{
+		{"string", "3", true, ""},
+		{"float", 3.0, true, ""},
+		{"json.Number", json.Number("3"), true, ""},
+		{"non-numeric string", "three", false, "not a number"},
+		{"negative", float64(-1), false, "must not be negative"},
+		{"fractional", 2.5, false, "whole number"},
+		{"bool", true, false, "expected a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			var updated int32 = -1
+			clientset.PrependReactor("get", "deployments/scale", func(action ktesting.Action) (bool, runtime.Object, error) {
+				return true, &autoscalingv1.Scale{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+					Spec:       autoscalingv1.ScaleSpec{Replicas: 1},
+				}, nil
+			})
+			clientset.PrependReactor("update", "deployments/scale", func(action ktesting.Action) (bool, runtime.Object, error) {
+				scale := action.(ktesting.UpdateAction).GetObject().(*autoscalingv1.Scale)
+				updated = scale.Spec.Replicas
+				return true, scale, nil
+			})
+
+			result := NewExecutor(clientset).Execute(context.Background(), Command{
+				ID: "cmd-scale", Action: "scale",
+				TargetKind: "Deployment", TargetNamespace: "default", TargetName: "web",
+				Parameters: map[string]any{"replicas": tt.replicas},
+			})
+
+			if result.Success != tt.wantOK {
+				t.Fatalf("success = %v, want %v (%s)", result.Success, tt.wantOK, result.Message)
+			}
+			if tt.wantOK && updated != 3 {
+				t.Errorf("scaled to %d, want 3", updated)
+			}
+			if !tt.wantOK && !strings.Contains(result.Message, tt.wantErr) {
+				t.Errorf("message %q should contain %q", result.Message, tt.wantErr)
+			}
+		})
+	}
 }
 
 func TestTuneResourceLimits(t *testing.T) {
@@ -259,3 +717,86 @@ func TestTuneResourceLimits(t *testing.T) {
 		t.Errorf("expected success: %s", result.Message)
 	}
 }
+
+func TestTuneResourceLimitsOOMKilledContainer(t *testing.T) {
+	isController := true
+	clientset := fake.NewSimpleClientset(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "default"},
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{
+						{Name: "redis", Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+						}},
+						{Name: "exporter"},
+					}},
+				},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cache-0", Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "cache", Controller: &isController}},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "redis", Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				}},
+				{Name: "exporter"},
+			}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "redis",
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						Reason: "OOMKilled", ExitCode: 137, FinishedAt: metav1.NewTime(time.Now().Add(-time.Minute)),
+					},
+				},
+			}}},
+		},
+	)
+
+	found, err := insights.NewOOMKilledAnalyzer().Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected 1 insight, got %+v", found)
+	}
+	in := found[0]
+	result := NewExecutor(clientset).Execute(context.Background(), Command{
+		ID: "cmd-11", Action: in.ProposedAction,
+		TargetKind: in.TargetKind, TargetNamespace: in.TargetNS, TargetName: in.TargetName,
+		Parameters: in.ProposedParams,
+	})
+	if !result.Success {
+		t.Fatalf("expected success: %s", result.Message)
+	}
+
+	sts, err := clientset.AppsV1().StatefulSets("default").Get(context.Background(), "cache", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		want := ""
+		if c.Name == "redis" {
+			want = "512Mi"
+		}
+		got := ""
+		if m := c.Resources.Limits.Memory(); !m.IsZero() {
+			got = m.String()
+		}
+		if got != want {
+			t.Errorf("container %s memory limit = %q, want %q", c.Name, got, want)
+		}
+	}
+
+	result = NewExecutor(clientset).Execute(context.Background(), Command{
+		ID: "cmd-12", Action: "tune_resource_limits",
+		TargetKind: "StatefulSet", TargetNamespace: "default", TargetName: "cache",
+		Parameters: map[string]any{"container": "missing", "memory_limit": "1Gi"},
+	})
+	if result.Success || result.Code != CodeNotFound {
+		t.Errorf("unknown container: got %+v, want not_found", result)
+	}
+}
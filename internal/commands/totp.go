@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TOTPParam is the command parameter carrying the second-factor code.
+const TOTPParam = "totp_code"
+
+const (
+	totpStep   = 30 * time.Second
+	totpDigits = 6
+	totpSkew   = 1 // accepted steps either side of now, for clock drift
+)
+
+// DefaultTOTPActions are the destructive actions gated when no explicit
+// list is configured.
+var DefaultTOTPActions = []string{
+	"delete_deployment", "delete_pvc", "drain_node", "delete_job", "delete_completed_jobs",
+}
+
+// TOTPPolicy requires an RFC 6238 code (SHA-1, 6 digits, 30s step) in the
+// parameters of selected actions. Each accepted time step can only be used
+// once, so an intercepted code cannot be replayed.
+type TOTPPolicy struct {
+	secret  []byte
+	actions map[string]bool
+	now     func() time.Time
+
+	mu          sync.Mutex
+	lastCounter int64
+}
+
+// NewTOTPPolicy creates a policy from a base32 shared secret (as shown by
+// authenticator apps). An empty actions list uses DefaultTOTPActions.
+func NewTOTPPolicy(secret string, actions []string) (*TOTPPolicy, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) == 0 {
+		actions = DefaultTOTPActions
+	}
+	p := &TOTPPolicy{
+		secret:      key,
+		actions:     make(map[string]bool, len(actions)),
+		now:         time.Now,
+		lastCounter: -1,
+	}
+	for _, a := range actions {
+		p.actions[strings.TrimSpace(a)] = true
+	}
+	return p, nil
+}
+
+// Requires reports whether action is gated by the policy.
+func (p *TOTPPolicy) Requires(action string) bool {
+	return p.actions[action]
+}
+
+// Check validates the TOTP code on a gated command. Commands for other
+// actions pass unchecked.
+func (p *TOTPPolicy) Check(cmd Command) error {
+	if !p.Requires(cmd.Action) {
+		return nil
+	}
+
+	code, _ := cmd.Parameters[TOTPParam].(string)
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return fmt.Errorf("action %s requires a %s parameter", cmd.Action, TOTPParam)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current := p.now().Unix() / int64(totpStep.Seconds())
+	for c := current - totpSkew; c <= current+totpSkew; c++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(p.secret, c)), []byte(code)) != 1 {
+			continue
+		}
+		if c <= p.lastCounter {
+			return fmt.Errorf("%s already used", TOTPParam)
+		}
+		p.lastCounter = c
+		return nil
+	}
+	return fmt.Errorf("invalid %s", TOTPParam)
+}
+
+// totpCode computes the HOTP value (RFC 4226) for a counter.
+func totpCode(secret []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+func decodeTOTPSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+	s = strings.TrimRight(s, "=")
+	if s == "" {
+		return nil, fmt.Errorf("empty TOTP secret")
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOTP secret: must be base32: %w", err)
+	}
+	return key, nil
+}
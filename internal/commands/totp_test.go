@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// RFC 6238 test secret "12345678901234567890" in base32.
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTestPolicy(t *testing.T, now time.Time) *TOTPPolicy {
+	t.Helper()
+	p, err := NewTOTPPolicy(testTOTPSecret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func TestTOTPCodeRFCVector(t *testing.T) {
+	key, _ := decodeTOTPSecret(testTOTPSecret)
+	// RFC 6238 Appendix B, T=59: 94287082 (last 6 digits)
+	if got := totpCode(key, 59/30); got != "287082" {
+		t.Errorf("totpCode = %s, want 287082", got)
+	}
+}
+
+func TestTOTPPolicyValidCode(t *testing.T) {
+	p := newTestPolicy(t, time.Unix(59, 0))
+	err := p.Check(Command{Action: "delete_pvc", Parameters: map[string]any{TOTPParam: "287082"}})
+	if err != nil {
+		t.Fatalf("expected valid code to pass: %v", err)
+	}
+
+	// The same code cannot be replayed
+	err = p.Check(Command{Action: "delete_pvc", Parameters: map[string]any{TOTPParam: "287082"}})
+	if err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Errorf("expected replay to be rejected, got %v", err)
+	}
+}
+
+func TestTOTPPolicyInvalidCode(t *testing.T) {
+	p := newTestPolicy(t, time.Unix(59, 0))
+
+	for _, params := range []map[string]any{
+		nil,
+		{TOTPParam: ""},
+		{TOTPParam: "000000"},
+		{TOTPParam: 287082}, // must be a string
+	} {
+		if err := p.Check(Command{Action: "delete_deployment", Parameters: params}); err == nil {
+			t.Errorf("expected rejection for params %v", params)
+		}
+	}
+
+	// A code from well outside the skew window is rejected
+	p.now = func() time.Time { return time.Unix(59+5*60, 0) }
+	if err := p.Check(Command{Action: "delete_deployment", Parameters: map[string]any{TOTPParam: "287082"}}); err == nil {
+		t.Error("expected stale code to be rejected")
+	}
+}
+
+func TestTOTPPolicyNonDestructiveBypass(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "target-pod", Namespace: "default"}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-pvc", Namespace: "default"}},
+	)
+	exec := NewExecutor(clientset)
+	exec.SetTOTPPolicy(newTestPolicy(t, time.Now()))
+
+	result := exec.Execute(context.Background(), Command{
+		ID: "cmd-1", Action: "delete_pod",
+		TargetKind: "Pod", TargetNamespace: "default", TargetName: "target-pod",
+	})
+	if !result.Success {
+		t.Errorf("non-destructive action should bypass the gate: %s", result.Message)
+	}
+
+	result = exec.Execute(context.Background(), Command{
+		ID: "cmd-2", Action: "delete_pvc",
+		TargetKind: "PersistentVolumeClaim", TargetNamespace: "default", TargetName: "data-pvc",
+	})
+	if result.Success {
+		t.Fatal("delete_pvc without a code should be rejected")
+	}
+	if result.Code != CodeForbidden {
+		t.Errorf("code = %q, want %q", result.Code, CodeForbidden)
+	}
+	if _, err := clientset.CoreV1().PersistentVolumeClaims("default").Get(context.Background(), "data-pvc", metav1.GetOptions{}); err != nil {
+		t.Error("PVC should not be deleted when the gate rejects the command")
+	}
+}
+
+func TestTOTPDefaultActionsGateJobDeletes(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "done", Namespace: "default"}})
+	exec := NewExecutor(clientset)
+	exec.SetTOTPPolicy(newTestPolicy(t, time.Unix(59, 0)))
+
+	for _, cmd := range []Command{
+		{ID: "cmd-1", Action: "delete_job", TargetKind: "Job", TargetNamespace: "default", TargetName: "done"},
+		{ID: "cmd-2", Action: "delete_completed_jobs", TargetNamespace: "default"},
+	} {
+		result := exec.Execute(context.Background(), cmd)
+		if result.Success || result.Code != CodeForbidden {
+			t.Errorf("%s without a code: got %+v, want forbidden", cmd.Action, result)
+		}
+	}
+	if _, err := clientset.BatchV1().Jobs("default").Get(context.Background(), "done", metav1.GetOptions{}); err != nil {
+		t.Error("job should not be deleted when the gate rejects the command")
+	}
+
+	cmd := Command{ID: "cmd-3", Action: "delete_completed_jobs", TargetNamespace: "default",
+		Parameters: map[string]any{TOTPParam: "287082", "older_than": "24h"}}
+	if result := exec.Execute(context.Background(), cmd); !result.Success {
+		t.Errorf("delete_completed_jobs with a valid code: %s", result.Message)
+	}
+}
+
+func TestNewTOTPPolicyInvalidSecret(t *testing.T) {
+	if _, err := NewTOTPPolicy("", nil); err == nil {
+		t.Error("expected error for empty secret")
+	}
+	if _, err := NewTOTPPolicy("not base32!", nil); err == nil {
+		t.Error("expected error for non-base32 secret")
+	}
+}
@@ -11,12 +11,43 @@ type Command struct {
 }
 
 // CommandResult is the outcome of executing a command.
+// Code is machine-readable; Message is for humans and may change.
 type CommandResult struct {
 	Success bool           `json:"success"`
+	Code    ResultCode     `json:"code"`
 	Message string         `json:"message"`
 	Details map[string]any `json:"details,omitempty"`
 }
 
+// CommandProgress is an intermediate update from a long-running command,
+// e.g. "evicted 3/12 pods".
+type CommandProgress struct {
+	Message string `json:"message"`
+	Done    int    `json:"done"`
+	Total   int    `json:"total"`
+}
+
+// CommandEvent is one event of Executor.ExecuteStream: either a progress
+// update or, last, the command's result.
+type CommandEvent struct {
+	Progress *CommandProgress
+	Result   *CommandResult
+}
+
+// ResultCode classifies a command outcome so the SaaS can branch on it.
+type ResultCode string
+
+const (
+	CodeOK               ResultCode = "ok"
+	CodeNotFound         ResultCode = "not_found"
+	CodeForbidden        ResultCode = "forbidden"
+	CodeConflict         ResultCode = "conflict"
+	CodeInvalidParameter ResultCode = "invalid_parameter"
+	CodeTimeout          ResultCode = "timeout"
+	CodeUnknownAction    ResultCode = "unknown_action"
+	CodeInternal         ResultCode = "internal"
+)
+
 // CompletionStatus indicates the final state of a command.
 type CompletionStatus string
 
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,11 +17,43 @@ type Config struct {
 	Identity          string        `yaml:"identity"` // "token" or "ssh-host-key"
 	AnonKey           string        `yaml:"anon_key"`
 	Profile           string        `yaml:"profile"`
-	ScanInterval      time.Duration `yaml:"scan_interval"`
+	ScanInterval      time.Duration `yaml:"scan_interval"` // 0 = one-shot
 	LogLevel          string        `yaml:"log_level"`
 	Permissions       []string      `yaml:"permissions"`        // e.g., ["terminal", "scan"]
 	ExcludeNamespaces []string      `yaml:"exclude_namespaces"` // namespaces to skip during k8s scan
+	SkipNamespaces    []string      `yaml:"skip_namespaces"`    // skipped on top of exclude_namespaces (or the defaults); globs allowed
+	ScanNamespaces    []string      `yaml:"scan_namespaces"`    // when set, only these namespaces are scanned; globs allowed
+	ClusterNameLabel  string        `yaml:"cluster_name_label"` // label on kube-system or nodes naming the cluster
 	TokenInURLFallback bool          `yaml:"token_in_url_fallback"` // DEPRECATED: also send token as query param (default true for migration)
+	Redact            RedactConfig  `yaml:"redact"`             // payload fields to strip/hash before upload
+	Enrich            EnrichConfig  `yaml:"enrich"`             // site fields added to every host upload
+	MaxPayloadBytes   int           `yaml:"max_payload_bytes"`  // trim uploads above this size (0 = no limit)
+}
+
+// EnrichConfig adds site-specific fields (site code, rack, owner, ...) to
+// every host upload, e.g.:
+//
+//	enrich:
+//	  static:
+//	    site: ams3
+//	    rack: r12
+//	  command: /usr/local/bin/cmdb-lookup --json
+//
+// The command's stdout must be a JSON object; its fields win over static
+// ones. Neither may reuse a core host field name.
+type EnrichConfig struct {
+	Static  map[string]any `yaml:"static"`
+	Command string         `yaml:"command"`
+}
+
+// RedactConfig lists upload payload fields, by JSON path, to drop or hash
+// for privacy (e.g. "host.network.interfaces[].mac"). HashKey is a secret
+// unique to this install that keys the hashes; it is required with Hash
+// and can also be set with TB_REDACT_HASH_KEY.
+type RedactConfig struct {
+	Remove  []string `yaml:"remove"`
+	Hash    []string `yaml:"hash"`
+	HashKey string   `yaml:"hash_key"`
 }
 
 // DefaultConfig returns sensible defaults.
@@ -61,19 +95,43 @@ func Load(path string) (*Config, error) {
 	if v := os.Getenv("TB_PROFILE"); v != "" {
 		cfg.Profile = v
 	}
+	if v := os.Getenv("SCAN_INTERVAL_SECONDS"); v != "" {
+		secs, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SCAN_INTERVAL_SECONDS %q: %w", v, err)
+		}
+		cfg.ScanInterval = time.Duration(secs) * time.Second
+	}
 	if v := os.Getenv("TB_LOG_LEVEL"); v != "" {
 		cfg.LogLevel = v
 	}
 	if v := os.Getenv("EXCLUDE_NAMESPACES"); v != "" {
-		var ns []string
-		for _, s := range strings.Split(v, ",") {
-			s = strings.TrimSpace(s)
-			if s != "" {
-				ns = append(ns, s)
-			}
-		}
-		cfg.ExcludeNamespaces = ns
+		cfg.ExcludeNamespaces = splitList(v)
+	}
+	if v := os.Getenv("TB_SKIP_NAMESPACES"); v != "" {
+		cfg.SkipNamespaces = splitList(v)
+	}
+	if v := os.Getenv("TB_SCAN_NAMESPACES"); v != "" {
+		cfg.ScanNamespaces = splitList(v)
+	}
+	if v := os.Getenv("CLUSTER_NAME_LABEL"); v != "" {
+		cfg.ClusterNameLabel = v
+	}
+	if v := os.Getenv("TB_REDACT_HASH_KEY"); v != "" {
+		cfg.Redact.HashKey = v
 	}
 
 	return cfg, nil
 }
+
+// splitList splits a comma-separated environment value, dropping blanks.
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+// Package helmrelease reads Helm 3 release revisions from the Secrets Helm
+// stores them in, shared by the cluster scanner and the insight analyzers.
+package helmrelease
+
+import (
+	"sort"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SecretType is the Secret type Helm 3 stores release revisions in.
+	SecretType = "helm.sh/release.v1"
+	// LabelSelector matches the Secrets Helm manages.
+	LabelSelector = "owner=helm"
+	// FieldSelector matches release Secrets by type, for lists that do not
+	// return the type (metadata-only lists).
+	FieldSelector = "type=" + SecretType
+)
+
+// Revision is one release revision, as recorded in its Secret's labels.
+type Revision struct {
+	Namespace string
+	Release   string
+	Number    int
+	Status    string // deployed, superseded, failed, pending-upgrade, ...
+	Secret    string // name of the Secret holding the revision
+}
+
+// ParseRevision reads the revision a release Secret's labels describe. ok
+// is false when the labels do not name a release and revision. Callers
+// check the Secret's type themselves.
+func ParseRevision(secret metav1.Object) (rev Revision, ok bool) {
+	labels := secret.GetLabels()
+	number, err := strconv.Atoi(labels["version"])
+	if labels["name"] == "" || err != nil {
+		return Revision{}, false
+	}
+	return Revision{
+		Namespace: secret.GetNamespace(),
+		Release:   labels["name"],
+		Number:    number,
+		Status:    labels["status"],
+		Secret:    secret.GetName(),
+	}, true
+}
+
+// Latest keeps the newest revision of each release, sorted by namespace and
+// release name. Each revision is its own Secret, so a release with a
+// history has several.
+func Latest(revs []Revision) []Revision {
+	newest := make(map[string]Revision)
+	for _, rev := range revs {
+		key := rev.Namespace + "/" + rev.Release
+		if cur, ok := newest[key]; !ok || rev.Number > cur.Number {
+			newest[key] = rev
+		}
+	}
+	latest := make([]Revision, 0, len(newest))
+	for _, rev := range newest {
+		latest = append(latest, rev)
+	}
+	sort.Slice(latest, func(i, j int) bool {
+		if latest[i].Namespace != latest[j].Namespace {
+			return latest[i].Namespace < latest[j].Namespace
+		}
+		return latest[i].Release < latest[j].Release
+	})
+	return latest
+}
@@ -0,0 +1,42 @@
+package helmrelease
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseRevisionAndLatest(t *testing.T) {
+	secret := func(ns, name string, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels}}
+	}
+	secrets := []*corev1.Secret{
+		secret("web", "sh.helm.release.v1.web.v1", map[string]string{"owner": "helm", "name": "web", "version": "1", "status": "superseded"}),
+		secret("web", "sh.helm.release.v1.web.v10", map[string]string{"owner": "helm", "name": "web", "version": "10", "status": "deployed"}),
+		secret("web", "sh.helm.release.v1.web.v9", map[string]string{"owner": "helm", "name": "web", "version": "9", "status": "superseded"}),
+		secret("apps", "sh.helm.release.v1.api.v2", map[string]string{"owner": "helm", "name": "api", "version": "2", "status": "failed"}),
+		// Not a release revision
+		secret("apps", "helm-token", map[string]string{"owner": "helm"}),
+		secret("apps", "bad-version", map[string]string{"owner": "helm", "name": "x", "version": "latest"}),
+	}
+
+	var revs []Revision
+	for _, s := range secrets {
+		if rev, ok := ParseRevision(s); ok {
+			revs = append(revs, rev)
+		}
+	}
+	if len(revs) != 4 {
+		t.Fatalf("parsed %d revisions, want 4: %+v", len(revs), revs)
+	}
+
+	want := []Revision{
+		{Namespace: "apps", Release: "api", Number: 2, Status: "failed", Secret: "sh.helm.release.v1.api.v2"},
+		{Namespace: "web", Release: "web", Number: 10, Status: "deployed", Secret: "sh.helm.release.v1.web.v10"},
+	}
+	if got := Latest(revs); !reflect.DeepEqual(got, want) {
+		t.Errorf("Latest =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+// Package imageref parses container image references the way Docker and
+// containerd normalize them.
+package imageref
+
+import "strings"
+
+// DockerHub is the registry implied by image references without an
+// explicit registry host, e.g. "nginx" or "bitnami/redis".
+const DockerHub = "docker.io"
+
+// Ref is a normalized image reference.
+type Ref struct {
+	Registry   string // e.g. "docker.io", "ghcr.io", "registry.local:5000"
+	Repository string // e.g. "library/nginx", "acme/api"
+	Tag        string // "latest" when neither a tag nor a digest is given
+	Digest     string // e.g. "sha256:abcd"; empty unless pinned
+}
+
+// String returns the reference in full, registry/repository:tag@digest,
+// leaving out the parts that are empty.
+func (r Ref) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// Parse normalizes image: the registry defaults to Docker Hub, official
+// Docker Hub images get the "library/" prefix, and a reference with
+// neither tag nor digest means the "latest" tag.
+func Parse(image string) Ref {
+	var r Ref
+	name, digest, _ := strings.Cut(image, "@")
+	r.Digest = digest
+
+	r.Registry = Registry(name)
+	repo := name
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		repo = rest
+	}
+	// A ':' after the last '/' starts the tag; one before it is a port
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo, r.Tag = repo[:i], repo[i+1:]
+	}
+	if r.Registry == DockerHub && !strings.Contains(repo, "/") {
+		repo = "library/" + repo
+	}
+	r.Repository = repo
+	if r.Tag == "" && r.Digest == "" {
+		r.Tag = "latest"
+	}
+	return r
+}
+
+// Registry returns the registry host of an image reference using the
+// Docker normalization rules: the first path component is a registry only
+// if it contains "." or ":" or is "localhost"; otherwise the image is on
+// Docker Hub. The legacy Docker Hub hosts are normalized to docker.io.
+func Registry(image string) string {
+	first, _, hasPath := strings.Cut(image, "/")
+	if !hasPath || (!strings.ContainsAny(first, ".:") && first != "localhost") {
+		return DockerHub
+	}
+	switch first {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return DockerHub
+	}
+	return strings.ToLower(first)
+}
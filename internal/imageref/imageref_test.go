@@ -0,0 +1,38 @@
+package imageref
+
+import "testing"
+
+func TestRegistry(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                               "docker.io",
+		"nginx:1.27":                          "docker.io",
+		"bitnami/redis:7":                     "docker.io",
+		"docker.io/library/nginx":             "docker.io",
+		"index.docker.io/library/nginx":       "docker.io",
+		"ghcr.io/acme/api:v2":                 "ghcr.io",
+		"localhost/app":                       "localhost",
+		"registry.local:5000/app@sha256:abcd": "registry.local:5000",
+	}
+	for image, want := range tests {
+		if got := Registry(image); got != want {
+			t.Errorf("Registry(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                               "docker.io/library/nginx:latest",
+		"nginx:1.27":                          "docker.io/library/nginx:1.27",
+		"bitnami/redis:7":                     "docker.io/bitnami/redis:7",
+		"index.docker.io/library/nginx":       "docker.io/library/nginx:latest",
+		"ghcr.io/acme/api:v2@sha256:abcd":     "ghcr.io/acme/api:v2@sha256:abcd",
+		"registry.local:5000/app@sha256:abcd": "registry.local:5000/app@sha256:abcd",
+		"registry.local:5000/team/app":        "registry.local:5000/team/app:latest",
+	}
+	for image, want := range tests {
+		if got := Parse(image).String(); got != want {
+			t.Errorf("Parse(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
@@ -11,3 +11,9 @@ type Analyzer interface {
 	Name() string
 	Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error)
 }
+
+// clusterScopedAnalyzer is implemented by analyzers of cluster-scoped
+// resources. The engine runs them once per pass with an empty namespace.
+type clusterScopedAnalyzer interface {
+	clusterScoped()
+}
@@ -1,16 +1,46 @@
 package insights
 
 import (
+	"bufio"
 	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func int32Ptr(i int32) *int32 { return &i }
@@ -389,5 +419,1768 @@ func TestCrashloopingAnalyzer(t *testing.T) {
 	}
 }
 
+func TestCrashloopingAnalyzerInitContainer(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate-pod", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:         "db-migrate",
+						RestartCount: 7,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+						},
+					},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "app",
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"},
+						},
+					},
+				},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "crash-pod", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:         "app",
+						RestartCount: 12,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	insights, err := NewCrashloopingAnalyzer().Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d: %+v", len(insights), insights)
+	}
+
+	var initInsight *ClusterInsight
+	for i := range insights {
+		if strings.Contains(insights[i].Title, "init container") {
+			initInsight = &insights[i]
+		}
+	}
+	if initInsight == nil {
+		t.Fatal("expected an init-container insight")
+	}
+	if initInsight.TargetName != "migrate-pod" || initInsight.Severity != "action" {
+		t.Errorf("unexpected init insight: %+v", initInsight)
+	}
+	for _, want := range []string{`"db-migrate"`, "7 times", "CrashLoopBackOff"} {
+		if !strings.Contains(initInsight.Title+initInsight.Description, want) {
+			t.Errorf("init insight missing %q: %s / %s", want, initInsight.Title, initInsight.Description)
+		}
+	}
+	if initInsight.Fingerprint == MakeFingerprint("crashlooping", "Pod", "default", "migrate-pod") {
+		t.Error("init-container insight should have its own fingerprint")
+	}
+}
+
+func TestHelmChartDriftAnalyzer(t *testing.T) {
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		fetches++
+		w.Write([]byte(`apiVersion: v1
+entries:
+  podinfo:
+  - version: 6.7.0
+  - version: 6.8.0-rc.1
+  - version: 6.5.4
+  redis:
+  - version: 19.0.0
+`))
+	}))
+	defer srv.Close()
+
+	repo := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "source.toolkit.fluxcd.io/v1",
+		"kind":       "HelmRepository",
+		"metadata":   map[string]interface{}{"name": "charts", "namespace": "flux-system"},
+		"spec":       map[string]interface{}{"url": srv.URL},
+	}}
+	helmRelease := func(name, chart, version string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "helm.toolkit.fluxcd.io/v2",
+			"kind":       "HelmRelease",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"spec": map[string]interface{}{
+				"chart": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"chart": chart,
+						"sourceRef": map[string]interface{}{
+							"kind": "HelmRepository", "name": "charts", "namespace": "flux-system",
+						},
+					},
+				},
+			},
+			"status": map[string]interface{}{
+				"history": []interface{}{
+					map[string]interface{}{"chartName": chart, "chartVersion": version},
+				},
+			},
+		}}
+	}
+
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			helmReleaseGVR:    "HelmReleaseList",
+			helmRepositoryGVR: "HelmRepositoryList",
+		},
+		repo,
+		helmRelease("podinfo", "podinfo", "6.5.4"),
+		helmRelease("redis", "redis", "19.0.0"),
+	)
+
+	a := NewHelmChartDriftAnalyzer(dynClient)
+	insights, err := a.Analyze(context.Background(), fake.NewSimpleClientset(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(insights) != 1 {
+		t.Fatalf("expected 1 insight, got %d", len(insights))
+	}
+	if insights[0].TargetName != "podinfo" {
+		t.Errorf("expected podinfo, got %s", insights[0].TargetName)
+	}
+	if insights[0].Severity != "suggestion" || insights[0].Category != "hygiene" {
+		t.Errorf("expected hygiene suggestion, got %s/%s", insights[0].Category, insights[0].Severity)
+	}
+	if fetches != 1 {
+		t.Errorf("expected index fetched once, got %d", fetches)
+	}
+}
+
+func TestHelmChartDriftFetchSingleFlight(t *testing.T) {
+	var fetches atomic.Int32
+	started, release := make(chan struct{}, 5), make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		started <- struct{}{}
+		<-release
+		w.Write([]byte("entries: {}\n"))
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("entries: {}\n"))
+	}))
+	defer fast.Close()
+
+	a := NewHelmChartDriftAnalyzer(nil).(*helmChartDriftAnalyzer)
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			_, err := a.fetchIndex(context.Background(), slow.URL)
+			errs <- err
+		}()
+	}
+
+	// Another repo is fetched while the slow one is still in flight
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := a.fetchIndex(ctx, fast.URL); err != nil {
+		t.Fatalf("fetch of another repo blocked behind the slow one: %v", err)
+	}
+
+	close(release)
+	for i := 0; i < 5; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("expected one fetch for concurrent callers, got %d", n)
+	}
+}
+
+func TestHelmChartDriftFetchErrorCaching(t *testing.T) {
+	var fetches atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("entries: {}\n"))
+	}))
+	defer srv.Close()
+
+	a := NewHelmChartDriftAnalyzer(nil).(*helmChartDriftAnalyzer)
+
+	// A cancelled fetch is not remembered
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.fetchIndex(cancelled, srv.URL); err == nil {
+		t.Fatal("expected an error from a cancelled fetch")
+	}
+	if _, cached := a.cache[srv.URL]; cached {
+		t.Error("cancelled fetch should not be cached")
+	}
+
+	// A failure is remembered only briefly
+	if _, err := a.fetchIndex(context.Background(), srv.URL); err == nil {
+		t.Fatal("expected an error while the repository is failing")
+	}
+	if _, err := a.fetchIndex(context.Background(), srv.URL); err == nil || fetches.Load() != 1 {
+		t.Fatalf("failure should be served from cache within its TTL (fetches %d, err %v)", fetches.Load(), err)
+	}
+	failing.Store(false)
+	a.cache[srv.URL].expires = time.Now().Add(-time.Second)
+	if _, err := a.fetchIndex(context.Background(), srv.URL); err != nil {
+		t.Fatalf("expected a refetch once the failure expired: %v", err)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("expected 2 fetches, got %d", n)
+	}
+}
+
+func TestHelmChartDriftListErrors(t *testing.T) {
+	newClient := func(err error) *dynamicfake.FakeDynamicClient {
+		c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{helmReleaseGVR: "HelmReleaseList"})
+		c.PrependReactor("list", "helmreleases", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, err
+		})
+		return c
+	}
+
+	notInstalled := apierrors.NewNotFound(helmReleaseGVR.GroupResource(), "")
+	if _, err := NewHelmChartDriftAnalyzer(newClient(notInstalled)).Analyze(context.Background(), fake.NewSimpleClientset(), "default"); err != nil {
+		t.Errorf("missing Flux CRDs should not be an error: %v", err)
+	}
+	forbidden := apierrors.NewForbidden(helmReleaseGVR.GroupResource(), "", fmt.Errorf("denied"))
+	if _, err := NewHelmChartDriftAnalyzer(newClient(forbidden)).Analyze(context.Background(), fake.NewSimpleClientset(), "default"); !apierrors.IsForbidden(err) {
+		t.Errorf("expected the list error to be returned, got %v", err)
+	}
+}
+
 // Suppress unused import warnings
 var _ = intstr.FromInt32
+
+func TestLogSampler(t *testing.T) {
+	labels := map[string]string{"app": "api"}
+	restarting := func(name string, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "sidecar", Ready: true},
+					{Name: "api", RestartCount: restarts, State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					}},
+				},
+			},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(2),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+			},
+			Status: appsv1.DeploymentStatus{ReadyReplicas: 0},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(1),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "worker"}},
+			},
+		},
+		restarting("api-a", 1),
+		restarting("api-b", 7),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker-a", Namespace: "default", Labels: map[string]string{"app": "worker"}}},
+	)
+
+	// The fake clientset always serves "fake logs"; record the requests instead
+	var logRequests []*corev1.PodLogOptions
+	clientset.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "log" {
+			return false, nil, nil
+		}
+		logRequests = append(logRequests, action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions))
+		return true, &corev1.Pod{}, nil
+	})
+
+	pod, err := findSamplePod(context.Background(), clientset, "Deployment", "default", "api")
+	if err != nil || pod == nil || pod.Name != "api-b" {
+		t.Fatalf("findSamplePod = %v, %v; want api-b (most restarts)", pod, err)
+	}
+
+	insights, err := NewUnreadyWorkloadsAnalyzer().Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	insights = append(insights, ClusterInsight{Analyzer: "missing_limits", TargetKind: "Deployment", TargetNS: "default", TargetName: "api"})
+
+	sampler := NewLogSampler(LogSampleOptions{
+		TailLines: 50,
+		MaxPods:   1,
+		Patterns:  []*regexp.Regexp{regexp.MustCompile(`fake`)},
+	})
+	sampler.Sample(context.Background(), clientset, insights)
+
+	if len(logRequests) != 1 {
+		t.Fatalf("expected 1 log request (pod cap), got %d", len(logRequests))
+	}
+	if logRequests[0].TailLines == nil || *logRequests[0].TailLines != 50 {
+		t.Errorf("expected TailLines=50, got %v", logRequests[0].TailLines)
+	}
+	if logRequests[0].LimitBytes == nil {
+		t.Error("expected LimitBytes to be set")
+	}
+	if logRequests[0].Container != "api" || logRequests[0].Previous {
+		t.Errorf("unready sample: container=%q previous=%v, want api from the current run", logRequests[0].Container, logRequests[0].Previous)
+	}
+	if insights[0].ErrorSampleCount != 1 || insights[0].ErrorSample != "fake logs" {
+		t.Errorf("expected sample attached to first insight, got count=%d sample=%q", insights[0].ErrorSampleCount, insights[0].ErrorSample)
+	}
+	if insights[2].ErrorSampleCount != 0 {
+		t.Error("insights from other analyzers should not be sampled")
+	}
+}
+
+func TestLogSamplerCrashloopPreviousContainer(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-x", Namespace: "default"},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{Name: "migrate", RestartCount: 6,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+			}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app"}},
+		},
+	})
+	var logRequests []*corev1.PodLogOptions
+	clientset.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "log" {
+			return false, nil, nil
+		}
+		logRequests = append(logRequests, action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions))
+		return true, &corev1.Pod{}, nil
+	})
+
+	NewLogSampler(LogSampleOptions{}).Sample(context.Background(), clientset, []ClusterInsight{
+		{Analyzer: "crashlooping", TargetKind: "Pod", TargetNS: "default", TargetName: "job-x"},
+	})
+	if len(logRequests) != 1 {
+		t.Fatalf("expected 1 log request, got %d", len(logRequests))
+	}
+	if logRequests[0].Container != "migrate" || !logRequests[0].Previous {
+		t.Errorf("crashloop sample: container=%q previous=%v, want migrate from the previous run", logRequests[0].Container, logRequests[0].Previous)
+	}
+}
+
+func TestCountErrorLines(t *testing.T) {
+	logs := `2024-01-01T00:00:00Z INFO starting server
+2024-01-01T00:00:01Z ERROR failed to connect to db: connection refused
+level=error msg="retrying"
+{"level":"error","msg":"timeout"}
+panic: runtime error: nil pointer dereference
+errorless line with terror
+`
+	count, excerpt := countErrorLines(bufio.NewScanner(strings.NewReader(logs)), defaultErrorPatterns)
+	if count != 4 {
+		t.Errorf("expected 4 error lines, got %d", count)
+	}
+	if lines := strings.Split(excerpt, "\n"); len(lines) != maxExcerptLines {
+		t.Errorf("expected excerpt capped at %d lines, got %d", maxExcerptLines, len(lines))
+	}
+
+	long := "ERROR " + strings.Repeat("é", maxExcerptLineLen)
+	_, excerpt = countErrorLines(bufio.NewScanner(strings.NewReader(long)), defaultErrorPatterns)
+	if !utf8.ValidString(excerpt) || !strings.HasSuffix(excerpt, "…") || len(excerpt) > maxExcerptLineLen+len("…") {
+		t.Errorf("long line should be cut on a rune boundary, got %q", excerpt)
+	}
+}
+
+func TestOrphanedPVAnalyzer(t *testing.T) {
+	pv := func(name string, phase corev1.PersistentVolumePhase, policy corev1.PersistentVolumeReclaimPolicy) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:                      corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				PersistentVolumeReclaimPolicy: policy,
+				ClaimRef:                      &corev1.ObjectReference{Namespace: "default", Name: name + "-claim"},
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-" + name},
+				},
+			},
+			Status: corev1.PersistentVolumeStatus{Phase: phase},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		pv("released-retain", corev1.VolumeReleased, corev1.PersistentVolumeReclaimRetain),
+		pv("bound-retain", corev1.VolumeBound, corev1.PersistentVolumeReclaimRetain),
+		pv("released-delete", corev1.VolumeReleased, corev1.PersistentVolumeReclaimDelete),
+	)
+
+	a := NewOrphanedPVAnalyzer()
+	insights, err := a.Analyze(context.Background(), clientset, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(insights) != 1 {
+		t.Fatalf("expected 1 insight, got %d", len(insights))
+	}
+	ins := insights[0]
+	if ins.TargetName != "released-retain" || ins.TargetKind != "PersistentVolume" {
+		t.Errorf("unexpected target %s/%s", ins.TargetKind, ins.TargetName)
+	}
+	if ins.Category != "hygiene" || ins.Severity != "suggestion" {
+		t.Errorf("expected hygiene suggestion, got %s %s", ins.Category, ins.Severity)
+	}
+	if !strings.Contains(ins.Description, "vol-released-retain") {
+		t.Errorf("description should include volume handle: %s", ins.Description)
+	}
+	if ins.AutoRemediable || ins.ProposedAction != "" {
+		t.Error("orphaned PVs must not be auto-remediable")
+	}
+}
+
+func TestOrphanedEndpointsAnalyzer(t *testing.T) {
+	isController := true
+	slice := func(name string, labels map[string]string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.7"}}},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		// Properly owned
+		slice("web-abc12", map[string]string{discoveryv1.LabelServiceName: "web"}),
+		&discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{
+			Name: "web-def34", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Service", Name: "web", Controller: &isController}},
+		}},
+		// The endpoints controller ties Endpoints to a Service by name only
+		&corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		// Service deleted, slice and Endpoints left behind
+		slice("api-xyz89", map[string]string{discoveryv1.LabelServiceName: "api"}),
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.7"}}}},
+		},
+		// Managed by hand: no service owner
+		slice("external-db", nil),
+		// Leader election lock, not a Service's endpoints
+		&corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{
+			Name: "kube-scheduler", Namespace: "default",
+			Annotations: map[string]string{"control-plane.alpha.kubernetes.io/leader": `{"holderIdentity":"cp-1"}`},
+		}},
+	)
+
+	insights, err := NewOrphanedEndpointsAnalyzer().Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d: %+v", len(insights), insights)
+	}
+	if ep := insights[1]; ep.TargetKind != "Endpoints" || ep.TargetName != "api" || !strings.Contains(ep.Description, "1 address(es)") {
+		t.Errorf("unexpected Endpoints insight: %+v", ep)
+	}
+	ins := insights[0]
+	if ins.TargetKind != "EndpointSlice" || ins.TargetName != "api-xyz89" || ins.TargetNS != "default" {
+		t.Errorf("unexpected target %s %s/%s", ins.TargetKind, ins.TargetNS, ins.TargetName)
+	}
+	if ins.Category != "hygiene" || ins.Severity != "warning" {
+		t.Errorf("expected hygiene warning, got %s %s", ins.Category, ins.Severity)
+	}
+	if !strings.Contains(ins.Description, `Service "api"`) {
+		t.Errorf("description should name the missing Service: %s", ins.Description)
+	}
+}
+
+func TestEngineRunsClusterScopedAnalyzersOnce(t *testing.T) {
+	released := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec:       corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain},
+		Status:     corev1.PersistentVolumeStatus{Phase: corev1.VolumeReleased},
+	}
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+		released,
+	)
+
+	e := &Engine{analyzers: []Analyzer{NewOrphanedPVAnalyzer()}, log: slog.Default()}
+	insights := e.Analyze(context.Background(), clientset)
+	if len(insights) != 1 {
+		t.Fatalf("expected cluster-scoped analyzer to run once, got %d insights", len(insights))
+	}
+}
+
+func TestEngineNamespaceFilter(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ci-runner-1"}},
+	)
+	e := &Engine{analyzers: []Analyzer{NewEvictedPodAnalyzer()}, log: slog.Default()}
+	e.SetNamespaceFilter(func(ns string) bool { return strings.HasPrefix(ns, "ci-") })
+	e.Analyze(context.Background(), clientset)
+
+	var listed []string
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "list" && action.GetResource().Resource == "pods" {
+			listed = append(listed, action.GetNamespace())
+		}
+	}
+	slices.Sort(listed)
+	if !slices.Equal(listed, []string{"default", "team-a"}) {
+		t.Errorf("analyzed namespaces = %v, want [default team-a]", listed)
+	}
+}
+
+// stubAnalyzer returns canned results for engine tests.
+type stubAnalyzer struct {
+	name     string
+	insights []ClusterInsight
+	err      error
+}
+
+func (s *stubAnalyzer) Name() string { return s.name }
+
+func (s *stubAnalyzer) Analyze(context.Context, kubernetes.Interface, string) ([]ClusterInsight, error) {
+	return s.insights, s.err
+}
+
+func TestEngineAnalyzerSummary(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+	)
+	e := &Engine{
+		analyzers: []Analyzer{
+			&stubAnalyzer{name: "broken", err: errors.New("forbidden")},
+			&stubAnalyzer{name: "working", insights: []ClusterInsight{{Analyzer: "working", Severity: "info"}}},
+		},
+		log: slog.Default(),
+	}
+
+	insights, summary := e.AnalyzeWithSummary(context.Background(), clientset)
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights (one per namespace), got %d", len(insights))
+	}
+	if len(summary) != 2 {
+		t.Fatalf("expected 2 summary entries, got %d", len(summary))
+	}
+
+	broken := summary[0]
+	if broken.Analyzer != "broken" || broken.Status != AnalyzerStatusError || broken.InsightCount != 0 {
+		t.Errorf("unexpected summary for broken analyzer: %+v", broken)
+	}
+	if !strings.Contains(broken.Error, "forbidden") || !strings.Contains(broken.Error, "1 more") {
+		t.Errorf("expected error with failure count, got %q", broken.Error)
+	}
+
+	working := summary[1]
+	if working.Analyzer != "working" || working.Status != AnalyzerStatusOK || working.InsightCount != 2 || working.Error != "" {
+		t.Errorf("unexpected summary for working analyzer: %+v", working)
+	}
+}
+
+func TestColocationAnalyzer(t *testing.T) {
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	pod := func(name, app, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	deploy := func(name, app string, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(replicas),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+			},
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		node("node-a"), node("node-b"),
+		deploy("colocated", "web", 2),
+		pod("web-1", "web", "node-a"),
+		pod("web-2", "web", "node-a"),
+		deploy("spread", "api", 2),
+		pod("api-1", "api", "node-a"),
+		pod("api-2", "api", "node-b"),
+		deploy("single", "worker", 1),
+		pod("worker-1", "worker", "node-a"),
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: int32Ptr(3),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			},
+		},
+		pod("db-0", "db", "node-b"),
+		pod("db-1", "db", "node-b"),
+		pod("db-2", "db", "node-b"),
+	)
+
+	a := NewColocationAnalyzer()
+	insights, err := a.Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d: %+v", len(insights), insights)
+	}
+	if insights[0].TargetName != "colocated" || insights[0].TargetKind != "Deployment" {
+		t.Errorf("expected Deployment colocated, got %s %s", insights[0].TargetKind, insights[0].TargetName)
+	}
+	if insights[1].TargetName != "db" || insights[1].TargetKind != "StatefulSet" {
+		t.Errorf("expected StatefulSet db, got %s %s", insights[1].TargetKind, insights[1].TargetName)
+	}
+	if insights[0].Category != "reliability" || insights[0].Severity != "suggestion" {
+		t.Errorf("unexpected category/severity: %s/%s", insights[0].Category, insights[0].Severity)
+	}
+
+	// Single-node clusters can't spread replicas, so nothing is reported
+	single := fake.NewSimpleClientset(node("only"), deploy("colocated", "web", 2),
+		pod("web-1", "web", "only"), pod("web-2", "web", "only"))
+	insights, err = a.Analyze(context.Background(), single, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 0 {
+		t.Errorf("expected no insights on a single-node cluster, got %d", len(insights))
+	}
+}
+
+func TestDockerHubRateLimitAnalyzer(t *testing.T) {
+	deploy := func(name, image string, secrets ...string) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+				},
+			},
+		}
+		for _, s := range secrets {
+			d.Spec.Template.Spec.ImagePullSecrets = append(d.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: s})
+		}
+		return d
+	}
+
+	clientset := fake.NewSimpleClientset(
+		deploy("hub-anonymous", "nginx:1.27"),
+		deploy("hub-with-secret", "bitnami/redis:7", "dockerhub-creds"),
+		deploy("private-registry", "ghcr.io/acme/api:v2"),
+		deploy("explicit-hub", "registry-1.docker.io/library/postgres:16"),
+	)
+
+	a := NewDockerHubRateLimitAnalyzer()
+	insights, err := a.Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flagged := make(map[string]bool)
+	for _, ins := range insights {
+		flagged[ins.TargetName] = true
+		if ins.Category != "reliability" || ins.Severity != "suggestion" {
+			t.Errorf("%s: unexpected category/severity %s/%s", ins.TargetName, ins.Category, ins.Severity)
+		}
+	}
+	if len(insights) != 2 || !flagged["hub-anonymous"] || !flagged["explicit-hub"] {
+		t.Errorf("expected hub-anonymous and explicit-hub to be flagged, got %v", flagged)
+	}
+
+	// A pull secret on the service account covers the pod too
+	withSA := fake.NewSimpleClientset(
+		deploy("hub-anonymous", "nginx:1.27"),
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "default", Namespace: "default"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "dockerhub-creds"}},
+		},
+	)
+	insights, err = a.Analyze(context.Background(), withSA, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 0 {
+		t.Errorf("expected no insights with a service account pull secret, got %d", len(insights))
+	}
+}
+
+func TestDrainRiskAnalyzer(t *testing.T) {
+	isController := true
+	owned := func(kind, name string, uid string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, UID: types.UID(uid), Controller: &isController}}
+	}
+	pod := func(name, node string, owners []metav1.OwnerReference, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: owners, Labels: labels},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		// Single-replica Deployment pinned to the target node
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "db-7f9c", Namespace: "default", UID: "rs-db",
+			OwnerReferences: owned("Deployment", "db", "deploy-db")}},
+		pod("db-7f9c-abcde", "node-a", owned("ReplicaSet", "db-7f9c", "rs-db"), nil),
+		// Spread workload with a ready replica on another node
+		pod("web-0", "node-a", owned("StatefulSet", "web", "sts-web"), nil),
+		pod("web-1", "node-b", owned("StatefulSet", "web", "sts-web"), nil),
+		// Replicated, but its PDB is at minimum
+		pod("api-0", "node-a", owned("StatefulSet", "api", "sts-api"), map[string]string{"app": "api"}),
+		pod("api-1", "node-b", owned("StatefulSet", "api", "sts-api"), map[string]string{"app": "api"}),
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-pdb", Namespace: "default"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+			Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+		},
+		// DaemonSet pods are left in place by drain
+		pod("agent-xyz", "node-a", owned("DaemonSet", "agent", "ds-agent"), nil),
+	)
+
+	a := NewDrainRiskAnalyzer("node-a")
+	insights, err := a.Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byTarget := make(map[string]ClusterInsight)
+	for _, ins := range insights {
+		byTarget[ins.TargetKind+"/"+ins.TargetName] = ins
+	}
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d: %v", len(insights), byTarget)
+	}
+	db, ok := byTarget["Deployment/db"]
+	if !ok {
+		t.Fatal("expected single-replica Deployment db to be flagged")
+	}
+	if db.Severity != "action" || !strings.Contains(db.Description, "no ready replicas") {
+		t.Errorf("db insight = %+v", db)
+	}
+	api, ok := byTarget["StatefulSet/api"]
+	if !ok || !strings.Contains(api.Description, "api-pdb") {
+		t.Errorf("expected api to be flagged for its PDB, got %+v", api)
+	}
+
+	// Without an explicit node only cordoned nodes are considered
+	insights, err = NewDrainRiskAnalyzer("").Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 0 {
+		t.Errorf("expected no insights with no cordoned nodes, got %d", len(insights))
+	}
+}
+
+func TestConflictingManagersAnalyzer(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		// Applied by a Flux Kustomization and also installed as a Helm release
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "api", Namespace: "default",
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by":          "Helm",
+				"kustomize.toolkit.fluxcd.io/name":      "apps",
+				"kustomize.toolkit.fluxcd.io/namespace": "flux-system",
+			},
+			Annotations: map[string]string{
+				"meta.helm.sh/release-name":      "api",
+				"meta.helm.sh/release-namespace": "default",
+			},
+		}},
+		// Flux HelmRelease: helm-controller drives Helm, a single manager
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "web", Namespace: "default",
+			Labels: map[string]string{"helm.toolkit.fluxcd.io/name": "web"},
+			Annotations: map[string]string{
+				"meta.helm.sh/release-name": "web",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "helm-controller", Operation: metav1.ManagedFieldsOperationUpdate},
+				{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate},
+			},
+		}},
+		// Helm-installed, then patched with kubectl apply
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "db", Namespace: "default",
+			Annotations: map[string]string{"meta.helm.sh/release-name": "db"},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "helm", Operation: metav1.ManagedFieldsOperationUpdate},
+				{Manager: "kubectl-client-side-apply", Operation: metav1.ManagedFieldsOperationUpdate},
+			},
+		}},
+	)
+
+	insights, err := NewConflictingManagersAnalyzer().Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d: %+v", len(insights), insights)
+	}
+
+	byName := make(map[string]ClusterInsight)
+	for _, i := range insights {
+		byName[i.TargetName] = i
+	}
+	api, ok := byName["api"]
+	if !ok {
+		t.Fatal("expected insight for Helm + Flux deployment")
+	}
+	if api.Category != "hygiene" || api.Severity != "warning" || api.TargetKind != "Deployment" {
+		t.Errorf("unexpected insight: %+v", api)
+	}
+	for _, want := range []string{`Helm release "api"`, `Flux Kustomization "flux-system/apps"`} {
+		if !strings.Contains(api.Description, want) {
+			t.Errorf("description missing %q: %s", want, api.Description)
+		}
+	}
+	if db := byName["db"]; !strings.Contains(db.Description, "kubectl apply") {
+		t.Errorf("db description = %q, want kubectl apply listed", db.Description)
+	}
+	if _, ok := byName["web"]; ok {
+		t.Error("Flux-driven Helm release should count as a single manager")
+	}
+}
+
+func TestStuckNamespaceAnalyzer(t *testing.T) {
+	terminating := func(name string, since time.Duration, conds ...corev1.NamespaceCondition) *corev1.Namespace {
+		deleted := metav1.NewTime(time.Now().Add(-since))
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, DeletionTimestamp: &deleted, Finalizers: []string{"kubernetes"}},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating, Conditions: conds},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		terminating("old-tenant", 3*time.Hour, corev1.NamespaceCondition{
+			Type:    corev1.NamespaceFinalizersRemaining,
+			Status:  corev1.ConditionTrue,
+			Message: "Some content in the namespace has finalizers remaining: example.com/cleanup in 1 resource instances",
+		}, corev1.NamespaceCondition{
+			Type:    corev1.NamespaceDeletionDiscoveryFailure,
+			Status:  corev1.ConditionFalse,
+			Message: "All resources successfully discovered",
+		}),
+		terminating("no-conditions", time.Hour),
+		terminating("just-deleted", time.Minute),
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		},
+	)
+
+	a := NewStuckNamespaceAnalyzer()
+	if _, ok := a.(clusterScopedAnalyzer); !ok {
+		t.Fatal("stuck_namespace should be cluster-scoped")
+	}
+	insights, err := a.Analyze(context.Background(), clientset, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d: %+v", len(insights), insights)
+	}
+
+	byName := map[string]ClusterInsight{}
+	for _, ins := range insights {
+		byName[ins.TargetName] = ins
+	}
+	ins, ok := byName["old-tenant"]
+	if !ok {
+		t.Fatalf("old-tenant not flagged: %+v", insights)
+	}
+	if ins.TargetKind != "Namespace" || ins.Severity != "action" {
+		t.Errorf("expected Namespace action, got %s %s", ins.TargetKind, ins.Severity)
+	}
+	if ins.AutoRemediable || ins.ProposedAction != "" {
+		t.Error("stuck namespaces must not be auto-remediable")
+	}
+	if !strings.Contains(ins.Description, "example.com/cleanup") {
+		t.Errorf("description should name the remaining finalizer: %s", ins.Description)
+	}
+	if strings.Contains(ins.Description, "successfully discovered") {
+		t.Errorf("description should skip false conditions: %s", ins.Description)
+	}
+	if !strings.Contains(byName["no-conditions"].Description, "finalizer") {
+		t.Errorf("description without conditions should point at finalizers: %s", byName["no-conditions"].Description)
+	}
+}
+
+func TestFailedHelmReleaseAnalyzer(t *testing.T) {
+	release := func(name string, rev int, status string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, rev),
+				Namespace: "apps",
+				Labels:    map[string]string{"owner": "helm", "name": name, "version": fmt.Sprint(rev), "status": status},
+			},
+			Type: "helm.sh/release.v1",
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		release("api", 1, "superseded"),
+		release("api", 2, "failed"),
+		release("web", 1, "failed"),
+		release("web", 2, "deployed"),
+		release("worker", 3, "pending-upgrade"),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-creds", Namespace: "apps", Labels: map[string]string{"owner": "helm", "status": "failed"}},
+			Type:       corev1.SecretTypeOpaque,
+		},
+	)
+
+	insights, err := NewFailedHelmReleaseAnalyzer().Analyze(context.Background(), clientset, "apps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d: %+v", len(insights), insights)
+	}
+
+	api, worker := insights[0], insights[1]
+	if api.TargetName != "api" || worker.TargetName != "worker" {
+		t.Fatalf("expected api and worker, got %s and %s", api.TargetName, worker.TargetName)
+	}
+	if api.Category != "reliability" || api.Severity != "warning" || api.TargetKind != "HelmRelease" || api.TargetNS != "apps" {
+		t.Errorf("unexpected api insight: %+v", api)
+	}
+	if !strings.Contains(api.Title, "failed") || !strings.Contains(api.Title, "revision 2") {
+		t.Errorf("title should carry status and revision: %s", api.Title)
+	}
+	if worker.Severity != "action" || !strings.Contains(worker.Description, `"pending-upgrade"`) {
+		t.Errorf("pending release should be an action naming its status: %+v", worker)
+	}
+}
+
+func TestExposedDatabaseAnalyzer(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "redis", Namespace: "cache"},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{{Port: 6379, TargetPort: intstr.FromInt32(6379), NodePort: 30379}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "cache"},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeClusterIP,
+				Ports: []corev1.ServicePort{{Port: 5432}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "cache"},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Port: 443, TargetPort: intstr.FromInt32(8443)}},
+			},
+		},
+	)
+
+	insights, err := NewExposedDatabaseAnalyzer().Analyze(context.Background(), clientset, "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 1 {
+		t.Fatalf("expected 1 insight, got %d: %+v", len(insights), insights)
+	}
+	ins := insights[0]
+	if ins.TargetName != "redis" || ins.TargetKind != "Service" {
+		t.Errorf("expected the redis Service, got %s %s", ins.TargetKind, ins.TargetName)
+	}
+	if ins.Category != "security" || ins.Severity != "action" {
+		t.Errorf("expected security action, got %s/%s", ins.Category, ins.Severity)
+	}
+	if !strings.Contains(ins.Title, "Redis (6379)") {
+		t.Errorf("title should name the engine and port: %s", ins.Title)
+	}
+}
+
+func TestBadStorageClassAnalyzer(t *testing.T) {
+	class := func(name string) *string { return &name }
+	pvc := func(name string, sc *string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "data"},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: sc},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		}
+	}
+	bound := pvc("bound-old", class("retired"))
+	bound.Status.Phase = corev1.ClaimBound
+
+	clientset := fake.NewSimpleClientset(
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fast-ssd"}},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-manual"},
+			Spec:       corev1.PersistentVolumeSpec{StorageClassName: "manual"},
+		},
+		pvc("typo", class("fast-sdd")),
+		pvc("existing", class("fast-ssd")),
+		pvc("default", nil),
+		pvc("no-class", class("")),
+		pvc("static", class("manual")),
+		bound,
+	)
+
+	insights, err := NewBadStorageClassAnalyzer().Analyze(context.Background(), clientset, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 1 {
+		t.Fatalf("expected 1 insight, got %d: %+v", len(insights), insights)
+	}
+	ins := insights[0]
+	if ins.TargetName != "typo" || ins.TargetKind != "PersistentVolumeClaim" || ins.Severity != "action" {
+		t.Errorf("unexpected insight: %+v", ins)
+	}
+	if !strings.Contains(ins.Title, `"fast-sdd"`) {
+		t.Errorf("title should name the missing class: %s", ins.Title)
+	}
+}
+
+func TestBrokenCronJobAnalyzer(t *testing.T) {
+	now := time.Now()
+	cronJob := func(name, schedule string, created time.Time, lastScheduled *time.Time) *batchv1.CronJob {
+		cj := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "batch", UID: types.UID(name),
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: batchv1.CronJobSpec{Schedule: schedule, FailedJobsHistoryLimit: int32Ptr(3)},
+		}
+		if lastScheduled != nil {
+			ts := metav1.NewTime(*lastScheduled)
+			cj.Status.LastScheduleTime = &ts
+		}
+		return cj
+	}
+	isController := true
+	job := func(cronJob string, hoursAgo int, result batchv1.JobConditionType) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("%s-%d", cronJob, hoursAgo),
+				Namespace:         "batch",
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Duration(hoursAgo) * time.Hour)),
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "CronJob", Name: cronJob, UID: types.UID(cronJob), Controller: &isController},
+				},
+			},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: result, Status: corev1.ConditionTrue}}},
+		}
+	}
+	lastRun := now.Add(-time.Hour)
+	suspended := cronJob("paused", "0 * * * *", now.Add(-48*time.Hour), &lastRun)
+	suspend := true
+	suspended.Spec.Suspend = &suspend
+	// Keeps only its newest failed Job: too few runs to judge
+	sync := cronJob("sync", "0 * * * *", now.Add(-48*time.Hour), &lastRun)
+	sync.Spec.FailedJobsHistoryLimit = int32Ptr(1)
+
+	clientset := fake.NewSimpleClientset(
+		// Every recent run failed
+		cronJob("report", "0 * * * *", now.Add(-48*time.Hour), &lastRun),
+		job("report", 1, batchv1.JobFailed),
+		job("report", 2, batchv1.JobFailed),
+		job("report", 3, batchv1.JobFailed),
+		job("report", 4, batchv1.JobComplete),
+		// Recovered: the newest run succeeded
+		cronJob("backup", "0 * * * *", now.Add(-48*time.Hour), &lastRun),
+		job("backup", 1, batchv1.JobComplete),
+		job("backup", 2, batchv1.JobFailed),
+		job("backup", 3, batchv1.JobFailed),
+		// Failing, but suspended
+		suspended,
+		job("paused", 1, batchv1.JobFailed),
+		job("paused", 2, batchv1.JobFailed),
+		job("paused", 3, batchv1.JobFailed),
+		sync,
+		job("sync", 1, batchv1.JobFailed),
+		// Daily schedule, never run in ten days
+		cronJob("cleanup", "30 2 * * *", now.Add(-240*time.Hour), nil),
+		// Monthly schedule: cannot tell yet
+		cronJob("invoice", "0 0 1 * *", now.Add(-240*time.Hour), nil),
+	)
+
+	insights, err := NewBrokenCronJobAnalyzer(0).Analyze(context.Background(), clientset, "batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d: %+v", len(insights), insights)
+	}
+	report, cleanup := insights[0], insights[1]
+	if report.TargetName == "cleanup" {
+		report, cleanup = cleanup, report
+	}
+	if report.TargetName != "report" || !strings.Contains(report.Title, "keeps failing") || !strings.Contains(report.Description, `"report-1"`) {
+		t.Errorf("unexpected failing insight: %+v", report)
+	}
+	if report.Category != "reliability" || report.Severity != "warning" || report.TargetKind != "CronJob" || report.TargetNS != "batch" {
+		t.Errorf("unexpected insight classification: %+v", report)
+	}
+	if cleanup.TargetName != "cleanup" || !strings.Contains(cleanup.Title, "never run") {
+		t.Errorf("unexpected never-run insight: %+v", cleanup)
+	}
+
+	// With a single run required, sync's one failed Job is enough
+	insights, err = NewBrokenCronJobAnalyzer(1).Analyze(context.Background(), clientset, "batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var flagged []string
+	for _, i := range insights {
+		flagged = append(flagged, i.TargetName)
+	}
+	slices.Sort(flagged)
+	if !slices.Equal(flagged, []string{"cleanup", "report", "sync"}) {
+		t.Errorf("flagged with runs=1: %v, want [cleanup report sync]", flagged)
+	}
+}
+
+func TestEngineSetAnalyzerReplacesByName(t *testing.T) {
+	e := NewEngine(nil)
+	n := len(e.analyzers)
+	e.SetAnalyzer(NewBrokenCronJobAnalyzer(5))
+	if len(e.analyzers) != n {
+		t.Fatalf("SetAnalyzer added a duplicate: %d analyzers, want %d", len(e.analyzers), n)
+	}
+	for _, a := range e.analyzers {
+		if b, ok := a.(*brokenCronJobAnalyzer); ok && b.runs != 5 {
+			t.Errorf("broken_cronjob runs = %d, want 5", b.runs)
+		}
+	}
+}
+
+func TestCertExpiryAnalyzer(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	cert := srv.Certificate()
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "etcd"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if err := os.WriteFile(filepath.Join(dir, "etcd", "server.crt"), certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ca.key"), []byte("not a cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{
+				Addresses:       []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: host}},
+				DaemonEndpoints: corev1.NodeDaemonEndpoints{KubeletEndpoint: corev1.DaemonEndpoint{Port: int32(portNum)}},
+			},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "no-address"}},
+	)
+
+	a := NewCertExpiryAnalyzer(CertExpiryOptions{
+		ProbeKubelets: true,
+		PKIDirs:       []string{dir, filepath.Join(dir, "missing")},
+	})
+	if _, ok := a.(clusterScopedAnalyzer); !ok {
+		t.Fatal("cert_expiry should be cluster-scoped")
+	}
+	ca := a.(*certExpiryAnalyzer)
+
+	// Far from expiry: nothing to report
+	ca.now = func() time.Time { return cert.NotAfter.Add(-90 * 24 * time.Hour) }
+	insights, err := a.Analyze(context.Background(), clientset, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 0 {
+		t.Fatalf("expected no insights 90 days out, got %+v", insights)
+	}
+
+	ca.now = func() time.Time { return cert.NotAfter.Add(-10*24*time.Hour - time.Hour) }
+	insights, err = a.Analyze(context.Background(), clientset, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 2 {
+		t.Fatalf("expected 2 insights, got %d: %+v", len(insights), insights)
+	}
+	node := insights[0]
+	if node.TargetKind != "Node" || node.TargetName != "node-1" || node.Severity != "action" {
+		t.Errorf("unexpected kubelet insight: %+v", node)
+	}
+	if !strings.Contains(node.Title, "expires in 10 days") {
+		t.Errorf("title = %q", node.Title)
+	}
+	file := insights[1]
+	if file.TargetKind != "Certificate" || file.TargetName != filepath.Join(dir, "etcd", "server.crt") {
+		t.Errorf("unexpected file insight: %+v", file)
+	}
+
+	// The same file on another control-plane node is a separate insight
+	other := NewCertExpiryAnalyzer(CertExpiryOptions{PKIDirs: []string{dir}, NodeName: "cp-2"}).(*certExpiryAnalyzer)
+	other.now = ca.now
+	fromOther, err := other.Analyze(context.Background(), clientset, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fromOther) != 1 || fromOther[0].Fingerprint == file.Fingerprint || !strings.Contains(fromOther[0].Description, "on node cp-2") {
+		t.Errorf("expected a distinct insight for node cp-2, got %+v", fromOther)
+	}
+
+	ca.now = func() time.Time { return cert.NotAfter.Add(time.Hour) }
+	insights, err = a.Analyze(context.Background(), clientset, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 2 || !strings.Contains(insights[0].Title, "expired") {
+		t.Fatalf("expected expired insights, got %+v", insights)
+	}
+}
+
+func TestOOMKilledAnalyzer(t *testing.T) {
+	isController := true
+	oomPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "cache", Controller: &isController}},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "redis", Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				}},
+				{Name: "exporter"},
+			}},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:         "redis",
+						RestartCount: 4,
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								Reason: "OOMKilled", ExitCode: 137, FinishedAt: metav1.NewTime(time.Now().Add(-time.Hour)),
+							},
+						},
+					},
+					{
+						Name: "exporter",
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1},
+						},
+					},
+				},
+			},
+		}
+	}
+	clientset := fake.NewSimpleClientset(oomPod("cache-0"), oomPod("cache-1"))
+
+	insights, err := NewOOMKilledAnalyzer().Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 1 {
+		t.Fatalf("expected 1 insight, got %d: %+v", len(insights), insights)
+	}
+	got := insights[0]
+	if got.Severity != "action" || got.Category != "reliability" {
+		t.Errorf("severity/category = %s/%s, want action/reliability", got.Severity, got.Category)
+	}
+	if got.TargetKind != "StatefulSet" || got.TargetName != "cache" {
+		t.Errorf("target = %s/%s, want StatefulSet/cache", got.TargetKind, got.TargetName)
+	}
+	if got.ProposedAction != "tune_resource_limits" {
+		t.Errorf("expected tune_resource_limits, got %s", got.ProposedAction)
+	}
+	if got.ProposedParams["memory_limit"] != "512Mi" || got.ProposedParams["container"] != "redis" {
+		t.Errorf("unexpected params: %v", got.ProposedParams)
+	}
+
+	bare := oomPod("debug")
+	bare.OwnerReferences = nil
+	insights, err = NewOOMKilledAnalyzer().Analyze(context.Background(), fake.NewSimpleClientset(bare), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 1 || insights[0].TargetKind != "Pod" || insights[0].ProposedAction != "" {
+		t.Errorf("bare pod should be reported without a proposed action, got %+v", insights)
+	}
+
+	// Killed two days ago and running fine since: outside the window
+	old := oomPod("cache-0")
+	old.Status.ContainerStatuses[0].LastTerminationState.Terminated.FinishedAt = metav1.NewTime(time.Now().Add(-48 * time.Hour))
+	insights, err = NewOOMKilledAnalyzer().Analyze(context.Background(), fake.NewSimpleClientset(old), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 0 {
+		t.Errorf("stale OOM kill should not be flagged, got %+v", insights)
+	}
+
+	for _, tc := range []struct {
+		res  corev1.ResourceRequirements
+		want string
+	}{
+		{corev1.ResourceRequirements{}, "512Mi"},
+		{corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("100M")}}, "191Mi"},
+		{corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}}, "2Gi"},
+	} {
+		if _, got := oomMemoryLimit(tc.res); got != tc.want {
+			t.Errorf("oomMemoryLimit(%v) = %s, want %s", tc.res, got, tc.want)
+		}
+	}
+}
+
+func TestPendingPodsAnalyzer(t *testing.T) {
+	now := time.Now()
+	pending := func(name string, since time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(since)},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:               corev1.PodScheduled,
+					Status:             corev1.ConditionFalse,
+					Reason:             corev1.PodReasonUnschedulable,
+					Message:            "0/3 nodes are available: 3 Insufficient memory.",
+					LastTransitionTime: metav1.NewTime(since),
+				}},
+			},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		pending("big-job", now.Add(-45*time.Minute)),
+		pending("just-created", now.Add(-time.Minute)),
+		// Scheduled, still pulling its image
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pulling", Namespace: "default", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodPending,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}},
+			},
+		},
+	)
+
+	insights, err := NewPendingPodsAnalyzer(0).Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 1 {
+		t.Fatalf("expected 1 insight, got %d: %+v", len(insights), insights)
+	}
+	got := insights[0]
+	if got.TargetName != "big-job" || got.Severity != "warning" || got.Category != "scheduling" || got.AutoRemediable {
+		t.Errorf("unexpected insight: %+v", got)
+	}
+	if !strings.Contains(got.Description, "3 Insufficient memory") {
+		t.Errorf("description lacks the scheduler message: %s", got.Description)
+	}
+}
+
+func TestDeprecatedReplacement(t *testing.T) {
+	tests := []struct {
+		groupVersion, kind string
+		replacement        string
+		removedIn          int
+	}{
+		{"policy/v1beta1", "PodDisruptionBudget", "policy/v1", 25},
+		{"networking.k8s.io/v1beta1", "Ingress", "networking.k8s.io/v1", 22},
+		{"extensions/v1beta1", "Ingress", "networking.k8s.io/v1", 22},
+		{"extensions/v1beta1", "Deployment", "apps/v1", 16},
+		{"apps/v1beta2", "StatefulSet", "apps/v1", 16},
+		{"batch/v1beta1", "CronJob", "batch/v1", 25},
+		{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "autoscaling/v2", 25},
+		{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "autoscaling/v2", 26},
+		{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "rbac.authorization.k8s.io/v1", 22},
+		// Current APIs, and a removed group-version with another kind
+		{"networking.k8s.io/v1", "Ingress", "", 0},
+		{"apps/v1", "Deployment", "", 0},
+		{"policy/v1beta1", "Ingress", "", 0},
+	}
+	for _, tt := range tests {
+		replacement, removedIn, ok := deprecatedReplacement(tt.groupVersion, tt.kind)
+		if ok != (tt.replacement != "") || replacement != tt.replacement || removedIn != tt.removedIn {
+			t.Errorf("deprecatedReplacement(%s, %s) = %q, 1.%d, %v; want %q, 1.%d",
+				tt.groupVersion, tt.kind, replacement, removedIn, ok, tt.replacement, tt.removedIn)
+		}
+	}
+
+	for v, want := range map[string]int{"1.25": 25, "v1.29.3": 29, "v1.31.4+k3s1": 31} {
+		if got, ok := parseMinorVersion(v); !ok || got != want {
+			t.Errorf("parseMinorVersion(%q) = %d, %v; want %d", v, got, ok, want)
+		}
+	}
+	if _, ok := parseMinorVersion("2.0"); ok {
+		t.Error("parseMinorVersion accepted 2.0")
+	}
+}
+
+func TestDeprecatedAPIAnalyzer(t *testing.T) {
+	obj := func(apiVersion, kind, name string, managed []interface{}, annotations map[string]interface{}) *unstructured.Unstructured {
+		meta := map[string]interface{}{"name": name, "namespace": "default"}
+		if managed != nil {
+			meta["managedFields"] = managed
+		}
+		if annotations != nil {
+			meta["annotations"] = annotations
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion, "kind": kind, "metadata": meta,
+		}}
+	}
+	managedBy := func(manager, apiVersion string) map[string]interface{} {
+		return map[string]interface{}{"manager": manager, "operation": "Update", "apiVersion": apiVersion}
+	}
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, d := range deprecatedAPIs {
+		listKinds[d.Replacement] = d.Kind + "List"
+	}
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		obj("policy/v1", "PodDisruptionBudget", "web", []interface{}{
+			managedBy("helm", "policy/v1beta1"),
+			managedBy("kube-controller-manager", "policy/v1"),
+		}, nil),
+		obj("networking.k8s.io/v1", "Ingress", "shop", nil, map[string]interface{}{
+			"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"networking.k8s.io/v1beta1","kind":"Ingress"}`,
+		}),
+		obj("networking.k8s.io/v1", "Ingress", "current", []interface{}{managedBy("kubectl", "networking.k8s.io/v1")}, nil),
+		// Removed in 1.26, after the target
+		obj("autoscaling/v2", "HorizontalPodAutoscaler", "web", []interface{}{managedBy("argocd", "autoscaling/v2beta2")}, nil),
+	)
+
+	clientset := fake.NewSimpleClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.21.4"}
+	insights, err := NewDeprecatedAPIAnalyzer(dynClient, "1.25").Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]ClusterInsight{}
+	for _, i := range insights {
+		got[i.TargetKind+"/"+i.TargetName] = i
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 insights, got %+v", insights)
+	}
+	pdb := got["PodDisruptionBudget/web"]
+	if pdb.Severity != "warning" || !strings.Contains(pdb.Description, "by helm") || !strings.Contains(pdb.Description, "policy/v1.") {
+		t.Errorf("unexpected PDB insight: %+v", pdb)
+	}
+	if ing := got["Ingress/shop"]; !strings.Contains(ing.Description, "networking.k8s.io/v1beta1 by kubectl") {
+		t.Errorf("unexpected Ingress insight: %+v", ing)
+	}
+
+	// On 1.22 the v1beta1 Ingress API is already gone; only the PDB's
+	// removal is still ahead
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.22.0"}
+	insights, err = NewDeprecatedAPIAnalyzer(dynClient, "1.25").Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 1 || insights[0].TargetKind != "PodDisruptionBudget" {
+		t.Errorf("expected only the PDB on 1.22, got %+v", insights)
+	}
+
+	if _, err := NewDeprecatedAPIAnalyzer(dynClient, "latest").Analyze(context.Background(), clientset, "default"); err == nil {
+		t.Error("expected an error for an unparseable target version")
+	}
+}
+
+func TestLatestTagAnalyzer(t *testing.T) {
+	deploy := func(name, image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+				},
+			},
+		}
+	}
+	clientset := fake.NewSimpleClientset(
+		deploy("floating", "registry.local:5000/acme/api:latest"),
+		deploy("pinned", "nginx:latest@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31"),
+		deploy("tagged", "nginx:1.27.3"),
+	)
+
+	insights, err := NewLatestTagAnalyzer().Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 1 {
+		t.Fatalf("expected 1 insight, got %d: %+v", len(insights), insights)
+	}
+	got := insights[0]
+	if got.TargetName != "floating" || got.Severity != "suggestion" || got.Category != "hygiene" {
+		t.Errorf("unexpected insight: %+v", got)
+	}
+	if got.Fingerprint != MakeFingerprint("latest_tag", "Deployment", "default", "floating") {
+		t.Errorf("unexpected fingerprint %s", got.Fingerprint)
+	}
+	if !strings.Contains(got.Description, "app (registry.local:5000/acme/api:latest)") {
+		t.Errorf("description does not list the container: %s", got.Description)
+	}
+
+	// An untagged image means latest
+	clientset = fake.NewSimpleClientset(deploy("untagged", "busybox"))
+	if insights, _ := NewLatestTagAnalyzer().Analyze(context.Background(), clientset, "default"); len(insights) != 1 {
+		t.Errorf("expected the untagged image to be flagged, got %+v", insights)
+	}
+}
+
+func TestPrivilegedWorkloadAnalyzer(t *testing.T) {
+	privileged := true
+	template := func(spec corev1.PodSpec) corev1.PodTemplateSpec {
+		if spec.Containers == nil {
+			spec.Containers = []corev1.Container{{Name: "app", Image: "app:1.0"}}
+		}
+		return corev1.PodTemplateSpec{Spec: spec}
+	}
+	meta := func(name string) metav1.ObjectMeta { return metav1.ObjectMeta{Name: name, Namespace: "default"} }
+	isController := true
+
+	clientset := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: meta("clean"), Spec: appsv1.DeploymentSpec{Template: template(corev1.PodSpec{})}},
+		&appsv1.Deployment{ObjectMeta: meta("priv"), Spec: appsv1.DeploymentSpec{Template: template(corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app", Image: "app:1.0"},
+				{Name: "dind", Image: "docker:27-dind", SecurityContext: &corev1.SecurityContext{Privileged: &privileged}},
+			},
+		})}},
+		&appsv1.StatefulSet{ObjectMeta: meta("hostpid"), Spec: appsv1.StatefulSetSpec{Template: template(corev1.PodSpec{HostPID: true})}},
+		&appsv1.DaemonSet{ObjectMeta: meta("agent"), Spec: appsv1.DaemonSetSpec{Template: template(corev1.PodSpec{
+			HostNetwork: true,
+			Volumes: []corev1.Volume{{Name: "docker-sock", VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/docker.sock"},
+			}}},
+		})}},
+		&batchv1.CronJob{ObjectMeta: meta("backup"), Spec: batchv1.CronJobSpec{JobTemplate: batchv1.JobTemplateSpec{
+			Spec: batchv1.JobSpec{Template: template(corev1.PodSpec{HostPID: true})},
+		}}},
+		&batchv1.Job{ObjectMeta: meta("node-setup"), Spec: batchv1.JobSpec{Template: template(corev1.PodSpec{HostNetwork: true})}},
+		// A CronJob's Jobs are covered by its job template
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-29000000", Namespace: "default", OwnerReferences: []metav1.OwnerReference{
+				{Kind: "CronJob", Name: "backup", Controller: &isController},
+			}},
+			Spec: batchv1.JobSpec{Template: template(corev1.PodSpec{HostPID: true})},
+		},
+		// Standalone pod is checked, a controlled one is left to its workload
+		&corev1.Pod{ObjectMeta: meta("debug"), Spec: corev1.PodSpec{HostNetwork: true}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-x7k2p", Namespace: "default", OwnerReferences: []metav1.OwnerReference{
+				{Kind: "DaemonSet", Name: "agent", Controller: &isController},
+			}},
+			Spec: corev1.PodSpec{HostNetwork: true},
+		},
+	)
+
+	insights, err := NewPrivilegedWorkloadAnalyzer().Analyze(context.Background(), clientset, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"Deployment/priv":     {`container "dind" is privileged`},
+		"StatefulSet/hostpid": {"shares the host PID namespace"},
+		"DaemonSet/agent":     {"uses the host network", `mounts host path /var/run/docker.sock as volume "docker-sock"`},
+		"CronJob/backup":      {"shares the host PID namespace"},
+		"Job/node-setup":      {"uses the host network"},
+		"Pod/debug":           {"uses the host network"},
+	}
+	if len(insights) != len(want) {
+		t.Fatalf("expected %d insights, got %d: %+v", len(want), len(insights), insights)
+	}
+	for _, i := range insights {
+		findings, ok := want[i.TargetKind+"/"+i.TargetName]
+		if !ok {
+			t.Errorf("unexpected insight for %s %s", i.TargetKind, i.TargetName)
+			continue
+		}
+		if i.Severity != "warning" || i.Category != "security" {
+			t.Errorf("%s: severity/category = %s/%s", i.TargetName, i.Severity, i.Category)
+		}
+		for _, f := range findings {
+			if !strings.Contains(i.Description, f) {
+				t.Errorf("%s: description %q lacks %q", i.TargetName, i.Description, f)
+			}
+		}
+	}
+}
+
+func TestReporterSyncsOnSummaryChange(t *testing.T) {
+	var syncs atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		syncs.Add(1)
+		w.Write([]byte(`{"upserted":0,"auto_resolved":0}`))
+	}))
+	defer srv.Close()
+
+	r := NewReporter(srv.URL, "tok", "")
+	found := []ClusterInsight{{Analyzer: "oom_killed", Fingerprint: "fp-1"}}
+	ok := []AnalyzerRun{{Analyzer: "oom_killed", Status: AnalyzerStatusOK, InsightCount: 1, DurationMS: 3}}
+	failed := []AnalyzerRun{{Analyzer: "oom_killed", Status: AnalyzerStatusError, Error: "forbidden"}}
+
+	for i, step := range []struct {
+		summary []AnalyzerRun
+		want    bool
+	}{
+		{ok, true},
+		{[]AnalyzerRun{{Analyzer: "oom_killed", Status: AnalyzerStatusOK, InsightCount: 1, DurationMS: 9}}, false},
+		{failed, true},
+		{failed, false},
+	} {
+		synced, err := r.Report(context.Background(), found, step.summary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if synced != step.want {
+			t.Errorf("step %d: synced = %v, want %v", i, synced, step.want)
+		}
+	}
+	if n := syncs.Load(); n != 2 {
+		t.Errorf("server saw %d syncs, want 2", n)
+	}
+}
+
+func TestEngineSharesPodList(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "a"}},
+	)
+	e := &Engine{
+		analyzers: []Analyzer{
+			NewCrashloopingAnalyzer(),
+			NewEvictedPodAnalyzer(),
+			NewImagePullIssuesAnalyzer(),
+		},
+		concurrency: 3,
+		log:         slog.Default(),
+	}
+	e.Analyze(context.Background(), clientset)
+
+	podLists := map[string]int{}
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "list" && action.GetResource().Resource == "pods" {
+			podLists[action.GetNamespace()]++
+		}
+	}
+	if podLists["a"] != 1 || podLists["b"] != 1 {
+		t.Errorf("expected one pod list per namespace, got %v", podLists)
+	}
+}
+
+func TestEngineSharesServerVersion(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+	)
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.24.3"}
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, d := range deprecatedAPIs {
+		listKinds[d.Replacement] = d.Kind + "List"
+	}
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	e := &Engine{
+		analyzers:   []Analyzer{NewDeprecatedAPIAnalyzer(dynClient, "")},
+		concurrency: 3,
+		log:         slog.Default(),
+	}
+	_, summary := e.AnalyzeWithSummary(context.Background(), clientset)
+	if len(summary) != 1 || summary[0].Status != AnalyzerStatusOK {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	versions := 0
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "get" && action.GetResource().Resource == "version" {
+			versions++
+		}
+	}
+	if versions != 1 {
+		t.Errorf("expected one server version request per pass, got %d", versions)
+	}
+}
+
+// blockingAnalyzer records how many of its runs overlap.
+type blockingAnalyzer struct {
+	inFlight, maxInFlight *atomic.Int32
+}
+
+func (b *blockingAnalyzer) Name() string { return "blocking" }
+
+func (b *blockingAnalyzer) Analyze(context.Context, kubernetes.Interface, string) ([]ClusterInsight, error) {
+	n := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+	for {
+		peak := b.maxInFlight.Load()
+		if n <= peak || b.maxInFlight.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil, nil
+}
+
+func TestEngineConcurrencyLimit(t *testing.T) {
+	var objects []runtime.Object
+	for i := 0; i < 8; i++ {
+		objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("ns-%d", i)}})
+	}
+	clientset := fake.NewSimpleClientset(objects...)
+
+	var inFlight, maxInFlight atomic.Int32
+	e := &Engine{
+		analyzers: []Analyzer{&blockingAnalyzer{&inFlight, &maxInFlight}},
+		log:       slog.Default(),
+	}
+	e.SetConcurrency(2)
+	_, summary := e.AnalyzeWithSummary(context.Background(), clientset)
+
+	if got := maxInFlight.Load(); got < 1 || got > 2 {
+		t.Errorf("max concurrent analyzer runs = %d, want 1-2", got)
+	}
+	if len(summary) != 1 || summary[0].Status != AnalyzerStatusOK {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
@@ -0,0 +1,91 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// betaStorageClassAnnotation is the pre-1.6 way of naming a PVC's class,
+// still honoured by Kubernetes when spec.storageClassName is unset.
+const betaStorageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+
+type badStorageClassAnalyzer struct{}
+
+// NewBadStorageClassAnalyzer flags unbound PVCs that name a StorageClass
+// the cluster doesn't have; they stay Pending forever. A PVC without a
+// class uses the default class and one with "" opts out of dynamic
+// provisioning, so neither is flagged. A class name that only exists on
+// PersistentVolumes is also fine: static provisioning matches PVs to
+// claims by that name without any StorageClass object.
+func NewBadStorageClassAnalyzer() Analyzer { return &badStorageClassAnalyzer{} }
+
+func (a *badStorageClassAnalyzer) Name() string { return "bad_storage_class" }
+
+func (a *badStorageClassAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only unbound claims with an explicit class need the cluster lookups
+	var candidates []corev1.PersistentVolumeClaim
+	for _, pvc := range pvcs.Items {
+		if pvc.Status.Phase != corev1.ClaimBound && pvcStorageClass(pvc) != "" {
+			candidates = append(candidates, pvc)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	classes, err := clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(classes.Items))
+	for _, sc := range classes.Items {
+		known[sc.Name] = true
+	}
+	pvs, err := clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pv := range pvs.Items {
+		if pv.Spec.StorageClassName != "" {
+			known[pv.Spec.StorageClassName] = true
+		}
+	}
+
+	var insights []ClusterInsight
+	for _, pvc := range candidates {
+		class := pvcStorageClass(pvc)
+		if known[class] {
+			continue
+		}
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "bad_storage_class",
+			Category:    "reliability",
+			Severity:    "action",
+			Title:       fmt.Sprintf("PVC %s requests missing StorageClass %q", pvc.Name, class),
+			Description: fmt.Sprintf("PVC %s/%s requests StorageClass %q, which does not exist and matches no PersistentVolume, so it will stay Pending. Create the class, or recreate the claim with an existing class (omit storageClassName to use the default).", namespace, pvc.Name, class),
+			TargetKind:  "PersistentVolumeClaim",
+			TargetNS:    namespace,
+			TargetName:  pvc.Name,
+			Fingerprint: MakeFingerprint("bad_storage_class", "PersistentVolumeClaim", namespace, pvc.Name),
+		})
+	}
+	return insights, nil
+}
+
+// pvcStorageClass returns the class a PVC explicitly asks for, or "" when
+// it uses the default class or opts out of classes.
+func pvcStorageClass(pvc corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil {
+		return *pvc.Spec.StorageClassName
+	}
+	return pvc.Annotations[betaStorageClassAnnotation]
+}
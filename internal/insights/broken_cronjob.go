@@ -0,0 +1,161 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultBrokenCronJobRuns is how many finished runs in a row must have
+// failed before a CronJob is flagged, unless configured otherwise.
+const DefaultBrokenCronJobRuns = 3
+
+type brokenCronJobAnalyzer struct {
+	runs int
+}
+
+// NewBrokenCronJobAnalyzer flags non-suspended CronJobs that no longer do
+// their job: the last runs finished Jobs all failed, or the CronJob never
+// scheduled a run although its schedule should have fired twice since it
+// was created. Only retained Jobs can be inspected: a CronJob that keeps
+// fewer than runs finished Jobs (failedJobsHistoryLimit defaults to 1) is
+// never flagged as failing, rather than judged on fewer runs. runs below 1
+// selects DefaultBrokenCronJobRuns.
+func NewBrokenCronJobAnalyzer(runs int) Analyzer {
+	if runs < 1 {
+		runs = DefaultBrokenCronJobRuns
+	}
+	return &brokenCronJobAnalyzer{runs: runs}
+}
+
+func (a *brokenCronJobAnalyzer) Name() string { return "broken_cronjob" }
+
+func (a *brokenCronJobAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	cronJobs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil || len(cronJobs.Items) == 0 {
+		return nil, err
+	}
+	jobList, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	jobs := make(map[types.UID][]batchv1.Job)
+	for _, job := range jobList.Items {
+		if ref := metav1.GetControllerOf(&job); ref != nil && ref.Kind == "CronJob" {
+			jobs[ref.UID] = append(jobs[ref.UID], job)
+		}
+	}
+
+	now := time.Now()
+	var insights []ClusterInsight
+	for _, cj := range cronJobs.Items {
+		if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+			continue
+		}
+		owned := jobs[cj.UID]
+		if cj.Status.LastScheduleTime == nil && len(owned) == 0 {
+			if every, ok := cronInterval(cj.Spec.Schedule); ok && now.Sub(cj.CreationTimestamp.Time) > 2*every {
+				insights = append(insights, brokenCronJobInsight(namespace, cj.Name,
+					fmt.Sprintf("CronJob %q has never run", cj.Name),
+					fmt.Sprintf("CronJob %q was created %s ago with schedule %q but has never scheduled a Job. Check the schedule and time zone, and the kube-controller-manager logs.", cj.Name, now.Sub(cj.CreationTimestamp.Time).Round(time.Hour), cj.Spec.Schedule)))
+			}
+			continue
+		}
+		if failed, ok := a.failingStreak(cj, owned); ok {
+			insights = append(insights, brokenCronJobInsight(namespace, cj.Name,
+				fmt.Sprintf("CronJob %q keeps failing", cj.Name),
+				fmt.Sprintf("The last %d finished Job(s) of CronJob %q failed and none has succeeded since. Inspect the logs of the newest failed Job, %q.", len(failed), cj.Name, failed[0].Name)))
+		}
+	}
+	return insights, nil
+}
+
+// failingStreak returns cj's last a.runs finished Jobs, newest first, when
+// they all failed with no success recorded since the oldest of them.
+func (a *brokenCronJobAnalyzer) failingStreak(cj batchv1.CronJob, owned []batchv1.Job) ([]batchv1.Job, bool) {
+	var finished []batchv1.Job
+	for _, job := range owned {
+		if jobFinished(job, batchv1.JobComplete) || jobFinished(job, batchv1.JobFailed) {
+			finished = append(finished, job)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[j].CreationTimestamp.Before(&finished[i].CreationTimestamp)
+	})
+	if len(finished) < a.runs {
+		return nil, false
+	}
+	streak := finished[:a.runs]
+	for _, job := range streak {
+		if !jobFinished(job, batchv1.JobFailed) {
+			return nil, false
+		}
+	}
+	// A successful Job may already be gone (ttlSecondsAfterFinished)
+	oldest := streak[len(streak)-1].CreationTimestamp
+	if last := cj.Status.LastSuccessfulTime; last != nil && !last.Before(&oldest) {
+		return nil, false
+	}
+	return streak, true
+}
+
+// jobFinished reports whether job has condition cond set to True.
+func jobFinished(job batchv1.Job, cond batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == cond && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// cronInterval returns the longest gap between two firings of schedule, for
+// the schedules where that is at most a week: macros up to @weekly and
+// five-field expressions that run on any day of the month in any month.
+func cronInterval(schedule string) (time.Duration, bool) {
+	fields := strings.Fields(schedule)
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "CRON_TZ=") || strings.HasPrefix(fields[0], "TZ=")) {
+		fields = fields[1:]
+	}
+	if len(fields) == 1 {
+		switch fields[0] {
+		case "@hourly":
+			return time.Hour, true
+		case "@daily", "@midnight":
+			return 24 * time.Hour, true
+		case "@weekly":
+			return 7 * 24 * time.Hour, true
+		}
+		return 0, false
+	}
+	anyValue := func(f string) bool { return f == "*" || f == "?" }
+	if len(fields) != 5 || !anyValue(fields[2]) || !anyValue(fields[3]) {
+		return 0, false
+	}
+	if !anyValue(fields[4]) {
+		return 7 * 24 * time.Hour, true
+	}
+	return 24 * time.Hour, true
+}
+
+func brokenCronJobInsight(namespace, name, title, description string) ClusterInsight {
+	return ClusterInsight{
+		Analyzer:    "broken_cronjob",
+		Category:    "reliability",
+		Severity:    "warning",
+		Title:       title,
+		Description: description,
+		TargetKind:  "CronJob",
+		TargetNS:    namespace,
+		TargetName:  name,
+		Fingerprint: MakeFingerprint("broken_cronjob", "CronJob", namespace, name),
+	}
+}
@@ -0,0 +1,208 @@
+package insights
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// CertExpiryWindow is how far ahead certificate expiry is flagged.
+	CertExpiryWindow = 30 * 24 * time.Hour
+	// DefaultPKIDir is where kubeadm keeps control-plane certificates.
+	DefaultPKIDir = "/etc/kubernetes/pki"
+
+	kubeletProbeTimeout = 5 * time.Second
+	defaultKubeletPort  = 10250
+)
+
+// CertExpiryOptions configures NewCertExpiryAnalyzer.
+type CertExpiryOptions struct {
+	// ProbeKubelets opens a TLS connection to every node's kubelet port to
+	// read its serving certificate
+	ProbeKubelets bool
+	// PKIDirs are read for *.crt files and their etcd/ subdirectory, e.g.
+	// DefaultPKIDir on a kubeadm control-plane node; missing dirs are skipped
+	PKIDirs []string
+	// NodeName is the node PKIDirs are read on. Every control-plane node
+	// holds its own copies, so it keeps their insights apart
+	NodeName string
+}
+
+type certExpiryAnalyzer struct {
+	opts CertExpiryOptions
+	now  func() time.Time
+}
+
+// NewCertExpiryAnalyzer flags certificates that expire within
+// CertExpiryWindow: kubelet serving certificates (when ProbeKubelets is
+// set) and the kubeadm CA, apiserver, etcd and front-proxy certificates
+// found in PKIDirs. An expired kubelet or apiserver certificate takes the
+// node or the whole control plane down, so both are "action" insights.
+// Probing dials every node, so it is opt-in and not part of the default set.
+func NewCertExpiryAnalyzer(opts CertExpiryOptions) Analyzer {
+	return &certExpiryAnalyzer{opts: opts, now: time.Now}
+}
+
+func (a *certExpiryAnalyzer) Name() string { return "cert_expiry" }
+
+func (a *certExpiryAnalyzer) clusterScoped() {}
+
+func (a *certExpiryAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, _ string) ([]ClusterInsight, error) {
+	var insights []ClusterInsight
+
+	if a.opts.ProbeKubelets {
+		nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes.Items {
+			addr := kubeletAddr(node)
+			if addr == "" {
+				continue
+			}
+			cert, err := probeServingCert(ctx, addr)
+			if err != nil {
+				continue // unreachable kubelets are reported by node readiness
+			}
+			if ins, ok := a.expiryInsight(cert, "Node", node.Name, node.Name, fmt.Sprintf("The kubelet serving certificate on node %s (%s)", node.Name, addr)); ok {
+				insights = append(insights, ins)
+			}
+		}
+	}
+
+	for _, dir := range a.opts.PKIDirs {
+		for _, path := range pkiCertFiles(dir) {
+			cert, err := readCertFile(path)
+			if err != nil {
+				continue
+			}
+			what := fmt.Sprintf("Certificate %s (%s)", path, cert.Subject.CommonName)
+			if a.opts.NodeName != "" {
+				what = fmt.Sprintf("Certificate %s (%s) on node %s", path, cert.Subject.CommonName, a.opts.NodeName)
+			}
+			if ins, ok := a.expiryInsight(cert, "Certificate", path, a.opts.NodeName+":"+path, what); ok {
+				insights = append(insights, ins)
+			}
+		}
+	}
+	return insights, nil
+}
+
+// expiryInsight returns an insight when cert expires within the window.
+// key identifies the certificate in the fingerprint.
+func (a *certExpiryAnalyzer) expiryInsight(cert *x509.Certificate, kind, name, key, what string) (ClusterInsight, bool) {
+	left := cert.NotAfter.Sub(a.now())
+	if left > CertExpiryWindow {
+		return ClusterInsight{}, false
+	}
+
+	var title, when string
+	if left <= 0 {
+		title = fmt.Sprintf("%s %s certificate expired", kind, name)
+		when = "expired on " + cert.NotAfter.UTC().Format(time.RFC3339)
+	} else {
+		days := int(left.Hours() / 24)
+		title = fmt.Sprintf("%s %s certificate expires in %d days", kind, name, days)
+		when = fmt.Sprintf("expires on %s (%d days)", cert.NotAfter.UTC().Format(time.RFC3339), days)
+	}
+	advice := "Renew it before it lapses: kubeadm certs renew for control-plane certificates, or enable kubelet serving certificate rotation (serverTLSBootstrap) and approve the CSRs."
+
+	return ClusterInsight{
+		Analyzer:    "cert_expiry",
+		Category:    "reliability",
+		Severity:    "action",
+		Title:       title,
+		Description: fmt.Sprintf("%s %s. %s", what, when, advice),
+		TargetKind:  kind,
+		TargetNS:    "",
+		TargetName:  name,
+		Fingerprint: MakeFingerprint("cert_expiry", kind, "", key),
+	}, true
+}
+
+// kubeletAddr returns host:port of a node's kubelet, preferring its
+// InternalIP.
+func kubeletAddr(node corev1.Node) string {
+	port := int(node.Status.DaemonEndpoints.KubeletEndpoint.Port)
+	if port == 0 {
+		port = defaultKubeletPort
+	}
+	var host, hostname string
+	for _, addr := range node.Status.Addresses {
+		switch addr.Type {
+		case corev1.NodeInternalIP:
+			if host == "" {
+				host = addr.Address
+			}
+		case corev1.NodeHostName:
+			hostname = addr.Address
+		}
+	}
+	if host == "" {
+		host = hostname
+	}
+	if host == "" {
+		return ""
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// probeServingCert returns the leaf certificate served at addr. The chain
+// is not verified: only the expiry date is read.
+func probeServingCert(ctx context.Context, addr string) (*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, kubeletProbeTimeout)
+	defer cancel()
+	dialer := &tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true}}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate from %s", addr)
+	}
+	return certs[0], nil
+}
+
+// pkiCertFiles lists the certificates kubeadm writes under dir.
+func pkiCertFiles(dir string) []string {
+	var files []string
+	for _, pattern := range []string{"*.crt", "etcd/*.crt"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// readCertFile parses the first certificate in a PEM file.
+func readCertFile(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("%s: no certificate", path)
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
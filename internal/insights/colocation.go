@@ -0,0 +1,114 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+type colocationAnalyzer struct{}
+
+// NewColocationAnalyzer flags multi-replica Deployments and StatefulSets
+// whose running pods all share a single node.
+func NewColocationAnalyzer() Analyzer { return &colocationAnalyzer{} }
+
+func (a *colocationAnalyzer) Name() string { return "colocated_replicas" }
+
+func (a *colocationAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	// On a single-node cluster co-location is unavoidable
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes.Items) < 2 {
+		return nil, nil
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var insights []ClusterInsight
+
+	deploys, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deploys.Items {
+		if d.Spec.Replicas == nil || *d.Spec.Replicas <= 1 {
+			continue
+		}
+		if node, n := singleNode(pods.Items, d.Spec.Selector); node != "" {
+			insights = append(insights, colocationInsight("Deployment", namespace, d.Name, node, n))
+		}
+	}
+
+	stss, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stss.Items {
+		if s.Spec.Replicas == nil || *s.Spec.Replicas <= 1 {
+			continue
+		}
+		if node, n := singleNode(pods.Items, s.Spec.Selector); node != "" {
+			insights = append(insights, colocationInsight("StatefulSet", namespace, s.Name, node, n))
+		}
+	}
+
+	return insights, nil
+}
+
+// singleNode returns the node hosting every scheduled, non-terminating pod
+// matched by selector, and the pod count. It returns "" unless at least two
+// such pods exist and all share one node.
+func singleNode(pods []corev1.Pod, selector *metav1.LabelSelector) (string, int) {
+	if selector == nil {
+		return "", 0
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil || sel.Empty() {
+		return "", 0
+	}
+
+	node, count := "", 0
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Spec.NodeName == "" {
+			continue
+		}
+		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		if !sel.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if node != "" && pod.Spec.NodeName != node {
+			return "", 0
+		}
+		node = pod.Spec.NodeName
+		count++
+	}
+	if count < 2 {
+		return "", 0
+	}
+	return node, count
+}
+
+func colocationInsight(kind, namespace, name, node string, replicas int) ClusterInsight {
+	return ClusterInsight{
+		Analyzer:    "colocated_replicas",
+		Category:    "reliability",
+		Severity:    "suggestion",
+		Title:       fmt.Sprintf("%s %q has all %d replicas on node %s", kind, name, replicas, node),
+		Description: fmt.Sprintf("All running replicas are scheduled on node %s, so losing that node takes the workload down. Add pod anti-affinity or a topologySpreadConstraint on kubernetes.io/hostname to spread replicas across nodes.", node),
+		TargetKind:  kind,
+		TargetNS:    namespace,
+		TargetName:  name,
+		Fingerprint: MakeFingerprint("colocated_replicas", kind, namespace, name),
+	}
+}
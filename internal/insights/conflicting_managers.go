@@ -0,0 +1,132 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type conflictingManagersAnalyzer struct{}
+
+// NewConflictingManagersAnalyzer flags workloads claimed by more than one
+// deployment tool (Helm, Flux, Argo CD, kubectl apply) or by more than one
+// controlling owner. Competing managers overwrite each other's changes,
+// which shows up as flapping replicas, images or config.
+func NewConflictingManagersAnalyzer() Analyzer { return &conflictingManagersAnalyzer{} }
+
+func (a *conflictingManagersAnalyzer) Name() string { return "conflicting_managers" }
+
+func (a *conflictingManagersAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	var insights []ClusterInsight
+	check := func(kind string, meta metav1.ObjectMeta) {
+		managers := resourceManagers(meta)
+		if len(managers) < 2 {
+			return
+		}
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "conflicting_managers",
+			Category:    "hygiene",
+			Severity:    "warning",
+			Title:       fmt.Sprintf("%s %q is managed by %d different tools", kind, meta.Name, len(managers)),
+			Description: fmt.Sprintf("%s %q is claimed by: %s. Each manager reverts the others' changes, causing flapping. Pick one owner and remove the resource from the others.", kind, meta.Name, strings.Join(managers, ", ")),
+			TargetKind:  kind,
+			TargetNS:    namespace,
+			TargetName:  meta.Name,
+			Fingerprint: MakeFingerprint("conflicting_managers", kind, namespace, meta.Name),
+		})
+	}
+
+	deploys, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deploys.Items {
+		check("Deployment", d.ObjectMeta)
+	}
+
+	stss, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stss.Items {
+		check("StatefulSet", s.ObjectMeta)
+	}
+
+	dss, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dss.Items {
+		check("DaemonSet", d.ObjectMeta)
+	}
+
+	return insights, nil
+}
+
+// fieldManagerTools maps managedFields manager names to the deployment tool
+// they belong to. Flux's helm-controller drives Helm, so it counts as Helm.
+var fieldManagerTools = map[string]string{
+	"helm":                          "helm",
+	"helm-controller":               "helm",
+	"kustomize-controller":          "flux",
+	"argocd-controller":             "argocd",
+	"argocd-application-controller": "argocd",
+	"kubectl-client-side-apply":     "kubectl",
+}
+
+// resourceManagers returns a sorted description of every distinct manager
+// claiming the object, from ownership labels/annotations, managedFields and
+// controller ownerReferences.
+func resourceManagers(meta metav1.ObjectMeta) []string {
+	tools := make(map[string]string) // tool -> description
+
+	if release := meta.Annotations["meta.helm.sh/release-name"]; release != "" {
+		tools["helm"] = fmt.Sprintf("Helm release %q", release)
+	}
+	if ks := meta.Labels["kustomize.toolkit.fluxcd.io/name"]; ks != "" {
+		if ns := meta.Labels["kustomize.toolkit.fluxcd.io/namespace"]; ns != "" {
+			ks = ns + "/" + ks
+		}
+		tools["flux"] = fmt.Sprintf("Flux Kustomization %q", ks)
+	}
+	if id := meta.Annotations["argocd.argoproj.io/tracking-id"]; id != "" {
+		app, _, _ := strings.Cut(id, ":")
+		tools["argocd"] = fmt.Sprintf("Argo CD application %q", app)
+	}
+
+	for _, mf := range meta.ManagedFields {
+		tool, ok := fieldManagerTools[mf.Manager]
+		if !ok && mf.Manager == "kubectl" && mf.Operation == metav1.ManagedFieldsOperationApply {
+			tool, ok = "kubectl", true // kubectl apply --server-side
+		}
+		if !ok || tools[tool] != "" {
+			continue
+		}
+		switch tool {
+		case "helm":
+			tools[tool] = "Helm"
+		case "flux":
+			tools[tool] = "Flux"
+		case "argocd":
+			tools[tool] = "Argo CD"
+		case "kubectl":
+			tools[tool] = "kubectl apply"
+		}
+	}
+
+	var managers []string
+	for _, desc := range tools {
+		managers = append(managers, desc)
+	}
+	for _, ref := range meta.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			managers = append(managers, fmt.Sprintf("controller %s %q", ref.Kind, ref.Name))
+		}
+	}
+	sort.Strings(managers)
+	return managers
+}
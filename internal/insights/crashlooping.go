@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/tinkerbelle-io/tb-manage/internal/podutil"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -22,61 +24,65 @@ func (a *crashloopingAnalyzer) Analyze(ctx context.Context, clientset kubernetes
 
 	var insights []ClusterInsight
 	for _, pod := range pods.Items {
-		for _, cs := range pod.Status.ContainerStatuses {
-			isCrashloop := cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff"
-			highRestarts := cs.RestartCount >= 5
-
-			if !isCrashloop && !highRestarts {
-				continue
+		// Init containers are reported separately: a crashlooping init
+		// container leaves the pod in Init:CrashLoopBackOff and its regular
+		// containers never start, so ContainerStatuses look clean.
+		for _, init := range []bool{true, false} {
+			statuses := pod.Status.ContainerStatuses
+			if init {
+				statuses = pod.Status.InitContainerStatuses
 			}
 
-			// Try to resolve owning workload
-			targetKind := "Pod"
-			targetName := pod.Name
-			for _, ref := range pod.OwnerReferences {
-				if ref.Kind == "ReplicaSet" {
-					// Look up the ReplicaSet to find the Deployment owner
-					rs, rsErr := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
-					if rsErr == nil {
-						for _, rsRef := range rs.OwnerReferences {
-							if rsRef.Kind == "Deployment" {
-								targetKind = "Deployment"
-								targetName = rsRef.Name
-							}
-						}
+			for _, cs := range statuses {
+				isCrashloop := cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff"
+				highRestarts := cs.RestartCount >= 5
+
+				if !isCrashloop && !highRestarts {
+					continue
+				}
+
+				targetKind, targetName := podWorkload(ctx, clientset, pod)
+
+				var title, desc string
+				switch {
+				case init:
+					reason := "none"
+					if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+						reason = cs.State.Waiting.Reason
 					}
-				} else if ref.Kind == "StatefulSet" || ref.Kind == "DaemonSet" {
-					targetKind = ref.Kind
-					targetName = ref.Name
+					title = fmt.Sprintf("%s %q has a crashlooping init container %q", targetKind, targetName, cs.Name)
+					desc = fmt.Sprintf("Init container %q has restarted %d times (waiting reason: %s). The pod cannot start until it succeeds. Check its logs with kubectl logs -c %s.", cs.Name, cs.RestartCount, reason, cs.Name)
+				case isCrashloop:
+					title = fmt.Sprintf("%s %q has crashlooping pods", targetKind, targetName)
+					desc = "One or more pods are in CrashLoopBackOff. Check logs for the root cause."
+				default:
+					title = fmt.Sprintf("%s %q pods have %d+ restarts", targetKind, targetName, cs.RestartCount)
+					desc = fmt.Sprintf("Pods have restarted %d+ times, indicating instability.", cs.RestartCount)
 				}
-			}
 
-			var title, desc string
-			if isCrashloop {
-				title = fmt.Sprintf("%s %q has crashlooping pods", targetKind, targetName)
-				desc = "One or more pods are in CrashLoopBackOff. Check logs for the root cause."
-			} else {
-				title = fmt.Sprintf("%s %q pods have %d+ restarts", targetKind, targetName, cs.RestartCount)
-				desc = fmt.Sprintf("Pods have restarted %d+ times, indicating instability.", cs.RestartCount)
-			}
+				severity := "action"
+				if !isCrashloop && cs.RestartCount < 10 {
+					severity = "warning"
+				}
 
-			severity := "action"
-			if !isCrashloop && cs.RestartCount < 10 {
-				severity = "warning"
-			}
+				fingerprintName := targetName
+				if init {
+					fingerprintName = targetName + "/init:" + cs.Name
+				}
 
-			insights = append(insights, ClusterInsight{
-				Analyzer:    "crashlooping",
-				Category:    "reliability",
-				Severity:    severity,
-				Title:       title,
-				Description: desc,
-				TargetKind:  targetKind,
-				TargetNS:    namespace,
-				TargetName:  targetName,
-				Fingerprint: MakeFingerprint("crashlooping", targetKind, namespace, targetName),
-			})
-			break // one insight per pod is enough
+				insights = append(insights, ClusterInsight{
+					Analyzer:    "crashlooping",
+					Category:    "reliability",
+					Severity:    severity,
+					Title:       title,
+					Description: desc,
+					TargetKind:  targetKind,
+					TargetNS:    namespace,
+					TargetName:  targetName,
+					Fingerprint: MakeFingerprint("crashlooping", targetKind, namespace, fingerprintName),
+				})
+				break // one insight per pod is enough
+			}
 		}
 	}
 
@@ -93,3 +99,12 @@ func (a *crashloopingAnalyzer) Analyze(ctx context.Context, clientset kubernetes
 	return deduped, nil
 }
 
+// podWorkload resolves a pod to its owning workload, following
+// ReplicaSets to their Deployment. Unowned pods are reported as themselves.
+func podWorkload(ctx context.Context, clientset kubernetes.Interface, pod corev1.Pod) (string, string) {
+	kind, name := podutil.Owner(pod, podutil.APIReplicaSetLookup(ctx, clientset))
+	if kind == "" {
+		return "Pod", pod.Name
+	}
+	return kind, name
+}
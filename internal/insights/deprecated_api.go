@@ -0,0 +1,193 @@
+package insights
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// deprecatedAPI is a namespaced group-version and kind that a Kubernetes
+// 1.x release stopped serving, and the API that replaces it.
+type deprecatedAPI struct {
+	GroupVersion string
+	Kind         string
+	RemovedIn    int // minor version of 1.x
+	Replacement  schema.GroupVersionResource
+}
+
+// deprecatedAPIs lists the removed APIs of namespaced resources, from the
+// Kubernetes deprecated API migration guide.
+var deprecatedAPIs = func() []deprecatedAPI {
+	gvr := func(gv, resource string) schema.GroupVersionResource {
+		g, _ := schema.ParseGroupVersion(gv)
+		return g.WithResource(resource)
+	}
+	var (
+		deployments   = gvr("apps/v1", "deployments")
+		statefulSets  = gvr("apps/v1", "statefulsets")
+		daemonSets    = gvr("apps/v1", "daemonsets")
+		replicaSets   = gvr("apps/v1", "replicasets")
+		ingresses     = gvr("networking.k8s.io/v1", "ingresses")
+		netpols       = gvr("networking.k8s.io/v1", "networkpolicies")
+		roles         = gvr("rbac.authorization.k8s.io/v1", "roles")
+		roleBindings  = gvr("rbac.authorization.k8s.io/v1", "rolebindings")
+		pdbs          = gvr("policy/v1", "poddisruptionbudgets")
+		cronJobs      = gvr("batch/v1", "cronjobs")
+		hpas          = gvr("autoscaling/v2", "horizontalpodautoscalers")
+		endpointSlice = gvr("discovery.k8s.io/v1", "endpointslices")
+	)
+	return []deprecatedAPI{
+		{"extensions/v1beta1", "Deployment", 16, deployments},
+		{"extensions/v1beta1", "DaemonSet", 16, daemonSets},
+		{"extensions/v1beta1", "ReplicaSet", 16, replicaSets},
+		{"extensions/v1beta1", "NetworkPolicy", 16, netpols},
+		{"apps/v1beta1", "Deployment", 16, deployments},
+		{"apps/v1beta1", "StatefulSet", 16, statefulSets},
+		{"apps/v1beta2", "Deployment", 16, deployments},
+		{"apps/v1beta2", "StatefulSet", 16, statefulSets},
+		{"apps/v1beta2", "DaemonSet", 16, daemonSets},
+		{"apps/v1beta2", "ReplicaSet", 16, replicaSets},
+		{"extensions/v1beta1", "Ingress", 22, ingresses},
+		{"networking.k8s.io/v1beta1", "Ingress", 22, ingresses},
+		{"rbac.authorization.k8s.io/v1beta1", "Role", 22, roles},
+		{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", 22, roleBindings},
+		{"policy/v1beta1", "PodDisruptionBudget", 25, pdbs},
+		{"batch/v1beta1", "CronJob", 25, cronJobs},
+		{"autoscaling/v2beta1", "HorizontalPodAutoscaler", 25, hpas},
+		{"discovery.k8s.io/v1beta1", "EndpointSlice", 25, endpointSlice},
+		{"autoscaling/v2beta2", "HorizontalPodAutoscaler", 26, hpas},
+	}
+}()
+
+// deprecatedReplacement returns the API that replaces groupVersion for
+// kind, and the 1.x minor release that removed it. ok is false when the
+// pair is not a removed API.
+func deprecatedReplacement(groupVersion, kind string) (replacement string, removedIn int, ok bool) {
+	for _, d := range deprecatedAPIs {
+		if d.GroupVersion == groupVersion && d.Kind == kind {
+			return d.Replacement.GroupVersion().String(), d.RemovedIn, true
+		}
+	}
+	return "", 0, false
+}
+
+type deprecatedAPIAnalyzer struct {
+	dynClient dynamic.Interface
+	target    string
+}
+
+// NewDeprecatedAPIAnalyzer flags objects last written through an API that
+// is removed after the cluster's current release and no later than target
+// (e.g. "1.25"), naming the API to migrate to. Objects are stored in their current version, so the old API
+// is found in the apiVersion of managedFields entries and of the
+// kubectl last-applied-configuration annotation: whatever wrote them there
+// will fail once the API is gone. An empty target checks against the next
+// minor release after the cluster's own; the Engine's per-pass cache
+// serves the server version, so it is fetched once per pass.
+func NewDeprecatedAPIAnalyzer(dynClient dynamic.Interface, target string) Analyzer {
+	return &deprecatedAPIAnalyzer{dynClient: dynClient, target: target}
+}
+
+func (a *deprecatedAPIAnalyzer) Name() string { return "deprecated_api" }
+
+func (a *deprecatedAPIAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	ver, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	current, ok := parseMinorVersion(ver.GitVersion)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse server version %q", ver.GitVersion)
+	}
+	target := current + 1
+	if a.target != "" {
+		if target, ok = parseMinorVersion(a.target); !ok {
+			return nil, fmt.Errorf("cannot parse target version %q", a.target)
+		}
+	}
+	// An API removed at or before the current release is no longer served,
+	// so old apiVersions in managedFields only record history
+	removedBetween := func(removedIn int) bool {
+		return current < removedIn && removedIn <= target
+	}
+
+	// Only resources that replaced an API removed by target need listing
+	var replacements []schema.GroupVersionResource
+	for _, d := range deprecatedAPIs {
+		if removedBetween(d.RemovedIn) && !slices.Contains(replacements, d.Replacement) {
+			replacements = append(replacements, d.Replacement)
+		}
+	}
+
+	var insights []ClusterInsight
+	for _, gvr := range replacements {
+		list, err := a.dynClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			continue // not served or not permitted; nothing to check
+		}
+		for _, item := range list.Items {
+			versions := writtenAPIVersions(item.GetManagedFields(), item.GetAnnotations())
+			for _, gv := range slices.Sorted(maps.Keys(versions)) {
+				replacement, removedIn, ok := deprecatedReplacement(gv, item.GetKind())
+				if !ok || !removedBetween(removedIn) {
+					continue
+				}
+				insights = append(insights, ClusterInsight{
+					Analyzer:    "deprecated_api",
+					Category:    "reliability",
+					Severity:    "warning",
+					Title:       fmt.Sprintf("%s %q is written through removed API %s", item.GetKind(), item.GetName(), gv),
+					Description: fmt.Sprintf("%s %q was last written as %s by %s. That API is removed in Kubernetes 1.%d, so those writes will fail; update the manifests or tooling to %s.", item.GetKind(), item.GetName(), gv, strings.Join(versions[gv], ", "), removedIn, replacement),
+					TargetKind:  item.GetKind(),
+					TargetNS:    namespace,
+					TargetName:  item.GetName(),
+					Fingerprint: MakeFingerprint("deprecated_api", item.GetKind(), namespace, item.GetName()+"/"+gv),
+				})
+			}
+		}
+	}
+	return insights, nil
+}
+
+// writtenAPIVersions maps each apiVersion an object was written through to
+// the field managers that used it.
+func writtenAPIVersions(managed []metav1.ManagedFieldsEntry, annotations map[string]string) map[string][]string {
+	versions := make(map[string][]string)
+	for _, m := range managed {
+		if m.APIVersion != "" && !slices.Contains(versions[m.APIVersion], m.Manager) {
+			versions[m.APIVersion] = append(versions[m.APIVersion], m.Manager)
+		}
+	}
+	if last := annotations["kubectl.kubernetes.io/last-applied-configuration"]; last != "" {
+		var applied struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if json.Unmarshal([]byte(last), &applied) == nil && applied.APIVersion != "" &&
+			!slices.Contains(versions[applied.APIVersion], "kubectl") {
+			versions[applied.APIVersion] = append(versions[applied.APIVersion], "kubectl")
+		}
+	}
+	for _, managers := range versions {
+		sort.Strings(managers)
+	}
+	return versions
+}
+
+// parseMinorVersion returns the minor number of a 1.x version such as
+// "1.29", "v1.29.3" or "v1.29.3+k3s1".
+func parseMinorVersion(v string) (int, bool) {
+	var major, minor int
+	if _, err := fmt.Sscanf(strings.TrimPrefix(v, "v"), "%d.%d", &major, &minor); err != nil || major != 1 {
+		return 0, false
+	}
+	return minor, true
+}
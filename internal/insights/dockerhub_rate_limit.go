@@ -0,0 +1,102 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/imageref"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type dockerHubRateLimitAnalyzer struct{}
+
+// NewDockerHubRateLimitAnalyzer flags workloads that pull Docker Hub images
+// without an image pull secret, so pulls count against the anonymous
+// per-IP rate limit.
+func NewDockerHubRateLimitAnalyzer() Analyzer { return &dockerHubRateLimitAnalyzer{} }
+
+func (a *dockerHubRateLimitAnalyzer) Name() string { return "dockerhub_rate_limit" }
+
+func (a *dockerHubRateLimitAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	// Pull secrets attached to service accounts apply to every pod using them
+	saSecrets := make(map[string]bool)
+	if sas, err := clientset.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, sa := range sas.Items {
+			saSecrets[sa.Name] = len(sa.ImagePullSecrets) > 0
+		}
+	}
+
+	var insights []ClusterInsight
+	check := func(kind, name string, spec corev1.PodSpec) {
+		if len(spec.ImagePullSecrets) > 0 {
+			return
+		}
+		sa := spec.ServiceAccountName
+		if sa == "" {
+			sa = "default"
+		}
+		if saSecrets[sa] {
+			return
+		}
+		images := dockerHubImages(spec)
+		if len(images) == 0 {
+			return
+		}
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "dockerhub_rate_limit",
+			Category:    "reliability",
+			Severity:    "suggestion",
+			Title:       fmt.Sprintf("%s %q pulls from Docker Hub without credentials", kind, name),
+			Description: fmt.Sprintf("Image(s) %s are pulled anonymously from Docker Hub, which is rate limited per IP. Pulls can fail intermittently on new or rescheduled nodes. Add an imagePullSecret (on the pod or its service account) or use a registry mirror.", strings.Join(images, ", ")),
+			TargetKind:  kind,
+			TargetNS:    namespace,
+			TargetName:  name,
+			Fingerprint: MakeFingerprint("dockerhub_rate_limit", kind, namespace, name),
+		})
+	}
+
+	deploys, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deploys.Items {
+		check("Deployment", d.Name, d.Spec.Template.Spec)
+	}
+
+	stss, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stss.Items {
+		check("StatefulSet", s.Name, s.Spec.Template.Spec)
+	}
+
+	dss, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dss.Items {
+		check("DaemonSet", d.Name, d.Spec.Template.Spec)
+	}
+
+	return insights, nil
+}
+
+// dockerHubImages returns the distinct Docker Hub images in a pod spec.
+func dockerHubImages(spec corev1.PodSpec) []string {
+	seen := make(map[string]bool)
+	var images []string
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, c := range containers {
+			if imageref.Registry(c.Image) != imageref.DockerHub || seen[c.Image] {
+				continue
+			}
+			seen[c.Image] = true
+			images = append(images, c.Image)
+		}
+	}
+	return images
+}
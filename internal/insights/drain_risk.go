@@ -0,0 +1,169 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/podutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+type drainRiskAnalyzer struct {
+	node string
+}
+
+// NewDrainRiskAnalyzer reports workloads that would lose availability if
+// node were drained: pods with no ready replica on another node, pods no
+// controller would recreate, and pods whose PodDisruptionBudget has no
+// disruptions left. An empty node checks every cordoned node, i.e. nodes
+// already being prepared for maintenance.
+func NewDrainRiskAnalyzer(node string) Analyzer { return &drainRiskAnalyzer{node: node} }
+
+func (a *drainRiskAnalyzer) Name() string { return "drain_risk" }
+
+func (a *drainRiskAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	draining, err := a.drainingNodes(ctx, clientset)
+	if err != nil || len(draining) == 0 {
+		return nil, err
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ready replicas per controller that survive the drain, and evictable
+	// pods on the draining nodes
+	readyElsewhere := make(map[types.UID]int)
+	var evicted []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if !draining[pod.Spec.NodeName] {
+			if ref := metav1.GetControllerOf(&pod); ref != nil && podReady(pod) {
+				readyElsewhere[ref.UID]++
+			}
+			continue
+		}
+		if !podutil.IsDrainablePod(pod) {
+			continue
+		}
+		evicted = append(evicted, pod)
+	}
+
+	type risk struct {
+		kind, name string
+		nodes      map[string]bool
+		reasons    []string
+	}
+	var order []string
+	risks := make(map[string]*risk)
+	add := func(pod corev1.Pod, reason string) {
+		kind, name := podWorkload(ctx, clientset, pod)
+		key := kind + "/" + name
+		r, ok := risks[key]
+		if !ok {
+			r = &risk{kind: kind, name: name, nodes: make(map[string]bool)}
+			risks[key] = r
+			order = append(order, key)
+		}
+		r.nodes[pod.Spec.NodeName] = true
+		for _, existing := range r.reasons {
+			if existing == reason {
+				return
+			}
+		}
+		r.reasons = append(r.reasons, reason)
+	}
+
+	for _, pod := range evicted {
+		ref := metav1.GetControllerOf(&pod)
+		switch {
+		case ref == nil:
+			add(pod, fmt.Sprintf("pod %s has no controller and will not be recreated", pod.Name))
+		case readyElsewhere[ref.UID] == 0:
+			add(pod, "no ready replicas on other nodes")
+		}
+	}
+
+	for _, pdb := range pdbs.Items {
+		sel, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || sel.Empty() {
+			continue
+		}
+		var covered []corev1.Pod
+		for _, pod := range evicted {
+			if sel.Matches(labels.Set(pod.Labels)) {
+				covered = append(covered, pod)
+			}
+		}
+		if len(covered) > 0 && int(pdb.Status.DisruptionsAllowed) < len(covered) {
+			reason := fmt.Sprintf("PodDisruptionBudget %s allows %d disruption(s), drain evicts %d pod(s)",
+				pdb.Name, pdb.Status.DisruptionsAllowed, len(covered))
+			for _, pod := range covered {
+				add(pod, reason)
+			}
+		}
+	}
+
+	var insights []ClusterInsight
+	for _, key := range order {
+		r := risks[key]
+		nodes := make([]string, 0, len(r.nodes))
+		for n := range r.nodes {
+			nodes = append(nodes, n)
+		}
+		sort.Strings(nodes)
+
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "drain_risk",
+			Category:    "reliability",
+			Severity:    "action",
+			Title:       fmt.Sprintf("Draining %s would disrupt %s %q", strings.Join(nodes, ", "), r.kind, r.name),
+			Description: fmt.Sprintf("Draining would cause downtime: %s. Scale out or relax the PodDisruptionBudget before maintenance.", strings.Join(r.reasons, "; ")),
+			TargetKind:  r.kind,
+			TargetNS:    namespace,
+			TargetName:  r.name,
+			Fingerprint: MakeFingerprint("drain_risk", r.kind, namespace, r.name),
+		})
+	}
+	return insights, nil
+}
+
+// drainingNodes returns the configured node, or all cordoned nodes.
+func (a *drainRiskAnalyzer) drainingNodes(ctx context.Context, clientset kubernetes.Interface) (map[string]bool, error) {
+	if a.node != "" {
+		return map[string]bool{a.node: true}, nil
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	draining := make(map[string]bool)
+	for _, n := range nodes.Items {
+		if n.Spec.Unschedulable {
+			draining[n.Name] = true
+		}
+	}
+	return draining, nil
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
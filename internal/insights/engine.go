@@ -2,18 +2,27 @@ package insights
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"sort"
+	"sync"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// DefaultAnalyzerConcurrency is how many analyzer runs the engine keeps in
+// flight at once unless configured otherwise.
+const DefaultAnalyzerConcurrency = 4
+
 // Engine runs all analyzers across non-excluded namespaces.
 type Engine struct {
-	analyzers         []Analyzer
-	excludeNamespaces map[string]bool
-	log               *slog.Logger
+	analyzers     []Analyzer
+	skipNamespace func(ns string) bool // nil = analyze every namespace
+	logSampler    *LogSampler
+	concurrency   int // 0 = DefaultAnalyzerConcurrency
+	log           *slog.Logger
 }
 
 // NewEngine creates an insight engine with all built-in analyzers.
@@ -34,39 +43,204 @@ func NewEngine(excludeNamespaces []string) *Engine {
 			NewResourcePressureAnalyzer(),
 			NewImagePullIssuesAnalyzer(),
 			NewMissingLimitsAnalyzer(),
+			NewOrphanedPVAnalyzer(),
+			NewOrphanedEndpointsAnalyzer(),
+			NewColocationAnalyzer(),
+			NewDockerHubRateLimitAnalyzer(),
+			NewDrainRiskAnalyzer(""),
+			NewConflictingManagersAnalyzer(),
+			NewStuckNamespaceAnalyzer(),
+			NewFailedHelmReleaseAnalyzer(),
+			NewExposedDatabaseAnalyzer(),
+			NewBadStorageClassAnalyzer(),
+			NewBrokenCronJobAnalyzer(0),
+			NewOOMKilledAnalyzer(),
+			NewPendingPodsAnalyzer(0),
+			NewLatestTagAnalyzer(),
+			NewPrivilegedWorkloadAnalyzer(),
 		},
-		excludeNamespaces: excl,
-		log:               slog.Default().With("component", "insights"),
+		skipNamespace: func(ns string) bool { return excl[ns] },
+		log:           slog.Default().With("component", "insights"),
 	}
 }
 
+// AddAnalyzer registers an extra analyzer, e.g. an opt-in one that needs
+// clients beyond the core clientset.
+func (e *Engine) AddAnalyzer(a Analyzer) {
+	e.analyzers = append(e.analyzers, a)
+}
+
+// SetAnalyzer replaces the registered analyzer with the same name as a, or
+// adds a if there is none, e.g. to configure a built-in analyzer.
+func (e *Engine) SetAnalyzer(a Analyzer) {
+	for i, existing := range e.analyzers {
+		if existing.Name() == a.Name() {
+			e.analyzers[i] = a
+			return
+		}
+	}
+	e.analyzers = append(e.analyzers, a)
+}
+
+// SetConcurrency bounds how many analyzer runs (one analyzer in one
+// namespace) execute at once. Values below 1 restore the default.
+func (e *Engine) SetConcurrency(n int) {
+	e.concurrency = n
+}
+
+// SetNamespaceFilter replaces the exclusion list given to NewEngine with
+// skip, which reports whether a namespace is left out of analysis. Pass the
+// cluster scan's filter so insights cover the namespaces the scan does.
+func (e *Engine) SetNamespaceFilter(skip func(ns string) bool) {
+	e.skipNamespace = skip
+}
+
+// SetLogSampler enables log sampling for crashlooping and unready workload
+// insights. Pass nil to disable.
+func (e *Engine) SetLogSampler(s *LogSampler) {
+	e.logSampler = s
+}
+
 // Analyze runs all analyzers across all non-excluded namespaces.
 func (e *Engine) Analyze(ctx context.Context, clientset kubernetes.Interface) []ClusterInsight {
+	insights, _ := e.AnalyzeWithSummary(ctx, clientset)
+	return insights
+}
+
+// AnalyzeWithSummary runs all analyzers like Analyze and also returns a
+// per-analyzer summary of status, insight count, duration and error.
+func (e *Engine) AnalyzeWithSummary(ctx context.Context, clientset kubernetes.Interface) ([]ClusterInsight, []AnalyzerRun) {
 	// Get namespaces
 	nsList, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		e.log.Error("failed to list namespaces for analysis", "error", err)
-		return nil
+		return nil, nil
 	}
 
-	var allInsights []ClusterInsight
+	// Analyzers share one list cache per pass, so each object list is
+	// fetched once however many analyzers read it
+	clientset = newCachingClientset(clientset)
+
+	type task struct {
+		analyzer  int
+		namespace string
+	}
+	var tasks []task
 	for _, ns := range nsList.Items {
-		if e.excludeNamespaces[ns.Name] {
+		if e.skipNamespace != nil && e.skipNamespace(ns.Name) {
 			continue
 		}
-		for _, analyzer := range e.analyzers {
-			insights, err := analyzer.Analyze(ctx, clientset, ns.Name)
-			if err != nil {
-				e.log.Warn("analyzer failed", "analyzer", analyzer.Name(), "namespace", ns.Name, "error", err)
+		for i, analyzer := range e.analyzers {
+			if _, ok := analyzer.(clusterScopedAnalyzer); ok {
 				continue
 			}
-			allInsights = append(allInsights, insights...)
+			tasks = append(tasks, task{i, ns.Name})
+		}
+	}
+	for i, analyzer := range e.analyzers {
+		if _, ok := analyzer.(clusterScopedAnalyzer); ok {
+			tasks = append(tasks, task{i, ""})
+		}
+	}
+
+	concurrency := e.concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultAnalyzerConcurrency
+	}
+
+	var (
+		runsMu  sync.Mutex
+		runs    = make([]analyzerRunStats, len(e.analyzers))
+		results = make([][]ClusterInsight, len(tasks))
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, concurrency)
+	)
+	for t, tk := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			analyzer := e.analyzers[tk.analyzer]
+			start := time.Now()
+			insights, err := analyzer.Analyze(ctx, clientset, tk.namespace)
+			elapsed := time.Since(start)
+
+			runsMu.Lock()
+			defer runsMu.Unlock()
+			r := &runs[tk.analyzer]
+			r.duration += elapsed
+			r.calls++
+			if err != nil {
+				e.log.Warn("analyzer failed", "analyzer", analyzer.Name(), "namespace", tk.namespace, "error", err)
+				r.failures++
+				if r.firstErr == nil {
+					r.firstErr = err
+				}
+				return
+			}
+			r.insights += len(insights)
+			results[t] = insights
+		}()
+	}
+	wg.Wait()
+
+	// Collect in task order so output does not depend on scheduling
+	var allInsights []ClusterInsight
+	for _, found := range results {
+		allInsights = append(allInsights, found...)
+	}
+
+	if e.logSampler != nil {
+		e.logSampler.Sample(ctx, clientset, allInsights)
+	}
+
+	summary := make([]AnalyzerRun, len(e.analyzers))
+	failed := 0
+	for i, analyzer := range e.analyzers {
+		summary[i] = runs[i].summary(analyzer.Name())
+		if summary[i].Status != AnalyzerStatusOK {
+			failed++
 		}
+		e.log.Debug("analyzer run", "analyzer", summary[i].Analyzer, "status", summary[i].Status,
+			"insights", summary[i].InsightCount, "duration_ms", summary[i].DurationMS, "error", summary[i].Error)
 	}
+	e.log.Info("analyzers complete", "analyzers", len(summary), "failed", failed, "insights", len(allInsights))
 
 	// Sort by severity: action > warning > suggestion > info
 	sortInsights(allInsights)
-	return allInsights
+	return allInsights, summary
+}
+
+// analyzerRunStats accumulates one analyzer's results across namespaces.
+type analyzerRunStats struct {
+	calls    int
+	failures int
+	insights int
+	duration time.Duration
+	firstErr error
+}
+
+func (s analyzerRunStats) summary(name string) AnalyzerRun {
+	r := AnalyzerRun{
+		Analyzer:     name,
+		Status:       AnalyzerStatusOK,
+		InsightCount: s.insights,
+		DurationMS:   s.duration.Milliseconds(),
+	}
+	switch {
+	case s.failures == 0:
+	case s.failures == s.calls:
+		r.Status = AnalyzerStatusError
+	default:
+		r.Status = AnalyzerStatusPartial
+	}
+	if s.firstErr != nil {
+		r.Error = s.firstErr.Error()
+		if s.failures > 1 {
+			r.Error = fmt.Sprintf("%s (and %d more failures)", r.Error, s.failures-1)
+		}
+	}
+	return r
 }
 
 // ActiveFingerprints returns a sorted list of fingerprints from insights.
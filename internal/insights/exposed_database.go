@@ -0,0 +1,103 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// databasePorts maps well-known database ports to the engine usually
+// listening on them.
+var databasePorts = map[int32]string{
+	1433:  "SQL Server",
+	1521:  "Oracle",
+	3306:  "MySQL",
+	5432:  "PostgreSQL",
+	5984:  "CouchDB",
+	6379:  "Redis",
+	8086:  "InfluxDB",
+	9042:  "Cassandra",
+	9200:  "Elasticsearch",
+	11211: "Memcached",
+	26257: "CockroachDB",
+	27017: "MongoDB",
+}
+
+type exposedDatabaseAnalyzer struct{}
+
+// NewExposedDatabaseAnalyzer flags LoadBalancer and NodePort Services that
+// publish a well-known database port. Either type makes the database
+// reachable from outside the cluster network, and most of these engines
+// ship without authentication or TLS by default. Both the Service port and
+// a numeric targetPort are checked, so a Redis published on 80 still counts.
+func NewExposedDatabaseAnalyzer() Analyzer { return &exposedDatabaseAnalyzer{} }
+
+func (a *exposedDatabaseAnalyzer) Name() string { return "exposed_database" }
+
+func (a *exposedDatabaseAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	services, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var insights []ClusterInsight
+	for _, svc := range services.Items {
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer && svc.Spec.Type != corev1.ServiceTypeNodePort {
+			continue
+		}
+		engines := exposedDatabases(svc.Spec.Ports)
+		if len(engines) == 0 {
+			continue
+		}
+
+		// An allow-list on the load balancer narrows who can connect
+		severity := "action"
+		reach := "on every node's IP"
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			reach = "through the cloud load balancer and on every node's IP"
+			if len(svc.Spec.LoadBalancerSourceRanges) > 0 {
+				severity = "warning"
+				reach += fmt.Sprintf(" (load balancer restricted to %s)", strings.Join(svc.Spec.LoadBalancerSourceRanges, ", "))
+			}
+		}
+
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "exposed_database",
+			Category:    "security",
+			Severity:    severity,
+			Title:       fmt.Sprintf("%s service %s exposes %s", svc.Spec.Type, svc.Name, strings.Join(engines, ", ")),
+			Description: fmt.Sprintf("Service %s/%s is type %s and publishes %s, which is reachable %s. Unless this is intentional, switch it to ClusterIP and reach the database through the cluster network, a port-forward or a bastion.", namespace, svc.Name, svc.Spec.Type, strings.Join(engines, ", "), reach),
+			TargetKind:  "Service",
+			TargetNS:    namespace,
+			TargetName:  svc.Name,
+			Fingerprint: MakeFingerprint("exposed_database", "Service", namespace, svc.Name),
+		})
+	}
+	return insights, nil
+}
+
+// exposedDatabases returns the sorted database engines matched by ports.
+func exposedDatabases(ports []corev1.ServicePort) []string {
+	seen := make(map[string]bool)
+	for _, p := range ports {
+		if p.Protocol != "" && p.Protocol != corev1.ProtocolTCP {
+			continue
+		}
+		for _, port := range []int32{p.Port, p.TargetPort.IntVal} {
+			if engine, ok := databasePorts[port]; ok {
+				seen[fmt.Sprintf("%s (%d)", engine, port)] = true
+			}
+		}
+	}
+	engines := make([]string, 0, len(seen))
+	for e := range seen {
+		engines = append(engines, e)
+	}
+	sort.Strings(engines)
+	return engines
+}
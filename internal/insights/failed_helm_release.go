@@ -0,0 +1,70 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/helmrelease"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type failedHelmReleaseAnalyzer struct{}
+
+// NewFailedHelmReleaseAnalyzer flags Helm releases whose latest revision is
+// not deployed. A release left pending-install, pending-upgrade or
+// pending-rollback by an interrupted helm run holds Helm's lock, so every
+// later upgrade fails with "another operation is in progress"; a failed
+// release leaves the chart half-applied. Read from the release Secrets'
+// labels, so the release payload itself is never decoded.
+func NewFailedHelmReleaseAnalyzer() Analyzer { return &failedHelmReleaseAnalyzer{} }
+
+func (a *failedHelmReleaseAnalyzer) Name() string { return "failed_helm_release" }
+
+func (a *failedHelmReleaseAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: helmrelease.LabelSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	var revs []helmrelease.Revision
+	for i := range secrets.Items {
+		if string(secrets.Items[i].Type) != helmrelease.SecretType {
+			continue
+		}
+		if rev, ok := helmrelease.ParseRevision(&secrets.Items[i]); ok {
+			revs = append(revs, rev)
+		}
+	}
+
+	// Each revision is its own Secret; only the newest one per release counts
+	var insights []ClusterInsight
+	for _, rev := range helmrelease.Latest(revs) {
+		name, namespace := rev.Release, rev.Namespace
+		var severity, advice string
+		switch {
+		case rev.Status == "deployed", rev.Status == "superseded", rev.Status == "uninstalled":
+			continue
+		case strings.HasPrefix(rev.Status, "pending-"):
+			severity = "action"
+			advice = fmt.Sprintf("Helm treats the release as locked and rejects further upgrades. If no helm operation is still running, roll back with helm rollback %s -n %s, or delete the pending revision's Secret.", name, namespace)
+		default:
+			severity = "warning"
+			advice = fmt.Sprintf("The last operation did not complete, so the chart may be partially applied. Check helm history %s -n %s and fix the cause before upgrading again or rolling back.", name, namespace)
+		}
+
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "failed_helm_release",
+			Category:    "reliability",
+			Severity:    severity,
+			Title:       fmt.Sprintf("Helm release %q is %s (revision %d)", name, rev.Status, rev.Number),
+			Description: fmt.Sprintf("Helm release %q in namespace %q is at revision %d with status %q. %s", name, namespace, rev.Number, rev.Status, advice),
+			TargetKind:  "HelmRelease",
+			TargetNS:    namespace,
+			TargetName:  name,
+			Fingerprint: MakeFingerprint("failed_helm_release", "HelmRelease", namespace, name),
+		})
+	}
+	return insights, nil
+}
@@ -0,0 +1,281 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	helmReleaseGVR = schema.GroupVersionResource{
+		Group:    "helm.toolkit.fluxcd.io",
+		Version:  "v2",
+		Resource: "helmreleases",
+	}
+	helmRepositoryGVR = schema.GroupVersionResource{
+		Group:    "source.toolkit.fluxcd.io",
+		Version:  "v1",
+		Resource: "helmrepositories",
+	}
+)
+
+const (
+	// maxHelmIndexBytes bounds how much of a repository index.yaml is read.
+	maxHelmIndexBytes = 16 << 20
+	// helmIndexTTL is how long a fetched index is reused before refetching.
+	helmIndexTTL = time.Hour
+	// helmIndexErrorTTL is how long a failed fetch is remembered, so an
+	// unreachable repository is not retried by every namespace in a pass.
+	helmIndexErrorTTL = time.Minute
+)
+
+// helmIndex is the subset of a Helm repository index.yaml we care about.
+type helmIndex struct {
+	Entries map[string][]struct {
+		Version string `yaml:"version"`
+	} `yaml:"entries"`
+}
+
+// cachedHelmIndex is one repository's index, or the fetch in flight for it.
+type cachedHelmIndex struct {
+	done    chan struct{} // closed once index and err are set
+	index   *helmIndex
+	err     error
+	expires time.Time // zero while the fetch is in flight
+	dropped bool      // the fetch was cancelled and is not cached
+}
+
+type helmChartDriftAnalyzer struct {
+	dynClient  dynamic.Interface
+	httpClient *http.Client
+
+	mu    sync.Mutex // guards cache, not the fetches it tracks
+	cache map[string]*cachedHelmIndex
+}
+
+// NewHelmChartDriftAnalyzer creates an analyzer that compares Flux HelmRelease
+// chart versions against the latest version in their HelmRepository index.
+// It fetches remote index.yaml files, so it is not part of the default set.
+func NewHelmChartDriftAnalyzer(dynClient dynamic.Interface) Analyzer {
+	return &helmChartDriftAnalyzer{
+		dynClient:  dynClient,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      make(map[string]*cachedHelmIndex),
+	}
+}
+
+func (a *helmChartDriftAnalyzer) Name() string { return "helm_chart_drift" }
+
+func (a *helmChartDriftAnalyzer) Analyze(ctx context.Context, _ kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	list, err := a.dynClient.Resource(helmReleaseGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		// Flux helm-controller not installed — nothing to analyze
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var insights []ClusterInsight
+	for _, hr := range list.Items {
+		chart, _, _ := unstructured.NestedString(hr.Object, "spec", "chart", "spec", "chart")
+		kind, _, _ := unstructured.NestedString(hr.Object, "spec", "chart", "spec", "sourceRef", "kind")
+		repoName, _, _ := unstructured.NestedString(hr.Object, "spec", "chart", "spec", "sourceRef", "name")
+		repoNS, _, _ := unstructured.NestedString(hr.Object, "spec", "chart", "spec", "sourceRef", "namespace")
+		if chart == "" || kind != "HelmRepository" || repoName == "" {
+			continue
+		}
+		if repoNS == "" {
+			repoNS = namespace
+		}
+
+		deployed := deployedChartVersion(hr)
+		if deployed == "" {
+			continue
+		}
+
+		repoURL, err := a.repositoryURL(ctx, repoNS, repoName)
+		if err != nil || repoURL == "" {
+			continue
+		}
+
+		index, err := a.fetchIndex(ctx, repoURL)
+		if err != nil {
+			continue
+		}
+
+		latest := latestChartVersion(index, chart)
+		if latest == "" || !versionLess(deployed, latest) {
+			continue
+		}
+
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "helm_chart_drift",
+			Category:    "hygiene",
+			Severity:    "suggestion",
+			Title:       fmt.Sprintf("HelmRelease %q chart %s is outdated (%s < %s)", hr.GetName(), chart, deployed, latest),
+			Description: fmt.Sprintf("Chart %q is deployed at version %s but %s is available in HelmRepository %s/%s. Review the changelog and bump the chart version.", chart, deployed, latest, repoNS, repoName),
+			TargetKind:  "HelmRelease",
+			TargetNS:    namespace,
+			TargetName:  hr.GetName(),
+			Fingerprint: MakeFingerprint("helm_chart_drift", "HelmRelease", namespace, hr.GetName()),
+		})
+	}
+	return insights, nil
+}
+
+// deployedChartVersion returns the chart version currently applied by a HelmRelease.
+func deployedChartVersion(hr unstructured.Unstructured) string {
+	// helm.toolkit.fluxcd.io/v2: status.history[0] is the latest release
+	if history, ok, _ := unstructured.NestedSlice(hr.Object, "status", "history"); ok && len(history) > 0 {
+		if snap, ok := history[0].(map[string]interface{}); ok {
+			if v, ok := snap["chartVersion"].(string); ok && v != "" {
+				return v
+			}
+		}
+	}
+	// v2beta1/v2beta2 status field
+	if v, _, _ := unstructured.NestedString(hr.Object, "status", "lastAppliedRevision"); v != "" {
+		return v
+	}
+	return ""
+}
+
+// repositoryURL resolves the index URL of an HTTP(S) HelmRepository.
+// OCI repositories have no index and are skipped.
+func (a *helmChartDriftAnalyzer) repositoryURL(ctx context.Context, namespace, name string) (string, error) {
+	repo, err := a.dynClient.Resource(helmRepositoryGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if t, _, _ := unstructured.NestedString(repo.Object, "spec", "type"); t == "oci" {
+		return "", nil
+	}
+	u, _, _ := unstructured.NestedString(repo.Object, "spec", "url")
+	if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		return "", nil
+	}
+	return u, nil
+}
+
+// fetchIndex downloads and parses a repository index, caching it for
+// helmIndexTTL and a failure for helmIndexErrorTTL. A fetch cut short by the
+// caller's context says nothing about the repository and is not cached.
+// Analyzer runs in other namespaces that need the same repo wait for the
+// fetch in flight instead of starting their own, and start one themselves
+// if it is cancelled; the lock is only held to read and update the cache.
+func (a *helmChartDriftAnalyzer) fetchIndex(ctx context.Context, repoURL string) (*helmIndex, error) {
+	for {
+		a.mu.Lock()
+		c, ok := a.cache[repoURL]
+		if ok && (c.expires.IsZero() || time.Now().Before(c.expires)) {
+			a.mu.Unlock()
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if c.dropped {
+				continue
+			}
+			return c.index, c.err
+		}
+		c = &cachedHelmIndex{done: make(chan struct{})}
+		a.cache[repoURL] = c
+		a.mu.Unlock()
+
+		index, err := a.downloadIndex(ctx, repoURL)
+
+		a.mu.Lock()
+		c.index, c.err = index, err
+		switch {
+		case ctx.Err() != nil:
+			c.dropped = true
+			if a.cache[repoURL] == c {
+				delete(a.cache, repoURL)
+			}
+		case err != nil:
+			c.expires = time.Now().Add(helmIndexErrorTTL)
+		default:
+			c.expires = time.Now().Add(helmIndexTTL)
+		}
+		a.mu.Unlock()
+		close(c.done)
+		return index, err
+	}
+}
+
+func (a *helmChartDriftAnalyzer) downloadIndex(ctx context.Context, repoURL string) (*helmIndex, error) {
+	url := strings.TrimSuffix(repoURL, "/") + "/index.yaml"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("fetch index failed (HTTP %d)", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxHelmIndexBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read index: %w", err)
+	}
+	if len(data) > maxHelmIndexBytes {
+		return nil, fmt.Errorf("index exceeds %d bytes", maxHelmIndexBytes)
+	}
+
+	var index helmIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parse index: %w", err)
+	}
+	return &index, nil
+}
+
+// latestChartVersion returns the highest stable version of chart in the index.
+func latestChartVersion(index *helmIndex, chart string) string {
+	var latest *utilversion.Version
+	var latestRaw string
+	for _, entry := range index.Entries[chart] {
+		v, err := utilversion.ParseSemantic(strings.TrimPrefix(entry.Version, "v"))
+		if err != nil || v.PreRelease() != "" {
+			continue
+		}
+		if latest == nil || latest.LessThan(v) {
+			latest = v
+			latestRaw = entry.Version
+		}
+	}
+	return latestRaw
+}
+
+// versionLess reports whether semver a is lower than b. Unparseable versions compare as equal.
+func versionLess(a, b string) bool {
+	va, err := utilversion.ParseSemantic(strings.TrimPrefix(a, "v"))
+	if err != nil {
+		return false
+	}
+	vb, err := utilversion.ParseSemantic(strings.TrimPrefix(b, "v"))
+	if err != nil {
+		return false
+	}
+	return va.LessThan(vb)
+}
@@ -0,0 +1,54 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/imageref"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type latestTagAnalyzer struct{}
+
+// NewLatestTagAnalyzer flags workloads, Jobs and CronJobs included, running
+// images tagged "latest", or not tagged at all, which means the same. Such
+// a workload cannot be rolled back to the image it ran before, and its
+// nodes may run different builds. Images pinned by digest are fine
+// whatever their tag.
+func NewLatestTagAnalyzer() Analyzer { return &latestTagAnalyzer{} }
+
+func (a *latestTagAnalyzer) Name() string { return "latest_tag" }
+
+func (a *latestTagAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	var insights []ClusterInsight
+	check := func(kind, namespace, name string, spec corev1.PodSpec) {
+		var offending []string
+		for _, c := range slices.Concat(spec.InitContainers, spec.Containers) {
+			if ref := imageref.Parse(c.Image); ref.Digest == "" && ref.Tag == "latest" {
+				offending = append(offending, fmt.Sprintf("%s (%s)", c.Name, c.Image))
+			}
+		}
+		if len(offending) == 0 {
+			return
+		}
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "latest_tag",
+			Category:    "hygiene",
+			Severity:    "suggestion",
+			Title:       fmt.Sprintf("%s %q runs images tagged latest", kind, name),
+			Description: fmt.Sprintf("Container(s) %s use the latest tag or no tag. Which build runs depends on when each node pulled it, and rollbacks cannot restore the previous image. Pin a version tag or digest.", strings.Join(offending, ", ")),
+			TargetKind:  kind,
+			TargetNS:    namespace,
+			TargetName:  name,
+			Fingerprint: MakeFingerprint("latest_tag", kind, namespace, name),
+		})
+	}
+
+	if err := forEachPodTemplate(ctx, clientset, namespace, check); err != nil {
+		return nil, err
+	}
+	return insights, nil
+}
@@ -0,0 +1,220 @@
+package insights
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// listCache memoizes List calls for one engine pass, so analyzers that need
+// the same objects (pods, nodes, workloads) share a single API request.
+// Concurrent callers of the same list wait for the first one.
+type listCache struct {
+	mu      sync.Mutex
+	entries map[string]*listEntry
+}
+
+type listEntry struct {
+	once sync.Once
+	obj  any
+	err  error
+}
+
+func newListCache() *listCache {
+	return &listCache{entries: make(map[string]*listEntry)}
+}
+
+func (c *listCache) get(key string, fetch func() (any, error)) (any, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &listEntry{}
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() { e.obj, e.err = fetch() })
+	return e.obj, e.err
+}
+
+// cachedList returns the memoized result of list. Each caller gets its own
+// deep copy, so analyzers may modify what they receive.
+func cachedList[T interface{ DeepCopy() T }](c *listCache, resource, namespace string, opts metav1.ListOptions, list func() (T, error)) (T, error) {
+	key := strings.Join([]string{resource, namespace, opts.LabelSelector, opts.FieldSelector}, "\x00")
+	if opts.ResourceVersion != "" || opts.Limit != 0 || opts.Continue != "" {
+		return list() // paged or pinned reads are not shared
+	}
+	obj, err := c.get(key, func() (any, error) { return list() })
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return obj.(T).DeepCopy(), nil
+}
+
+// cachingClientset wraps a clientset so that List calls on the resources
+// analyzers read most, and the server version, go through a listCache.
+// Everything else passes through unchanged.
+type cachingClientset struct {
+	kubernetes.Interface
+	cache *listCache
+}
+
+func newCachingClientset(cs kubernetes.Interface) kubernetes.Interface {
+	return &cachingClientset{Interface: cs, cache: newListCache()}
+}
+
+func (c *cachingClientset) CoreV1() corev1client.CoreV1Interface {
+	return &cachingCoreV1{CoreV1Interface: c.Interface.CoreV1(), cache: c.cache}
+}
+
+func (c *cachingClientset) AppsV1() appsv1client.AppsV1Interface {
+	return &cachingAppsV1{AppsV1Interface: c.Interface.AppsV1(), cache: c.cache}
+}
+
+func (c *cachingClientset) Discovery() discovery.DiscoveryInterface {
+	return &cachingDiscovery{DiscoveryInterface: c.Interface.Discovery(), cache: c.cache}
+}
+
+type cachingDiscovery struct {
+	discovery.DiscoveryInterface
+	cache *listCache
+}
+
+func (d *cachingDiscovery) ServerVersion() (*version.Info, error) {
+	obj, err := d.cache.get("version", func() (any, error) { return d.DiscoveryInterface.ServerVersion() })
+	if err != nil {
+		return nil, err
+	}
+	info := *obj.(*version.Info)
+	return &info, nil
+}
+
+type cachingCoreV1 struct {
+	corev1client.CoreV1Interface
+	cache *listCache
+}
+
+func (c *cachingCoreV1) Pods(namespace string) corev1client.PodInterface {
+	return &cachingPods{PodInterface: c.CoreV1Interface.Pods(namespace), namespace: namespace, cache: c.cache}
+}
+
+func (c *cachingCoreV1) Nodes() corev1client.NodeInterface {
+	return &cachingNodes{NodeInterface: c.CoreV1Interface.Nodes(), cache: c.cache}
+}
+
+func (c *cachingCoreV1) PersistentVolumes() corev1client.PersistentVolumeInterface {
+	return &cachingPVs{PersistentVolumeInterface: c.CoreV1Interface.PersistentVolumes(), cache: c.cache}
+}
+
+func (c *cachingCoreV1) PersistentVolumeClaims(namespace string) corev1client.PersistentVolumeClaimInterface {
+	return &cachingPVCs{PersistentVolumeClaimInterface: c.CoreV1Interface.PersistentVolumeClaims(namespace), namespace: namespace, cache: c.cache}
+}
+
+type cachingPods struct {
+	corev1client.PodInterface
+	namespace string
+	cache     *listCache
+}
+
+func (p *cachingPods) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PodList, error) {
+	return cachedList(p.cache, "pods", p.namespace, opts, func() (*corev1.PodList, error) {
+		return p.PodInterface.List(ctx, opts)
+	})
+}
+
+type cachingNodes struct {
+	corev1client.NodeInterface
+	cache *listCache
+}
+
+func (n *cachingNodes) List(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+	return cachedList(n.cache, "nodes", "", opts, func() (*corev1.NodeList, error) {
+		return n.NodeInterface.List(ctx, opts)
+	})
+}
+
+type cachingPVs struct {
+	corev1client.PersistentVolumeInterface
+	cache *listCache
+}
+
+func (p *cachingPVs) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PersistentVolumeList, error) {
+	return cachedList(p.cache, "persistentvolumes", "", opts, func() (*corev1.PersistentVolumeList, error) {
+		return p.PersistentVolumeInterface.List(ctx, opts)
+	})
+}
+
+type cachingPVCs struct {
+	corev1client.PersistentVolumeClaimInterface
+	namespace string
+	cache     *listCache
+}
+
+func (p *cachingPVCs) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PersistentVolumeClaimList, error) {
+	return cachedList(p.cache, "persistentvolumeclaims", p.namespace, opts, func() (*corev1.PersistentVolumeClaimList, error) {
+		return p.PersistentVolumeClaimInterface.List(ctx, opts)
+	})
+}
+
+type cachingAppsV1 struct {
+	appsv1client.AppsV1Interface
+	cache *listCache
+}
+
+func (c *cachingAppsV1) Deployments(namespace string) appsv1client.DeploymentInterface {
+	return &cachingDeployments{DeploymentInterface: c.AppsV1Interface.Deployments(namespace), namespace: namespace, cache: c.cache}
+}
+
+func (c *cachingAppsV1) StatefulSets(namespace string) appsv1client.StatefulSetInterface {
+	return &cachingStatefulSets{StatefulSetInterface: c.AppsV1Interface.StatefulSets(namespace), namespace: namespace, cache: c.cache}
+}
+
+func (c *cachingAppsV1) DaemonSets(namespace string) appsv1client.DaemonSetInterface {
+	return &cachingDaemonSets{DaemonSetInterface: c.AppsV1Interface.DaemonSets(namespace), namespace: namespace, cache: c.cache}
+}
+
+type cachingDeployments struct {
+	appsv1client.DeploymentInterface
+	namespace string
+	cache     *listCache
+}
+
+func (d *cachingDeployments) List(ctx context.Context, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	return cachedList(d.cache, "deployments", d.namespace, opts, func() (*appsv1.DeploymentList, error) {
+		return d.DeploymentInterface.List(ctx, opts)
+	})
+}
+
+type cachingStatefulSets struct {
+	appsv1client.StatefulSetInterface
+	namespace string
+	cache     *listCache
+}
+
+func (s *cachingStatefulSets) List(ctx context.Context, opts metav1.ListOptions) (*appsv1.StatefulSetList, error) {
+	return cachedList(s.cache, "statefulsets", s.namespace, opts, func() (*appsv1.StatefulSetList, error) {
+		return s.StatefulSetInterface.List(ctx, opts)
+	})
+}
+
+type cachingDaemonSets struct {
+	appsv1client.DaemonSetInterface
+	namespace string
+	cache     *listCache
+}
+
+func (d *cachingDaemonSets) List(ctx context.Context, opts metav1.ListOptions) (*appsv1.DaemonSetList, error) {
+	return cachedList(d.cache, "daemonsets", d.namespace, opts, func() (*appsv1.DaemonSetList, error) {
+		return d.DaemonSetInterface.List(ctx, opts)
+	})
+}
@@ -0,0 +1,234 @@
+package insights
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultSampleTailLines = 200
+	defaultSampleMaxPods   = 10
+	defaultSampleTimeout   = 5 * time.Second
+
+	// maxSampleLogBytes caps the bytes read per pod regardless of line count.
+	maxSampleLogBytes = 256 << 10
+	// maxExcerptLines and maxExcerptLineLen bound the excerpt attached to an insight.
+	maxExcerptLines   = 3
+	maxExcerptLineLen = 200
+)
+
+// defaultErrorPatterns match common error markers in container logs.
+var defaultErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(error|fatal|panic|exception)\b`),
+	regexp.MustCompile(`(?i)\blevel=(error|fatal)\b`),
+	regexp.MustCompile(`"level"\s*:\s*"(?i:error|fatal)"`),
+}
+
+// sampledAnalyzers are the analyzers whose insights get a log sample.
+var sampledAnalyzers = map[string]bool{
+	"crashlooping":      true,
+	"unready_workloads": true,
+}
+
+// LogSampleOptions bounds the log sampling step. Zero values use defaults.
+type LogSampleOptions struct {
+	TailLines int64            // log lines fetched per pod
+	MaxPods   int              // pods sampled per Analyze run
+	Timeout   time.Duration    // per-pod log fetch timeout
+	Patterns  []*regexp.Regexp // lines matching any pattern count as errors
+}
+
+// LogSampler attaches error-rate samples from recent container logs to
+// crashlooping and unready workload insights. Logs may contain sensitive
+// data, so sampling is opt-in.
+type LogSampler struct {
+	opts LogSampleOptions
+}
+
+// NewLogSampler creates a LogSampler with the given bounds.
+func NewLogSampler(opts LogSampleOptions) *LogSampler {
+	if opts.TailLines <= 0 {
+		opts.TailLines = defaultSampleTailLines
+	}
+	if opts.MaxPods <= 0 {
+		opts.MaxPods = defaultSampleMaxPods
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultSampleTimeout
+	}
+	if len(opts.Patterns) == 0 {
+		opts.Patterns = defaultErrorPatterns
+	}
+	return &LogSampler{opts: opts}
+}
+
+// Sample fetches logs for eligible insights and sets ErrorSampleCount and
+// ErrorSample in place. Failures leave the insight unchanged.
+func (s *LogSampler) Sample(ctx context.Context, clientset kubernetes.Interface, insights []ClusterInsight) {
+	sampled := 0
+	for i := range insights {
+		if sampled >= s.opts.MaxPods {
+			return
+		}
+		ins := &insights[i]
+		if !sampledAnalyzers[ins.Analyzer] {
+			continue
+		}
+
+		pod, err := findSamplePod(ctx, clientset, ins.TargetKind, ins.TargetNS, ins.TargetName)
+		if err != nil || pod == nil {
+			continue
+		}
+		sampled++
+
+		container, previous := sampleContainer(pod)
+		// A crashlooping container's current run has usually just started;
+		// the error that killed it is in the previous one
+		previous = previous && ins.Analyzer == "crashlooping"
+		count, excerpt, err := s.samplePod(ctx, clientset, ins.TargetNS, pod.Name, container, previous)
+		if err != nil {
+			continue
+		}
+		ins.ErrorSampleCount = count
+		ins.ErrorSample = excerpt
+	}
+}
+
+func (s *LogSampler) samplePod(ctx context.Context, clientset kubernetes.Interface, namespace, pod, container string, previous bool) (int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+
+	tail := s.opts.TailLines
+	limit := int64(maxSampleLogBytes)
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container:  container,
+		Previous:   previous,
+		TailLines:  &tail,
+		LimitBytes: &limit,
+	}).Stream(ctx)
+	if err != nil {
+		return 0, "", fmt.Errorf("stream logs for %s/%s: %w", namespace, pod, err)
+	}
+	defer stream.Close()
+
+	count, excerpt := countErrorLines(bufio.NewScanner(stream), s.opts.Patterns)
+	return count, excerpt, nil
+}
+
+// sampleContainer picks the container to read logs from: one in
+// CrashLoopBackOff, else the first unready one, else the one with the most
+// restarts, checking init containers first. previous reports whether it
+// has a terminated run to read.
+func sampleContainer(pod *corev1.Pod) (name string, previous bool) {
+	statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	best, bestScore := -1, -1
+	for i, cs := range statuses {
+		score := 0
+		switch {
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff":
+			score = 2
+		case !cs.Ready && cs.State.Terminated == nil:
+			score = 1
+		}
+		if score > bestScore || score == bestScore && cs.RestartCount > statuses[best].RestartCount {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	cs := statuses[best]
+	return cs.Name, cs.LastTerminationState.Terminated != nil || cs.RestartCount > 0
+}
+
+// countErrorLines counts lines matching any pattern and returns the first few
+// matches, truncated, as an excerpt.
+func countErrorLines(sc *bufio.Scanner, patterns []*regexp.Regexp) (int, string) {
+	count := 0
+	var excerpt []string
+	for sc.Scan() {
+		line := sc.Text()
+		for _, p := range patterns {
+			if !p.MatchString(line) {
+				continue
+			}
+			count++
+			if len(excerpt) < maxExcerptLines {
+				if len(line) > maxExcerptLineLen {
+					cut := maxExcerptLineLen
+					for cut > 0 && !utf8.RuneStart(line[cut]) {
+						cut--
+					}
+					line = line[:cut] + "…"
+				}
+				excerpt = append(excerpt, line)
+			}
+			break
+		}
+	}
+	return count, strings.Join(excerpt, "\n")
+}
+
+// findSamplePod picks the pod with the most restarts for a workload insight.
+// It returns nil when the kind has no pods to sample.
+func findSamplePod(ctx context.Context, clientset kubernetes.Interface, kind, namespace, name string) (*corev1.Pod, error) {
+	var selector *metav1.LabelSelector
+	switch kind {
+	case "Pod":
+		return clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "Deployment":
+		d, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = d.Spec.Selector
+	case "StatefulSet":
+		s, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = s.Spec.Selector
+	case "DaemonSet":
+		d, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = d.Spec.Selector
+	default:
+		return nil, nil
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	var best *corev1.Pod
+	bestRestarts := int32(-1)
+	for i, pod := range pods.Items {
+		var restarts int32
+		for _, cs := range pod.Status.InitContainerStatuses {
+			restarts += cs.RestartCount
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			restarts += cs.RestartCount
+		}
+		if restarts > bestRestarts {
+			best, bestRestarts = &pods.Items[i], restarts
+		}
+	}
+	return best, nil
+}
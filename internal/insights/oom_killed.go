@@ -0,0 +1,109 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// OOMKilledWindow is how recently a container must have been OOMKilled to
+// be flagged. The last termination state is kept until the next one, so an
+// old kill would otherwise be reported for as long as the container runs.
+const OOMKilledWindow = 24 * time.Hour
+
+// defaultOOMMemoryLimit is proposed for OOMKilled containers that set
+// neither a memory limit nor a request to derive one from.
+const defaultOOMMemoryLimit = "512Mi"
+
+type oomKilledAnalyzer struct {
+	now func() time.Time
+}
+
+// NewOOMKilledAnalyzer flags containers whose last run was OOMKilled within
+// OOMKilledWindow and proposes doubling their memory limit. Pods are reported as their owning
+// workload, one insight per workload container; Pods without one get no
+// proposed action.
+func NewOOMKilledAnalyzer() Analyzer { return &oomKilledAnalyzer{now: time.Now} }
+
+func (a *oomKilledAnalyzer) Name() string { return "oom_killed" }
+
+func (a *oomKilledAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	since := a.now().Add(-OOMKilledWindow)
+	seen := make(map[string]bool)
+	var insights []ClusterInsight
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			t := cs.LastTerminationState.Terminated
+			if t == nil || t.Reason != "OOMKilled" || t.FinishedAt.Time.Before(since) {
+				continue
+			}
+			targetKind, targetName := podWorkload(ctx, clientset, pod)
+			fingerprint := MakeFingerprint("oom_killed", targetKind, namespace, targetName+"/"+cs.Name)
+			if seen[fingerprint] {
+				continue
+			}
+			seen[fingerprint] = true
+
+			current, limit := "none", defaultOOMMemoryLimit
+			if c := podContainer(pod, cs.Name); c != nil {
+				current, limit = oomMemoryLimit(c.Resources)
+			}
+			insight := ClusterInsight{
+				Analyzer:    "oom_killed",
+				Category:    "reliability",
+				Severity:    "action",
+				Title:       fmt.Sprintf("%s %q container %q was OOMKilled", targetKind, targetName, cs.Name),
+				Description: fmt.Sprintf("Container %q was killed for running out of memory (memory limit: %s) and has restarted %d times. Raise its memory limit to %s, or find what makes it use more memory than expected.", cs.Name, current, cs.RestartCount, limit),
+				TargetKind:  targetKind,
+				TargetNS:    namespace,
+				TargetName:  targetName,
+				Fingerprint: fingerprint,
+			}
+			// A bare Pod's resources can't be patched; only workloads get
+			// the proposed action
+			if targetKind != "Pod" {
+				insight.ProposedAction = "tune_resource_limits"
+				insight.ProposedParams = map[string]any{"container": cs.Name, "memory_limit": limit}
+			}
+			insights = append(insights, insight)
+		}
+	}
+	return insights, nil
+}
+
+func podContainer(pod corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// oomMemoryLimit returns a container's memory limit ("none" when unset) and
+// the limit to propose instead: twice the current limit, else twice the
+// memory request, rounded up to whole MiB.
+func oomMemoryLimit(res corev1.ResourceRequirements) (current, proposed string) {
+	current = "none"
+	base := res.Requests.Memory()
+	if lim := res.Limits.Memory(); !lim.IsZero() {
+		current = lim.String()
+		base = lim
+	}
+	if base.IsZero() {
+		return current, defaultOOMMemoryLimit
+	}
+	const mi = 1 << 20
+	doubled := (2*base.Value() + mi - 1) / mi * mi
+	return current, resource.NewQuantity(doubled, resource.BinarySI).String()
+}
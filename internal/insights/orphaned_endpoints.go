@@ -0,0 +1,106 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// endpointSliceMirroringController is the managed-by value of slices that
+// mirror a manually managed Endpoints object.
+const endpointSliceMirroringController = "endpointslicemirroring-controller.k8s.io"
+
+// leaderElectionAnnotation marks an Endpoints object used as a leader
+// election lock by older control planes rather than to route traffic.
+const leaderElectionAnnotation = "control-plane.alpha.kubernetes.io/leader"
+
+type orphanedEndpointsAnalyzer struct{}
+
+// NewOrphanedEndpointsAnalyzer flags EndpointSlices and Endpoints that belong
+// to a Service which no longer exists. Traffic can still be routed to their
+// addresses by anything that reads them directly, usually to pods that are
+// gone. EndpointSlices are tied to a Service by the kubernetes.io/service-name
+// label or an owner reference, and slices without either are managed by hand
+// and left alone. Endpoints carry neither: they belong to the Service of the
+// same name, so any Endpoints without one is flagged, bar leader election
+// locks.
+func NewOrphanedEndpointsAnalyzer() Analyzer { return &orphanedEndpointsAnalyzer{} }
+
+func (a *orphanedEndpointsAnalyzer) Name() string { return "orphaned_endpoints" }
+
+func (a *orphanedEndpointsAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	svcList, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	services := make(map[string]bool, len(svcList.Items))
+	for _, svc := range svcList.Items {
+		services[svc.Name] = true
+	}
+
+	var insights []ClusterInsight
+	slices, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, slice := range slices.Items {
+		// Mirrored slices go with their Endpoints, checked below
+		if slice.Labels[discoveryv1.LabelManagedBy] == endpointSliceMirroringController {
+			continue
+		}
+		svc := sliceService(slice)
+		if svc == "" || services[svc] {
+			continue
+		}
+		addrs := 0
+		for _, ep := range slice.Endpoints {
+			addrs += len(ep.Addresses)
+		}
+		insights = append(insights, orphanedEndpointsInsight("EndpointSlice", namespace, slice.Name, svc, addrs))
+	}
+
+	endpoints, err := clientset.CoreV1().Endpoints(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ep := range endpoints.Items {
+		if services[ep.Name] || ep.Annotations[leaderElectionAnnotation] != "" {
+			continue
+		}
+		addrs := 0
+		for _, subset := range ep.Subsets {
+			addrs += len(subset.Addresses) + len(subset.NotReadyAddresses)
+		}
+		insights = append(insights, orphanedEndpointsInsight("Endpoints", namespace, ep.Name, ep.Name, addrs))
+	}
+	return insights, nil
+}
+
+// sliceService returns the Service an EndpointSlice belongs to: the owning
+// Service reference, else the service-name label. It returns "" for slices
+// managed by hand.
+func sliceService(slice discoveryv1.EndpointSlice) string {
+	for _, ref := range slice.OwnerReferences {
+		if ref.Kind == "Service" {
+			return ref.Name
+		}
+	}
+	return slice.Labels[discoveryv1.LabelServiceName]
+}
+
+func orphanedEndpointsInsight(kind, namespace, name, service string, addrs int) ClusterInsight {
+	return ClusterInsight{
+		Analyzer:    "orphaned_endpoints",
+		Category:    "hygiene",
+		Severity:    "warning",
+		Title:       fmt.Sprintf("%s %q outlived its Service %q", kind, name, service),
+		Description: fmt.Sprintf("%s %q lists %d address(es) for Service %q, which no longer exists. Clients or controllers that read endpoints directly may still send traffic to them. Delete the %s if the Service is gone for good.", kind, name, addrs, service, kind),
+		TargetKind:  kind,
+		TargetNS:    namespace,
+		TargetName:  name,
+		Fingerprint: MakeFingerprint("orphaned_endpoints", kind, namespace, name),
+	}
+}
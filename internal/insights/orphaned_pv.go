@@ -0,0 +1,80 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type orphanedPVAnalyzer struct{}
+
+// NewOrphanedPVAnalyzer flags Released PVs with a Retain reclaim policy. Their
+// claim is gone but the backend volume is kept, so storage leaks until an
+// operator reclaims it by hand.
+func NewOrphanedPVAnalyzer() Analyzer { return &orphanedPVAnalyzer{} }
+
+func (a *orphanedPVAnalyzer) Name() string { return "orphaned_pv" }
+
+func (a *orphanedPVAnalyzer) clusterScoped() {}
+
+func (a *orphanedPVAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, _ string) ([]ClusterInsight, error) {
+	pvList, err := clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var insights []ClusterInsight
+	for _, pv := range pvList.Items {
+		if pv.Status.Phase != corev1.VolumeReleased || pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+			continue
+		}
+
+		formerClaim := "unknown"
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			formerClaim = ref.Namespace + "/" + ref.Name
+		}
+		handle := pvVolumeHandle(pv)
+		if handle == "" {
+			handle = "unknown"
+		}
+		capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "orphaned_pv",
+			Category:    "hygiene",
+			Severity:    "suggestion",
+			Title:       fmt.Sprintf("PV %q is Released and retained without a claim", pv.Name),
+			Description: fmt.Sprintf("PV %q (%s, formerly bound to %s) has reclaim policy Retain and its claim was deleted. The backend volume %s still consumes storage. Back it up if needed, then delete the PV and the backend volume.", pv.Name, capacity.String(), formerClaim, handle),
+			TargetKind:  "PersistentVolume",
+			TargetNS:    "",
+			TargetName:  pv.Name,
+			Fingerprint: MakeFingerprint("orphaned_pv", "PersistentVolume", "", pv.Name),
+		})
+	}
+	return insights, nil
+}
+
+// pvVolumeHandle returns the backend identifier of a PV's volume source.
+func pvVolumeHandle(pv corev1.PersistentVolume) string {
+	src := pv.Spec.PersistentVolumeSource
+	switch {
+	case src.CSI != nil:
+		return fmt.Sprintf("%s (driver %s)", src.CSI.VolumeHandle, src.CSI.Driver)
+	case src.AWSElasticBlockStore != nil:
+		return src.AWSElasticBlockStore.VolumeID
+	case src.GCEPersistentDisk != nil:
+		return src.GCEPersistentDisk.PDName
+	case src.AzureDisk != nil:
+		return src.AzureDisk.DataDiskURI
+	case src.NFS != nil:
+		return src.NFS.Server + ":" + src.NFS.Path
+	case src.Local != nil:
+		return src.Local.Path
+	case src.HostPath != nil:
+		return src.HostPath.Path
+	}
+	return ""
+}
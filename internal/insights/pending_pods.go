@@ -0,0 +1,86 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultPendingPodThreshold is how long a pod may wait to be scheduled
+// before it is flagged, unless configured otherwise.
+const defaultPendingPodThreshold = 10 * time.Minute
+
+type pendingPodsAnalyzer struct {
+	threshold time.Duration
+}
+
+// NewPendingPodsAnalyzer flags Pending pods the scheduler has failed to
+// place (PodScheduled=False) for longer than threshold, quoting the
+// scheduler's reason, e.g. insufficient memory or an unmatched node
+// selector. threshold of 0 or less selects the default of 10 minutes.
+func NewPendingPodsAnalyzer(threshold time.Duration) Analyzer {
+	if threshold <= 0 {
+		threshold = defaultPendingPodThreshold
+	}
+	return &pendingPodsAnalyzer{threshold: threshold}
+}
+
+func (a *pendingPodsAnalyzer) Name() string { return "pending_pods" }
+
+func (a *pendingPodsAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var insights []ClusterInsight
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		cond := unscheduledCondition(pod)
+		if cond == nil {
+			continue
+		}
+		since := pod.CreationTimestamp.Time
+		if !cond.LastTransitionTime.IsZero() {
+			since = cond.LastTransitionTime.Time
+		}
+		if now.Sub(since) < a.threshold {
+			continue
+		}
+
+		message := cond.Message
+		if message == "" {
+			message = "no message from the scheduler"
+		}
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "pending_pods",
+			Category:    "scheduling",
+			Severity:    "warning",
+			Title:       fmt.Sprintf("Pod %q cannot be scheduled", pod.Name),
+			Description: fmt.Sprintf("Pod %q has been Pending for %s (%s): %s", pod.Name, now.Sub(since).Round(time.Minute), cond.Reason, message),
+			TargetKind:  "Pod",
+			TargetNS:    namespace,
+			TargetName:  pod.Name,
+			Fingerprint: MakeFingerprint("pending_pods", "Pod", namespace, pod.Name),
+		})
+	}
+	return insights, nil
+}
+
+// unscheduledCondition returns pod's PodScheduled condition when it is
+// False, else nil.
+func unscheduledCondition(pod corev1.Pod) *corev1.PodCondition {
+	for i, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package insights
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// forEachPodTemplate calls fn with the pod template of every Deployment,
+// StatefulSet, DaemonSet, CronJob and Job in namespace. Jobs created by a
+// CronJob are covered by the CronJob's job template and skipped.
+func forEachPodTemplate(ctx context.Context, clientset kubernetes.Interface, namespace string, fn func(kind, namespace, name string, spec corev1.PodSpec)) error {
+	deploys, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, d := range deploys.Items {
+		fn("Deployment", d.Namespace, d.Name, d.Spec.Template.Spec)
+	}
+	stss, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, s := range stss.Items {
+		fn("StatefulSet", s.Namespace, s.Name, s.Spec.Template.Spec)
+	}
+	dss, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, d := range dss.Items {
+		fn("DaemonSet", d.Namespace, d.Name, d.Spec.Template.Spec)
+	}
+	cronJobs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, cj := range cronJobs.Items {
+		fn("CronJob", cj.Namespace, cj.Name, cj.Spec.JobTemplate.Spec.Template.Spec)
+	}
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs.Items {
+		if ref := metav1.GetControllerOf(&j); ref == nil || ref.Kind != "CronJob" {
+			fn("Job", j.Namespace, j.Name, j.Spec.Template.Spec)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type privilegedWorkloadAnalyzer struct{}
+
+// NewPrivilegedWorkloadAnalyzer flags workloads, Jobs and CronJobs
+// included, and standalone pods whose pod spec reaches into the node:
+// privileged containers, the host network or PID namespace, and hostPath
+// volumes. Each insight names every finding for its workload.
+func NewPrivilegedWorkloadAnalyzer() Analyzer { return &privilegedWorkloadAnalyzer{} }
+
+func (a *privilegedWorkloadAnalyzer) Name() string { return "privileged_workload" }
+
+func (a *privilegedWorkloadAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]ClusterInsight, error) {
+	var insights []ClusterInsight
+	check := func(kind, namespace, name string, spec corev1.PodSpec) {
+		findings := privilegedFindings(spec)
+		if len(findings) == 0 {
+			return
+		}
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "privileged_workload",
+			Category:    "security",
+			Severity:    "warning",
+			Title:       fmt.Sprintf("%s %q has access to its node", kind, name),
+			Description: fmt.Sprintf("%s %q: %s. A compromised container can use this to take over the node. Drop what the workload does not need.", kind, name, strings.Join(findings, "; ")),
+			TargetKind:  kind,
+			TargetNS:    namespace,
+			TargetName:  name,
+			Fingerprint: MakeFingerprint("privileged_workload", kind, namespace, name),
+		})
+	}
+
+	if err := forEachPodTemplate(ctx, clientset, namespace, check); err != nil {
+		return nil, err
+	}
+	// Pods of a controller are covered by it, or by the workload above it
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if metav1.GetControllerOf(&pod) == nil {
+			check("Pod", pod.Namespace, pod.Name, pod.Spec)
+		}
+	}
+	return insights, nil
+}
+
+// privilegedFindings describes each way spec gives its containers access
+// to the node.
+func privilegedFindings(spec corev1.PodSpec) []string {
+	var findings []string
+	for _, c := range slices.Concat(spec.InitContainers, spec.Containers) {
+		if sc := c.SecurityContext; sc != nil && sc.Privileged != nil && *sc.Privileged {
+			findings = append(findings, fmt.Sprintf("container %q is privileged", c.Name))
+		}
+	}
+	if spec.HostNetwork {
+		findings = append(findings, "uses the host network")
+	}
+	if spec.HostPID {
+		findings = append(findings, "shares the host PID namespace")
+	}
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			findings = append(findings, fmt.Sprintf("mounts host path %s as volume %q", v.HostPath.Path, v.Name))
+		}
+	}
+	return findings
+}
@@ -21,8 +21,10 @@ type Reporter struct {
 	httpClient *http.Client
 	log        *slog.Logger
 
-	// Track last-seen fingerprint set to skip unnecessary uploads
+	// Track last-seen fingerprint set and analyzer statuses to skip
+	// unnecessary uploads
 	lastFingerprints string
+	lastSummary      string
 }
 
 // NewReporter creates a new insight reporter.
@@ -38,14 +40,16 @@ func NewReporter(baseURL, token, anonKey string) *Reporter {
 	}
 }
 
-// Report uploads insights to the SaaS endpoint.
-// Returns true if the upload was performed, false if skipped (unchanged).
-func (r *Reporter) Report(ctx context.Context, insights []ClusterInsight) (bool, error) {
+// Report uploads insights to the SaaS endpoint. A non-nil summary is sent
+// along with them. Returns true if the upload was performed, false if
+// skipped (unchanged fingerprints and analyzer statuses).
+func (r *Reporter) Report(ctx context.Context, insights []ClusterInsight, summary []AnalyzerRun) (bool, error) {
 	fps := ActiveFingerprints(insights)
 
-	// Check if fingerprints changed since last sync
+	// Check if fingerprints or an analyzer's status changed since last sync
 	fpsKey := strings.Join(fps, ",")
-	if fpsKey == r.lastFingerprints {
+	summaryKey := analyzerSummaryKey(summary)
+	if fpsKey == r.lastFingerprints && summaryKey == r.lastSummary {
 		r.log.Debug("insights unchanged, skipping sync", "count", len(insights))
 		return false, nil
 	}
@@ -54,6 +58,7 @@ func (r *Reporter) Report(ctx context.Context, insights []ClusterInsight) (bool,
 		AgentToken:         r.token,
 		Insights:           insights,
 		ActiveFingerprints: fps,
+		AnalyzerSummary:    summary,
 	}
 
 	body, err := json.Marshal(req)
@@ -90,10 +95,22 @@ func (r *Reporter) Report(ctx context.Context, insights []ClusterInsight) (bool,
 	}
 
 	r.lastFingerprints = fpsKey
+	r.lastSummary = summaryKey
 	r.log.Info("insights synced", "upserted", result.Upserted, "auto_resolved", result.AutoResolved)
 	return true, nil
 }
 
+// analyzerSummaryKey returns a comparable string for the status and error
+// of each analyzer run. Durations change every pass and are left out.
+func analyzerSummaryKey(summary []AnalyzerRun) string {
+	keys := make([]string, 0, len(summary))
+	for _, run := range summary {
+		keys = append(keys, run.Analyzer+"="+run.Status+":"+run.Error)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\n")
+}
+
 // FingerprintSetKey returns a comparable string for a set of fingerprints.
 func FingerprintSetKey(fps []string) string {
 	sorted := make([]string, len(fps))
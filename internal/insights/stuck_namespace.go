@@ -0,0 +1,80 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// stuckNamespaceAfter is how long a namespace may spend Terminating before
+// it is flagged. Deleting a large namespace legitimately takes minutes.
+const stuckNamespaceAfter = 15 * time.Minute
+
+type stuckNamespaceAnalyzer struct{}
+
+// NewStuckNamespaceAnalyzer flags namespaces stuck in Terminating, usually
+// because a finalizer on a custom resource inside them has no controller
+// left to clear it, or an aggregated API is down so the namespace controller
+// cannot enumerate content. Not auto-remediable: force-removing finalizers
+// can orphan external resources.
+func NewStuckNamespaceAnalyzer() Analyzer { return &stuckNamespaceAnalyzer{} }
+
+func (a *stuckNamespaceAnalyzer) Name() string { return "stuck_namespace" }
+
+func (a *stuckNamespaceAnalyzer) clusterScoped() {}
+
+func (a *stuckNamespaceAnalyzer) Analyze(ctx context.Context, clientset kubernetes.Interface, _ string) ([]ClusterInsight, error) {
+	nsList, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-stuckNamespaceAfter)
+	var insights []ClusterInsight
+	for _, ns := range nsList.Items {
+		if ns.Status.Phase != corev1.NamespaceTerminating || ns.DeletionTimestamp == nil || ns.DeletionTimestamp.Time.After(cutoff) {
+			continue
+		}
+		stuckMinutes := int(math.Round(time.Since(ns.DeletionTimestamp.Time).Minutes()))
+
+		insights = append(insights, ClusterInsight{
+			Analyzer:    "stuck_namespace",
+			Category:    "reliability",
+			Severity:    "action",
+			Title:       fmt.Sprintf("Namespace %q stuck terminating for %dmin", ns.Name, stuckMinutes),
+			Description: fmt.Sprintf("Namespace %q has been Terminating since %s. %s", ns.Name, ns.DeletionTimestamp.Format(time.RFC3339), namespaceStuckCause(ns)),
+			TargetKind:  "Namespace",
+			TargetNS:    "",
+			TargetName:  ns.Name,
+			Fingerprint: MakeFingerprint("stuck_namespace", "Namespace", "", ns.Name),
+		})
+	}
+	return insights, nil
+}
+
+// namespaceStuckCause explains why deletion is blocked from the conditions
+// the namespace controller sets while it works.
+func namespaceStuckCause(ns corev1.Namespace) string {
+	var causes []string
+	for _, cond := range ns.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case corev1.NamespaceFinalizersRemaining, corev1.NamespaceContentRemaining,
+			corev1.NamespaceDeletionDiscoveryFailure, corev1.NamespaceDeletionContentFailure,
+			corev1.NamespaceDeletionGVParsingFailure:
+			causes = append(causes, cond.Message)
+		}
+	}
+	if len(causes) == 0 {
+		return "The likely cause is a finalizer on a resource inside it whose controller is gone. Find it with kubectl api-resources --verbs=list --namespaced -o name | xargs -n1 kubectl get -n " + ns.Name + " --ignore-not-found."
+	}
+	return "Namespace controller reports: " + strings.Join(causes, "; ") + ". Restore the missing controller or API service, or remove the stale finalizers by hand after confirming nothing external depends on them."
+}
@@ -14,6 +14,26 @@ type ClusterInsight struct {
 	ProposedAction string            `json:"proposed_action,omitempty"`
 	ProposedParams map[string]any    `json:"proposed_params,omitempty"`
 	AutoRemediable bool              `json:"auto_remediable,omitempty"`
+
+	// Set by the opt-in LogSampler for crashlooping/unready workloads
+	ErrorSampleCount int    `json:"error_sample_count,omitempty"`
+	ErrorSample      string `json:"error_sample,omitempty"`
+}
+
+// Analyzer run statuses reported in AnalyzerRun.
+const (
+	AnalyzerStatusOK      = "ok"
+	AnalyzerStatusPartial = "partial" // failed in some namespaces
+	AnalyzerStatusError   = "error"   // failed everywhere it ran
+)
+
+// AnalyzerRun summarizes one analyzer's results for a single engine pass.
+type AnalyzerRun struct {
+	Analyzer     string `json:"analyzer"`
+	Status       string `json:"status"`
+	InsightCount int    `json:"insight_count"`
+	DurationMS   int64  `json:"duration_ms"`
+	Error        string `json:"error,omitempty"`
 }
 
 // SyncRequest is the payload for POST /functions/v1/cluster-insights/sync.
@@ -21,6 +41,7 @@ type SyncRequest struct {
 	AgentToken         string           `json:"agent_token"`
 	Insights           []ClusterInsight `json:"insights"`
 	ActiveFingerprints []string         `json:"active_fingerprints"`
+	AnalyzerSummary    []AnalyzerRun    `json:"analyzer_summary,omitempty"`
 }
 
 // SyncResponse is the response from cluster-insights/sync.
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"time"
 )
 
@@ -102,5 +103,6 @@ func (p *HueProvider) Discover(ctx context.Context) ([]Device, error) {
 		})
 	}
 
+	sort.Slice(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
 	return devices, nil
 }
@@ -3,7 +3,10 @@ package iot
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"testing"
+	"time"
 )
 
 func TestClassifyDomain(t *testing.T) {
@@ -284,6 +287,48 @@ func TestRegistryScanNoProviders(t *testing.T) {
 	}
 }
 
+// fakeProvider is a Provider with canned Detect/Discover results.
+type fakeProvider struct {
+	name        string
+	detected    bool
+	detectErr   error
+	devices     []Device
+	discoverErr error
+}
+
+func (f *fakeProvider) Name() string                               { return f.name }
+func (f *fakeProvider) Detect(context.Context) (bool, error)       { return f.detected, f.detectErr }
+func (f *fakeProvider) Discover(context.Context) ([]Device, error) { return f.devices, f.discoverErr }
+
+func TestRegistryScanSurfacesProviderErrors(t *testing.T) {
+	reg := &Registry{
+		all: []Provider{
+			&fakeProvider{name: "homeassistant", detected: true, discoverErr: errors.New("HTTP 401")},
+			&fakeProvider{name: "hue", detected: true, devices: []Device{{ID: "hue-1", Type: TypeLight}}},
+			&fakeProvider{name: "unifi", detectErr: errors.New("connection refused")},
+		},
+		log: slog.Default(),
+	}
+
+	result := reg.Scan(context.Background())
+
+	if len(result.Providers) != 2 {
+		t.Errorf("providers = %v, want [homeassistant hue]", result.Providers)
+	}
+	if len(result.Devices) != 1 {
+		t.Errorf("got %d devices, want 1", len(result.Devices))
+	}
+	if len(result.Errors) != 2 {
+		t.Fatalf("got %d errors, want 2: %+v", len(result.Errors), result.Errors)
+	}
+	if result.Errors[0].Provider != "homeassistant" || result.Errors[0].Message != "HTTP 401" {
+		t.Errorf("unexpected discover error: %+v", result.Errors[0])
+	}
+	if result.Errors[1].Provider != "unifi" {
+		t.Errorf("unexpected detect error: %+v", result.Errors[1])
+	}
+}
+
 func TestDeviceTypeConstants(t *testing.T) {
 	types := []DeviceType{
 		TypeLight, TypeSwitch, TypeThermostat, TypeLock, TypeCamera,
@@ -300,3 +345,80 @@ func TestDeviceTypeConstants(t *testing.T) {
 		}
 	}
 }
+
+// countingProvider counts Detect and Discover calls.
+type countingProvider struct {
+	fakeProvider
+	detects, discovers int
+}
+
+func (c *countingProvider) Detect(ctx context.Context) (bool, error) {
+	c.detects++
+	return c.fakeProvider.Detect(ctx)
+}
+
+func (c *countingProvider) Discover(ctx context.Context) ([]Device, error) {
+	c.discovers++
+	return c.fakeProvider.Discover(ctx)
+}
+
+func TestRegistryScanCachesWithinTTL(t *testing.T) {
+	unifi := &countingProvider{fakeProvider: fakeProvider{name: "unifi", detected: true, devices: []Device{{ID: "aa:bb", Type: TypeCamera}}}}
+	broken := &countingProvider{fakeProvider: fakeProvider{name: "homeassistant", detected: true, discoverErr: errors.New("HTTP 500")}}
+
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	reg := &Registry{
+		all: []Provider{unifi, broken},
+		log: slog.Default(),
+		ttl: 5 * time.Minute,
+		now: func() time.Time { return clock },
+	}
+
+	first := reg.Scan(context.Background())
+	if len(first.Devices) != 1 || first.CacheAgeSeconds != nil {
+		t.Fatalf("first scan = %+v, want 1 fresh device", first)
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	second := reg.Scan(context.Background())
+	if unifi.detects != 1 || unifi.discovers != 1 {
+		t.Errorf("within TTL: detect=%d discover=%d, want 1 each", unifi.detects, unifi.discovers)
+	}
+	if len(second.Devices) != 1 || second.Devices[0].ID != "aa:bb" {
+		t.Errorf("cached scan devices = %+v", second.Devices)
+	}
+	if got := second.CacheAgeSeconds["unifi"]; got != 120 {
+		t.Errorf("cache age = %d, want 120 (%+v)", got, second.CacheAgeSeconds)
+	}
+	if len(second.Providers) != 2 {
+		t.Errorf("cached provider should still be listed: %v", second.Providers)
+	}
+	// Failures are not cached
+	if broken.discovers != 2 || len(second.Errors) != 1 {
+		t.Errorf("failed provider discover=%d errors=%+v, want retried", broken.discovers, second.Errors)
+	}
+	if _, ok := second.CacheAgeSeconds["homeassistant"]; ok {
+		t.Error("failed provider reported as cached")
+	}
+
+	clock = clock.Add(4 * time.Minute)
+	third := reg.Scan(context.Background())
+	if unifi.discovers != 2 {
+		t.Errorf("stale cache: discover=%d, want refresh", unifi.discovers)
+	}
+	if third.CacheAgeSeconds != nil {
+		t.Errorf("refreshed scan reports cache age: %+v", third.CacheAgeSeconds)
+	}
+}
+
+func TestRegistrySetCacheTTLDisables(t *testing.T) {
+	p := &countingProvider{fakeProvider: fakeProvider{name: "hue", detected: true}}
+	reg := &Registry{all: []Provider{p}, log: slog.Default(), ttl: DefaultCacheTTL}
+	reg.SetCacheTTL(0)
+
+	reg.Scan(context.Background())
+	reg.Scan(context.Background())
+	if p.discovers != 2 {
+		t.Errorf("discover = %d, want 2 with caching disabled", p.discovers)
+	}
+}
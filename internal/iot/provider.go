@@ -34,8 +34,17 @@ type Device struct {
 
 // DiscoveryResult holds all discovered IoT devices.
 type DiscoveryResult struct {
-	Providers []string `json:"providers"`
-	Devices   []Device `json:"devices"`
+	Providers []string        `json:"providers"`
+	Devices   []Device        `json:"devices"`
+	Errors    []ProviderError `json:"errors,omitempty"`
+	// Providers answered from cache, with the cached data's age
+	CacheAgeSeconds map[string]int `json:"cache_age_seconds,omitempty"`
+}
+
+// ProviderError records a provider that failed during detection or discovery.
+type ProviderError struct {
+	Provider string `json:"provider"`
+	Message  string `json:"message"`
 }
 
 // Provider is implemented by each IoT discovery source.
@@ -3,12 +3,30 @@ package iot
 import (
 	"context"
 	"log/slog"
+	"sync"
+	"time"
 )
 
+// DefaultCacheTTL is how long a provider's discovery is reused by
+// NewRegistry. UniFi logins and Home Assistant full-state fetches are too
+// expensive to repeat on every short scan cycle.
+const DefaultCacheTTL = 3 * time.Minute
+
 // Registry manages IoT providers and auto-detects available ones.
 type Registry struct {
 	all []Provider
 	log *slog.Logger
+
+	// Per-provider discovery cache; a zero ttl disables it
+	ttl   time.Duration
+	mu    sync.Mutex
+	cache map[string]cachedDiscovery
+	now   func() time.Time
+}
+
+type cachedDiscovery struct {
+	devices []Device
+	fetched time.Time
 }
 
 // NewRegistry creates a registry with all known IoT providers.
@@ -21,17 +39,49 @@ func NewRegistry() *Registry {
 			NewUniFiProvider(),
 		},
 		log: slog.Default().With("component", "iot"),
+		ttl: DefaultCacheTTL,
 	}
 }
 
-// Scan detects providers and discovers all IoT devices.
+// SetCacheTTL sets how long each provider's discovered devices are reused
+// before the provider is queried again. Zero or negative disables caching.
+func (r *Registry) SetCacheTTL(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ttl = ttl
+	r.cache = nil
+}
+
+// Scan detects providers and discovers all IoT devices. Providers with a
+// discovery younger than the cache TTL are not queried; their cached
+// devices are returned and the cache age reported in CacheAgeSeconds.
 func (r *Registry) Scan(ctx context.Context) DiscoveryResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	result := DiscoveryResult{}
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
 
 	for _, p := range r.all {
+		if cached, ok := r.cache[p.Name()]; ok && r.ttl > 0 {
+			if age := now().Sub(cached.fetched); age < r.ttl {
+				result.Providers = append(result.Providers, p.Name())
+				result.Devices = append(result.Devices, cached.devices...)
+				if result.CacheAgeSeconds == nil {
+					result.CacheAgeSeconds = make(map[string]int)
+				}
+				result.CacheAgeSeconds[p.Name()] = int(age.Seconds())
+				continue
+			}
+		}
+
 		ok, err := p.Detect(ctx)
 		if err != nil {
 			r.log.Debug("iot provider detection failed", "provider", p.Name(), "error", err)
+			result.Errors = append(result.Errors, ProviderError{Provider: p.Name(), Message: err.Error()})
 			continue
 		}
 		if !ok {
@@ -44,9 +94,17 @@ func (r *Registry) Scan(ctx context.Context) DiscoveryResult {
 		devices, err := p.Discover(ctx)
 		if err != nil {
 			r.log.Warn("iot discovery failed", "provider", p.Name(), "error", err)
+			result.Errors = append(result.Errors, ProviderError{Provider: p.Name(), Message: err.Error()})
 			continue
 		}
 		result.Devices = append(result.Devices, devices...)
+
+		if r.ttl > 0 {
+			if r.cache == nil {
+				r.cache = make(map[string]cachedDiscovery)
+			}
+			r.cache[p.Name()] = cachedDiscovery{devices: devices, fetched: now()}
+		}
 	}
 
 	return result
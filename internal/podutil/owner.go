@@ -0,0 +1,54 @@
+package podutil
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ReplicaSetLookup returns the controller of the named ReplicaSet, or nil
+// when it has none. An error means the ReplicaSet could not be fetched.
+type ReplicaSetLookup func(namespace, name string) (*metav1.OwnerReference, error)
+
+// APIReplicaSetLookup looks ReplicaSets up through clientset.
+func APIReplicaSetLookup(ctx context.Context, clientset kubernetes.Interface) ReplicaSetLookup {
+	return func(namespace, name string) (*metav1.OwnerReference, error) {
+		rs, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return metav1.GetControllerOf(rs), nil
+	}
+}
+
+// Owner returns the workload that controls pod, or "" for pods without a
+// controller. Pods of a ReplicaSet are reported as the ReplicaSet's own
+// controller, normally a Deployment, found with lookup. When lookup is nil
+// or fails, a ReplicaSet named after the Deployment plus the pod's
+// pod-template-hash is taken to belong to that Deployment.
+func Owner(pod corev1.Pod, lookup ReplicaSetLookup) (kind, name string) {
+	ref := metav1.GetControllerOf(&pod)
+	if ref == nil {
+		return "", ""
+	}
+	if ref.Kind != "ReplicaSet" {
+		return ref.Kind, ref.Name
+	}
+	if lookup != nil {
+		if owner, err := lookup(pod.Namespace, ref.Name); err == nil {
+			if owner == nil {
+				return ref.Kind, ref.Name
+			}
+			return owner.Kind, owner.Name
+		}
+	}
+	if hash := pod.Labels["pod-template-hash"]; hash != "" {
+		if deploy, ok := strings.CutSuffix(ref.Name, "-"+hash); ok {
+			return "Deployment", deploy
+		}
+	}
+	return ref.Kind, ref.Name
+}
@@ -0,0 +1,31 @@
+// Package podutil holds pod classification shared by the drain command,
+// the insight analyzers and the cluster scanner.
+package podutil
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// configSourceAnnotation records where the kubelet got a pod from: "api"
+// for pods from the API server, "file" or "http" for static pods.
+const configSourceAnnotation = "kubernetes.io/config.source"
+
+// IsDrainablePod reports whether draining a node would move pod off it.
+// kubectl drain leaves three kinds of pods in place:
+//   - DaemonSet pods, which the DaemonSet controller keeps on every node
+//   - mirror pods, the API server's read-only copies of static pods
+//   - static pods, which the kubelet runs from its manifest directory and
+//     recreates regardless; their mirrors are owned by the Node
+func IsDrainablePod(pod corev1.Pod) bool {
+	if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+		return false
+	}
+	if src, ok := pod.Annotations[configSourceAnnotation]; ok && src != "api" {
+		return false
+	}
+	if ref := metav1.GetControllerOf(&pod); ref != nil && (ref.Kind == "DaemonSet" || ref.Kind == "Node") {
+		return false
+	}
+	return true
+}
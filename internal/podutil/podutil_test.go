@@ -0,0 +1,82 @@
+package podutil
+
+import (
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsDrainablePod(t *testing.T) {
+	isController := true
+	owned := func(kind string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: "owner", Controller: &isController}}
+	}
+	tests := []struct {
+		name string
+		meta metav1.ObjectMeta
+		want bool
+	}{
+		{"bare pod", metav1.ObjectMeta{}, true},
+		{"replicaset pod", metav1.ObjectMeta{OwnerReferences: owned("ReplicaSet")}, true},
+		{"statefulset pod", metav1.ObjectMeta{OwnerReferences: owned("StatefulSet")}, true},
+		{"api-sourced pod", metav1.ObjectMeta{Annotations: map[string]string{"kubernetes.io/config.source": "api"}}, true},
+		{"daemonset pod", metav1.ObjectMeta{OwnerReferences: owned("DaemonSet")}, false},
+		{"mirror pod", metav1.ObjectMeta{Annotations: map[string]string{corev1.MirrorPodAnnotationKey: "3f2c"}}, false},
+		{"static pod from file", metav1.ObjectMeta{Annotations: map[string]string{"kubernetes.io/config.source": "file"}}, false},
+		{"static pod owned by node", metav1.ObjectMeta{OwnerReferences: owned("Node")}, false},
+		// Not the controller: does not pin the pod
+		{"daemonset non-controller ref", metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "x"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDrainablePod(corev1.Pod{ObjectMeta: tt.meta}); got != tt.want {
+				t.Errorf("IsDrainablePod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwner(t *testing.T) {
+	isController := true
+	pod := func(kind, name, hash string) corev1.Pod {
+		p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Labels: map[string]string{}}}
+		if kind != "" {
+			p.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &isController}}
+		}
+		if hash != "" {
+			p.Labels["pod-template-hash"] = hash
+		}
+		return p
+	}
+	deployment := func(string, string) (*metav1.OwnerReference, error) {
+		return &metav1.OwnerReference{Kind: "Deployment", Name: "api"}, nil
+	}
+	unowned := func(string, string) (*metav1.OwnerReference, error) { return nil, nil }
+	failing := func(string, string) (*metav1.OwnerReference, error) { return nil, errors.New("forbidden") }
+
+	tests := []struct {
+		name      string
+		pod       corev1.Pod
+		lookup    ReplicaSetLookup
+		wantKind  string
+		wantOwner string
+	}{
+		{"bare pod", pod("", "", ""), nil, "", ""},
+		{"statefulset pod", pod("StatefulSet", "db", ""), deployment, "StatefulSet", "db"},
+		{"replicaset looked up", pod("ReplicaSet", "api-7d9f8c6b5", ""), deployment, "Deployment", "api"},
+		{"replicaset without controller", pod("ReplicaSet", "standalone", ""), unowned, "ReplicaSet", "standalone"},
+		{"replicaset from hash", pod("ReplicaSet", "web-7d9f8c6b5", "7d9f8c6b5"), nil, "Deployment", "web"},
+		{"lookup fails, hash used", pod("ReplicaSet", "web-7d9f8c6b5", "7d9f8c6b5"), failing, "Deployment", "web"},
+		{"replicaset without hash", pod("ReplicaSet", "standalone", ""), nil, "ReplicaSet", "standalone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, name := Owner(tt.pod, tt.lookup)
+			if kind != tt.wantKind || name != tt.wantOwner {
+				t.Errorf("Owner() = %s/%s, want %s/%s", kind, name, tt.wantKind, tt.wantOwner)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FakeEnv enables the fake provider when set. Its value is either "1"/"true"
+// for the default targets or a comma-separated list of "name[=on|off]";
+// "0"/"false"/"no"/"off" leave it disabled.
+const FakeEnv = "TB_POWER_FAKE"
+
+// defaultFakeTargets are used when FakeEnv does not list targets.
+const defaultFakeTargets = "fake-1=on,fake-2=off"
+
+// FakeProvider keeps power state in memory for development and tests.
+// It is only registered when TB_POWER_FAKE is set.
+type FakeProvider struct {
+	mu     sync.Mutex
+	states map[string]PowerState
+}
+
+// NewFakeProvider creates a fake provider with the given targets and initial states.
+func NewFakeProvider(states map[string]PowerState) *FakeProvider {
+	p := &FakeProvider{states: make(map[string]PowerState, len(states))}
+	for id, s := range states {
+		p.states[id] = s
+	}
+	return p
+}
+
+// newFakeProviderFromEnv parses FakeEnv. It returns nil when the variable is
+// unset or disabled.
+func newFakeProviderFromEnv() (*FakeProvider, error) {
+	spec, ok := os.LookupEnv(FakeEnv)
+	if !ok || spec == "" {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(spec)) {
+	case "1", "true", "yes", "on":
+		spec = defaultFakeTargets
+	case "0", "false", "no", "off":
+		return nil, nil
+	}
+
+	states := make(map[string]PowerState)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, state, _ := strings.Cut(entry, "=")
+		switch PowerState(state) {
+		case "", StateOn:
+			states[sanitizeID(name)] = StateOn
+		case StateOff:
+			states[sanitizeID(name)] = StateOff
+		default:
+			return nil, fmt.Errorf("%s: invalid state %q for target %q", FakeEnv, state, name)
+		}
+	}
+	return NewFakeProvider(states), nil
+}
+
+func (p *FakeProvider) Name() string        { return "fake" }
+func (p *FakeProvider) Method() PowerMethod { return MethodFake }
+
+// Detect always returns true — the provider is opt-in via TB_POWER_FAKE.
+func (p *FakeProvider) Detect(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// ListTargets returns the configured targets sorted by ID.
+func (p *FakeProvider) ListTargets(ctx context.Context) ([]PowerTarget, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	targets := make([]PowerTarget, 0, len(p.states))
+	for id, s := range p.states {
+		targets = append(targets, PowerTarget{
+			ID:       id,
+			Name:     id,
+			State:    s,
+			Method:   MethodFake,
+			Provider: "fake",
+		})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].ID < targets[j].ID })
+	return targets, nil
+}
+
+// GetState returns the in-memory state of a target.
+func (p *FakeProvider) GetState(ctx context.Context, targetID string) (PowerState, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.states[targetID]
+	if !ok {
+		return StateUnknown, fmt.Errorf("fake target %q not found", targetID)
+	}
+	return s, nil
+}
+
+// Execute applies a power action in memory. Cycle and reset leave the
+// target on, matching a real power cycle.
+func (p *FakeProvider) Execute(ctx context.Context, targetID string, action PowerAction) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.states[targetID]; !ok {
+		return fmt.Errorf("fake target %q not found", targetID)
+	}
+	switch action {
+	case ActionOn, ActionCycle, ActionReset:
+		p.states[targetID] = StateOn
+	case ActionOff:
+		p.states[targetID] = StateOff
+	case ActionStatus:
+	default:
+		return fmt.Errorf("fake provider does not support action %q", action)
+	}
+	return nil
+}
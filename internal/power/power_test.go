@@ -154,6 +154,88 @@ func TestRegistryScan(t *testing.T) {
 	}
 }
 
+func TestFakeProviderToggle(t *testing.T) {
+	ctx := context.Background()
+	p := NewFakeProvider(map[string]PowerState{"node-1": StateOn})
+
+	if err := p.Execute(ctx, "node-1", ActionOff); err != nil {
+		t.Fatalf("off: %v", err)
+	}
+	if s, _ := p.GetState(ctx, "node-1"); s != StateOff {
+		t.Errorf("state after off = %q, want off", s)
+	}
+
+	if err := p.Execute(ctx, "node-1", ActionCycle); err != nil {
+		t.Fatalf("cycle: %v", err)
+	}
+	if s, _ := p.GetState(ctx, "node-1"); s != StateOn {
+		t.Errorf("state after cycle = %q, want on", s)
+	}
+
+	if err := p.Execute(ctx, "missing", ActionOn); err == nil {
+		t.Error("expected error for unknown target")
+	}
+}
+
+func TestRegistryFakeProvider(t *testing.T) {
+	t.Setenv(FakeEnv, "rack-a=off,rack-b")
+	reg := NewRegistry()
+	caps := reg.Scan(context.Background())
+
+	var fake Provider
+	for _, p := range reg.Available() {
+		if p.Name() == "fake" {
+			fake = p
+		}
+	}
+	if fake == nil {
+		t.Fatal("expected fake provider to be registered")
+	}
+
+	var states []PowerState
+	for _, target := range caps.Targets {
+		if target.Provider == "fake" {
+			states = append(states, target.State)
+		}
+	}
+	if len(states) != 2 || states[0] != StateOff || states[1] != StateOn {
+		t.Fatalf("fake target states = %v, want [off on]", states)
+	}
+
+	if err := fake.Execute(context.Background(), "rack-a", ActionOn); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := fake.GetState(context.Background(), "rack-a"); s != StateOn {
+		t.Errorf("rack-a state = %q, want on", s)
+	}
+}
+
+func TestRegistryWithoutFakeEnv(t *testing.T) {
+	for _, v := range []string{"", "0", "false", "No", "off"} {
+		t.Setenv(FakeEnv, v)
+		reg := NewRegistry()
+		reg.Detect(context.Background())
+		for _, p := range reg.Available() {
+			if p.Name() == "fake" {
+				t.Fatalf("fake provider should not be registered with %s=%q", FakeEnv, v)
+			}
+		}
+	}
+}
+
+func TestFakeProviderFromEnvDefaults(t *testing.T) {
+	for _, v := range []string{"1", "true", "YES", "on"} {
+		t.Setenv(FakeEnv, v)
+		p, err := newFakeProviderFromEnv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p == nil || len(p.states) != 2 {
+			t.Errorf("%s=%q: want the default targets, got %+v", FakeEnv, v, p)
+		}
+	}
+}
+
 func TestPowerStateValues(t *testing.T) {
 	if StateOn != "on" || StateOff != "off" || StateUnknown != "unknown" {
 		t.Error("power state constants have wrong values")
@@ -168,6 +250,7 @@ func TestPowerMethodValues(t *testing.T) {
 		MethodSmartPlug:  "smart-plug",
 		MethodPoE:        "poe",
 		MethodCloud:      "cloud",
+		MethodFake:       "fake",
 	}
 	for m, want := range methods {
 		if string(m) != want {
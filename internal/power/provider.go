@@ -32,6 +32,7 @@ const (
 	MethodSmartPlug  PowerMethod = "smart-plug"
 	MethodPoE        PowerMethod = "poe"
 	MethodCloud      PowerMethod = "cloud"
+	MethodFake       PowerMethod = "fake"
 )
 
 // PowerTarget represents something whose power can be controlled.
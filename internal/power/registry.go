@@ -12,9 +12,10 @@ type Registry struct {
 	log       *slog.Logger
 }
 
-// NewRegistry creates a registry with all known providers.
+// NewRegistry creates a registry with all known providers. The fake
+// provider is added only when TB_POWER_FAKE is set.
 func NewRegistry() *Registry {
-	return &Registry{
+	r := &Registry{
 		all: []Provider{
 			NewIPMIProvider(),
 			NewWoLProvider(),
@@ -25,6 +26,15 @@ func NewRegistry() *Registry {
 		},
 		log: slog.Default().With("component", "power"),
 	}
+
+	fake, err := newFakeProviderFromEnv()
+	if err != nil {
+		r.log.Warn("fake power provider disabled", "error", err)
+	} else if fake != nil {
+		r.log.Warn("fake power provider enabled", "env", FakeEnv)
+		r.all = append(r.all, fake)
+	}
+	return r
 }
 
 // Detect probes all providers and returns those that are available.
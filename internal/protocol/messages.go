@@ -10,6 +10,9 @@ const (
 	TypePTYOutput    = "pty.output"
 	TypePTYResize    = "pty.resize"
 	TypeHeartbeat    = "agent.heartbeat"
+
+	TypeCommandProgress = "command.progress"
+	TypeCommandResult   = "command.result"
 )
 
 // Envelope is used for initial JSON decode to determine message type
@@ -76,8 +79,28 @@ type PTYResizeMessage struct {
 }
 
 type HeartbeatMessage struct {
+	Type      string   `json:"type"`
+	AgentID   string   `json:"agentId"`
+	ClusterID string   `json:"clusterId"`
+	Timestamp int64    `json:"timestamp"`
+	Warnings  []string `json:"warnings,omitempty"` // agent self-health warnings (e.g. key material)
+}
+
+// CommandProgressMessage reports progress of a long-running command, e.g.
+// "evicted 3/12 pods". A CommandResultMessage for the same command ends
+// the stream.
+type CommandProgressMessage struct {
+	Type      string `json:"type"`
+	CommandID string `json:"commandId"`
+	Message   string `json:"message"`
+	Done      int    `json:"done"`
+	Total     int    `json:"total"`
+}
+
+type CommandResultMessage struct {
 	Type      string `json:"type"`
-	AgentID   string `json:"agentId"`
-	ClusterID string `json:"clusterId"`
-	Timestamp int64  `json:"timestamp"`
+	CommandID string `json:"commandId"`
+	Success   bool   `json:"success"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
 }
@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+// collectCgroupLimits reads the cgroup version and the memory/CPU limits
+// of the agent's own cgroup. root is prepended to /proc and /sys paths
+// (empty in production, a temp dir in tests).
+//
+// The agent's cgroup path from /proc/self/cgroup is tried first, then the
+// mount root: inside a container without a cgroup namespace the path names
+// the host-side cgroup, but the container's own limits are mounted at the
+// root of /sys/fs/cgroup.
+func collectCgroupLimits(root string) (int, parser.CgroupLimits) {
+	data, err := os.ReadFile(filepath.Join(root, "/proc/self/cgroup"))
+	if err != nil {
+		return 0, parser.CgroupLimits{}
+	}
+	version, paths := parser.ParseProcCgroup(string(data))
+
+	read := func(controller, file string) string {
+		base := filepath.Join(root, "/sys/fs/cgroup", controller)
+		for _, dir := range []string{filepath.Join(base, paths[controller]), base} {
+			if data, err := os.ReadFile(filepath.Join(dir, file)); err == nil {
+				return string(data)
+			}
+		}
+		return ""
+	}
+
+	switch version {
+	case 2:
+		return 2, parser.ParseCgroupV2Limits(read("", "memory.max"), read("", "cpu.max"))
+	case 1:
+		return 1, parser.ParseCgroupV1Limits(
+			read("memory", "memory.limit_in_bytes"),
+			read("cpu", "cpu.cfs_quota_us"),
+			read("cpu", "cpu.cfs_period_us"),
+		)
+	}
+	return 0, parser.CgroupLimits{}
+}
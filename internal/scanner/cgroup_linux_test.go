@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCollectCgroupLimitsV2(t *testing.T) {
+	// Agent running as a systemd service: limits live under its own cgroup path
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"proc/self/cgroup": "0::/system.slice/tb-manage.service\n",
+		"sys/fs/cgroup/system.slice/tb-manage.service/memory.max": "268435456\n",
+		"sys/fs/cgroup/system.slice/tb-manage.service/cpu.max":    "50000 100000\n",
+		"sys/fs/cgroup/memory.max":                                "max\n",
+	})
+
+	version, limits := collectCgroupLimits(root)
+	if version != 2 {
+		t.Fatalf("version = %d, want 2", version)
+	}
+	if limits.MemoryBytes != 268435456 || limits.CPUs != 0.5 {
+		t.Errorf("limits = %+v", limits)
+	}
+}
+
+func TestCollectCgroupLimitsV1Container(t *testing.T) {
+	// Container without a cgroup namespace: the host-side path from
+	// /proc/self/cgroup doesn't exist, limits are at the mount root
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"proc/self/cgroup":                           "5:memory:/docker/abc123\n4:cpu,cpuacct:/docker/abc123\n",
+		"sys/fs/cgroup/memory/memory.limit_in_bytes": "2147483648\n",
+		"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         "100000\n",
+		"sys/fs/cgroup/cpu/cpu.cfs_period_us":        "100000\n",
+	})
+
+	version, limits := collectCgroupLimits(root)
+	if version != 1 {
+		t.Fatalf("version = %d, want 1", version)
+	}
+	if limits.MemoryBytes != 2147483648 || limits.CPUs != 1 {
+		t.Errorf("limits = %+v", limits)
+	}
+}
+
+func TestCollectCgroupLimitsMissing(t *testing.T) {
+	if version, limits := collectCgroupLimits(t.TempDir()); version != 0 || limits.MemoryBytes != 0 {
+		t.Errorf("expected nothing without /proc/self/cgroup, got %d %+v", version, limits)
+	}
+}
@@ -12,6 +12,12 @@ type ContainerInfo struct {
 	Version    string          `json:"version,omitempty"`
 	Containers []ContainerItem `json:"containers,omitempty"`
 	Images     []string        `json:"images,omitempty"`
+
+	// Whether Runtime's daemon answered. An installed runtime whose socket
+	// or daemon is unreachable reports false with the reason, rather than
+	// an empty container list that looks like an idle host.
+	RuntimeHealthy bool   `json:"runtime_healthy"`
+	RuntimeError   string `json:"runtime_error,omitempty"`
 }
 
 // ContainerItem represents a running container.
@@ -49,12 +55,21 @@ func (s *ContainerScanner) Scan(ctx context.Context, runner CommandRunner) (json
 		{"nerdctl", "nerdctl info", "nerdctl version --format '{{.Client.Version}}'", "nerdctl ps --format '{{.ID}}\\t{{.Names}}\\t{{.Image}}\\t{{.Status}}'", "nerdctl images --format '{{.Repository}}:{{.Tag}}'"},
 	}
 
+	var unhealthy *ContainerInfo
 	for _, rt := range runtimes {
-		if _, err := runner.Run(ctx, rt.check+" 2>/dev/null"); err != nil {
+		if _, err := runner.Run(ctx, "command -v "+rt.name+" >/dev/null 2>&1"); err != nil {
+			continue // not installed
+		}
+		if out, err := runner.Run(ctx, rt.check+" 2>&1"); err != nil {
+			// Installed but not answering; a later runtime may still work
+			if unhealthy == nil {
+				unhealthy = &ContainerInfo{Runtime: rt.name, RuntimeError: runtimeError(out, err)}
+			}
 			continue
 		}
 
 		info.Runtime = rt.name
+		info.RuntimeHealthy = true
 
 		// Version
 		if out, err := runner.Run(ctx, rt.version+" 2>/dev/null"); err == nil {
@@ -78,10 +93,24 @@ func (s *ContainerScanner) Scan(ctx context.Context, runner CommandRunner) (json
 
 		break // Use first available runtime
 	}
+	if info.Runtime == "" && unhealthy != nil {
+		info = *unhealthy
+	}
 
 	return json.Marshal(info)
 }
 
+// runtimeError is the reason a runtime's info command failed: the last
+// line of its output (e.g. "Cannot connect to the Docker daemon at
+// unix:///var/run/docker.sock. Is the docker daemon running?"), else err.
+func runtimeError(out []byte, err error) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
+	}
+	return err.Error()
+}
+
 func parseContainerPS(output string) []ContainerItem {
 	var containers []ContainerItem
 	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
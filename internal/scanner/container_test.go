@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// failingRunner fails the commands in fail with their output, like a
+// command that printed an error and exited non-zero.
+type failingRunner struct {
+	cannedRunner
+	fail map[string]string
+}
+
+func (r failingRunner) Run(ctx context.Context, cmd string) ([]byte, error) {
+	if out, ok := r.fail[cmd]; ok {
+		return []byte(out), errors.New("exit status 1")
+	}
+	return r.cannedRunner.Run(ctx, cmd)
+}
+
+func scanContainers(t *testing.T, runner CommandRunner) ContainerInfo {
+	t.Helper()
+	data, err := NewContainerScanner().Scan(context.Background(), runner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info ContainerInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestContainerScanDaemonUnreachable(t *testing.T) {
+	info := scanContainers(t, failingRunner{
+		cannedRunner: cannedRunner{"command -v docker >/dev/null 2>&1": ""},
+		fail: map[string]string{
+			"docker info 2>&1": "Client:\n Version: 27.1.1\n\n" +
+				"Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?\n",
+		},
+	})
+
+	if info.Runtime != "docker" || info.RuntimeHealthy {
+		t.Errorf("runtime = %q healthy=%v, want unhealthy docker", info.Runtime, info.RuntimeHealthy)
+	}
+	want := "Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"
+	if info.RuntimeError != want {
+		t.Errorf("RuntimeError = %q, want %q", info.RuntimeError, want)
+	}
+}
+
+func TestContainerScanFallsBackToHealthyRuntime(t *testing.T) {
+	info := scanContainers(t, failingRunner{
+		cannedRunner: cannedRunner{
+			"command -v docker >/dev/null 2>&1": "",
+			"command -v podman >/dev/null 2>&1": "",
+			"podman info 2>&1":                  "host:\n  arch: amd64\n",
+			"podman ps --format '{{.ID}}\\t{{.Names}}\\t{{.Image}}\\t{{.State}}' 2>/dev/null": "abc123\tweb\tnginx:1.27\trunning\n",
+		},
+		fail: map[string]string{"docker info 2>&1": "permission denied while trying to connect to the Docker daemon socket\n"},
+	})
+
+	if info.Runtime != "podman" || !info.RuntimeHealthy || info.RuntimeError != "" {
+		t.Errorf("runtime = %q healthy=%v error=%q, want healthy podman", info.Runtime, info.RuntimeHealthy, info.RuntimeError)
+	}
+	if len(info.Containers) != 1 || info.Containers[0].Name != "web" {
+		t.Errorf("containers = %+v", info.Containers)
+	}
+}
+
+func TestContainerScanNoRuntime(t *testing.T) {
+	info := scanContainers(t, cannedRunner{})
+	if info.Runtime != "" || info.RuntimeHealthy || info.RuntimeError != "" {
+		t.Errorf("expected no runtime, got %+v", info)
+	}
+}
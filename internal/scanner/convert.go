@@ -18,3 +18,19 @@ func convertParserInterfaces(in []parser.InterfaceInfo) []InterfaceInfo {
 	}
 	return out
 }
+
+// applyInterfaceCounters copies traffic counters onto matching interfaces.
+func applyInterfaceCounters(ifaces []InterfaceInfo, counters map[string]parser.InterfaceCounters) {
+	for i := range ifaces {
+		c, ok := counters[ifaces[i].Name]
+		if !ok {
+			continue
+		}
+		ifaces[i].RxBytes = c.RxBytes
+		ifaces[i].TxBytes = c.TxBytes
+		ifaces[i].RxPackets = c.RxPackets
+		ifaces[i].TxPackets = c.TxPackets
+		ifaces[i].RxErrors = c.RxErrors
+		ifaces[i].TxErrors = c.TxErrors
+	}
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxDecompressedSize caps how much a compressed blob read from the cluster
+// (Helm release secrets, compressed Flux specs) may expand to. Real releases
+// are well under a megabyte; anything past this is corrupt or hostile.
+const MaxDecompressedSize = 8 << 20
+
+// ErrDecompressedTooLarge is returned when a gzip stream expands past its
+// limit.
+var ErrDecompressedTooLarge = errors.New("decompressed data exceeds size limit")
+
+// gunzipLimited decompresses data, reading at most limit bytes of output.
+// A decompression bomb fails with ErrDecompressedTooLarge after limit+1
+// bytes instead of exhausting memory.
+func gunzipLimited(data []byte, limit int64) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	defer zr.Close()
+
+	out, err := io.ReadAll(io.LimitReader(zr, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	if int64(len(out)) > limit {
+		return nil, fmt.Errorf("%w (%d bytes)", ErrDecompressedTooLarge, limit)
+	}
+	return out, nil
+}
+
+// decodeGzipBase64 decodes base64 text and, when the result is gzip
+// compressed, decompresses it within limit. Helm stores releases this way;
+// uncompressed payloads are returned as decoded.
+func decodeGzipBase64(data []byte, limit int64) ([]byte, error) {
+	raw := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+	n, err := base64.StdEncoding.Decode(raw, bytes.TrimSpace(data))
+	if err != nil {
+		return nil, fmt.Errorf("base64: %w", err)
+	}
+	raw = raw[:n]
+
+	// gzip magic number
+	if len(raw) < 2 || raw[0] != 0x1f || raw[1] != 0x8b {
+		if int64(len(raw)) > limit {
+			return nil, fmt.Errorf("%w (%d bytes)", ErrDecompressedTooLarge, limit)
+		}
+		return raw, nil
+	}
+	return gunzipLimited(raw, limit)
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGunzipLimitedRejectsBomb(t *testing.T) {
+	// 64 MiB of zeros compresses to ~64 KiB
+	bomb := gzipBytes(t, make([]byte, 64<<20))
+
+	_, err := gunzipLimited(bomb, MaxDecompressedSize)
+	if !errors.Is(err, ErrDecompressedTooLarge) {
+		t.Fatalf("err = %v, want ErrDecompressedTooLarge", err)
+	}
+
+	_, err = decodeGzipBase64([]byte(base64.StdEncoding.EncodeToString(bomb)), MaxDecompressedSize)
+	if !errors.Is(err, ErrDecompressedTooLarge) {
+		t.Fatalf("base64 err = %v, want ErrDecompressedTooLarge", err)
+	}
+}
+
+func TestGunzipLimitedAtLimit(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 1024)
+	got, err := gunzipLimited(gzipBytes(t, data), 1024)
+	if err != nil {
+		t.Fatalf("exactly at limit: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("round trip mismatch")
+	}
+	if _, err := gunzipLimited(gzipBytes(t, data), 1023); !errors.Is(err, ErrDecompressedTooLarge) {
+		t.Errorf("one byte over: err = %v, want ErrDecompressedTooLarge", err)
+	}
+}
+
+func TestDecodeGzipBase64(t *testing.T) {
+	release := []byte(`{"name":"podinfo","version":3}`)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []byte
+		wantErr bool
+	}{
+		{"gzipped", base64.StdEncoding.EncodeToString(gzipBytes(t, release)), release, false},
+		{"plain", base64.StdEncoding.EncodeToString(release), release, false},
+		{"trailing newline", base64.StdEncoding.EncodeToString(release) + "\n", release, false},
+		{"bad base64", "not base64!", nil, true},
+		{"truncated gzip", base64.StdEncoding.EncodeToString(gzipBytes(t, release)[:12]), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeGzipBase64([]byte(tt.input), MaxDecompressedSize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package scanner
+
+import (
+	"context"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+// collectGPUs inventories GPUs. NVIDIA cards come from nvidia-smi, which
+// also reports memory and driver version, falling back to the driver's
+// /proc entries when the tool is missing; other vendors come from lspci.
+func collectGPUs(ctx context.Context, runner CommandRunner, info *HostInfo) {
+	var gpus []parser.GPU
+	if out, err := runner.Run(ctx, "nvidia-smi --query-gpu=name,memory.total,driver_version --format=csv,noheader 2>/dev/null"); err == nil {
+		gpus = parser.ParseNvidiaSmiCSV(string(out))
+	}
+	if len(gpus) == 0 {
+		if out, err := runner.Run(ctx, "cat /proc/driver/nvidia/gpus/*/information 2>/dev/null"); err == nil {
+			gpus = parser.ParseNvidiaProcGPUs(string(out))
+		}
+	}
+	if out, err := runner.Run(ctx, "lspci -mm 2>/dev/null"); err == nil {
+		haveNvidia := len(gpus) > 0
+		for _, g := range parser.ParseLspciGPUs(string(out)) {
+			if g.Vendor == "NVIDIA" && haveNvidia {
+				continue // already listed with more detail
+			}
+			gpus = append(gpus, g)
+		}
+	}
+
+	for _, g := range gpus {
+		info.System.GPUs = append(info.System.GPUs, GPUInfo{
+			Vendor:        g.Vendor,
+			Model:         g.Model,
+			MemoryBytes:   g.MemoryBytes,
+			DriverVersion: g.Driver,
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+const gpuSmiCmd = "nvidia-smi --query-gpu=name,memory.total,driver_version --format=csv,noheader 2>/dev/null"
+
+func TestCollectGPUsNvidiaSmi(t *testing.T) {
+	var info HostInfo
+	collectGPUs(context.Background(), cannedRunner{
+		gpuSmiCmd: "Tesla T4, 15360 MiB, 535.129.03\n",
+		"lspci -mm 2>/dev/null": `00:02.0 "VGA compatible controller" "Intel Corporation" "HD Graphics 630" -r04 "Dell" "Device 07a1"
+3b:00.0 "3D controller" "NVIDIA Corporation" "TU104GL [Tesla T4]" -ra1 "NVIDIA Corporation" "Device 12a2"
+`,
+	}, &info)
+
+	want := []GPUInfo{
+		{Vendor: "NVIDIA", Model: "Tesla T4", MemoryBytes: 15360 << 20, DriverVersion: "535.129.03"},
+		{Vendor: "Intel", Model: "HD Graphics 630"},
+	}
+	if !reflect.DeepEqual(info.System.GPUs, want) {
+		t.Errorf("GPUs =\n%+v\nwant\n%+v", info.System.GPUs, want)
+	}
+}
+
+func TestCollectGPUsProcFallback(t *testing.T) {
+	var info HostInfo
+	collectGPUs(context.Background(), cannedRunner{
+		"cat /proc/driver/nvidia/gpus/*/information 2>/dev/null": "Model: \t\t Tesla T4\nIRQ:   \t\t 38\nGPU UUID: \t GPU-5a1b\n",
+	}, &info)
+
+	want := []GPUInfo{{Vendor: "NVIDIA", Model: "Tesla T4"}}
+	if !reflect.DeepEqual(info.System.GPUs, want) {
+		t.Errorf("GPUs = %+v, want %+v", info.System.GPUs, want)
+	}
+}
+
+func TestHostScanNoGPUs(t *testing.T) {
+	info := HostInfo{System: SystemInfo{GPUs: []GPUInfo{}}}
+	collectGPUs(context.Background(), cannedRunner{}, &info)
+	if info.System.GPUs == nil || len(info.System.GPUs) != 0 {
+		t.Errorf("expected empty, non-nil GPUs, got %#v", info.System.GPUs)
+	}
+}
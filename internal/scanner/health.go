@@ -0,0 +1,248 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// HealthReport holds compact 0-100 health scores derived from a scan.
+// The raw data the scores are computed from stays in the result.
+type HealthReport struct {
+	Host    *HealthScore `json:"host,omitempty"`
+	Cluster *HealthScore `json:"cluster,omitempty"`
+}
+
+// HealthScore is a weighted 0-100 score (100 = healthy) and the factors
+// that produced it, so consumers can see why a score dropped.
+type HealthScore struct {
+	Score   int            `json:"score"`
+	Factors []HealthFactor `json:"factors"`
+}
+
+// HealthFactor is one input to a HealthScore. Score is 0-100 for this
+// factor alone; Weight is its share of the total.
+type HealthFactor struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+	Score  int    `json:"score"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Factor weights. Factors whose inputs were not collected are left out and
+// the remaining weights renormalized, so a host without systemd is not
+// penalized for having no service data.
+const (
+	healthWeightDisk     = 40
+	healthWeightServices = 30
+	healthWeightLoad     = 30
+
+	healthWeightWorkloads = 40
+	healthWeightNodes     = 40
+	healthWeightPods      = 20
+)
+
+// ApplyHealth computes host and cluster health scores from the scan data
+// and stores them in result.Health.
+func ApplyHealth(result *Result) {
+	report := &HealthReport{}
+
+	if result.Host != nil {
+		var hostInfo HostInfo
+		if err := json.Unmarshal(result.Host, &hostInfo); err == nil {
+			var storage *StorageInfo
+			if result.Storage != nil {
+				var s StorageInfo
+				if err := json.Unmarshal(result.Storage, &s); err == nil {
+					storage = &s
+				}
+			}
+			report.Host = HostHealth(hostInfo, storage)
+		}
+	}
+
+	if result.Cluster != nil {
+		var cluster ClusterScanResult
+		if err := json.Unmarshal(result.Cluster, &cluster); err == nil {
+			report.Cluster = ClusterHealth(cluster)
+		}
+	}
+
+	if report.Host == nil && report.Cluster == nil {
+		return
+	}
+	result.Health = report
+}
+
+// HostHealth scores a host on disk fullness, failed services and load
+// relative to CPU count. storage may be nil. Read-only images (snaps,
+// loop mounts) are always full and do not count towards disk fullness;
+// services are scored only when systemctl could be queried. Returns nil if
+// no factor could be computed.
+func HostHealth(host HostInfo, storage *StorageInfo) *HealthScore {
+	var factors []HealthFactor
+
+	var worst *FilesystemInfo
+	if storage != nil {
+		for i, fs := range storage.Filesystems {
+			if readOnlyImage(fs) {
+				continue
+			}
+			if worst == nil || fs.UsePct > worst.UsePct {
+				worst = &storage.Filesystems[i]
+			}
+		}
+	}
+	if worst != nil {
+		// Full marks up to 80% used, falling to zero at 100%
+		factors = append(factors, HealthFactor{
+			Name:   "disk",
+			Weight: healthWeightDisk,
+			Score:  linearScore(worst.UsePct, 80, 100),
+			Detail: fmt.Sprintf("%s %.0f%% used", worst.MountPoint, worst.UsePct),
+		})
+	}
+
+	if host.System.ServicesChecked {
+		failed := len(host.System.FailedServices)
+		factors = append(factors, HealthFactor{
+			Name:   "services",
+			Weight: healthWeightServices,
+			Score:  clampScore(100 - 10*failed),
+			Detail: fmt.Sprintf("%d failed", failed),
+		})
+	}
+
+	if len(host.System.LoadAvg) > 0 && host.System.CPUCores > 0 {
+		perCore := host.System.LoadAvg[0] / float64(host.System.CPUCores)
+		// Full marks up to one runnable task per core, zero at three
+		factors = append(factors, HealthFactor{
+			Name:   "load",
+			Weight: healthWeightLoad,
+			Score:  linearScore(perCore, 1, 3),
+			Detail: fmt.Sprintf("load %.2f on %d cores", host.System.LoadAvg[0], host.System.CPUCores),
+		})
+	}
+
+	return weightedScore(factors)
+}
+
+// ClusterHealth scores a cluster on the share of fully ready workloads, the
+// share of nodes that are Ready without resource pressure and the share of
+// pods that have not failed. Returns nil for a cluster with no nodes,
+// workloads or pods.
+func ClusterHealth(cluster ClusterScanResult) *HealthScore {
+	var factors []HealthFactor
+
+	var total, ready int
+	for _, ns := range cluster.Namespaces {
+		for _, w := range ns.Workloads {
+			want, have, ok := workloadReadiness(w)
+			if !ok {
+				continue
+			}
+			total++
+			if have >= want {
+				ready++
+			}
+		}
+	}
+	if total > 0 {
+		factors = append(factors, HealthFactor{
+			Name:   "workloads",
+			Weight: healthWeightWorkloads,
+			Score:  ready * 100 / total,
+			Detail: fmt.Sprintf("%d/%d ready", ready, total),
+		})
+	}
+
+	if len(cluster.Nodes) > 0 {
+		healthy := 0
+		for _, n := range cluster.Nodes {
+			if n.Status == "Ready" && len(n.Pressure) == 0 {
+				healthy++
+			}
+		}
+		factors = append(factors, HealthFactor{
+			Name:   "nodes",
+			Weight: healthWeightNodes,
+			Score:  healthy * 100 / len(cluster.Nodes),
+			Detail: fmt.Sprintf("%d/%d ready without pressure", healthy, len(cluster.Nodes)),
+		})
+	}
+
+	if len(cluster.PodPlacements) > 0 {
+		failed := 0
+		for _, p := range cluster.PodPlacements {
+			if p.Phase == "Failed" {
+				failed++
+			}
+		}
+		total := len(cluster.PodPlacements)
+		factors = append(factors, HealthFactor{
+			Name:   "pods",
+			Weight: healthWeightPods,
+			Score:  (total - failed) * 100 / total,
+			Detail: fmt.Sprintf("%d/%d failed", failed, total),
+		})
+	}
+
+	return weightedScore(factors)
+}
+
+// readOnlyImage reports whether fs is a mounted image such as a snap or
+// squashfs root, which is full by design.
+func readOnlyImage(fs FilesystemInfo) bool {
+	return fs.Type == "squashfs" || strings.HasPrefix(fs.Filesystem, "/dev/loop")
+}
+
+// workloadReadiness returns desired and ready pod counts for a workload.
+// ok is false for workloads scaled to zero, which have nothing to be ready.
+func workloadReadiness(w WorkloadScanResult) (want, have int32, ok bool) {
+	if w.DesiredNumberScheduled != nil {
+		want = *w.DesiredNumberScheduled
+		if w.NumberReady != nil {
+			have = *w.NumberReady
+		}
+	} else if w.Replicas != nil {
+		want = *w.Replicas
+		if w.ReadyReplicas != nil {
+			have = *w.ReadyReplicas
+		}
+	}
+	return want, have, want > 0
+}
+
+// weightedScore combines factors into a single score, renormalizing over
+// the weights present. Returns nil for no factors.
+func weightedScore(factors []HealthFactor) *HealthScore {
+	if len(factors) == 0 {
+		return nil
+	}
+	var sum, weights int
+	for _, f := range factors {
+		sum += f.Score * f.Weight
+		weights += f.Weight
+	}
+	return &HealthScore{
+		Score:   int(math.Round(float64(sum) / float64(weights))),
+		Factors: factors,
+	}
+}
+
+// linearScore returns 100 for v <= good, 0 for v >= bad, and a linear
+// interpolation in between.
+func linearScore(v, good, bad float64) int {
+	if v <= good {
+		return 100
+	}
+	if v >= bad {
+		return 0
+	}
+	return int(math.Round(100 * (bad - v) / (bad - good)))
+}
+
+func clampScore(s int) int {
+	return max(0, min(100, s))
+}
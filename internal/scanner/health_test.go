@@ -0,0 +1,184 @@
+package scanner
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func int32p(v int32) *int32 { return &v }
+
+func TestHostHealthDirection(t *testing.T) {
+	healthy := HostInfo{System: SystemInfo{OS: "linux", CPUCores: 4, LoadAvg: []float64{0.5, 0.4, 0.3}, ServicesChecked: true}}
+	storage := &StorageInfo{Filesystems: []FilesystemInfo{{MountPoint: "/", UsePct: 40}}}
+
+	base := HostHealth(healthy, storage)
+	if base == nil || base.Score != 100 {
+		t.Fatalf("healthy host score = %+v, want 100", base)
+	}
+
+	fullDisk := &StorageInfo{Filesystems: []FilesystemInfo{
+		{MountPoint: "/", UsePct: 40},
+		{MountPoint: "/var", UsePct: 95},
+	}}
+	diskScore := HostHealth(healthy, fullDisk)
+	if diskScore.Score >= base.Score {
+		t.Errorf("full disk score %d should be below %d", diskScore.Score, base.Score)
+	}
+	if diskScore.Factors[0].Detail != "/var 95% used" {
+		t.Errorf("disk detail = %q", diskScore.Factors[0].Detail)
+	}
+
+	failing := healthy
+	failing.System.FailedServices = []string{"nginx.service", "cron.service"}
+	if s := HostHealth(failing, storage).Score; s >= base.Score {
+		t.Errorf("failed services score %d should be below %d", s, base.Score)
+	}
+
+	loaded := healthy
+	loaded.System.LoadAvg = []float64{10, 8, 6}
+	loadScore := HostHealth(loaded, storage).Score
+	if loadScore >= base.Score {
+		t.Errorf("high load score %d should be below %d", loadScore, base.Score)
+	}
+
+	worse := loaded
+	worse.System.FailedServices = failing.System.FailedServices
+	if s := HostHealth(worse, fullDisk).Score; s >= loadScore {
+		t.Errorf("combined degradation score %d should be below %d", s, loadScore)
+	}
+}
+
+func TestHostHealthIgnoresImagesAndUncheckedServices(t *testing.T) {
+	// systemctl failed: no services factor, only disk and load
+	host := HostInfo{System: SystemInfo{OS: "linux", CPUCores: 4, LoadAvg: []float64{0.5, 0.4, 0.3}}}
+	storage := &StorageInfo{Filesystems: []FilesystemInfo{
+		{Filesystem: "/dev/sda1", MountPoint: "/", UsePct: 40},
+		{Filesystem: "/dev/loop3", MountPoint: "/snap/core20/2105", Type: "squashfs", UsePct: 100},
+		{Filesystem: "/dev/loop4", MountPoint: "/mnt/image", UsePct: 100},
+	}}
+	score := HostHealth(host, storage)
+	if score == nil || score.Score != 100 || len(score.Factors) != 2 {
+		t.Fatalf("score = %+v, want 100 from disk and load only", score)
+	}
+	if score.Factors[0].Detail != "/ 40% used" {
+		t.Errorf("disk detail = %q, loop and squashfs mounts should be ignored", score.Factors[0].Detail)
+	}
+}
+
+func TestHostHealthMissingInputs(t *testing.T) {
+	// darwin: no failed-service data, no storage — only load is scored
+	host := HostInfo{System: SystemInfo{OS: "darwin", CPUCores: 8, LoadAvg: []float64{1, 1, 1}}}
+	score := HostHealth(host, nil)
+	if score == nil || score.Score != 100 || len(score.Factors) != 1 {
+		t.Fatalf("score = %+v, want 100 from a single load factor", score)
+	}
+
+	if HostHealth(HostInfo{System: SystemInfo{OS: "darwin"}}, nil) != nil {
+		t.Error("expected nil score with no inputs")
+	}
+}
+
+func TestClusterHealthDirection(t *testing.T) {
+	cluster := func(readyReplicas int32, nodes ...NodeScanResult) ClusterScanResult {
+		return ClusterScanResult{
+			Nodes: nodes,
+			Namespaces: []NamespaceScanResult{{
+				Name: "default",
+				Workloads: []WorkloadScanResult{
+					{Name: "web", Kind: "Deployment", Replicas: int32p(2), ReadyReplicas: int32p(readyReplicas)},
+					{Name: "agent", Kind: "DaemonSet", DesiredNumberScheduled: int32p(2), NumberReady: int32p(2)},
+					{Name: "idle", Kind: "Deployment", Replicas: int32p(0)},
+				},
+			}},
+		}
+	}
+	ready := NodeScanResult{Name: "n1", Status: "Ready"}
+
+	base := ClusterHealth(cluster(2, ready, ready))
+	if base == nil || base.Score != 100 {
+		t.Fatalf("healthy cluster score = %+v, want 100", base)
+	}
+
+	unready := ClusterHealth(cluster(1, ready, ready))
+	if unready.Score >= base.Score {
+		t.Errorf("unready workload score %d should be below %d", unready.Score, base.Score)
+	}
+	if unready.Factors[0].Detail != "1/2 ready" {
+		t.Errorf("workloads detail = %q, scaled-to-zero workloads should be ignored", unready.Factors[0].Detail)
+	}
+
+	pressured := NodeScanResult{Name: "n2", Status: "Ready", Pressure: []string{"DiskPressure"}}
+	if s := ClusterHealth(cluster(2, ready, pressured)).Score; s >= base.Score {
+		t.Errorf("node pressure score %d should be below %d", s, base.Score)
+	}
+
+	notReady := NodeScanResult{Name: "n2", Status: "NotReady"}
+	both := ClusterHealth(cluster(0, ready, notReady))
+	if both.Score >= unready.Score {
+		t.Errorf("degraded cluster score %d should be below %d", both.Score, unready.Score)
+	}
+}
+
+func TestClusterHealthFailedPods(t *testing.T) {
+	cluster := ClusterScanResult{
+		Nodes: []NodeScanResult{{Name: "n1", Status: "Ready"}},
+		PodPlacements: []PodPlacement{
+			{Name: "web-1", Phase: "Running"},
+			{Name: "migrate-x", Phase: "Succeeded"},
+			{Name: "backup-y", Phase: "Failed"},
+			{Name: "backup-z", Phase: "Failed"},
+		},
+	}
+	score := ClusterHealth(cluster)
+	if score == nil || score.Score >= 100 {
+		t.Fatalf("score = %+v, want degraded by failed pods", score)
+	}
+	pods := score.Factors[len(score.Factors)-1]
+	if pods.Name != "pods" || pods.Score != 50 || pods.Detail != "2/4 failed" {
+		t.Errorf("pods factor = %+v", pods)
+	}
+}
+
+func TestForProfileDropsUnkeptHealth(t *testing.T) {
+	result := NewResult()
+	result.Set("host", json.RawMessage(`{"name":"h"}`))
+	result.Set("cluster", json.RawMessage(`{}`))
+	result.Health = &HealthReport{Host: &HealthScore{Score: 90}, Cluster: &HealthScore{Score: 80}}
+
+	minimal := result.ForProfile(ProfileMinimal)
+	if minimal.Health == nil || minimal.Health.Host == nil || minimal.Health.Cluster != nil {
+		t.Errorf("minimal profile health = %+v, want host score only", minimal.Health)
+	}
+	if full := result.ForProfile(ProfileFull); full.Health == nil || full.Health.Cluster == nil {
+		t.Errorf("full profile health = %+v, want cluster score kept", full.Health)
+	}
+}
+
+func TestApplyHealth(t *testing.T) {
+	result := NewResult()
+	result.Set("host", json.RawMessage(`{"name":"h","system":{"os":"linux","cpu_cores":2,"load_avg":[4,4,4]}}`))
+	result.Set("storage", json.RawMessage(`{"filesystems":[{"mount_point":"/","use_pct":50}]}`))
+
+	ApplyHealth(result)
+	if result.Health == nil || result.Health.Host == nil {
+		t.Fatal("expected host health")
+	}
+	if result.Health.Cluster != nil {
+		t.Error("expected no cluster health without cluster data")
+	}
+	if s := result.Health.Host.Score; s <= 0 || s >= 100 {
+		t.Errorf("host score = %d, want partially degraded by load", s)
+	}
+
+	// Raw data is preserved alongside the score
+	var host HostInfo
+	if err := json.Unmarshal(result.Host, &host); err != nil || len(host.System.LoadAvg) != 3 {
+		t.Errorf("raw host data lost: %v %+v", err, host)
+	}
+
+	empty := NewResult()
+	ApplyHealth(empty)
+	if empty.Health != nil {
+		t.Error("expected no health for empty result")
+	}
+}
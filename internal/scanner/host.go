@@ -3,9 +3,14 @@ package scanner
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
 )
 
 // HostInfo is the data collected by the host scanner.
@@ -13,9 +18,14 @@ type HostInfo struct {
 	Name   string     `json:"name"`
 	Type   string     `json:"type"` // Set later by topology inference
 	System SystemInfo `json:"system"`
+
+	// PCI devices from lspci (Linux only); nil when lspci is unavailable
+	PCI []PCIDevice `json:"pci,omitempty"`
 }
 
 // SystemInfo contains OS and hardware details.
+// CPUCores and MemoryGB are host totals; in containerized deployments the
+// agent may be constrained further by the Cgroup* limits.
 type SystemInfo struct {
 	OS           string  `json:"os"`
 	OSVersion    string  `json:"os_version,omitempty"`
@@ -25,6 +35,198 @@ type SystemInfo struct {
 	MemoryGB     float64 `json:"memory_gb"`
 	SerialNumber string  `json:"serial_number,omitempty"`
 	MachineID    string  `json:"machine_id,omitempty"`
+
+	// CPU frequency scaling: cpu0's maximum frequency and, on Linux, its
+	// cpufreq governor. Zero/empty on hosts without cpufreq (most VMs)
+	CPUMaxMHz   float64 `json:"cpu_max_mhz,omitempty"`
+	CPUGovernor string  `json:"cpu_governor,omitempty"` // performance, powersave, schedutil, ...
+
+	// NUMA layout (Linux only); hosts without NUMA report a single node
+	NUMA *NUMATopology `json:"numa,omitempty"`
+
+	// Swap space usage; nil when swap is disabled
+	Swap *SwapInfo `json:"swap,omitempty"`
+
+	// Linux only: cgroup version (1 or 2) and the agent's own cgroup limits.
+	// Zero limits mean unlimited.
+	CgroupVersion  int     `json:"cgroup_version,omitempty"`
+	CgroupMemoryGB float64 `json:"cgroup_memory_gb,omitempty"`
+	CgroupCPUs     float64 `json:"cgroup_cpus,omitempty"`
+
+	LoadAvg        []float64 `json:"load_avg,omitempty"`        // 1, 5 and 15 minute load averages
+	FailedServices []string  `json:"failed_services,omitempty"` // failed systemd units (Linux only)
+	// systemctl answered, so an empty FailedServices means none failed
+	ServicesChecked bool `json:"services_checked,omitempty"`
+
+	// Seconds since boot (macOS only for now)
+	UptimeSeconds int64 `json:"uptime_seconds,omitempty"`
+
+	// Zombie (defunct) processes; many usually means one parent is not
+	// reaping its children
+	ZombieProcesses  int  `json:"zombie_processes,omitempty"`
+	ZombieParentPID  int  `json:"zombie_parent_pid,omitempty"` // parent with the most zombies
+	ZombiesExcessive bool `json:"zombies_excessive,omitempty"` // ZombieProcesses >= ZombieExcessiveThreshold
+
+	// Linux only: a reboot is needed to finish applying updates
+	RebootRequired       bool   `json:"reboot_required,omitempty"`
+	RebootRequiredReason string `json:"reboot_required_reason,omitempty"`
+
+	// Temperature sensors the agent could read; nil when none were
+	Thermal *ThermalInfo `json:"thermal,omitempty"`
+
+	// GPUs found on the host; empty (not null) when there are none.
+	// Linux only for now
+	GPUs []GPUInfo `json:"gpus"`
+
+	// Power source and internal battery state (macOS, and Linux hosts
+	// with a battery). BatteryStatus is one of batteryStatuses.
+	PowerSource       string `json:"power_source,omitempty"` // "AC Power", "Battery Power", "UPS Power"
+	BatteryPercent    int    `json:"battery_percent,omitempty"`
+	BatteryStatus     string `json:"battery_status,omitempty"`
+	BatteryCycleCount int    `json:"battery_cycle_count,omitempty"` // macOS only
+}
+
+// GPUInfo describes one graphics/compute adapter.
+type GPUInfo struct {
+	Vendor        string `json:"vendor"` // NVIDIA, AMD, Intel, ...
+	Model         string `json:"model"`
+	MemoryBytes   int64  `json:"memory_bytes,omitempty"`
+	DriverVersion string `json:"driver_version,omitempty"`
+}
+
+// PCIDevice is one device on the host's PCI bus.
+type PCIDevice struct {
+	Slot   string `json:"slot"`  // bus address, e.g. "3b:00.0"
+	Class  string `json:"class"` // "Ethernet controller", "RAID bus controller", ...
+	Vendor string `json:"vendor"`
+	Device string `json:"device"`
+}
+
+// NUMATopology is the host's NUMA layout.
+type NUMATopology struct {
+	NodeCount int        `json:"node_count"`
+	Nodes     []NUMANode `json:"nodes"`
+}
+
+// NUMANode is one NUMA node's CPUs and memory.
+type NUMANode struct {
+	ID          int    `json:"id"`
+	CPUs        string `json:"cpus"` // kernel cpulist, e.g. "0-15,32-47"
+	MemoryBytes int64  `json:"memory_bytes,omitempty"`
+}
+
+// SwapInfo is the host's swap space usage.
+type SwapInfo struct {
+	TotalBytes int64   `json:"total_bytes"`
+	UsedBytes  int64   `json:"used_bytes"`
+	FreeBytes  int64   `json:"free_bytes"`
+	UsedPct    float64 `json:"used_pct"`
+}
+
+// newSwapInfo returns swap usage from total and free bytes, or nil when
+// there is no swap.
+func newSwapInfo(total, free int64) *SwapInfo {
+	if total <= 0 {
+		return nil
+	}
+	used := total - free
+	if used < 0 {
+		used = 0
+	}
+	return &SwapInfo{
+		TotalBytes: total,
+		UsedBytes:  used,
+		FreeBytes:  free,
+		UsedPct:    math.Round(float64(used)/float64(total)*1000) / 10,
+	}
+}
+
+// ThermalInfo holds temperature sensor readings and the hottest of them.
+type ThermalInfo struct {
+	Sensors    []ThermalSensor `json:"sensors"`
+	MaxCelsius float64         `json:"max_celsius"`
+}
+
+// ThermalSensor is one temperature reading.
+type ThermalSensor struct {
+	Name    string  `json:"name"` // thermal zone type or hwmon "chip/label"
+	Celsius float64 `json:"celsius"`
+}
+
+// newThermalInfo summarizes sensor readings, returning nil when there are
+// none so hosts without readable sensors simply omit the field.
+func newThermalInfo(readings []parser.ThermalSensor) *ThermalInfo {
+	if len(readings) == 0 {
+		return nil
+	}
+	t := &ThermalInfo{MaxCelsius: readings[0].Celsius}
+	for _, r := range readings {
+		t.Sensors = append(t.Sensors, ThermalSensor{Name: r.Name, Celsius: r.Celsius})
+		if r.Celsius > t.MaxCelsius {
+			t.MaxCelsius = r.Celsius
+		}
+	}
+	return t
+}
+
+// batteryStatuses maps the battery states reported by pmset and sysfs
+// onto one vocabulary: "charging", "discharging", "charged" or
+// "not charging".
+var batteryStatuses = map[string]string{
+	"charging":         "charging",
+	"finishing charge": "charging",
+	"discharging":      "discharging",
+	"charged":          "charged",
+	"full":             "charged",
+	"not charging":     "not charging",
+	"ac attached":      "not charging",
+}
+
+// normalizeBatteryStatus returns the batteryStatuses term for a pmset or
+// sysfs battery state, or "" when the state is unknown.
+func normalizeBatteryStatus(status string) string {
+	return batteryStatuses[strings.ToLower(strings.TrimSpace(status))]
+}
+
+// applyPowerSupplies fills the power fields of sys from sysfs power
+// supplies, leaving them empty when the host has no battery. The charge of
+// hosts with several batteries is their average. Without an AC adapter
+// entry, the host is on battery when a battery is discharging.
+func applyPowerSupplies(sys *SystemInfo, supplies []parser.PowerSupply) {
+	var (
+		batteries, total      int
+		status                string
+		hasAdapter, pluggedIn bool
+	)
+	for _, ps := range supplies {
+		switch {
+		case ps.Type == "Battery" || ps.Type == "" && strings.HasPrefix(ps.Name, "BAT"):
+			if ps.Capacity < 0 {
+				continue
+			}
+			batteries++
+			total += ps.Capacity
+			if status == "" || ps.Status == "Discharging" {
+				status = ps.Status
+			}
+		case ps.Type == "Mains" || ps.Type == "" && (strings.HasPrefix(ps.Name, "AC") || strings.HasPrefix(ps.Name, "ADP")):
+			hasAdapter = true
+			pluggedIn = pluggedIn || ps.Online
+		}
+	}
+	if batteries == 0 {
+		return
+	}
+	onBattery := !pluggedIn
+	if !hasAdapter {
+		onBattery = status == "Discharging"
+	}
+	sys.PowerSource = "AC Power"
+	if onBattery {
+		sys.PowerSource = "Battery Power"
+	}
+	sys.BatteryPercent = total / batteries
+	sys.BatteryStatus = normalizeBatteryStatus(status)
 }
 
 // junkSerials are DMI serial values that indicate no real serial is available.
@@ -44,6 +246,69 @@ func IsJunkSerial(s string) bool {
 	return junkSerials[strings.ToLower(strings.TrimSpace(s))]
 }
 
+// parseLoadAvg parses the 1/5/15 minute load averages from /proc/loadavg
+// ("0.52 0.58 0.59 1/467 12345") or `sysctl -n vm.loadavg` ("{ 1.23 1.10 0.98 }").
+func parseLoadAvg(out string) []float64 {
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(out), "{}"))
+	if len(fields) < 3 {
+		return nil
+	}
+	loads := make([]float64, 0, 3)
+	for _, f := range fields[:3] {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil
+		}
+		loads = append(loads, v)
+	}
+	return loads
+}
+
+// parseBoottime parses the boot time from `sysctl -n kern.boottime`
+// ("{ sec = 1760600000, usec = 123456 } Thu Oct 16 08:33:20 2026").
+func parseBoottime(out string) (time.Time, bool) {
+	_, rest, ok := strings.Cut(out, "{ sec = ")
+	if !ok {
+		return time.Time{}, false
+	}
+	secs, _, _ := strings.Cut(rest, ",")
+	sec, err := strconv.ParseInt(strings.TrimSpace(secs), 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0), true
+}
+
+// parseFailedUnits parses `systemctl list-units --state=failed --no-legend --plain`
+// output, one "unit load active sub description" line per failed unit.
+func parseFailedUnits(out string) []string {
+	var units []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "●"))
+		if len(fields) > 0 {
+			units = append(units, fields[0])
+		}
+	}
+	return units
+}
+
+// ZombieExcessiveThreshold is the zombie count at which a host is flagged.
+// A few short-lived zombies are normal; this many means a parent has
+// stopped reaping and PIDs are leaking.
+const ZombieExcessiveThreshold = 50
+
+// collectZombies counts zombie processes from `ps -eo stat,ppid`.
+func collectZombies(ctx context.Context, runner CommandRunner, info *HostInfo) {
+	out, err := runner.Run(ctx, "ps -eo stat,ppid")
+	if err != nil {
+		return
+	}
+	z := parser.ParsePsZombies(string(out))
+	info.System.ZombieProcesses = z.Count
+	info.System.ZombieParentPID = z.WorstParent()
+	info.System.ZombiesExcessive = z.Count >= ZombieExcessiveThreshold
+}
+
 // HostScanner collects basic host information.
 type HostScanner struct{}
 
@@ -63,6 +328,7 @@ func (s *HostScanner) Scan(ctx context.Context, runner CommandRunner) (json.RawM
 		System: SystemInfo{
 			OS:   runtime.GOOS,
 			Arch: runtime.GOARCH,
+			GPUs: []GPUInfo{},
 		},
 	}
 
@@ -4,6 +4,9 @@ import (
 	"context"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
 )
 
 // extractIORegValue parses ioreg output like: "Key" = "Value"
@@ -33,6 +36,13 @@ func collectHostInfo(ctx context.Context, runner CommandRunner, info *HostInfo)
 		}
 	}
 
+	// CPU frequency in Hz; Apple Silicon does not report it
+	if out, err := runner.Run(ctx, "sysctl -n hw.cpufrequency"); err == nil {
+		if hz, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64); err == nil {
+			info.System.CPUMaxMHz = hz / 1e6
+		}
+	}
+
 	// Memory in GB
 	if out, err := runner.Run(ctx, "sysctl -n hw.memsize"); err == nil {
 		if bytes, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64); err == nil {
@@ -40,6 +50,56 @@ func collectHostInfo(ctx context.Context, runner CommandRunner, info *HostInfo)
 		}
 	}
 
+	// Swap usage; total is 0 when no swap file is in use
+	if out, err := runner.Run(ctx, "sysctl -n vm.swapusage"); err == nil {
+		total, _, free := parser.ParseSwapUsage(string(out))
+		info.System.Swap = newSwapInfo(total, free)
+	}
+
+	// Load averages
+	if out, err := runner.Run(ctx, "sysctl -n vm.loadavg"); err == nil {
+		info.System.LoadAvg = parseLoadAvg(string(out))
+	}
+
+	// Uptime from the boot time
+	if out, err := runner.Run(ctx, "sysctl -n kern.boottime"); err == nil {
+		if boot, ok := parseBoottime(string(out)); ok {
+			info.System.UptimeSeconds = int64(time.Since(boot).Seconds())
+		}
+	}
+
+	// Zombie processes
+	collectZombies(ctx, runner, info)
+
+	// CPU/GPU die temperatures; powermetrics needs root, so unprivileged
+	// agents skip them
+	if out, err := runner.Run(ctx, "powermetrics --samplers smc -i 1 -n 1 2>/dev/null"); err == nil {
+		info.System.Thermal = newThermalInfo(parser.ParsePowermetricsTemps(string(out)))
+	}
+
+	// Power source and battery charge
+	if out, err := runner.Run(ctx, "pmset -g batt"); err == nil {
+		batt := parser.ParsePmsetBatt(string(out))
+		info.System.PowerSource = batt.Source
+		if batt.Present {
+			info.System.BatteryPercent = batt.Percent
+			info.System.BatteryStatus = normalizeBatteryStatus(batt.Charging)
+		}
+	}
+
+	// Battery cycle count
+	// Output format: "CycleCount" = 123
+	if out, err := runner.Run(ctx, "ioreg -rn AppleSmartBattery"); err == nil {
+		for _, line := range strings.Split(string(out), "\n") {
+			if strings.Contains(line, `"CycleCount" =`) {
+				if n, err := strconv.Atoi(extractIORegValue(line)); err == nil {
+					info.System.BatteryCycleCount = n
+				}
+				break
+			}
+		}
+	}
+
 	// Serial number from IOKit
 	// Output format: "IOPlatformSerialNumber" = "C02XXXXXXXXX"
 	if out, err := runner.Run(ctx, `ioreg -rd1 -c IOPlatformExpertDevice | grep IOPlatformSerialNumber`); err == nil {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"strconv"
 	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
 )
 
 func collectHostInfo(ctx context.Context, runner CommandRunner, info *HostInfo) error {
@@ -31,14 +33,16 @@ func collectHostInfo(ctx context.Context, runner CommandRunner, info *HostInfo)
 		}
 	}
 
-	// Memory from /proc/meminfo (MemTotal in kB)
-	if out, err := runner.Run(ctx, `grep MemTotal /proc/meminfo 2>/dev/null`); err == nil {
-		fields := strings.Fields(string(out))
-		if len(fields) >= 2 {
-			if kb, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
-				info.System.MemoryGB = float64(kb) / (1024 * 1024)
-			}
-		}
+	// CPU frequency scaling (absent on most VMs)
+	if out, err := runner.Run(ctx, `grep -H . /sys/devices/system/cpu/cpu0/cpufreq/scaling_max_freq /sys/devices/system/cpu/cpu0/cpufreq/scaling_governor 2>/dev/null`); err == nil {
+		info.System.CPUMaxMHz, info.System.CPUGovernor = parser.ParseCPUFreq(string(out))
+	}
+
+	// Memory and swap from /proc/meminfo
+	if out, err := runner.Run(ctx, `grep -E "^(MemTotal|SwapTotal|SwapFree):" /proc/meminfo 2>/dev/null`); err == nil {
+		mem := parser.ParseMeminfo(string(out))
+		info.System.MemoryGB = float64(mem["MemTotal"]) / (1024 * 1024 * 1024)
+		info.System.Swap = newSwapInfo(mem["SwapTotal"], mem["SwapFree"])
 	}
 
 	// Serial number from DMI/SMBIOS (requires root or readable sysfs)
@@ -54,5 +58,43 @@ func collectHostInfo(ctx context.Context, runner CommandRunner, info *HostInfo)
 		info.System.MachineID = strings.TrimSpace(string(out))
 	}
 
+	// Load averages
+	if out, err := runner.Run(ctx, `cat /proc/loadavg 2>/dev/null`); err == nil {
+		info.System.LoadAvg = parseLoadAvg(string(out))
+	}
+
+	// Failed systemd units (absent on non-systemd hosts)
+	if out, err := runner.Run(ctx, `systemctl list-units --state=failed --no-legend --plain 2>/dev/null`); err == nil {
+		info.System.FailedServices = parseFailedUnits(string(out))
+		info.System.ServicesChecked = true
+	}
+
+	// Zombie processes
+	collectZombies(ctx, runner, info)
+
+	// GPU inventory
+	collectGPUs(ctx, runner, info)
+
+	// PCI devices
+	collectPCI(ctx, runner, info)
+
+	// NUMA layout
+	collectNUMA(ctx, runner, info)
+
+	// Temperature sensors
+	collectThermal(ctx, runner, info)
+
+	// AC/battery state (laptops and battery-backed edge nodes)
+	collectPowerSupply(ctx, runner, info)
+
+	// Pending reboot after kernel/package updates
+	collectRebootRequired(ctx, runner, info)
+
+	// cgroup version and the limits the agent itself runs under
+	version, limits := collectCgroupLimits("")
+	info.System.CgroupVersion = version
+	info.System.CgroupMemoryGB = float64(limits.MemoryBytes) / (1024 * 1024 * 1024)
+	info.System.CgroupCPUs = limits.CPUs
+
 	return nil
 }
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLoadAvg(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []float64
+	}{
+		{"0.52 0.58 0.59 1/467 12345\n", []float64{0.52, 0.58, 0.59}},
+		{"{ 1.23 1.10 0.98 }\n", []float64{1.23, 1.10, 0.98}},
+		{"", nil},
+		{"garbage here now", nil},
+	}
+	for _, tt := range tests {
+		if got := parseLoadAvg(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseLoadAvg(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseBoottime(t *testing.T) {
+	boot, ok := parseBoottime("{ sec = 1760600000, usec = 123456 } Thu Oct 16 08:33:20 2026\n")
+	if !ok || boot.Unix() != 1760600000 {
+		t.Errorf("parseBoottime = %v, %v; want unix 1760600000", boot, ok)
+	}
+	for _, in := range []string{"", "{ usec = 5 }", "{ sec = abc, usec = 0 }"} {
+		if _, ok := parseBoottime(in); ok {
+			t.Errorf("parseBoottime(%q) should fail", in)
+		}
+	}
+}
+
+func TestParseFailedUnits(t *testing.T) {
+	out := "nginx.service loaded failed failed A high performance web server\n" +
+		"● cron.service loaded failed failed Regular background program processing daemon\n\n"
+	got := parseFailedUnits(out)
+	want := []string{"nginx.service", "cron.service"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFailedUnits = %v, want %v", got, want)
+	}
+	if got := parseFailedUnits(""); got != nil {
+		t.Errorf("expected nil for no failed units, got %v", got)
+	}
+}
+
+func TestNewSwapInfo(t *testing.T) {
+	// SwapTotal/SwapFree from testdata/proc_meminfo.txt
+	got := newSwapInfo(2097148*1024, 1572860*1024)
+	want := &SwapInfo{
+		TotalBytes: 2147479552,
+		UsedBytes:  536870912,
+		FreeBytes:  1610608640,
+		UsedPct:    25,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newSwapInfo = %+v, want %+v", got, want)
+	}
+	if got := newSwapInfo(0, 0); got != nil {
+		t.Errorf("expected nil when swap is disabled, got %+v", got)
+	}
+}
@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+// windowsSystemScript gathers OS, CPU, memory and identity from CIM in one
+// PowerShell start-up, which costs far more than the queries themselves.
+const windowsSystemScript = `$os = Get-CimInstance Win32_OperatingSystem
+$cpu = @(Get-CimInstance Win32_Processor)
+$cs = Get-CimInstance Win32_ComputerSystem
+$bios = Get-CimInstance Win32_BIOS
+$csp = Get-CimInstance Win32_ComputerSystemProduct
+[pscustomobject]@{
+  Caption = $os.Caption; Version = $os.Version
+  CPUModel = $cpu[0].Name; Cores = ($cpu | Measure-Object NumberOfCores -Sum).Sum
+  MemoryBytes = $cs.TotalPhysicalMemory
+  SerialNumber = $bios.SerialNumber; UUID = $csp.UUID
+} | ConvertTo-Json -Compress`
+
+func collectHostInfo(ctx context.Context, runner CommandRunner, info *HostInfo) error {
+	out, err := runner.Run(ctx, PowerShellCommand(windowsSystemScript))
+	if err != nil {
+		return nil
+	}
+	sys, ok := parser.ParsePSSystem(out)
+	if !ok {
+		return nil
+	}
+
+	info.System.OSVersion = strings.TrimSpace(sys.Caption + " " + sys.Version)
+	info.System.CPUModel = sys.CPUModel
+	info.System.CPUCores = sys.Cores
+	info.System.MemoryGB = float64(sys.MemoryBytes) / (1024 * 1024 * 1024)
+	if !IsJunkSerial(sys.SerialNumber) {
+		info.System.SerialNumber = sys.SerialNumber
+	}
+	// Hypervisors and white-box boards report an all-F UUID when unset
+	if sys.UUID != "" && sys.UUID != "FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF" {
+		info.System.MachineID = sys.UUID
+	}
+	return nil
+}
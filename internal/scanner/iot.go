@@ -7,16 +7,18 @@ import (
 	"github.com/tinkerbelle-io/tb-manage/internal/iot"
 )
 
-// IoTScanner discovers IoT devices via available providers.
-type IoTScanner struct{}
+// IoTScanner discovers IoT devices via available providers. It keeps one
+// provider registry so discoveries are cached across scans.
+type IoTScanner struct {
+	registry *iot.Registry
+}
 
-func NewIoTScanner() *IoTScanner { return &IoTScanner{} }
+func NewIoTScanner() *IoTScanner { return &IoTScanner{registry: iot.NewRegistry()} }
 
-func (s *IoTScanner) Name() string       { return "iot" }
+func (s *IoTScanner) Name() string        { return "iot" }
 func (s *IoTScanner) Platforms() []string { return nil }
 
 func (s *IoTScanner) Scan(ctx context.Context, _ CommandRunner) (json.RawMessage, error) {
-	reg := iot.NewRegistry()
-	result := reg.Scan(ctx)
+	result := s.registry.Scan(ctx)
 	return json.Marshal(result)
 }
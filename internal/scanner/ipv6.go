@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// ipv6EgressTarget is dialed by the opt-in IPv6 egress check: Google
+// Public DNS, which answers TCP on port 53.
+const ipv6EgressTarget = "[2001:4860:4860::8888]:53"
+
+// hasGlobalIPv6 reports whether any interface carries a globally routable
+// IPv6 address. Loopback, link-local (fe80::/10) and unique local
+// (fc00::/7) addresses do not count.
+func hasGlobalIPv6(ifaces []InterfaceInfo) bool {
+	for _, iface := range ifaces {
+		if isGlobalIPv6(iface.IPv6) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGlobalIPv6 accepts an address with an optional /prefix or %zone.
+func isGlobalIPv6(s string) bool {
+	s, _, _ = strings.Cut(s, "/")
+	s, _, _ = strings.Cut(s, "%")
+	addr, err := netip.ParseAddr(s)
+	if err != nil || !addr.Is6() || addr.Is4In6() {
+		return false
+	}
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// checkIPv6Egress reports whether a TCP connection to an Internet host
+// over IPv6 succeeds. It fails fast when the host has no IPv6 default
+// route, so it also covers that case.
+func checkIPv6Egress(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp6", ipv6EgressTarget)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
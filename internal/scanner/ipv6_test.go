@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+func TestHasGlobalIPv6(t *testing.T) {
+	tests := []struct {
+		fixture string
+		parse   func(string) []parser.InterfaceInfo
+		want    bool
+	}{
+		{"ip_addr_linux.txt", parser.ParseIPAddr, true},                 // 2001:db8::50 on eth0
+		{"ifconfig_macos.txt", parser.ParseIfconfig, true},              // 2600:1700:... on en0
+		{"ip_addr_linux_no_global_ipv6.txt", parser.ParseIPAddr, false}, // loopback, ULA and link-local only
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile("../../testdata/" + tt.fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ifaces := convertParserInterfaces(tt.parse(string(data)))
+		if got := hasGlobalIPv6(ifaces); got != tt.want {
+			t.Errorf("%s: hasGlobalIPv6 = %v, want %v", tt.fixture, got, tt.want)
+		}
+	}
+}
+
+func TestIsGlobalIPv6(t *testing.T) {
+	tests := map[string]bool{
+		"2001:db8::50":       true,
+		"2600:1700::1/64":    true,
+		"::1":                false,
+		"fe80::1%en0":        false,
+		"fd12:3456:789a::50": false,
+		"::ffff:192.0.2.1":   false,
+		"10.0.0.1":           false,
+		"":                   false,
+	}
+	for addr, want := range tests {
+		if got := isGlobalIPv6(addr); got != want {
+			t.Errorf("isGlobalIPv6(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
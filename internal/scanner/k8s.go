@@ -3,22 +3,28 @@ package scanner
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -33,9 +39,34 @@ var DefaultExcludeNamespaces = []string{
 
 // K8sScanner discovers Kubernetes cluster resources using client-go.
 type K8sScanner struct {
+	// Namespaces left out of the scan, by exact name or glob
 	ExcludeNamespaces map[string]bool
+	// When set, only namespaces matching one of these names or globs are
+	// scanned; exclusions still apply. See skipNamespace.
+	IncludeNamespaces []string
+	// ClusterNameLabel is read off the kube-system namespace, then the
+	// nodes, to name the cluster before falling back to the kubeconfig
+	// context. Tenant clusters run in-cluster with no kubeconfig.
+	ClusterNameLabel string
+
+	// Client-side rate limit for API requests (0 = DefaultK8sQPS and
+	// DefaultK8sBurst)
+	QPS   float32
+	Burst int
+
+	// Namespaces scanned at once (0 = DefaultNamespaceConcurrency); see
+	// scanNamespaces for how this backs off when the API server throttles
+	NamespaceConcurrency int
+
+	// ImageInventory lists every distinct running image with its digest,
+	// for correlating with vulnerability feeds. Built from the same pod
+	// list as the pod placements.
+	ImageInventory bool
 }
 
+// DefaultClusterNameLabel is the label key used when none is configured.
+const DefaultClusterNameLabel = "tinkerbelle.io/tenant"
+
 // NewK8sScanner creates a K8sScanner with default exclusions.
 func NewK8sScanner() *K8sScanner {
 	return NewK8sScannerWithExclusions(DefaultExcludeNamespaces)
@@ -47,7 +78,7 @@ func NewK8sScannerWithExclusions(exclude []string) *K8sScanner {
 	for _, ns := range exclude {
 		m[ns] = true
 	}
-	return &K8sScanner{ExcludeNamespaces: m}
+	return &K8sScanner{ExcludeNamespaces: m, ClusterNameLabel: DefaultClusterNameLabel}
 }
 
 func (s *K8sScanner) Name() string       { return "cluster" }
@@ -58,6 +89,13 @@ func (s *K8sScanner) Scan(ctx context.Context, _ CommandRunner) (json.RawMessage
 	if err != nil {
 		return nil, fmt.Errorf("k8s config: %w", err)
 	}
+	config.QPS, config.Burst = DefaultK8sQPS, DefaultK8sBurst
+	if s.QPS > 0 {
+		config.QPS = s.QPS
+	}
+	if s.Burst > 0 {
+		config.Burst = s.Burst
+	}
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -69,9 +107,52 @@ func (s *K8sScanner) Scan(ctx context.Context, _ CommandRunner) (json.RawMessage
 		return nil, fmt.Errorf("k8s dynamic client: %w", err)
 	}
 
+	metaClient, err := metadata.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("k8s metadata client: %w", err)
+	}
+
 	log := slog.Default().With("scanner", "k8s")
+	access := newAccessChecker(clientset, log)
+
+	result, err := s.scanCluster(ctx, clientset, access, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flux CD
+	result.FluxKustomizations, result.FluxDetected = scanFlux(ctx, dynClient, log)
+
+	// Vertical Pod Autoscaler recommendations
+	attachVPAs(&result, scanVPA(ctx, dynClient, access, log))
+
+	// cert-manager Certificates
+	attachCertificates(&result, scanCertificates(ctx, dynClient, access, log))
+
+	// CPU and memory usage from metrics-server
+	attachUsage(&result, scanUsage(ctx, dynClient, access, log))
+
+	// Installed CRDs
+	result.CRDs = scanCRDs(ctx, dynClient, access, log)
 
+	// Include the optional APIs listed above in the access summary
+	result.Access = access.summary()
+	logDenied(result.Access, log)
+
+	// Helm releases
+	if result.HelmReleases, err = s.scanHelmReleases(ctx, clientset, metaClient, log); err != nil {
+		log.Debug("cannot list helm releases", "error", err)
+	}
+
+	return json.Marshal(result)
+}
+
+// scanCluster collects cluster resources, skipping those the agent's RBAC
+// does not allow it to list. The skipped resources are reported in
+// result.Access instead of failing one List call at a time.
+func (s *K8sScanner) scanCluster(ctx context.Context, clientset kubernetes.Interface, access *accessChecker, log *slog.Logger) (ClusterScanResult, error) {
 	result := ClusterScanResult{}
+	var err error
 
 	// Cluster version
 	if ver, err := clientset.Discovery().ServerVersion(); err == nil {
@@ -80,12 +161,34 @@ func (s *K8sScanner) Scan(ctx context.Context, _ CommandRunner) (json.RawMessage
 
 	// Detect provider
 	result.Provider = "kubernetes"
-	result.Name = detectClusterName(clientset, ctx)
+	canListNodes := access.canListCluster(ctx, resNodes)
+	result.Name = labeledClusterName(ctx, clientset, s.ClusterNameLabel, canListNodes)
+	if result.Name == "" {
+		result.Name = detectClusterName(clientset, ctx, canListNodes)
+	}
 
 	// Nodes
-	result.Nodes, err = scanNodes(ctx, clientset)
-	if err != nil {
-		log.Warn("failed to scan nodes", "error", err)
+	if canListNodes {
+		result.Nodes, err = scanNodes(ctx, clientset)
+		if err != nil {
+			log.Warn("failed to scan nodes", "error", err)
+		}
+	}
+
+	// PersistentVolumes (cluster-scoped)
+	if access.canListCluster(ctx, resPVs) {
+		result.PersistentVolumes, err = scanPVs(ctx, clientset)
+		if err != nil {
+			log.Warn("failed to scan persistent volumes", "error", err)
+		}
+	}
+
+	// StorageClasses (cluster-scoped)
+	if access.canListCluster(ctx, resStorageClasses) {
+		result.StorageClasses, err = scanStorageClasses(ctx, clientset)
+		if err != nil {
+			log.Warn("failed to scan storage classes", "error", err)
+		}
 	}
 
 	// Detect k3s
@@ -97,27 +200,62 @@ func (s *K8sScanner) Scan(ctx context.Context, _ CommandRunner) (json.RawMessage
 	}
 
 	// Namespaces
+	if !access.canListCluster(ctx, resNamespaces) {
+		log.Warn("not permitted to list namespaces, skipping namespaced resources")
+		result.Access = access.summary()
+		return result, nil
+	}
 	nsList, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("list namespaces: %w", err)
+		return result, fmt.Errorf("list namespaces: %w", err)
 	}
 
+	sort.Slice(nsList.Items, func(i, j int) bool { return nsList.Items[i].Name < nsList.Items[j].Name })
+	var namespaces []corev1.Namespace
 	for _, ns := range nsList.Items {
-		if s.ExcludeNamespaces[ns.Name] {
-			continue
+		if !s.skipNamespace(ns.Name) {
+			namespaces = append(namespaces, ns)
 		}
-		nsResult, err := scanNamespace(ctx, clientset, ns)
+	}
+	result.Namespaces = s.scanNamespaces(ctx, clientset, access, namespaces, log)
+
+	// Pod-to-node placements and the image inventory
+	if access.canListCluster(ctx, resPods) {
+		podList, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
 		if err != nil {
-			log.Warn("failed to scan namespace", "namespace", ns.Name, "error", err)
-			continue
+			log.Warn("failed to list pods", "error", err)
+		} else {
+			result.PodPlacements = s.podPlacements(podList.Items, time.Now(), log)
+			if s.ImageInventory {
+				result.Images = s.imageInventory(podList.Items)
+			}
 		}
-		result.Namespaces = append(result.Namespaces, nsResult)
 	}
 
-	// Flux CD
-	result.FluxKustomizations, result.FluxDetected = scanFlux(ctx, dynClient, log)
+	result.Access = access.summary()
+	return result, nil
+}
 
-	return json.Marshal(result)
+// logDenied warns about each resource the scan was not permitted to list.
+func logDenied(access *AccessSummary, log *slog.Logger) {
+	for _, d := range access.Denied {
+		log.Warn("not permitted to list resource, skipped", "resource", d.Resource, "namespaces", d.Namespaces)
+	}
+}
+
+// attachByNamespace adds each item to its namespace's result with add.
+// Items in namespaces that were not scanned (excluded, or not listable)
+// are dropped.
+func attachByNamespace[T any](result *ClusterScanResult, items []T, namespace func(T) string, add func(*NamespaceScanResult, T)) {
+	byName := make(map[string]int, len(result.Namespaces))
+	for i, ns := range result.Namespaces {
+		byName[ns.Name] = i
+	}
+	for _, item := range items {
+		if i, ok := byName[namespace(item)]; ok {
+			add(&result.Namespaces[i], item)
+		}
+	}
 }
 
 // GetK8sConfig returns in-cluster config or falls back to kubeconfig.
@@ -138,8 +276,30 @@ func GetK8sConfig() (*rest.Config, error) {
 	return clientcmd.BuildConfigFromFlags("", kubeconfig)
 }
 
-// detectClusterName tries to determine the cluster name.
-func detectClusterName(clientset kubernetes.Interface, ctx context.Context) string {
+// labeledClusterName returns the value of label on the kube-system
+// namespace, or else on the first node carrying it. Returns "" when label
+// is empty, unreadable or not set anywhere.
+func labeledClusterName(ctx context.Context, clientset kubernetes.Interface, label string, listNodes bool) string {
+	if label == "" {
+		return ""
+	}
+	if ns, err := clientset.CoreV1().Namespaces().Get(ctx, "kube-system", metav1.GetOptions{}); err == nil {
+		if v := ns.Labels[label]; v != "" {
+			return v
+		}
+	}
+	if listNodes {
+		nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: label, Limit: 1})
+		if err == nil && len(nodes.Items) > 0 {
+			return nodes.Items[0].Labels[label]
+		}
+	}
+	return ""
+}
+
+// detectClusterName tries to determine the cluster name. The node-name
+// fallback is only tried when listNodes is set.
+func detectClusterName(clientset kubernetes.Interface, ctx context.Context, listNodes bool) string {
 	// Try to get it from kubeconfig context
 	kubeconfig := os.Getenv("KUBECONFIG")
 	if kubeconfig == "" {
@@ -151,9 +311,11 @@ func detectClusterName(clientset kubernetes.Interface, ctx context.Context) stri
 	}
 
 	// Fall back to first node name prefix
-	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
-	if err == nil && len(nodes.Items) > 0 {
-		return nodes.Items[0].Name
+	if listNodes {
+		nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+		if err == nil && len(nodes.Items) > 0 {
+			return nodes.Items[0].Name
+		}
 	}
 
 	return "unknown"
@@ -168,31 +330,56 @@ func scanNodes(ctx context.Context, clientset kubernetes.Interface) ([]NodeScanR
 	var nodes []NodeScanResult
 	for _, node := range nodeList.Items {
 		status := "Unknown"
+		var pressure []string
 		for _, cond := range node.Status.Conditions {
-			if cond.Type == corev1.NodeReady {
+			switch cond.Type {
+			case corev1.NodeReady:
 				if cond.Status == corev1.ConditionTrue {
 					status = "Ready"
 				} else {
 					status = "NotReady"
 				}
-				break
+			case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
+				if cond.Status == corev1.ConditionTrue {
+					pressure = append(pressure, string(cond.Type))
+				}
 			}
 		}
 
 		roles := extractRoles(node.Labels)
 
 		nodes = append(nodes, NodeScanResult{
-			Name:    node.Name,
-			Status:  status,
-			Roles:   roles,
-			Version: node.Status.NodeInfo.KubeletVersion,
-			OS:      node.Status.NodeInfo.OperatingSystem,
-			OSImage: node.Status.NodeInfo.OSImage,
+			Name:        node.Name,
+			Status:      status,
+			Roles:       roles,
+			Version:     node.Status.NodeInfo.KubeletVersion,
+			OS:          node.Status.NodeInfo.OperatingSystem,
+			OSImage:     node.Status.NodeInfo.OSImage,
+			Pressure:    pressure,
+			Taints:      nodeTaints(node.Spec.Taints),
+			Capacity:    resourceListToMap(node.Status.Capacity),
+			Allocatable: resourceListToMap(node.Status.Allocatable),
+			Labels:      node.Labels,
 		})
 	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
 	return nodes, nil
 }
 
+// nodeTaints renders taints the way kubectl describe does:
+// "key=value:Effect", or "key:Effect" when the taint has no value.
+func nodeTaints(taints []corev1.Taint) []string {
+	var out []string
+	for _, t := range taints {
+		s := t.Key
+		if t.Value != "" {
+			s += "=" + t.Value
+		}
+		out = append(out, s+":"+string(t.Effect))
+	}
+	return out
+}
+
 func extractRoles(labels map[string]string) []string {
 	var roles []string
 	for k, v := range labels {
@@ -209,77 +396,160 @@ func extractRoles(labels map[string]string) []string {
 	if len(roles) == 0 {
 		roles = []string{"worker"}
 	}
+	sort.Strings(roles)
 	return roles
 }
 
-func scanNamespace(ctx context.Context, clientset kubernetes.Interface, ns corev1.Namespace) (NamespaceScanResult, error) {
+// scanNamespace lists a namespace's resources. Lists that fail are left
+// empty and their errors joined into the returned error, so the caller
+// can keep the partial result and still see why it is partial.
+func scanNamespace(ctx context.Context, clientset kubernetes.Interface, access *accessChecker, ns corev1.Namespace) (NamespaceScanResult, error) {
 	nsName := ns.Name
 	result := NamespaceScanResult{
 		Name:   nsName,
 		Labels: ns.Labels,
 	}
+	var errs []error
+	var err error
 
 	// Workloads
-	result.Workloads = scanWorkloads(ctx, clientset, nsName)
+	result.Workloads, err = scanWorkloads(ctx, clientset, access, nsName)
+	errs = append(errs, err)
 
 	// Services
-	result.Services = scanServices(ctx, clientset, nsName)
+	if access.canList(ctx, resServices, nsName) {
+		result.Services, err = scanServices(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
 
 	// Ingresses
-	result.Ingresses = scanIngresses(ctx, clientset, nsName)
+	if access.canList(ctx, resIngresses, nsName) {
+		result.Ingresses, err = scanIngresses(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
 
 	// ConfigMaps
-	result.ConfigMaps = scanConfigMaps(ctx, clientset, nsName)
+	if access.canList(ctx, resConfigMaps, nsName) {
+		result.ConfigMaps, err = scanConfigMaps(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
 
 	// Secrets
-	result.Secrets = scanSecrets(ctx, clientset, nsName)
+	if access.canList(ctx, resSecrets, nsName) {
+		result.Secrets, err = scanSecrets(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
 
 	// PVCs
-	result.PVCs = scanPVCs(ctx, clientset, nsName)
+	if access.canList(ctx, resPVCs, nsName) {
+		result.PVCs, err = scanPVCs(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
 
 	// CronJobs
-	result.CronJobs = scanCronJobs(ctx, clientset, nsName)
+	if access.canList(ctx, resCronJobs, nsName) {
+		result.CronJobs, err = scanCronJobs(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
 
 	// NetworkPolicies
-	result.NetworkPolicies = scanNetworkPolicies(ctx, clientset, nsName)
+	if access.canList(ctx, resNetworkPolicies, nsName) {
+		result.NetworkPolicies, err = scanNetworkPolicies(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
 
 	// PDBs
-	result.PDBs = scanPDBs(ctx, clientset, nsName)
+	if access.canList(ctx, resPDBs, nsName) {
+		result.PDBs, err = scanPDBs(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
 
-	return result, nil
+	// HPAs
+	if access.canList(ctx, resHPAs, nsName) {
+		result.HPAs, err = scanHPAs(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
+
+	// ResourceQuotas
+	if access.canList(ctx, resResourceQuotas, nsName) {
+		result.ResourceQuotas, err = scanResourceQuotas(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
+
+	// LimitRanges
+	if access.canList(ctx, resLimitRanges, nsName) {
+		result.LimitRanges, err = scanLimitRanges(ctx, clientset, nsName)
+		errs = append(errs, err)
+	}
+
+	sortNamespaceResult(&result)
+	return result, errors.Join(errs...)
+}
+
+// sortNamespaceResult orders a namespace's resources by name (workloads by
+// kind, then name), so that repeated scans of an unchanged namespace
+// marshal identically whatever order the API returned them in.
+func sortNamespaceResult(r *NamespaceScanResult) {
+	sort.SliceStable(r.Workloads, func(i, j int) bool {
+		if r.Workloads[i].Kind != r.Workloads[j].Kind {
+			return r.Workloads[i].Kind < r.Workloads[j].Kind
+		}
+		return r.Workloads[i].Name < r.Workloads[j].Name
+	})
+	sort.Slice(r.Services, func(i, j int) bool { return r.Services[i].Name < r.Services[j].Name })
+	sort.Slice(r.Ingresses, func(i, j int) bool { return r.Ingresses[i].Name < r.Ingresses[j].Name })
+	sort.Slice(r.ConfigMaps, func(i, j int) bool { return r.ConfigMaps[i].Name < r.ConfigMaps[j].Name })
+	sort.Slice(r.Secrets, func(i, j int) bool { return r.Secrets[i].Name < r.Secrets[j].Name })
+	sort.Slice(r.PVCs, func(i, j int) bool { return r.PVCs[i].Name < r.PVCs[j].Name })
+	sort.Slice(r.CronJobs, func(i, j int) bool { return r.CronJobs[i].Name < r.CronJobs[j].Name })
+	sort.Slice(r.NetworkPolicies, func(i, j int) bool { return r.NetworkPolicies[i].Name < r.NetworkPolicies[j].Name })
+	sort.Slice(r.PDBs, func(i, j int) bool { return r.PDBs[i].Name < r.PDBs[j].Name })
+	sort.Slice(r.HPAs, func(i, j int) bool { return r.HPAs[i].Name < r.HPAs[j].Name })
+	sort.Slice(r.ResourceQuotas, func(i, j int) bool { return r.ResourceQuotas[i].Name < r.ResourceQuotas[j].Name })
+	sort.Slice(r.LimitRanges, func(i, j int) bool { return r.LimitRanges[i].Name < r.LimitRanges[j].Name })
 }
 
-func scanWorkloads(ctx context.Context, clientset kubernetes.Interface, ns string) []WorkloadScanResult {
+func scanWorkloads(ctx context.Context, clientset kubernetes.Interface, access *accessChecker, ns string) ([]WorkloadScanResult, error) {
 	var workloads []WorkloadScanResult
+	var errs []error
 
 	// Deployments
-	deploys, err := clientset.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
-	if err == nil {
-		for _, d := range deploys.Items {
-			w := deploymentToWorkload(d)
-			workloads = append(workloads, w)
+	if access.canList(ctx, resDeployments, ns) {
+		deploys, err := clientset.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			for _, d := range deploys.Items {
+				w := deploymentToWorkload(d)
+				workloads = append(workloads, w)
+			}
 		}
+		errs = append(errs, err)
 	}
 
 	// StatefulSets
-	stss, err := clientset.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{})
-	if err == nil {
-		for _, s := range stss.Items {
-			w := statefulSetToWorkload(s)
-			workloads = append(workloads, w)
+	if access.canList(ctx, resStatefulSets, ns) {
+		stss, err := clientset.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			for _, s := range stss.Items {
+				w := statefulSetToWorkload(s)
+				workloads = append(workloads, w)
+			}
 		}
+		errs = append(errs, err)
 	}
 
 	// DaemonSets
-	dss, err := clientset.AppsV1().DaemonSets(ns).List(ctx, metav1.ListOptions{})
-	if err == nil {
-		for _, d := range dss.Items {
-			w := daemonSetToWorkload(d)
-			workloads = append(workloads, w)
+	if access.canList(ctx, resDaemonSets, ns) {
+		dss, err := clientset.AppsV1().DaemonSets(ns).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			for _, d := range dss.Items {
+				w := daemonSetToWorkload(d)
+				workloads = append(workloads, w)
+			}
 		}
+		errs = append(errs, err)
 	}
 
-	return workloads
+	return workloads, errors.Join(errs...)
 }
 
 func deploymentToWorkload(d appsv1.Deployment) WorkloadScanResult {
@@ -300,6 +570,12 @@ func deploymentToWorkload(d appsv1.Deployment) WorkloadScanResult {
 	if d.Spec.Strategy.Type != "" {
 		w.Strategy = string(d.Spec.Strategy.Type)
 	}
+	if ru := d.Spec.Strategy.RollingUpdate; ru != nil {
+		w.StrategyParams = &StrategyParams{
+			MaxSurge:       intOrStringValue(ru.MaxSurge),
+			MaxUnavailable: intOrStringValue(ru.MaxUnavailable),
+		}
+	}
 	w.Containers = extractContainers(d.Spec.Template.Spec.Containers)
 	w.Requests, w.Limits = aggregateResources(d.Spec.Template.Spec.Containers)
 	return w
@@ -316,6 +592,15 @@ func statefulSetToWorkload(s appsv1.StatefulSet) WorkloadScanResult {
 		rr := s.Status.ReadyReplicas
 		w.ReadyReplicas = &rr
 	}
+	if s.Spec.UpdateStrategy.Type != "" {
+		w.Strategy = string(s.Spec.UpdateStrategy.Type)
+	}
+	if ru := s.Spec.UpdateStrategy.RollingUpdate; ru != nil {
+		w.StrategyParams = &StrategyParams{
+			MaxUnavailable: intOrStringValue(ru.MaxUnavailable),
+			Partition:      ru.Partition,
+		}
+	}
 	w.Containers = extractContainers(s.Spec.Template.Spec.Containers)
 	w.Requests, w.Limits = aggregateResources(s.Spec.Template.Spec.Containers)
 	return w
@@ -331,11 +616,28 @@ func daemonSetToWorkload(d appsv1.DaemonSet) WorkloadScanResult {
 	w.DesiredNumberScheduled = &dns
 	nr := d.Status.NumberReady
 	w.NumberReady = &nr
+	if d.Spec.UpdateStrategy.Type != "" {
+		w.Strategy = string(d.Spec.UpdateStrategy.Type)
+	}
+	if ru := d.Spec.UpdateStrategy.RollingUpdate; ru != nil {
+		w.StrategyParams = &StrategyParams{
+			MaxSurge:       intOrStringValue(ru.MaxSurge),
+			MaxUnavailable: intOrStringValue(ru.MaxUnavailable),
+		}
+	}
 	w.Containers = extractContainers(d.Spec.Template.Spec.Containers)
 	w.Requests, w.Limits = aggregateResources(d.Spec.Template.Spec.Containers)
 	return w
 }
 
+// intOrStringValue renders a count or percentage as in the spec ("1", "25%").
+func intOrStringValue(v *intstr.IntOrString) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
 func extractContainers(containers []corev1.Container) []ContainerInfoK8s {
 	var result []ContainerInfoK8s
 	for _, c := range containers {
@@ -380,11 +682,11 @@ func aggregateResources(containers []corev1.Container) (*ResourceRequirements, *
 	return req, lim
 }
 
-func scanServices(ctx context.Context, clientset kubernetes.Interface, ns string) []K8sServiceScanResult {
+func scanServices(ctx context.Context, clientset kubernetes.Interface, ns string) ([]K8sServiceScanResult, error) {
 	var services []K8sServiceScanResult
 	svcList, err := clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	for _, svc := range svcList.Items {
@@ -412,14 +714,14 @@ func scanServices(ctx context.Context, clientset kubernetes.Interface, ns string
 		}
 		services = append(services, s)
 	}
-	return services
+	return services, nil
 }
 
-func scanIngresses(ctx context.Context, clientset kubernetes.Interface, ns string) []IngressScanResult {
+func scanIngresses(ctx context.Context, clientset kubernetes.Interface, ns string) ([]IngressScanResult, error) {
 	var ingresses []IngressScanResult
 	ingList, err := clientset.NetworkingV1().Ingresses(ns).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	for _, ing := range ingList.Items {
@@ -456,14 +758,14 @@ func scanIngresses(ctx context.Context, clientset kubernetes.Interface, ns strin
 		}
 		ingresses = append(ingresses, i)
 	}
-	return ingresses
+	return ingresses, nil
 }
 
-func scanConfigMaps(ctx context.Context, clientset kubernetes.Interface, ns string) []ConfigMapScanResult {
+func scanConfigMaps(ctx context.Context, clientset kubernetes.Interface, ns string) ([]ConfigMapScanResult, error) {
 	var cms []ConfigMapScanResult
 	cmList, err := clientset.CoreV1().ConfigMaps(ns).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	for _, cm := range cmList.Items {
@@ -475,20 +777,21 @@ func scanConfigMaps(ctx context.Context, clientset kubernetes.Interface, ns stri
 		for k := range cm.Data {
 			keys = append(keys, k)
 		}
+		sort.Strings(keys)
 		cms = append(cms, ConfigMapScanResult{
 			Name:      cm.Name,
 			Namespace: cm.Namespace,
 			DataKeys:  keys,
 		})
 	}
-	return cms
+	return cms, nil
 }
 
-func scanSecrets(ctx context.Context, clientset kubernetes.Interface, ns string) []SecretScanResult {
+func scanSecrets(ctx context.Context, clientset kubernetes.Interface, ns string) ([]SecretScanResult, error) {
 	var secrets []SecretScanResult
 	secretList, err := clientset.CoreV1().Secrets(ns).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	for _, s := range secretList.Items {
@@ -500,6 +803,7 @@ func scanSecrets(ctx context.Context, clientset kubernetes.Interface, ns string)
 		for k := range s.Data {
 			keys = append(keys, k)
 		}
+		sort.Strings(keys)
 		secrets = append(secrets, SecretScanResult{
 			Name:      s.Name,
 			Namespace: s.Namespace,
@@ -507,14 +811,14 @@ func scanSecrets(ctx context.Context, clientset kubernetes.Interface, ns string)
 			DataKeys:  keys,
 		})
 	}
-	return secrets
+	return secrets, nil
 }
 
-func scanPVCs(ctx context.Context, clientset kubernetes.Interface, ns string) []PVCScanResult {
+func scanPVCs(ctx context.Context, clientset kubernetes.Interface, ns string) ([]PVCScanResult, error) {
 	var pvcs []PVCScanResult
 	pvcList, err := clientset.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	for _, pvc := range pvcList.Items {
@@ -534,14 +838,75 @@ func scanPVCs(ctx context.Context, clientset kubernetes.Interface, ns string) []
 		}
 		pvcs = append(pvcs, p)
 	}
-	return pvcs
+	return pvcs, nil
+}
+
+func scanPVs(ctx context.Context, clientset kubernetes.Interface) ([]PVScanResult, error) {
+	var pvs []PVScanResult
+	pvList, err := clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pv := range pvList.Items {
+		p := PVScanResult{
+			Name:          pv.Name,
+			ReclaimPolicy: string(pv.Spec.PersistentVolumeReclaimPolicy),
+			Status:        string(pv.Status.Phase),
+			StorageClass:  pv.Spec.StorageClassName,
+		}
+		if cap, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+			p.Capacity = cap.String()
+		}
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			p.ClaimRef = ref.Namespace + "/" + ref.Name
+		}
+		pvs = append(pvs, p)
+	}
+	sort.Slice(pvs, func(i, j int) bool { return pvs[i].Name < pvs[j].Name })
+	return pvs, nil
 }
 
-func scanCronJobs(ctx context.Context, clientset kubernetes.Interface, ns string) []CronJobScanResult {
+// Annotations marking the default StorageClass; the beta one is still set
+// by older provisioners.
+const (
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+func scanStorageClasses(ctx context.Context, clientset kubernetes.Interface) ([]StorageClassScanResult, error) {
+	var classes []StorageClassScanResult
+	scList, err := clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sc := range scList.Items {
+		c := StorageClassScanResult{
+			Name:        sc.Name,
+			Provisioner: sc.Provisioner,
+			IsDefault:   sc.Annotations[defaultStorageClassAnnotation] == "true" || sc.Annotations[betaDefaultStorageClassAnnotation] == "true",
+		}
+		// API defaults: Delete and Immediate
+		c.ReclaimPolicy = string(corev1.PersistentVolumeReclaimDelete)
+		if sc.ReclaimPolicy != nil {
+			c.ReclaimPolicy = string(*sc.ReclaimPolicy)
+		}
+		c.VolumeBindingMode = string(storagev1.VolumeBindingImmediate)
+		if sc.VolumeBindingMode != nil {
+			c.VolumeBindingMode = string(*sc.VolumeBindingMode)
+		}
+		classes = append(classes, c)
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i].Name < classes[j].Name })
+	return classes, nil
+}
+
+func scanCronJobs(ctx context.Context, clientset kubernetes.Interface, ns string) ([]CronJobScanResult, error) {
 	var cronJobs []CronJobScanResult
 	cjList, err := clientset.BatchV1().CronJobs(ns).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	for _, cj := range cjList.Items {
@@ -557,14 +922,14 @@ func scanCronJobs(ctx context.Context, clientset kubernetes.Interface, ns string
 		}
 		cronJobs = append(cronJobs, c)
 	}
-	return cronJobs
+	return cronJobs, nil
 }
 
-func scanNetworkPolicies(ctx context.Context, clientset kubernetes.Interface, ns string) []NetworkPolicyScanResult {
+func scanNetworkPolicies(ctx context.Context, clientset kubernetes.Interface, ns string) ([]NetworkPolicyScanResult, error) {
 	var nps []NetworkPolicyScanResult
 	npList, err := clientset.NetworkingV1().NetworkPolicies(ns).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	for _, np := range npList.Items {
@@ -579,14 +944,14 @@ func scanNetworkPolicies(ctx context.Context, clientset kubernetes.Interface, ns
 			PolicyTypes: pTypes,
 		})
 	}
-	return nps
+	return nps, nil
 }
 
-func scanPDBs(ctx context.Context, clientset kubernetes.Interface, ns string) []PDBScanResult {
+func scanPDBs(ctx context.Context, clientset kubernetes.Interface, ns string) ([]PDBScanResult, error) {
 	var pdbs []PDBScanResult
 	pdbList, err := clientset.PolicyV1().PodDisruptionBudgets(ns).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	for _, pdb := range pdbList.Items {
@@ -607,7 +972,7 @@ func scanPDBs(ctx context.Context, clientset kubernetes.Interface, ns string) []
 		}
 		pdbs = append(pdbs, p)
 	}
-	return pdbs
+	return pdbs, nil
 }
 
 func labelSelectorToMap(sel metav1.LabelSelector) map[string]interface{} {
@@ -675,6 +1040,12 @@ func scanFlux(ctx context.Context, dynClient dynamic.Interface, log *slog.Logger
 		kustomizations = append(kustomizations, k)
 	}
 
+	sort.Slice(kustomizations, func(i, j int) bool {
+		if kustomizations[i].Name != kustomizations[j].Name {
+			return kustomizations[i].Name < kustomizations[j].Name
+		}
+		return kustomizations[i].Path < kustomizations[j].Path
+	})
 	return kustomizations, true
 }
 
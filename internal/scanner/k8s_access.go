@@ -0,0 +1,222 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+
+	authv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AccessSummary reports which resources the agent's credentials may list,
+// so a sparse scan can be told apart from an empty cluster.
+type AccessSummary struct {
+	// Permitted resources were listable everywhere they were scanned.
+	Permitted []string `json:"permitted"`
+	// Denied resources were skipped, cluster-wide or in some namespaces.
+	Denied []AccessDenial `json:"denied,omitempty"`
+}
+
+// AccessDenial is a resource the agent may not list.
+type AccessDenial struct {
+	Resource string `json:"resource"`
+	// Namespaces where listing is denied; empty for cluster-scoped
+	// resources.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// k8sResource identifies a resource the cluster scanner lists.
+type k8sResource struct {
+	group    string
+	resource string
+}
+
+func (r k8sResource) String() string {
+	if r.group == "" {
+		return r.resource
+	}
+	return r.group + "/" + r.resource
+}
+
+var (
+	resNodes           = k8sResource{"", "nodes"}
+	resNamespaces      = k8sResource{"", "namespaces"}
+	resPods            = k8sResource{"", "pods"}
+	resDeployments     = k8sResource{"apps", "deployments"}
+	resStatefulSets    = k8sResource{"apps", "statefulsets"}
+	resDaemonSets      = k8sResource{"apps", "daemonsets"}
+	resServices        = k8sResource{"", "services"}
+	resIngresses       = k8sResource{"networking.k8s.io", "ingresses"}
+	resConfigMaps      = k8sResource{"", "configmaps"}
+	resSecrets         = k8sResource{"", "secrets"}
+	resPVCs            = k8sResource{"", "persistentvolumeclaims"}
+	resPVs             = k8sResource{"", "persistentvolumes"}
+	resStorageClasses  = k8sResource{"storage.k8s.io", "storageclasses"}
+	resCronJobs        = k8sResource{"batch", "cronjobs"}
+	resNetworkPolicies = k8sResource{"networking.k8s.io", "networkpolicies"}
+	resPDBs            = k8sResource{"policy", "poddisruptionbudgets"}
+	resHPAs            = k8sResource{"autoscaling", "horizontalpodautoscalers"}
+	resResourceQuotas  = k8sResource{"", "resourcequotas"}
+	resLimitRanges     = k8sResource{"", "limitranges"}
+
+	// Optional APIs, listed through the dynamic client when installed
+	resVPAs         = k8sResource{"autoscaling.k8s.io", "verticalpodautoscalers"}
+	resCertificates = k8sResource{"cert-manager.io", "certificates"}
+	resCRDs         = k8sResource{"apiextensions.k8s.io", "customresourcedefinitions"}
+	resNodeMetrics  = k8sResource{"metrics.k8s.io", "nodes"}
+	resPodMetrics   = k8sResource{"metrics.k8s.io", "pods"}
+)
+
+// accessChecker answers "may the agent list this resource here?" from the
+// API server's own view of the agent's RBAC. When the server cannot answer
+// (review API unavailable, or an authorizer that does not support rule
+// enumeration), access is assumed and the List call decides. It is safe
+// for concurrent use by namespace scans.
+type accessChecker struct {
+	clientset kubernetes.Interface
+	log       *slog.Logger
+
+	mu     sync.Mutex                                  // guards the maps below
+	rules  map[string]*authv1.SubjectRulesReviewStatus // per namespace; nil = unknown
+	denied map[k8sResource]map[string]bool             // resource -> namespaces ("" = cluster)
+	seen   map[k8sResource]bool
+}
+
+func newAccessChecker(clientset kubernetes.Interface, log *slog.Logger) *accessChecker {
+	return &accessChecker{
+		clientset: clientset,
+		log:       log,
+		rules:     make(map[string]*authv1.SubjectRulesReviewStatus),
+		denied:    make(map[k8sResource]map[string]bool),
+		seen:      make(map[k8sResource]bool),
+	}
+}
+
+// canListCluster checks a cluster-scoped resource with a
+// SelfSubjectAccessReview.
+func (c *accessChecker) canListCluster(ctx context.Context, res k8sResource) bool {
+	review := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Verb:     "list",
+				Group:    res.group,
+				Resource: res.resource,
+			},
+		},
+	}
+	allowed := true
+	resp, err := c.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		c.log.Debug("access review failed, assuming access", "resource", res.String(), "error", err)
+	} else {
+		allowed = resp.Status.Allowed
+	}
+	c.record(res, "", allowed)
+	return allowed
+}
+
+// canList checks a namespaced resource against the namespace's rules,
+// fetched once per namespace with a SelfSubjectRulesReview.
+func (c *accessChecker) canList(ctx context.Context, res k8sResource, ns string) bool {
+	c.mu.Lock()
+	status, ok := c.rules[ns]
+	c.mu.Unlock()
+	if !ok {
+		review := &authv1.SelfSubjectRulesReview{
+			Spec: authv1.SelfSubjectRulesReviewSpec{Namespace: ns},
+		}
+		resp, err := c.clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			c.log.Debug("rules review failed, assuming access", "namespace", ns, "error", err)
+		} else if resp.Status.Incomplete {
+			c.log.Debug("rules review incomplete, assuming access", "namespace", ns, "error", resp.Status.EvaluationError)
+		} else {
+			status = &resp.Status
+		}
+		c.mu.Lock()
+		c.rules[ns] = status
+		c.mu.Unlock()
+	}
+
+	allowed := status == nil || rulesAllowList(status.ResourceRules, res)
+	c.record(res, ns, allowed)
+	return allowed
+}
+
+// rulesAllowList reports whether any rule grants list on res. Rules limited
+// to named objects do not grant list.
+func rulesAllowList(rules []authv1.ResourceRule, res k8sResource) bool {
+	for _, r := range rules {
+		if len(r.ResourceNames) > 0 {
+			continue
+		}
+		if matchesRule(r.Verbs, "list") && matchesRule(r.APIGroups, res.group) && matchesRule(r.Resources, res.resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRule(values []string, want string) bool {
+	for _, v := range values {
+		if v == want || v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// recordList records the outcome of a cluster-wide List of an optional API
+// that is not checked up front. A Forbidden error is a denial, so missing
+// RBAC is not mistaken for the API not being installed; other errors (the
+// API is not served) are not recorded.
+func (c *accessChecker) recordList(res k8sResource, err error) {
+	switch {
+	case err == nil:
+		c.record(res, "", true)
+	case apierrors.IsForbidden(err):
+		c.record(res, "", false)
+	}
+}
+
+func (c *accessChecker) record(res k8sResource, ns string, allowed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seen[res] = true
+	if allowed {
+		return
+	}
+	if c.denied[res] == nil {
+		c.denied[res] = make(map[string]bool)
+	}
+	c.denied[res][ns] = true
+}
+
+// summary returns the permitted/denied resources seen so far, sorted.
+func (c *accessChecker) summary() *AccessSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := &AccessSummary{Permitted: []string{}}
+	for res := range c.seen {
+		nss, denied := c.denied[res]
+		if !denied {
+			s.Permitted = append(s.Permitted, res.String())
+			continue
+		}
+		d := AccessDenial{Resource: res.String()}
+		for ns := range nss {
+			if ns != "" {
+				d.Namespaces = append(d.Namespaces, ns)
+			}
+		}
+		sort.Strings(d.Namespaces)
+		s.Denied = append(s.Denied, d)
+	}
+	sort.Strings(s.Permitted)
+	sort.Slice(s.Denied, func(i, j int) bool { return s.Denied[i].Resource < s.Denied[j].Resource })
+	return s
+}
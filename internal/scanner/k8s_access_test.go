@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// limitedRBAC simulates an agent that may list namespaces but not nodes,
+// everything in team-b, and everything except secrets in team-a.
+func limitedRBAC(clientset *fake.Clientset) {
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Resource != "nodes"
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectRulesReview)
+		switch review.Spec.Namespace {
+		case "team-a":
+			review.Status.ResourceRules = []authv1.ResourceRule{
+				{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"services", "configmaps", "persistentvolumeclaims", "resourcequotas", "limitranges"}},
+				{Verbs: []string{"list"}, APIGroups: []string{"apps", "autoscaling", "batch", "networking.k8s.io", "policy"}, Resources: []string{"*"}},
+				// Named-object access does not allow listing
+				{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"app-config"}},
+			}
+		case "team-b":
+			review.Status.ResourceRules = []authv1.ResourceRule{
+				{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			}
+		}
+		return true, review, nil
+	})
+}
+
+func TestScanClusterSkipsDeniedResources(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-b"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"}},
+	)
+	limitedRBAC(clientset)
+
+	s := NewK8sScannerWithExclusions(nil)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	result, err := s.scanCluster(context.Background(), clientset, newAccessChecker(clientset, log), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Nodes) != 0 {
+		t.Errorf("nodes should be skipped, got %v", result.Nodes)
+	}
+	byNS := make(map[string]NamespaceScanResult)
+	for _, ns := range result.Namespaces {
+		byNS[ns.Name] = ns
+	}
+	if len(byNS["team-a"].Secrets) != 0 || len(byNS["team-a"].Services) != 1 {
+		t.Errorf("team-a: secrets=%v services=%v", byNS["team-a"].Secrets, byNS["team-a"].Services)
+	}
+	if len(byNS["team-b"].Secrets) != 1 {
+		t.Errorf("team-b secrets = %v, want 1", byNS["team-b"].Secrets)
+	}
+
+	// Denied resources are never listed
+	for _, a := range clientset.Actions() {
+		if !a.Matches("list", "secrets") && !a.Matches("list", "nodes") {
+			continue
+		}
+		if a.GetResource().Resource == "nodes" || a.GetNamespace() == "team-a" {
+			t.Errorf("unexpected list of denied resource: %s in %q", a.GetResource().Resource, a.GetNamespace())
+		}
+	}
+
+	if result.Access == nil {
+		t.Fatal("expected access summary")
+	}
+	wantDenied := []AccessDenial{
+		{Resource: "nodes"},
+		{Resource: "secrets", Namespaces: []string{"team-a"}},
+	}
+	if !reflect.DeepEqual(result.Access.Denied, wantDenied) {
+		t.Errorf("denied = %+v, want %+v", result.Access.Denied, wantDenied)
+	}
+	for _, p := range result.Access.Permitted {
+		if p == "secrets" || p == "nodes" {
+			t.Errorf("%s should not be reported as permitted", p)
+		}
+	}
+	if len(result.Access.Permitted) != 17 {
+		t.Errorf("permitted = %v, want the 17 other resources", result.Access.Permitted)
+	}
+}
+
+func TestScanClusterAssumesAccessWhenReviewUnanswered(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"}},
+	)
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectRulesReview)
+		review.Status.Incomplete = true
+		return true, review, nil
+	})
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	result, err := NewK8sScannerWithExclusions(nil).scanCluster(context.Background(), clientset, newAccessChecker(clientset, log), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Namespaces) != 1 || len(result.Namespaces[0].Secrets) != 1 {
+		t.Errorf("incomplete rules should not skip resources: %+v", result.Namespaces)
+	}
+	if len(result.Access.Denied) != 0 {
+		t.Errorf("denied = %+v, want none", result.Access.Denied)
+	}
+}
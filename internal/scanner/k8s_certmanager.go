@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var certificateGVR = schema.GroupVersionResource{
+	Group:    "cert-manager.io",
+	Version:  "v1",
+	Resource: "certificates",
+}
+
+// scanCertificates lists cert-manager Certificates in every namespace with
+// their expiry and planned renewal. It returns nil when cert-manager is not
+// installed or the agent may not list Certificates.
+func scanCertificates(ctx context.Context, dynClient dynamic.Interface, access *accessChecker, log *slog.Logger) []CertificateScanResult {
+	list, err := dynClient.Resource(certificateGVR).Namespace("").List(ctx, metav1.ListOptions{})
+	access.recordList(resCertificates, err)
+	if err != nil {
+		// cert-manager not installed — not an error
+		log.Debug("cannot list cert-manager certificates", "error", err)
+		return nil
+	}
+
+	var certs []CertificateScanResult
+	for _, item := range list.Items {
+		c := CertificateScanResult{
+			Name:       item.GetName(),
+			Namespace:  item.GetNamespace(),
+			IssuerKind: "Issuer", // the API default
+		}
+		c.DNSNames, _, _ = unstructured.NestedStringSlice(item.Object, "spec", "dnsNames")
+		c.SecretName, _, _ = unstructured.NestedString(item.Object, "spec", "secretName")
+		c.IssuerName, _, _ = unstructured.NestedString(item.Object, "spec", "issuerRef", "name")
+		if kind, _, _ := unstructured.NestedString(item.Object, "spec", "issuerRef", "kind"); kind != "" {
+			c.IssuerKind = kind
+		}
+		c.NotAfter = certificateTime(item, "notAfter")
+		c.RenewalTime = certificateTime(item, "renewalTime")
+		certs = append(certs, c)
+	}
+
+	sort.Slice(certs, func(i, j int) bool {
+		if certs[i].Namespace != certs[j].Namespace {
+			return certs[i].Namespace < certs[j].Namespace
+		}
+		return certs[i].Name < certs[j].Name
+	})
+	return certs
+}
+
+// certificateTime parses an RFC 3339 timestamp from a Certificate's status,
+// or returns nil when it is absent (not issued yet) or malformed.
+func certificateTime(item unstructured.Unstructured, field string) *time.Time {
+	s, _, _ := unstructured.NestedString(item.Object, "status", field)
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// attachCertificates adds each Certificate to its namespace's result,
+// dropping those in namespaces that were not scanned.
+func attachCertificates(result *ClusterScanResult, certs []CertificateScanResult) {
+	attachByNamespace(result, certs, func(c CertificateScanResult) string { return c.Namespace },
+		func(ns *NamespaceScanResult, c CertificateScanResult) { ns.Certificates = append(ns.Certificates, c) })
+}
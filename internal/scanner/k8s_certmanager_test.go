@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestScanCertificates(t *testing.T) {
+	cert := func(ns, name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+		obj := map[string]interface{}{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "Certificate",
+			"metadata":   map[string]interface{}{"name": name, "namespace": ns},
+			"spec":       spec,
+		}
+		if status != nil {
+			obj["status"] = status
+		}
+		return &unstructured.Unstructured{Object: obj}
+	}
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{certificateGVR: "CertificateList"},
+		cert("shop", "web-tls", map[string]interface{}{
+			"secretName": "web-tls",
+			"dnsNames":   []interface{}{"shop.example.com", "www.shop.example.com"},
+			"issuerRef":  map[string]interface{}{"name": "letsencrypt", "kind": "ClusterIssuer", "group": "cert-manager.io"},
+		}, map[string]interface{}{
+			"notAfter":    "2026-05-01T12:00:00Z",
+			"renewalTime": "2026-03-31T12:00:00Z",
+		}),
+		// Not issued yet, namespaced issuer by default
+		cert("shop", "api-tls", map[string]interface{}{
+			"secretName": "api-tls",
+			"dnsNames":   []interface{}{"api.shop.example.com"},
+			"issuerRef":  map[string]interface{}{"name": "internal-ca"},
+		}, nil),
+		cert("excluded", "tool-tls", map[string]interface{}{
+			"issuerRef": map[string]interface{}{"name": "internal-ca"},
+		}, nil),
+	)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	certs := scanCertificates(context.Background(), dynClient, newAccessChecker(nil, log), log)
+	result := ClusterScanResult{Namespaces: []NamespaceScanResult{{Name: "shop"}}}
+	attachCertificates(&result, certs)
+
+	notAfter := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	renewal := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	want := []CertificateScanResult{
+		{
+			Name: "api-tls", Namespace: "shop", DNSNames: []string{"api.shop.example.com"},
+			SecretName: "api-tls", IssuerKind: "Issuer", IssuerName: "internal-ca",
+		},
+		{
+			Name: "web-tls", Namespace: "shop", DNSNames: []string{"shop.example.com", "www.shop.example.com"},
+			SecretName: "web-tls", IssuerKind: "ClusterIssuer", IssuerName: "letsencrypt",
+			NotAfter: &notAfter, RenewalTime: &renewal,
+		},
+	}
+	if got := result.Namespaces[0].Certificates; !reflect.DeepEqual(got, want) {
+		t.Errorf("certificates =\n%+v\nwant\n%+v", got, want)
+	}
+}
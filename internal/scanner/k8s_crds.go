@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var crdGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// builtinAPIGroups are served by the API server itself. A CRD claiming one
+// of them is not an installed extension and is left out of the inventory.
+var builtinAPIGroups = map[string]bool{
+	"":                             true,
+	"admissionregistration.k8s.io": true,
+	"apiextensions.k8s.io":         true,
+	"apiregistration.k8s.io":       true,
+	"apps":                         true,
+	"authentication.k8s.io":        true,
+	"authorization.k8s.io":         true,
+	"autoscaling":                  true,
+	"batch":                        true,
+	"certificates.k8s.io":          true,
+	"coordination.k8s.io":          true,
+	"discovery.k8s.io":             true,
+	"events.k8s.io":                true,
+	"flowcontrol.apiserver.k8s.io": true,
+	"networking.k8s.io":            true,
+	"node.k8s.io":                  true,
+	"policy":                       true,
+	"rbac.authorization.k8s.io":    true,
+	"resource.k8s.io":              true,
+	"scheduling.k8s.io":            true,
+	"storage.k8s.io":               true,
+	"storagemigration.k8s.io":      true,
+}
+
+// scanCRDs lists the CustomResourceDefinitions installed in the cluster,
+// i.e. which operators and extensions it runs. It returns nil when the
+// agent may not list CRDs.
+func scanCRDs(ctx context.Context, dynClient dynamic.Interface, access *accessChecker, log *slog.Logger) []CRDScanResult {
+	list, err := dynClient.Resource(crdGVR).List(ctx, metav1.ListOptions{})
+	access.recordList(resCRDs, err)
+	if err != nil {
+		log.Debug("cannot list CRDs", "error", err)
+		return nil
+	}
+
+	var crds []CRDScanResult
+	for _, item := range list.Items {
+		group, _, _ := unstructured.NestedString(item.Object, "spec", "group")
+		if builtinAPIGroups[group] {
+			continue
+		}
+		c := CRDScanResult{Name: item.GetName(), Group: group}
+		c.Kind, _, _ = unstructured.NestedString(item.Object, "spec", "names", "kind")
+		c.Scope, _, _ = unstructured.NestedString(item.Object, "spec", "scope")
+
+		versions, _, _ := unstructured.NestedSlice(item.Object, "spec", "versions")
+		for _, v := range versions {
+			ver, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if served, found, _ := unstructured.NestedBool(ver, "served"); found && !served {
+				continue
+			}
+			if name, _, _ := unstructured.NestedString(ver, "name"); name != "" {
+				c.Versions = append(c.Versions, name)
+			}
+		}
+		crds = append(crds, c)
+	}
+	sort.Slice(crds, func(i, j int) bool { return crds[i].Name < crds[j].Name })
+	return crds
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestScanCRDs(t *testing.T) {
+	crd := func(name, group, kind, scope string, versions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec": map[string]interface{}{
+				"group":    group,
+				"names":    map[string]interface{}{"kind": kind},
+				"scope":    scope,
+				"versions": versions,
+			},
+		}}
+	}
+	version := func(name string, served bool) interface{} {
+		return map[string]interface{}{"name": name, "served": served, "storage": served}
+	}
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{crdGVR: "CustomResourceDefinitionList"},
+		crd("certificates.cert-manager.io", "cert-manager.io", "Certificate", "Namespaced",
+			version("v1", true), version("v1alpha2", false)),
+		// Claims a built-in group: not an installed extension
+		crd("widgets.apps", "apps", "Widget", "Namespaced", version("v1", true)),
+	)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	got := scanCRDs(context.Background(), dynClient, newAccessChecker(nil, log), log)
+	want := []CRDScanResult{{
+		Name:     "certificates.cert-manager.io",
+		Group:    "cert-manager.io",
+		Kind:     "Certificate",
+		Versions: []string{"v1"},
+		Scope:    "Namespaced",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("crds =\n%+v\nwant\n%+v", got, want)
+	}
+}
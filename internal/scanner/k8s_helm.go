@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/helmrelease"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+)
+
+// secretsGVR is read through the metadata client, which lists Secrets
+// without their data.
+var secretsGVR = corev1.SchemeGroupVersion.WithResource("secrets")
+
+// helmRelease is the part of Helm's release record the scanner reads.
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"` // revision
+	Info      struct {
+		Status string `json:"status"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+// scanHelmReleases reports the latest revision of every Helm 3 release in
+// namespaces that are not excluded. Revisions are found with a
+// metadata-only list, so only the latest revision's Secret is fetched and
+// decoded; one that cannot be fetched or decoded is skipped.
+func (s *K8sScanner) scanHelmReleases(ctx context.Context, clientset kubernetes.Interface, metaClient metadata.Interface, log *slog.Logger) ([]HelmReleaseScanResult, error) {
+	list, err := metaClient.Resource(secretsGVR).Namespace("").List(ctx, metav1.ListOptions{
+		LabelSelector: helmrelease.LabelSelector,
+		FieldSelector: helmrelease.FieldSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var revs []helmrelease.Revision
+	for i := range list.Items {
+		rev, ok := helmrelease.ParseRevision(&list.Items[i])
+		if ok && !s.skipNamespace(rev.Namespace) {
+			revs = append(revs, rev)
+		}
+	}
+
+	var releases []HelmReleaseScanResult
+	for _, rev := range helmrelease.Latest(revs) {
+		sec, err := clientset.CoreV1().Secrets(rev.Namespace).Get(ctx, rev.Secret, metav1.GetOptions{})
+		if err != nil {
+			log.Debug("cannot get helm release secret", "namespace", rev.Namespace, "secret", rev.Secret, "error", err)
+			continue
+		}
+		rel, err := decodeHelmRelease(sec.Data["release"])
+		if err != nil {
+			log.Debug("skipping undecodable helm release", "namespace", rev.Namespace, "secret", rev.Secret, "error", err)
+			continue
+		}
+		releases = append(releases, HelmReleaseScanResult{
+			Name:       rel.Name,
+			Namespace:  rev.Namespace,
+			Chart:      rel.Chart.Metadata.Name,
+			Version:    rel.Chart.Metadata.Version,
+			AppVersion: rel.Chart.Metadata.AppVersion,
+			Status:     rel.Info.Status,
+			Revision:   rel.Version,
+		})
+	}
+	return releases, nil
+}
+
+// decodeHelmRelease decodes a release Secret's payload: base64 over JSON
+// that Helm gzips by default, bounded by MaxDecompressedSize.
+func decodeHelmRelease(data []byte) (*helmRelease, error) {
+	raw, err := decodeGzipBase64(data, MaxDecompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	var rel helmRelease
+	if err := json.Unmarshal(raw, &rel); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+	if rel.Name == "" {
+		return nil, fmt.Errorf("release has no name")
+	}
+	return &rel, nil
+}
@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/helmrelease"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// helmSecret builds a release Secret the way Helm 3 stores it: the release
+// JSON, gzipped, base64-encoded.
+func helmSecret(t *testing.T, ns, name string, rev int, status string) *corev1.Secret {
+	t.Helper()
+	payload := fmt.Sprintf(`{"name":%q,"namespace":%q,"version":%d,"info":{"status":%q},`+
+		`"chart":{"metadata":{"name":"ingress-nginx","version":"4.%d.0","appVersion":"1.%d.0"}}}`, name, ns, rev, status, rev, rev)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(payload)); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, rev),
+			Namespace: ns,
+			Labels:    map[string]string{"owner": "helm", "name": name, "version": fmt.Sprint(rev), "status": status},
+		},
+		Type: helmrelease.SecretType,
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))},
+	}
+}
+
+// helmClients returns a clientset holding secrets and a metadata client
+// that lists their metadata, as the API server would.
+func helmClients(secrets ...*corev1.Secret) (*fake.Clientset, *metadatafake.FakeMetadataClient) {
+	scheme := metadatafake.NewTestScheme()
+	metav1.AddMetaToScheme(scheme)
+	var objs, metas []runtime.Object
+	for _, sec := range secrets {
+		objs = append(objs, sec)
+		metas = append(metas, &metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: sec.ObjectMeta,
+		})
+	}
+	return fake.NewSimpleClientset(objs...), metadatafake.NewSimpleMetadataClient(scheme, metas...)
+}
+
+func TestScanHelmReleases(t *testing.T) {
+	corrupt := helmSecret(t, "apps", "broken", 1, "deployed")
+	corrupt.Data["release"] = []byte("not base64!")
+	clientset, metaClient := helmClients(
+		helmSecret(t, "ingress", "ingress", 1, "superseded"),
+		helmSecret(t, "ingress", "ingress", 2, "deployed"),
+		helmSecret(t, "apps", "api", 3, "failed"),
+		helmSecret(t, "kube-system", "traefik", 1, "deployed"),
+		corrupt,
+	)
+
+	s := NewK8sScanner()
+	got, err := s.scanHelmReleases(context.Background(), clientset, metaClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HelmReleaseScanResult{
+		{Name: "api", Namespace: "apps", Chart: "ingress-nginx", Version: "4.3.0", AppVersion: "1.3.0", Status: "failed", Revision: 3},
+		{Name: "ingress", Namespace: "ingress", Chart: "ingress-nginx", Version: "4.2.0", AppVersion: "1.2.0", Status: "deployed", Revision: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("releases =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Secret data is only read for the latest revision of each release
+	var fetched []string
+	for _, action := range clientset.Actions() {
+		switch a := action.(type) {
+		case ktesting.GetAction:
+			fetched = append(fetched, a.GetNamespace()+"/"+a.GetName())
+		case ktesting.ListAction:
+			t.Errorf("unexpected %s list through the typed client", a.GetResource().Resource)
+		}
+	}
+	wantFetched := []string{"apps/sh.helm.release.v1.api.v3", "apps/sh.helm.release.v1.broken.v1", "ingress/sh.helm.release.v1.ingress.v2"}
+	if !reflect.DeepEqual(fetched, wantFetched) {
+		t.Errorf("fetched secrets = %v, want %v", fetched, wantFetched)
+	}
+}
+
+func TestScanHelmReleasesSkipsOversized(t *testing.T) {
+	// A small gzip payload that inflates past MaxDecompressedSize must be
+	// skipped rather than read into memory.
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(`{"name":"bomb","padding":"`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zw.Write(make([]byte, MaxDecompressedSize+1)); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	bomb := helmSecret(t, "apps", "bomb", 1, "deployed")
+	bomb.Data["release"] = []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))
+
+	if _, err := decodeHelmRelease(bomb.Data["release"]); !errors.Is(err, ErrDecompressedTooLarge) {
+		t.Fatalf("decodeHelmRelease err = %v, want ErrDecompressedTooLarge", err)
+	}
+
+	clientset, metaClient := helmClients(bomb, helmSecret(t, "apps", "api", 1, "deployed"))
+	s := NewK8sScanner()
+	got, err := s.scanHelmReleases(context.Background(), clientset, metaClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "api" {
+		t.Errorf("releases = %+v, want only api", got)
+	}
+}
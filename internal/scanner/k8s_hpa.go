@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// scanHPAs lists a namespace's HorizontalPodAutoscalers. Clusters older
+// than 1.23 do not serve autoscaling/v2; there the v1 API is used, which
+// only knows a CPU utilization target.
+func scanHPAs(ctx context.Context, clientset kubernetes.Interface, ns string) ([]HPAScanResult, error) {
+	hpaList, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(ns).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return scanHPAsV1(ctx, clientset, ns)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var hpas []HPAScanResult
+	for _, hpa := range hpaList.Items {
+		h := HPAScanResult{
+			Name:            hpa.Name,
+			Namespace:       hpa.Namespace,
+			TargetKind:      hpa.Spec.ScaleTargetRef.Kind,
+			TargetName:      hpa.Spec.ScaleTargetRef.Name,
+			MinReplicas:     1,
+			MaxReplicas:     hpa.Spec.MaxReplicas,
+			CurrentReplicas: hpa.Status.CurrentReplicas,
+			DesiredReplicas: hpa.Status.DesiredReplicas,
+		}
+		if hpa.Spec.MinReplicas != nil {
+			h.MinReplicas = *hpa.Spec.MinReplicas
+		}
+		for _, m := range hpa.Spec.Metrics {
+			if metric, ok := hpaMetric(m); ok {
+				h.Metrics = append(h.Metrics, metric)
+			}
+		}
+		hpas = append(hpas, h)
+	}
+	return hpas, nil
+}
+
+func scanHPAsV1(ctx context.Context, clientset kubernetes.Interface, ns string) ([]HPAScanResult, error) {
+	hpaList, err := clientset.AutoscalingV1().HorizontalPodAutoscalers(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var hpas []HPAScanResult
+	for _, hpa := range hpaList.Items {
+		h := HPAScanResult{
+			Name:            hpa.Name,
+			Namespace:       hpa.Namespace,
+			TargetKind:      hpa.Spec.ScaleTargetRef.Kind,
+			TargetName:      hpa.Spec.ScaleTargetRef.Name,
+			MinReplicas:     1,
+			MaxReplicas:     hpa.Spec.MaxReplicas,
+			CurrentReplicas: hpa.Status.CurrentReplicas,
+			DesiredReplicas: hpa.Status.DesiredReplicas,
+		}
+		if hpa.Spec.MinReplicas != nil {
+			h.MinReplicas = *hpa.Spec.MinReplicas
+		}
+		if pct := hpa.Spec.TargetCPUUtilizationPercentage; pct != nil {
+			h.Metrics = []HPAMetric{{Type: "Resource", Name: "cpu", Target: fmt.Sprintf("%d%%", *pct)}}
+		}
+		hpas = append(hpas, h)
+	}
+	return hpas, nil
+}
+
+// hpaMetric summarizes one autoscaling/v2 metric spec.
+func hpaMetric(m autoscalingv2.MetricSpec) (HPAMetric, bool) {
+	metric := HPAMetric{Type: string(m.Type)}
+	switch {
+	case m.Resource != nil:
+		metric.Name = string(m.Resource.Name)
+		metric.Target = hpaTarget(m.Resource.Target)
+	case m.ContainerResource != nil:
+		metric.Name = m.ContainerResource.Container + "/" + string(m.ContainerResource.Name)
+		metric.Target = hpaTarget(m.ContainerResource.Target)
+	case m.Pods != nil:
+		metric.Name = m.Pods.Metric.Name
+		metric.Target = hpaTarget(m.Pods.Target)
+	case m.Object != nil:
+		metric.Name = m.Object.Metric.Name
+		metric.Target = hpaTarget(m.Object.Target)
+	case m.External != nil:
+		metric.Name = m.External.Metric.Name
+		metric.Target = hpaTarget(m.External.Target)
+	default:
+		return HPAMetric{}, false
+	}
+	return metric, true
+}
+
+// hpaTarget formats a metric target: "80%" for utilization, else the
+// (average) value.
+func hpaTarget(t autoscalingv2.MetricTarget) string {
+	switch {
+	case t.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *t.AverageUtilization)
+	case t.AverageValue != nil:
+		return t.AverageValue.String()
+	case t.Value != nil:
+		return t.Value.String()
+	}
+	return ""
+}
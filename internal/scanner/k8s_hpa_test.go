@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func TestScanHPAs(t *testing.T) {
+	avgValue := resource.MustParse("100")
+	clientset := fake.NewSimpleClientset(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+			MinReplicas:    int32Ptr(2),
+			MaxReplicas:    10,
+			Metrics: []autoscalingv2.MetricSpec{
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name:   corev1.ResourceCPU,
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: int32Ptr(75)},
+					},
+				},
+				{
+					Type: autoscalingv2.PodsMetricSourceType,
+					Pods: &autoscalingv2.PodsMetricSource{
+						Metric: autoscalingv2.MetricIdentifier{Name: "http_requests_per_second"},
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &avgValue},
+					},
+				},
+			},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 3, DesiredReplicas: 4},
+	})
+
+	got, err := scanHPAs(context.Background(), clientset, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HPAScanResult{{
+		Name: "web", Namespace: "shop", TargetKind: "Deployment", TargetName: "web",
+		MinReplicas: 2, MaxReplicas: 10, CurrentReplicas: 3, DesiredReplicas: 4,
+		Metrics: []HPAMetric{
+			{Type: "Resource", Name: "cpu", Target: "75%"},
+			{Type: "Pods", Name: "http_requests_per_second", Target: "100"},
+		},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HPAs =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestScanHPAsFallsBackToV1(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef:                 autoscalingv1.CrossVersionObjectReference{Kind: "Deployment", Name: "web"},
+			MaxReplicas:                    5,
+			TargetCPUUtilizationPercentage: int32Ptr(80),
+		},
+		Status: autoscalingv1.HorizontalPodAutoscalerStatus{CurrentReplicas: 1, DesiredReplicas: 1},
+	})
+	// An old API server does not serve autoscaling/v2
+	clientset.PrependReactor("list", "horizontalpodautoscalers", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Version != "v2" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "autoscaling", Resource: "horizontalpodautoscalers"}, "")
+	})
+
+	got, err := scanHPAs(context.Background(), clientset, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []HPAScanResult{{
+		Name: "web", Namespace: "shop", TargetKind: "Deployment", TargetName: "web",
+		MinReplicas: 1, MaxReplicas: 5, CurrentReplicas: 1, DesiredReplicas: 1,
+		Metrics: []HPAMetric{{Type: "Resource", Name: "cpu", Target: "80%"}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HPAs =\n%+v\nwant\n%+v", got, want)
+	}
+}
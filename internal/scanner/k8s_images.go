@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/imageref"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// imageInventory lists every distinct image running in pods from
+// namespaces that are not excluded, pinned to the digest the kubelet
+// resolved where the container status reports one. Pods that have finished
+// are left out.
+func (s *K8sScanner) imageInventory(pods []corev1.Pod) []ImageInventoryEntry {
+	byImage := make(map[string]*ImageInventoryEntry)
+	namespaces := make(map[string]map[string]bool)
+	for _, pod := range pods {
+		if s.skipNamespace(pod.Namespace) || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		digests := make(map[string]string)
+		for _, cs := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+			if _, digest, ok := strings.Cut(cs.ImageID, "@"); ok {
+				digests[cs.Name] = digest
+			}
+		}
+		for _, c := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+			ref := imageref.Parse(c.Image)
+			if ref.Digest == "" {
+				ref.Digest = digests[c.Name]
+			}
+			key := ref.String()
+			e, ok := byImage[key]
+			if !ok {
+				e = &ImageInventoryEntry{
+					Image:      key,
+					Registry:   ref.Registry,
+					Repository: ref.Repository,
+					Tag:        ref.Tag,
+					Digest:     ref.Digest,
+				}
+				byImage[key] = e
+				namespaces[key] = make(map[string]bool)
+			}
+			e.Containers++
+			namespaces[key][pod.Namespace] = true
+		}
+	}
+
+	images := make([]ImageInventoryEntry, 0, len(byImage))
+	for key, e := range byImage {
+		for ns := range namespaces[key] {
+			e.Namespaces = append(e.Namespaces, ns)
+		}
+		sort.Strings(e.Namespaces)
+		images = append(images, *e)
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].Image < images[j].Image })
+	return images
+}
@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestImageInventory(t *testing.T) {
+	const nginxDigest = "sha256:1111"
+	pod := func(ns, name string, phase corev1.PodPhase, images ...string) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+		for i, image := range images {
+			c := corev1.Container{Name: fmt.Sprintf("c%d", i), Image: image}
+			p.Spec.Containers = append(p.Spec.Containers, c)
+			if strings.Contains(image, "nginx") {
+				p.Status.ContainerStatuses = append(p.Status.ContainerStatuses, corev1.ContainerStatus{
+					Name: c.Name, ImageID: "docker.io/library/nginx@" + nginxDigest,
+				})
+			}
+		}
+		return p
+	}
+
+	pods := []corev1.Pod{
+		// Two workloads in different namespaces share nginx
+		*pod("shop", "web-1", corev1.PodRunning, "nginx:1.27", "ghcr.io/acme/api:v2"),
+		*pod("shop", "web-2", corev1.PodRunning, "nginx:1.27", "ghcr.io/acme/api:v2"),
+		*pod("blog", "frontend-1", corev1.PodRunning, "docker.io/library/nginx:1.27"),
+		// Finished and excluded pods are not counted
+		*pod("shop", "migrate-1", corev1.PodSucceeded, "nginx:1.27"),
+		*pod("kube-system", "coredns", corev1.PodRunning, "nginx:1.27"),
+	}
+
+	got := NewK8sScanner().imageInventory(pods)
+	want := []ImageInventoryEntry{
+		{
+			Image: "docker.io/library/nginx:1.27@" + nginxDigest, Registry: "docker.io", Repository: "library/nginx",
+			Tag: "1.27", Digest: nginxDigest, Containers: 3, Namespaces: []string{"blog", "shop"},
+		},
+		{
+			Image: "ghcr.io/acme/api:v2", Registry: "ghcr.io", Repository: "acme/api",
+			Tag: "v2", Containers: 2, Namespaces: []string{"shop"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("inventory =\n%+v\nwant\n%+v", got, want)
+	}
+}
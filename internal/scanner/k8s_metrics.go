@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	nodeMetricsGVR = schema.GroupVersionResource{
+		Group:    "metrics.k8s.io",
+		Version:  "v1beta1",
+		Resource: "nodes",
+	}
+	podMetricsGVR = schema.GroupVersionResource{
+		Group:    "metrics.k8s.io",
+		Version:  "v1beta1",
+		Resource: "pods",
+	}
+)
+
+// clusterUsage is the current CPU and memory usage reported by
+// metrics-server, by node name and by "namespace/pod".
+type clusterUsage struct {
+	nodes map[string]ResourceRequirements
+	pods  map[string]ResourceRequirements
+}
+
+// scanUsage reads NodeMetrics and PodMetrics from the metrics.k8s.io API.
+// It returns nil when metrics-server is not installed or not readable.
+func scanUsage(ctx context.Context, dynClient dynamic.Interface, access *accessChecker, log *slog.Logger) *clusterUsage {
+	nodeList, err := dynClient.Resource(nodeMetricsGVR).List(ctx, metav1.ListOptions{})
+	access.recordList(resNodeMetrics, err)
+	if err != nil {
+		// metrics-server not installed — not an error
+		log.Debug("node metrics not available", "error", err)
+		return nil
+	}
+	podList, err := dynClient.Resource(podMetricsGVR).Namespace("").List(ctx, metav1.ListOptions{})
+	access.recordList(resPodMetrics, err)
+	if err != nil {
+		log.Debug("pod metrics not available", "error", err)
+		return nil
+	}
+
+	usage := &clusterUsage{
+		nodes: make(map[string]ResourceRequirements, len(nodeList.Items)),
+		pods:  make(map[string]ResourceRequirements, len(podList.Items)),
+	}
+	for _, item := range nodeList.Items {
+		usage.nodes[item.GetName()] = metricsUsage(item.Object)
+	}
+	for _, item := range podList.Items {
+		var total ResourceRequirements
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		for _, c := range containers {
+			if container, ok := c.(map[string]interface{}); ok {
+				u := metricsUsage(container)
+				total.CPUMillicores += u.CPUMillicores
+				total.MemoryBytes += u.MemoryBytes
+			}
+		}
+		usage.pods[item.GetNamespace()+"/"+item.GetName()] = total
+	}
+	return usage
+}
+
+// metricsUsage parses the usage field ({cpu: "250m", memory: "64Mi"}) of a
+// NodeMetrics object or a PodMetrics container.
+func metricsUsage(obj map[string]interface{}) ResourceRequirements {
+	list, _, _ := unstructured.NestedStringMap(obj, "usage")
+	var r ResourceRequirements
+	if q, err := resource.ParseQuantity(list["cpu"]); err == nil {
+		r.CPUMillicores = q.MilliValue()
+	}
+	if q, err := resource.ParseQuantity(list["memory"]); err == nil {
+		r.MemoryBytes = q.Value()
+	}
+	return r
+}
+
+// attachUsage sets the usage of every node and workload that metrics are
+// reported for. Pods are tied to their workload through
+// result.PodPlacements, so workloads are left without usage when pods
+// could not be listed.
+func attachUsage(result *ClusterScanResult, usage *clusterUsage) {
+	if usage == nil {
+		return
+	}
+	for i, n := range result.Nodes {
+		if u, ok := usage.nodes[n.Name]; ok {
+			result.Nodes[i].UsageCPUMillicores = &u.CPUMillicores
+			result.Nodes[i].UsageMemoryBytes = &u.MemoryBytes
+		}
+	}
+
+	workloads := make(map[string]ResourceRequirements)
+	for _, p := range result.PodPlacements {
+		u, ok := usage.pods[p.Namespace+"/"+p.Name]
+		if !ok || p.OwnerKind == "" {
+			continue
+		}
+		key := p.Namespace + "/" + p.OwnerKind + "/" + p.OwnerName
+		total := workloads[key]
+		total.CPUMillicores += u.CPUMillicores
+		total.MemoryBytes += u.MemoryBytes
+		workloads[key] = total
+	}
+	for i := range result.Namespaces {
+		ns := &result.Namespaces[i]
+		for j, w := range ns.Workloads {
+			if u, ok := workloads[ns.Name+"/"+w.Kind+"/"+w.Name]; ok {
+				ns.Workloads[j].UsageCPUMillicores = &u.CPUMillicores
+				ns.Workloads[j].UsageMemoryBytes = &u.MemoryBytes
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package scanner
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// metricsClient serves NodeMetrics and PodMetrics objects. They are added
+// through the tracker because the fake client would guess their resources
+// from the kinds, not as nodes and pods.
+func metricsClient(t *testing.T, objs ...*unstructured.Unstructured) *dynamicfake.FakeDynamicClient {
+	t.Helper()
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			nodeMetricsGVR: "NodeMetricsList",
+			podMetricsGVR:  "PodMetricsList",
+		})
+	for _, obj := range objs {
+		gvr := podMetricsGVR
+		if obj.GetKind() == "NodeMetrics" {
+			gvr = nodeMetricsGVR
+		}
+		if err := dynClient.Tracker().Create(gvr, obj, obj.GetNamespace()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dynClient
+}
+
+func TestAttachUsage(t *testing.T) {
+	nodeMetrics := func(name, cpu, memory string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "metrics.k8s.io/v1beta1",
+			"kind":       "NodeMetrics",
+			"metadata":   map[string]interface{}{"name": name},
+			"usage":      map[string]interface{}{"cpu": cpu, "memory": memory},
+		}}
+	}
+	podMetrics := func(ns, name string, usage ...[2]string) *unstructured.Unstructured {
+		var containers []interface{}
+		for i, u := range usage {
+			containers = append(containers, map[string]interface{}{
+				"name":  string(rune('a' + i)),
+				"usage": map[string]interface{}{"cpu": u[0], "memory": u[1]},
+			})
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "metrics.k8s.io/v1beta1",
+			"kind":       "PodMetrics",
+			"metadata":   map[string]interface{}{"name": name, "namespace": ns},
+			"containers": containers,
+		}}
+	}
+	dynClient := metricsClient(t,
+		nodeMetrics("node-a", "1500m", "4Gi"),
+		// Two replicas of web, one with a sidecar
+		podMetrics("shop", "web-7d9f8c6b5-x2k4p", [2]string{"100m", "128Mi"}, [2]string{"5m", "16Mi"}),
+		podMetrics("shop", "web-7d9f8c6b5-q8r2m", [2]string{"120m", "128Mi"}),
+		podMetrics("shop", "db-0", [2]string{"250m", "1Gi"}),
+		podMetrics("shop", "debug", [2]string{"1m", "8Mi"}),
+	)
+
+	result := ClusterScanResult{
+		Nodes: []NodeScanResult{{Name: "node-a"}, {Name: "node-b"}},
+		Namespaces: []NamespaceScanResult{{Name: "shop", Workloads: []WorkloadScanResult{
+			{Name: "web", Namespace: "shop", Kind: "Deployment"},
+			{Name: "db", Namespace: "shop", Kind: "StatefulSet"},
+			{Name: "queue", Namespace: "shop", Kind: "StatefulSet"},
+		}}},
+		PodPlacements: []PodPlacement{
+			{Name: "web-7d9f8c6b5-x2k4p", Namespace: "shop", OwnerKind: "Deployment", OwnerName: "web"},
+			{Name: "web-7d9f8c6b5-q8r2m", Namespace: "shop", OwnerKind: "Deployment", OwnerName: "web"},
+			{Name: "db-0", Namespace: "shop", OwnerKind: "StatefulSet", OwnerName: "db"},
+			{Name: "debug", Namespace: "shop"},
+		},
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	attachUsage(&result, scanUsage(context.Background(), dynClient, newAccessChecker(nil, log), log))
+
+	check := func(what string, cpu, memory *int64, wantCPU, wantMemory int64) {
+		t.Helper()
+		if cpu == nil || memory == nil {
+			t.Errorf("%s: no usage", what)
+			return
+		}
+		if *cpu != wantCPU || *memory != wantMemory {
+			t.Errorf("%s: usage = %dm/%d bytes, want %dm/%d bytes", what, *cpu, *memory, wantCPU, wantMemory)
+		}
+	}
+	check("node-a", result.Nodes[0].UsageCPUMillicores, result.Nodes[0].UsageMemoryBytes, 1500, 4<<30)
+	if result.Nodes[1].UsageCPUMillicores != nil {
+		t.Error("node-b has usage without metrics")
+	}
+	workloads := result.Namespaces[0].Workloads
+	check("web", workloads[0].UsageCPUMillicores, workloads[0].UsageMemoryBytes, 225, 272<<20)
+	check("db", workloads[1].UsageCPUMillicores, workloads[1].UsageMemoryBytes, 250, 1<<30)
+	if workloads[2].UsageCPUMillicores != nil || workloads[2].UsageMemoryBytes != nil {
+		t.Error("queue has usage without pod metrics")
+	}
+}
+
+func TestScanUsageWithoutMetricsServer(t *testing.T) {
+	dynClient := metricsClient(t)
+	dynClient.PrependReactor("list", "nodes", func(ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "nodes"}, "")
+	})
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	usage := scanUsage(context.Background(), dynClient, newAccessChecker(nil, log), log)
+	if usage != nil {
+		t.Fatalf("usage = %+v, want nil", usage)
+	}
+	result := ClusterScanResult{
+		Nodes:      []NodeScanResult{{Name: "node-a"}},
+		Namespaces: []NamespaceScanResult{{Name: "shop", Workloads: []WorkloadScanResult{{Name: "web", Kind: "Deployment"}}}},
+	}
+	attachUsage(&result, usage)
+	if result.Nodes[0].UsageCPUMillicores != nil || result.Namespaces[0].Workloads[0].UsageMemoryBytes != nil {
+		t.Error("usage set without metrics-server")
+	}
+}
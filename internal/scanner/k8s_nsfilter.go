@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"path"
+	"strings"
+)
+
+// skipNamespace reports whether the cluster scan leaves namespace ns out:
+// it matches an exclusion, or an include list is set and ns matches none
+// of it. Entries are exact names or path.Match globs such as "team-*"; a
+// malformed glob matches nothing.
+func (s *K8sScanner) skipNamespace(ns string) bool {
+	if s.ExcludeNamespaces[ns] {
+		return true
+	}
+	for pattern := range s.ExcludeNamespaces {
+		if isGlob(pattern) && globMatch(pattern, ns) {
+			return true
+		}
+	}
+	if len(s.IncludeNamespaces) == 0 {
+		return false
+	}
+	for _, pattern := range s.IncludeNamespaces {
+		if pattern == ns || (isGlob(pattern) && globMatch(pattern, ns)) {
+			return false
+		}
+	}
+	return true
+}
+
+// NamespaceFilter returns the predicate a cluster scan built from opts uses
+// to leave namespaces out, so the insights engine can skip the same ones.
+func NamespaceFilter(opts RegistryOptions) func(ns string) bool {
+	return newNamespacedK8sScanner(opts).skipNamespace
+}
+
+func isGlob(pattern string) bool { return strings.ContainsAny(pattern, "*?[") }
+
+func globMatch(pattern, name string) bool {
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
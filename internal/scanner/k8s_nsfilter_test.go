@@ -0,0 +1,96 @@
+package scanner
+
+import "testing"
+
+func TestSkipNamespace(t *testing.T) {
+	tests := []struct {
+		name    string
+		exclude []string
+		include []string
+		skipped []string
+		scanned []string
+	}{
+		{
+			name:    "no filters",
+			scanned: []string{"default", "kube-system", "team-a"},
+		},
+		{
+			name:    "denylist only",
+			exclude: []string{"kube-system", "monitoring"},
+			skipped: []string{"kube-system", "monitoring"},
+			scanned: []string{"default", "team-a"},
+		},
+		{
+			name:    "allowlist only",
+			include: []string{"team-a", "default"},
+			skipped: []string{"kube-system", "team-b"},
+			scanned: []string{"team-a", "default"},
+		},
+		{
+			name:    "globs",
+			exclude: []string{"*-system", "team-b-?"},
+			include: []string{"team-*", "kube-*"},
+			skipped: []string{"kube-system", "team-b-1", "default", "cert-manager-system"},
+			scanned: []string{"team-a", "team-b", "team-b-10", "kube-public"},
+		},
+		{
+			name:    "malformed glob matches nothing",
+			exclude: []string{"team-["},
+			scanned: []string{"team-a", "team-b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewK8sScannerWithExclusions(tt.exclude)
+			s.IncludeNamespaces = tt.include
+			for _, ns := range tt.skipped {
+				if !s.skipNamespace(ns) {
+					t.Errorf("%s should be skipped", ns)
+				}
+			}
+			for _, ns := range tt.scanned {
+				if s.skipNamespace(ns) {
+					t.Errorf("%s should be scanned", ns)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistrySkipNamespacesAddToDefaults(t *testing.T) {
+	reg := NewRegistryWithOptions(RegistryOptions{SkipNamespaces: []string{"ci-*"}})
+	var k8s *K8sScanner
+	for _, s := range reg.ForProfile(ProfileFull) {
+		if ks, ok := s.(*K8sScanner); ok {
+			k8s = ks
+		}
+	}
+	if k8s == nil {
+		t.Fatal("no cluster scanner in the full profile")
+	}
+	for _, ns := range []string{"kube-system", "ci-runner-42"} {
+		if !k8s.skipNamespace(ns) {
+			t.Errorf("%s should be skipped", ns)
+		}
+	}
+	if k8s.skipNamespace("default") {
+		t.Error("default should be scanned")
+	}
+}
+
+func TestNamespaceFilterMatchesRegistry(t *testing.T) {
+	skip := NamespaceFilter(RegistryOptions{
+		SkipNamespaces:    []string{"ci-*"},
+		IncludeNamespaces: []string{"team-*", "ci-*", "kube-system"},
+	})
+	for ns, want := range map[string]bool{
+		"kube-system":  true, // default exclusion wins over the include list
+		"ci-runner-42": true,
+		"team-a":       false,
+		"default":      true, // not included
+	} {
+		if got := skip(ns); got != want {
+			t.Errorf("skip(%q) = %v, want %v", ns, got, want)
+		}
+	}
+}
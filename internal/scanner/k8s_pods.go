@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/podutil"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// PodPlacementWindow is how long finished (Succeeded or Failed) pods
+	// stay in the placement map after their last container exits.
+	PodPlacementWindow = time.Hour
+	// MaxPodPlacements caps the placement map so huge clusters don't blow
+	// up the payload.
+	MaxPodPlacements = 5000
+)
+
+// podPlacements maps scheduled pods to their node and owning workload.
+// Only running or pending pods, and pods that finished within
+// PodPlacementWindow, are included, from namespaces that are not excluded.
+func (s *K8sScanner) podPlacements(pods []corev1.Pod, now time.Time, log *slog.Logger) []PodPlacement {
+	var placements []PodPlacement
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || s.skipNamespace(pod.Namespace) || !recentPod(pod, now) {
+			continue
+		}
+		kind, name := podutil.Owner(pod, nil)
+		placements = append(placements, PodPlacement{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Node:      pod.Spec.NodeName,
+			OwnerKind: kind,
+			OwnerName: name,
+			Phase:     string(pod.Status.Phase),
+			Drainable: podutil.IsDrainablePod(pod),
+		})
+	}
+
+	sort.Slice(placements, func(i, j int) bool {
+		if placements[i].Namespace != placements[j].Namespace {
+			return placements[i].Namespace < placements[j].Namespace
+		}
+		return placements[i].Name < placements[j].Name
+	})
+	if len(placements) > MaxPodPlacements {
+		log.Warn("too many pods, truncating pod placements", "pods", len(placements), "max", MaxPodPlacements)
+		placements = placements[:MaxPodPlacements]
+	}
+	return placements
+}
+
+// recentPod reports whether pod is still running or pending, or finished
+// within PodPlacementWindow. A finished pod's end is its last container
+// exit, or its creation time when no container ever ran.
+func recentPod(pod corev1.Pod, now time.Time) bool {
+	if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+		return true
+	}
+	finished := pod.CreationTimestamp.Time
+	for _, cs := range pod.Status.ContainerStatuses {
+		if t := cs.State.Terminated; t != nil && t.FinishedAt.After(finished) {
+			finished = t.FinishedAt.Time
+		}
+	}
+	return now.Sub(finished) <= PodPlacementWindow
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodPlacements(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	isController := true
+	owned := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &isController}}
+	}
+	pod := func(ns, name, node string, phase corev1.PodPhase, owners []metav1.OwnerReference, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: ns, Labels: labels, OwnerReferences: owners,
+				CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour)),
+			},
+			Spec:   corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	finished := func(p *corev1.Pod, at time.Time) *corev1.Pod {
+		p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(at)}},
+		}}
+		return p
+	}
+
+	pods := []corev1.Pod{
+		*pod("shop", "web-7d9f8c6b5-x2k4p", "node-a", corev1.PodRunning,
+			owned("ReplicaSet", "web-7d9f8c6b5"), map[string]string{"pod-template-hash": "7d9f8c6b5"}),
+		*pod("shop", "db-0", "node-b", corev1.PodRunning, owned("StatefulSet", "db"), nil),
+		*pod("monitoring", "node-exporter-abcde", "node-b", corev1.PodRunning, owned("DaemonSet", "node-exporter"), nil),
+		*finished(pod("shop", "migrate-9zq7w", "node-a", corev1.PodSucceeded, owned("Job", "migrate"), nil), now.Add(-10*time.Minute)),
+		// Finished long ago, unscheduled, and excluded: left out
+		*finished(pod("shop", "migrate-old", "node-a", corev1.PodSucceeded, owned("Job", "migrate-old"), nil), now.Add(-3*time.Hour)),
+		*pod("shop", "pending-unscheduled", "", corev1.PodPending, nil, nil),
+		*pod("kube-system", "coredns-abc", "node-a", corev1.PodRunning, owned("ReplicaSet", "coredns-5d78c9869d"), nil),
+	}
+
+	got := NewK8sScanner().podPlacements(pods, now, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	want := []PodPlacement{
+		{Name: "node-exporter-abcde", Namespace: "monitoring", Node: "node-b", OwnerKind: "DaemonSet", OwnerName: "node-exporter", Phase: "Running"},
+		{Name: "db-0", Namespace: "shop", Node: "node-b", OwnerKind: "StatefulSet", OwnerName: "db", Phase: "Running", Drainable: true},
+		{Name: "migrate-9zq7w", Namespace: "shop", Node: "node-a", OwnerKind: "Job", OwnerName: "migrate", Phase: "Succeeded", Drainable: true},
+		{Name: "web-7d9f8c6b5-x2k4p", Namespace: "shop", Node: "node-a", OwnerKind: "Deployment", OwnerName: "web", Phase: "Running", Drainable: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("placements =\n%+v\nwant\n%+v", got, want)
+	}
+}
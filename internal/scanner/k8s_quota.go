@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// scanResourceQuotas lists a namespace's ResourceQuotas with their hard
+// limits and current usage. A namespace without quotas yields an empty,
+// non-nil slice so "no quota" is told apart from "not scanned".
+func scanResourceQuotas(ctx context.Context, clientset kubernetes.Interface, ns string) ([]ResourceQuotaScanResult, error) {
+	quotaList, err := clientset.CoreV1().ResourceQuotas(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := []ResourceQuotaScanResult{}
+	for _, q := range quotaList.Items {
+		r := ResourceQuotaScanResult{
+			Name:      q.Name,
+			Namespace: q.Namespace,
+			Hard:      resourceListToMap(q.Spec.Hard),
+			Used:      resourceListToMap(q.Status.Used),
+		}
+		for _, scope := range q.Spec.Scopes {
+			r.Scopes = append(r.Scopes, string(scope))
+		}
+		quotas = append(quotas, r)
+	}
+	return quotas, nil
+}
+
+// scanLimitRanges lists a namespace's LimitRanges. Like quotas, a namespace
+// without any yields an empty slice.
+func scanLimitRanges(ctx context.Context, clientset kubernetes.Interface, ns string) ([]LimitRangeScanResult, error) {
+	lrList, err := clientset.CoreV1().LimitRanges(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := []LimitRangeScanResult{}
+	for _, lr := range lrList.Items {
+		r := LimitRangeScanResult{Name: lr.Name, Namespace: lr.Namespace}
+		for _, item := range lr.Spec.Limits {
+			r.Limits = append(r.Limits, LimitRangeItem{
+				Type:                 string(item.Type),
+				Max:                  resourceListToMap(item.Max),
+				Min:                  resourceListToMap(item.Min),
+				Default:              resourceListToMap(item.Default),
+				DefaultRequest:       resourceListToMap(item.DefaultRequest),
+				MaxLimitRequestRatio: resourceListToMap(item.MaxLimitRequestRatio),
+			})
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// resourceListToMap renders quantities in their canonical form, e.g.
+// {"requests.cpu": "4", "limits.memory": "8Gi"}. It returns nil for an
+// empty list.
+func resourceListToMap(rl corev1.ResourceList) map[string]string {
+	if len(rl) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(rl))
+	for name, q := range rl {
+		m[string(name)] = q.String()
+	}
+	return m
+}
@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScanResourceQuotas(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "shop"},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:  resource.MustParse("4"),
+				corev1.ResourceLimitsMemory: resource.MustParse("8Gi"),
+				corev1.ResourcePods:         resource.MustParse("20"),
+			},
+		},
+		Status: corev1.ResourceQuotaStatus{
+			Used: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:  resource.MustParse("1500m"),
+				corev1.ResourceLimitsMemory: resource.MustParse("3Gi"),
+				corev1.ResourcePods:         resource.MustParse("7"),
+			},
+		},
+	})
+
+	got, err := scanResourceQuotas(context.Background(), clientset, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ResourceQuotaScanResult{{
+		Name:      "compute",
+		Namespace: "shop",
+		Hard:      map[string]string{"requests.cpu": "4", "limits.memory": "8Gi", "pods": "20"},
+		Used:      map[string]string{"requests.cpu": "1500m", "limits.memory": "3Gi", "pods": "7"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("quotas =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// A namespace without quotas or limit ranges reports empty lists
+	quotas, err := scanResourceQuotas(context.Background(), clientset, "empty")
+	if err != nil || quotas == nil || len(quotas) != 0 {
+		t.Errorf("quotas in empty namespace = %#v, %v; want an empty slice", quotas, err)
+	}
+	ranges, err := scanLimitRanges(context.Background(), clientset, "empty")
+	if err != nil || ranges == nil || len(ranges) != 0 {
+		t.Errorf("limit ranges in empty namespace = %#v, %v; want an empty slice", ranges, err)
+	}
+}
+
+func TestScanLimitRanges(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "shop"},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				Max:            corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+				Default:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			}},
+		},
+	})
+
+	got, err := scanLimitRanges(context.Background(), clientset, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LimitRangeScanResult{{
+		Name:      "defaults",
+		Namespace: "shop",
+		Limits: []LimitRangeItem{{
+			Type:           "Container",
+			Max:            map[string]string{"memory": "2Gi"},
+			Default:        map[string]string{"cpu": "500m"},
+			DefaultRequest: map[string]string{"cpu": "100m"},
+		}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("limit ranges =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -1,10 +1,22 @@
 package scanner
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
 	"testing"
 
+	appsv1 "k8s.io/api/apps/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
 )
 
 func TestClusterScanResultJSONShape(t *testing.T) {
@@ -22,12 +34,16 @@ func TestClusterScanResultJSONShape(t *testing.T) {
 		Version:  "v1.34.3+k3s3",
 		Nodes: []NodeScanResult{
 			{
-				Name:    "node-1",
-				Status:  "Ready",
-				Roles:   []string{"control-plane", "etcd"},
-				Version: "v1.34.3+k3s3",
-				OS:      "linux",
-				OSImage: "Ubuntu 24.04.3 LTS",
+				Name:        "node-1",
+				Status:      "Ready",
+				Roles:       []string{"control-plane", "etcd"},
+				Version:     "v1.34.3+k3s3",
+				OS:          "linux",
+				OSImage:     "Ubuntu 24.04.3 LTS",
+				Taints:      []string{"node-role.kubernetes.io/control-plane:NoSchedule"},
+				Capacity:    map[string]string{"cpu": "4", "memory": "16318480Ki", "pods": "110"},
+				Allocatable: map[string]string{"cpu": "4", "memory": "16216080Ki", "pods": "110"},
+				Labels:      map[string]string{"kubernetes.io/hostname": "node-1"},
 			},
 		},
 		Namespaces: []NamespaceScanResult{
@@ -108,6 +124,13 @@ func TestClusterScanResultJSONShape(t *testing.T) {
 				PDBs: []PDBScanResult{
 					{Name: "nginx-pdb", Namespace: "default", MinAvailable: &minAvail, Selector: map[string]interface{}{"matchLabels": map[string]string{"app": "nginx"}}},
 				},
+				HPAs: []HPAScanResult{
+					{
+						Name: "nginx", Namespace: "default", TargetKind: "Deployment", TargetName: "nginx",
+						MinReplicas: 2, MaxReplicas: 10, CurrentReplicas: 3, DesiredReplicas: 3,
+						Metrics: []HPAMetric{{Type: "Resource", Name: "cpu", Target: "80%"}},
+					},
+				},
 			},
 		},
 		FluxDetected: true,
@@ -121,6 +144,13 @@ func TestClusterScanResultJSONShape(t *testing.T) {
 				Prune:           true,
 			},
 		},
+		PersistentVolumes: []PVScanResult{
+			{Name: "pvc-4f1c", Capacity: "10Gi", ReclaimPolicy: "Retain", Status: "Released", StorageClass: "longhorn", ClaimRef: "default/data"},
+		},
+		StorageClasses: []StorageClassScanResult{
+			{Name: "local-path", Provisioner: "rancher.io/local-path", ReclaimPolicy: "Delete", VolumeBindingMode: "WaitForFirstConsumer", IsDefault: true},
+			{Name: "longhorn", Provisioner: "driver.longhorn.io", ReclaimPolicy: "Retain", VolumeBindingMode: "Immediate"},
+		},
 	}
 
 	data, err := json.Marshal(result)
@@ -135,7 +165,7 @@ func TestClusterScanResultJSONShape(t *testing.T) {
 	}
 
 	// Top-level keys
-	for _, key := range []string{"name", "provider", "version", "nodes", "namespaces", "fluxDetected", "fluxKustomizations"} {
+	for _, key := range []string{"name", "provider", "version", "nodes", "namespaces", "fluxDetected", "fluxKustomizations", "persistentVolumes"} {
 		if _, ok := m[key]; !ok {
 			t.Errorf("missing top-level key %q", key)
 		}
@@ -144,7 +174,7 @@ func TestClusterScanResultJSONShape(t *testing.T) {
 	// Node shape
 	nodes := m["nodes"].([]interface{})
 	node := nodes[0].(map[string]interface{})
-	for _, key := range []string{"name", "status", "roles", "version", "os", "os_image"} {
+	for _, key := range []string{"name", "status", "roles", "version", "os", "os_image", "taints", "capacity", "allocatable", "labels"} {
 		if _, ok := node[key]; !ok {
 			t.Errorf("node missing key %q", key)
 		}
@@ -153,7 +183,7 @@ func TestClusterScanResultJSONShape(t *testing.T) {
 	// Namespace shape
 	namespaces := m["namespaces"].([]interface{})
 	ns := namespaces[0].(map[string]interface{})
-	for _, key := range []string{"name", "labels", "workloads", "services", "ingresses", "configMaps", "secrets", "pvcs", "cronJobs", "networkPolicies", "pdbs"} {
+	for _, key := range []string{"name", "labels", "workloads", "services", "ingresses", "configMaps", "secrets", "pvcs", "cronJobs", "networkPolicies", "pdbs", "hpas", "resourceQuotas", "limitRanges"} {
 		if _, ok := ns[key]; !ok {
 			t.Errorf("namespace missing key %q", key)
 		}
@@ -220,6 +250,44 @@ func TestClusterScanResultJSONShape(t *testing.T) {
 		}
 	}
 
+	// HPA shape
+	hpas := ns["hpas"].([]interface{})
+	hpa := hpas[0].(map[string]interface{})
+	for _, key := range []string{"name", "namespace", "targetKind", "targetName", "minReplicas", "maxReplicas", "currentReplicas", "desiredReplicas", "metrics"} {
+		if _, ok := hpa[key]; !ok {
+			t.Errorf("hpa missing key %q", key)
+		}
+	}
+	metric := hpa["metrics"].([]interface{})[0].(map[string]interface{})
+	for _, key := range []string{"type", "name", "target"} {
+		if _, ok := metric[key]; !ok {
+			t.Errorf("hpa metric missing key %q", key)
+		}
+	}
+
+	// PersistentVolume shape
+	pvs := m["persistentVolumes"].([]interface{})
+	pv := pvs[0].(map[string]interface{})
+	for _, key := range []string{"name", "capacity", "reclaimPolicy", "status", "storageClass", "claimRef"} {
+		if _, ok := pv[key]; !ok {
+			t.Errorf("persistent volume missing key %q", key)
+		}
+	}
+
+	// StorageClass shape; isDefault is present for non-default classes too
+	classes := m["storageClasses"].([]interface{})
+	for i, c := range classes {
+		sc := c.(map[string]interface{})
+		for _, key := range []string{"name", "provisioner", "reclaimPolicy", "volumeBindingMode", "isDefault"} {
+			if _, ok := sc[key]; !ok {
+				t.Errorf("storage class missing key %q", key)
+			}
+		}
+		if want := i == 0; sc["isDefault"] != want {
+			t.Errorf("storage class %v: isDefault = %v, want %v", sc["name"], sc["isDefault"], want)
+		}
+	}
+
 	// Flux kustomization shape
 	fluxKs := m["fluxKustomizations"].([]interface{})
 	fk := fluxKs[0].(map[string]interface{})
@@ -230,6 +298,66 @@ func TestClusterScanResultJSONShape(t *testing.T) {
 	}
 }
 
+func TestWorkloadStrategyParamsJSON(t *testing.T) {
+	surge := intstr.FromString("25%")
+	unavailable := intstr.FromInt32(0)
+	d := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxSurge:       &surge,
+					MaxUnavailable: &unavailable,
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(deploymentToWorkload(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	json.Unmarshal(data, &m)
+
+	if m["strategy"] != "RollingUpdate" {
+		t.Errorf("strategy = %v", m["strategy"])
+	}
+	params, ok := m["strategyParams"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("missing strategyParams in %s", data)
+	}
+	if params["maxSurge"] != "25%" || params["maxUnavailable"] != "0" {
+		t.Errorf("strategyParams = %v", params)
+	}
+	if _, ok := params["partition"]; ok {
+		t.Error("partition should be omitted for Deployments")
+	}
+
+	// StatefulSet partitioned rollout
+	partition := int32(2)
+	w := statefulSetToWorkload(appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+			},
+		},
+	})
+	if w.Strategy != "RollingUpdate" || w.StrategyParams == nil || *w.StrategyParams.Partition != 2 {
+		t.Errorf("statefulset strategy = %q %+v", w.Strategy, w.StrategyParams)
+	}
+
+	// Recreate has no rolling parameters
+	w = deploymentToWorkload(appsv1.Deployment{Spec: appsv1.DeploymentSpec{
+		Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+	}})
+	if w.StrategyParams != nil {
+		t.Errorf("recreate params = %+v, want nil", w.StrategyParams)
+	}
+}
+
 func TestExtractRoles(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -256,20 +384,100 @@ func TestExtractRoles(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			roles := extractRoles(tt.labels)
-			if len(roles) == 0 {
-				t.Fatal("expected at least one role")
+			if !slices.Equal(roles, tt.expected) {
+				t.Errorf("roles = %v, want %v", roles, tt.expected)
+			}
+		})
+	}
+}
+
+// fullRBAC lets the agent list everything.
+func fullRBAC(clientset *fake.Clientset) {
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectRulesReview)
+		review.Status.ResourceRules = []authv1.ResourceRule{
+			{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+		}
+		return true, review, nil
+	})
+}
+
+func TestScanClusterStableOrder(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	objs := []runtime.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{
+			"node-role.kubernetes.io/etcd":          "",
+			"node-role.kubernetes.io/control-plane": "",
+			"node-role.kubernetes.io/master":        "",
+		}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+	}
+	for _, ns := range []string{"zeta", "alpha", "mid"} {
+		objs = append(objs, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+		for _, name := range []string{"web", "api", "db"} {
+			objs = append(objs,
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+					Data:       map[string]string{"z.conf": "", "a.conf": "", "m.conf": "", "b.conf": ""},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+					Data:       map[string][]byte{"tls.key": nil, "ca.crt": nil, "tls.crt": nil},
+				},
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}},
+				&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}},
+			)
+		}
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	scanner := NewK8sScannerWithExclusions(nil)
+
+	var first []byte
+	for i := 0; i < 5; i++ {
+		clientset := fake.NewSimpleClientset(objs...)
+		fullRBAC(clientset)
+		result, err := scanner.scanCluster(context.Background(), clientset, newAccessChecker(clientset, log), log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			first = data
+			if len(result.Nodes) != 2 || len(result.Namespaces) != 3 {
+				t.Fatalf("scanned %d nodes and %d namespaces, want 2 and 3", len(result.Nodes), len(result.Namespaces))
 			}
-			// Check all expected roles are present (order may vary due to map iteration)
-			roleSet := make(map[string]bool)
-			for _, r := range roles {
-				roleSet[r] = true
+			if result.Nodes[0].Name != "node-a" || !slices.Equal(result.Nodes[1].Roles, []string{"control-plane", "etcd", "master"}) {
+				t.Errorf("nodes not sorted: %+v", result.Nodes)
 			}
-			for _, exp := range tt.expected {
-				if !roleSet[exp] {
-					t.Errorf("expected role %q not found in %v", exp, roles)
-				}
+			if result.Namespaces[0].Name != "alpha" || result.Namespaces[2].Name != "zeta" {
+				t.Errorf("namespaces not sorted: %s, %s", result.Namespaces[0].Name, result.Namespaces[2].Name)
 			}
-		})
+			ns := result.Namespaces[0]
+			if len(ns.Workloads) != 3 || len(ns.Services) != 3 || len(ns.ConfigMaps) != 3 || len(ns.Secrets) != 3 {
+				t.Fatalf("namespace %s resources missing: %+v", ns.Name, ns)
+			}
+			if ns.Workloads[0].Name != "api" || ns.Services[0].Name != "api" || ns.ConfigMaps[2].Name != "web" {
+				t.Errorf("namespace resources not sorted: %+v", ns)
+			}
+			if !slices.Equal(ns.ConfigMaps[0].DataKeys, []string{"a.conf", "b.conf", "m.conf", "z.conf"}) {
+				t.Errorf("configmap keys = %v", ns.ConfigMaps[0].DataKeys)
+			}
+			if !slices.Equal(ns.Secrets[0].DataKeys, []string{"ca.crt", "tls.crt", "tls.key"}) {
+				t.Errorf("secret keys = %v", ns.Secrets[0].DataKeys)
+			}
+			continue
+		}
+		if string(data) != string(first) {
+			t.Fatalf("scan %d differs from the first:\n%s\n%s", i, data, first)
+		}
 	}
 }
 
@@ -325,9 +533,94 @@ func TestOmitemptyBehavior(t *testing.T) {
 	json.Unmarshal(wData, &wm)
 
 	// replicas, readyReplicas, strategy should be omitted
-	for _, key := range []string{"replicas", "readyReplicas", "availableReplicas", "strategy", "requests", "limits"} {
+	for _, key := range []string{"replicas", "readyReplicas", "availableReplicas", "strategy", "strategyParams", "requests", "limits"} {
 		if _, ok := wm[key]; ok {
 			t.Errorf("optional field %q should be omitted when zero/nil", key)
 		}
 	}
 }
+
+func TestLabeledClusterName(t *testing.T) {
+	label := DefaultClusterNameLabel
+	kubeSystem := func(labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", Labels: labels}}
+	}
+	node := func(name string, labels map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	tests := []struct {
+		name      string
+		label     string
+		listNodes bool
+		objects   []runtime.Object
+		want      string
+	}{
+		{
+			name:      "namespace label wins over nodes",
+			label:     label,
+			listNodes: true,
+			objects: []runtime.Object{
+				kubeSystem(map[string]string{label: "acme-prod"}),
+				node("node-1", map[string]string{label: "other"}),
+			},
+			want: "acme-prod",
+		},
+		{
+			name:      "falls back to labeled node",
+			label:     label,
+			listNodes: true,
+			objects: []runtime.Object{
+				kubeSystem(nil),
+				node("node-1", nil),
+				node("node-2", map[string]string{label: "acme-staging"}),
+			},
+			want: "acme-staging",
+		},
+		{
+			name:      "nodes not consulted without list access",
+			label:     label,
+			listNodes: false,
+			objects:   []runtime.Object{node("node-1", map[string]string{label: "acme-staging"})},
+			want:      "",
+		},
+		{
+			name:      "custom label key",
+			label:     "example.com/cluster",
+			listNodes: true,
+			objects:   []runtime.Object{kubeSystem(map[string]string{"example.com/cluster": "edge-7", label: "ignored"})},
+			want:      "edge-7",
+		},
+		{
+			name:      "label disabled",
+			label:     "",
+			listNodes: true,
+			objects:   []runtime.Object{kubeSystem(map[string]string{label: "acme-prod"})},
+			want:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.objects...)
+			got := labeledClusterName(context.Background(), clientset, tt.label, tt.listNodes)
+			if got != tt.want {
+				t.Errorf("labeledClusterName = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanClusterNameFromLabel(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "kube-system",
+		Labels: map[string]string{DefaultClusterNameLabel: "acme-prod"},
+	}})
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	result, err := NewK8sScanner().scanCluster(context.Background(), clientset, newAccessChecker(clientset, log), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "acme-prod" {
+		t.Errorf("cluster name = %q, want acme-prod", result.Name)
+	}
+}
@@ -0,0 +1,159 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultNamespaceConcurrency is how many namespaces the cluster scanner
+// lists at once unless configured otherwise.
+const DefaultNamespaceConcurrency = 8
+
+// DefaultK8sQPS and DefaultK8sBurst are the cluster scanner's client-side
+// rate limit unless configured otherwise. client-go's own 5 QPS would
+// serialize the lists of DefaultNamespaceConcurrency namespaces; the server
+// still pushes back with 429s, which scanNamespaces handles.
+const (
+	DefaultK8sQPS   = 50
+	DefaultK8sBurst = 100
+)
+
+const (
+	// throttleStrikes consecutive throttled namespace scans halve the
+	// namespace concurrency.
+	throttleStrikes = 2
+	// throttleRetries is how many times a throttled namespace is rescanned
+	// before its partial result is kept.
+	throttleRetries = 3
+)
+
+// throttleBackoff is the wait before rescanning a throttled namespace; it
+// doubles on each retry. Tests shorten it.
+var throttleBackoff = time.Second
+
+// scanNamespaces scans namespaces in parallel and returns their results
+// in the order given. A namespace whose lists were throttled (HTTP 429) is
+// rescanned after a backoff, and repeated throttling halves the number of
+// namespaces scanned at once, down to one, for the rest of the scan. A
+// rate-limited API server then sees fewer requests rather than a burst of
+// retries.
+func (s *K8sScanner) scanNamespaces(ctx context.Context, clientset kubernetes.Interface, access *accessChecker, namespaces []corev1.Namespace, log *slog.Logger) []NamespaceScanResult {
+	workers := s.NamespaceConcurrency
+	if workers <= 0 {
+		workers = DefaultNamespaceConcurrency
+	}
+	lim := newAdaptiveLimiter(workers)
+
+	results := make([]NamespaceScanResult, len(namespaces))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(namespaces)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = scanNamespaceWithBackoff(ctx, clientset, access, namespaces[i], lim, log)
+			}
+		}()
+	}
+	for i := range namespaces {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// scanNamespaceWithBackoff scans one namespace, rescanning it while the API
+// server throttles. Other list errors are not retried; the partial result
+// is kept as before.
+func scanNamespaceWithBackoff(ctx context.Context, clientset kubernetes.Interface, access *accessChecker, ns corev1.Namespace, lim *adaptiveLimiter, log *slog.Logger) NamespaceScanResult {
+	backoff := throttleBackoff
+	for attempt := 1; ; attempt++ {
+		lim.acquire()
+		result, err := scanNamespace(ctx, clientset, access, ns)
+		lim.release()
+
+		if !apierrors.IsTooManyRequests(err) {
+			if err != nil {
+				log.Warn("some namespace resources could not be listed", "namespace", ns.Name, "error", err)
+			}
+			lim.succeeded()
+			return result
+		}
+		if n := lim.throttled(); n > 0 {
+			log.Warn("API server is throttling requests, reducing namespace scan concurrency", "concurrency", n)
+		}
+		if attempt > throttleRetries {
+			log.Warn("namespace scan still throttled, keeping partial result", "namespace", ns.Name, "attempts", attempt)
+			return result
+		}
+		log.Info("namespace scan throttled, retrying", "namespace", ns.Name, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// adaptiveLimiter bounds how many namespace scans run at once, halving the
+// bound after throttleStrikes throttled scans in a row.
+type adaptiveLimiter struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	limit   int
+	active  int
+	strikes int
+}
+
+func newAdaptiveLimiter(limit int) *adaptiveLimiter {
+	l := &adaptiveLimiter{limit: limit}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire blocks until a scan may start.
+func (l *adaptiveLimiter) acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+}
+
+func (l *adaptiveLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.cond.Broadcast()
+}
+
+// throttled records a throttled scan. It returns the new limit when this
+// scan lowered it, or 0.
+func (l *adaptiveLimiter) throttled() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.strikes++
+	if l.strikes < throttleStrikes || l.limit == 1 {
+		return 0
+	}
+	l.strikes = 0
+	l.limit = max(l.limit/2, 1)
+	return l.limit
+}
+
+// succeeded records a scan that was not throttled.
+func (l *adaptiveLimiter) succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.strikes = 0
+}
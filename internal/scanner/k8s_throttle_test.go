@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestScanClusterBacksOffWhenThrottled(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	defer func(d time.Duration) { throttleBackoff = d }(throttleBackoff)
+	throttleBackoff = time.Millisecond
+
+	var objs []runtime.Object
+	namespaces := []string{"ns-a", "ns-b", "ns-c", "ns-d", "ns-e", "ns-f"}
+	for _, ns := range namespaces {
+		objs = append(objs,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns}},
+		)
+	}
+	clientset := fake.NewSimpleClientset(objs...)
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectRulesReview)
+		review.Status.ResourceRules = []authv1.ResourceRule{
+			{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+		}
+		return true, review, nil
+	})
+	// Each namespace's first two service lists are throttled
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	clientset.PrependReactor("list", "services", func(action ktesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[action.GetNamespace()]++
+		if attempts[action.GetNamespace()] <= 2 {
+			return true, nil, apierrors.NewTooManyRequests("slow down", 1)
+		}
+		return false, nil, nil
+	})
+
+	var logs bytes.Buffer
+	s := NewK8sScannerWithExclusions(nil)
+	s.NamespaceConcurrency = 4
+	log := slog.New(slog.NewTextHandler(&logs, nil))
+	result, err := s.scanCluster(context.Background(), clientset, newAccessChecker(clientset, log), log)
+	if err != nil {
+		t.Fatalf("scanCluster: %v", err)
+	}
+
+	if len(result.Namespaces) != len(namespaces) {
+		t.Fatalf("expected %d namespaces, got %d", len(namespaces), len(result.Namespaces))
+	}
+	for i, ns := range result.Namespaces {
+		if ns.Name != namespaces[i] {
+			t.Errorf("namespace %d = %s, want %s", i, ns.Name, namespaces[i])
+		}
+		if len(ns.Services) != 1 {
+			t.Errorf("%s: services = %v, want the service listed after retrying", ns.Name, ns.Services)
+		}
+	}
+	for _, ns := range namespaces {
+		if attempts[ns] != 3 {
+			t.Errorf("%s: service lists = %d, want 3", ns, attempts[ns])
+		}
+	}
+	if !strings.Contains(logs.String(), "reducing namespace scan concurrency") {
+		t.Errorf("expected throttling to be logged, got:\n%s", logs.String())
+	}
+}
+
+func TestScanClusterManyNamespacesInOrder(t *testing.T) {
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+	// Created in reverse so the API's order is not already the sorted one
+	var objs []runtime.Object
+	var want []string
+	for i := 60; i > 0; i-- {
+		ns := fmt.Sprintf("ns-%02d", i)
+		want = append([]string{ns}, want...)
+		objs = append(objs,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns}},
+		)
+	}
+	clientset := fake.NewSimpleClientset(objs...)
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectRulesReview)
+		review.Status.ResourceRules = []authv1.ResourceRule{
+			{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+		}
+		return true, review, nil
+	})
+	// Uneven list latency so namespaces finish out of order
+	clientset.PrependReactor("list", "services", func(action ktesting.Action) (bool, runtime.Object, error) {
+		ns := action.GetNamespace()
+		time.Sleep(time.Duration(ns[len(ns)-1]-'0') * time.Millisecond)
+		return false, nil, nil
+	})
+
+	s := NewK8sScannerWithExclusions(nil)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	first, err := s.scanCluster(context.Background(), clientset, newAccessChecker(clientset, log), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.scanCluster(context.Background(), clientset, newAccessChecker(clientset, log), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(first.Namespaces) != len(want) {
+		t.Fatalf("expected %d namespaces, got %d", len(want), len(first.Namespaces))
+	}
+	for i, ns := range first.Namespaces {
+		if ns.Name != want[i] {
+			t.Errorf("namespace %d = %s, want %s", i, ns.Name, want[i])
+		}
+		if len(ns.Services) != 1 {
+			t.Errorf("%s: services = %v, want 1", ns.Name, ns.Services)
+		}
+	}
+	if !reflect.DeepEqual(first.Namespaces, second.Namespaces) {
+		t.Error("repeated scans of an unchanged cluster differ")
+	}
+}
+
+func TestAdaptiveLimiterHalvesOnRepeatedThrottling(t *testing.T) {
+	lim := newAdaptiveLimiter(8)
+
+	if n := lim.throttled(); n != 0 {
+		t.Errorf("first throttle lowered limit to %d", n)
+	}
+	lim.succeeded()
+	if n := lim.throttled(); n != 0 {
+		t.Errorf("throttle after a success lowered limit to %d", n)
+	}
+	if n := lim.throttled(); n != 4 {
+		t.Errorf("second throttle in a row: limit = %d, want 4", n)
+	}
+	for range 2 * throttleStrikes {
+		lim.throttled()
+	}
+	if lim.limit != 1 {
+		t.Errorf("limit = %d, want 1", lim.limit)
+	}
+	for range throttleStrikes {
+		if n := lim.throttled(); n != 0 {
+			t.Errorf("limit lowered below 1: %d", n)
+		}
+	}
+}
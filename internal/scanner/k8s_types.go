@@ -1,5 +1,7 @@
 package scanner
 
+import "time"
+
 // ClusterScanResult matches the edge-ingest ClusterScanResult interface.
 type ClusterScanResult struct {
 	Name               string                      `json:"name,omitempty"`
@@ -9,6 +11,64 @@ type ClusterScanResult struct {
 	Namespaces         []NamespaceScanResult        `json:"namespaces"`
 	FluxDetected       bool                         `json:"fluxDetected,omitempty"`
 	FluxKustomizations []FluxKustomizationResult    `json:"fluxKustomizations,omitempty"`
+	Access             *AccessSummary               `json:"access,omitempty"`
+	// Pods and the nodes they are scheduled on; see K8sScanner.podPlacements
+	PodPlacements      []PodPlacement               `json:"podPlacements,omitempty"`
+	// Cluster-scoped PersistentVolumes, including Released ones no claim
+	// uses any more
+	PersistentVolumes []PVScanResult `json:"persistentVolumes,omitempty"`
+	// StorageClasses, with the cluster default flagged
+	StorageClasses []StorageClassScanResult `json:"storageClasses,omitempty"`
+	// Installed CustomResourceDefinitions, built-in API groups excluded
+	CRDs []CRDScanResult `json:"crds,omitempty"`
+	// Latest revision of each Helm 3 release
+	HelmReleases []HelmReleaseScanResult `json:"helmReleases,omitempty"`
+	// Distinct running images; only with K8sScanner.ImageInventory
+	Images []ImageInventoryEntry `json:"images,omitempty"`
+}
+
+// ImageInventoryEntry is one distinct image running in the cluster.
+type ImageInventoryEntry struct {
+	Image      string   `json:"image"` // registry/repository:tag@digest
+	Registry   string   `json:"registry"`
+	Repository string   `json:"repository"`
+	Tag        string   `json:"tag,omitempty"`
+	Digest     string   `json:"digest,omitempty"` // empty when no container reported one
+	Containers int      `json:"containers"`       // running containers using the image
+	Namespaces []string `json:"namespaces"`
+}
+
+// HelmReleaseScanResult describes a Helm release at its latest revision.
+type HelmReleaseScanResult struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Chart      string `json:"chart"`
+	Version    string `json:"version"` // chart version
+	AppVersion string `json:"appVersion,omitempty"`
+	Status     string `json:"status"` // deployed, failed, pending-upgrade, ...
+	Revision   int    `json:"revision"`
+}
+
+// CRDScanResult describes an installed CustomResourceDefinition.
+type CRDScanResult struct {
+	Name     string   `json:"name"` // <plural>.<group>
+	Group    string   `json:"group"`
+	Kind     string   `json:"kind"`
+	Versions []string `json:"versions"` // served versions
+	Scope    string   `json:"scope"`    // Namespaced or Cluster
+}
+
+// PodPlacement links a pod to its node and owning workload.
+type PodPlacement struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Node      string `json:"node"`
+	OwnerKind string `json:"ownerKind,omitempty"` // Deployment, StatefulSet, DaemonSet, Job, ...; empty for bare pods
+	OwnerName string `json:"ownerName,omitempty"`
+	Phase     string `json:"phase"`
+	// Drainable is false for pods a node drain leaves in place: DaemonSet,
+	// mirror and static pods
+	Drainable bool `json:"drainable"`
 }
 
 // NodeScanResult matches the edge-ingest NodeScanResult.
@@ -19,6 +79,17 @@ type NodeScanResult struct {
 	Version string   `json:"version"`
 	OS      string   `json:"os"`
 	OSImage string   `json:"os_image"`
+	// Pressure lists node conditions currently true, e.g. "MemoryPressure"
+	Pressure []string `json:"pressure,omitempty"`
+	// Taints as "key=value:Effect", e.g. "node-role.kubernetes.io/control-plane:NoSchedule"
+	Taints []string `json:"taints,omitempty"`
+	// Resource quantities by name, e.g. {"cpu": "4", "memory": "16318480Ki", "pods": "110"}
+	Capacity    map[string]string `json:"capacity"`
+	Allocatable map[string]string `json:"allocatable"`
+	Labels      map[string]string `json:"labels"`
+	// Current usage from metrics-server; omitted when it is not installed
+	UsageCPUMillicores *int64 `json:"usage_cpu_millicores,omitempty"`
+	UsageMemoryBytes   *int64 `json:"usage_memory_bytes,omitempty"`
 }
 
 // NamespaceScanResult matches the edge-ingest NamespaceScanResult.
@@ -34,7 +105,14 @@ type NamespaceScanResult struct {
 	CronJobs          []CronJobScanResult          `json:"cronJobs"`
 	NetworkPolicies   []NetworkPolicyScanResult    `json:"networkPolicies"`
 	PDBs              []PDBScanResult              `json:"pdbs"`
+	HPAs              []HPAScanResult              `json:"hpas"`
 	ExternalSecrets   []ExternalSecretScanResult   `json:"externalSecrets"`
+	ResourceQuotas    []ResourceQuotaScanResult    `json:"resourceQuotas"`
+	LimitRanges       []LimitRangeScanResult       `json:"limitRanges"`
+	// VerticalPodAutoscalers; empty when the VPA CRDs are not installed
+	VPAs []VPAScanResult `json:"vpas,omitempty"`
+	// cert-manager Certificates; empty when cert-manager is not installed
+	Certificates []CertificateScanResult `json:"certificates,omitempty"`
 }
 
 // WorkloadScanResult matches the edge-ingest WorkloadScanResult.
@@ -48,9 +126,23 @@ type WorkloadScanResult struct {
 	DesiredNumberScheduled *int32                `json:"desiredNumberScheduled,omitempty"`
 	NumberReady            *int32                `json:"numberReady,omitempty"`
 	Strategy               string                `json:"strategy,omitempty"`
+	StrategyParams         *StrategyParams       `json:"strategyParams,omitempty"`
 	Containers             []ContainerInfoK8s    `json:"containers"`
 	Requests               *ResourceRequirements `json:"requests,omitempty"`
 	Limits                 *ResourceRequirements `json:"limits,omitempty"`
+	// Current usage summed over the workload's pods, from metrics-server;
+	// omitted when it is not installed
+	UsageCPUMillicores *int64 `json:"usageCpuMillicores,omitempty"`
+	UsageMemoryBytes   *int64 `json:"usageMemoryBytes,omitempty"`
+}
+
+// StrategyParams holds rolling update parameters. MaxSurge and
+// MaxUnavailable keep the spec's form: a count ("1") or a percentage
+// ("25%"). Partition applies to StatefulSets only.
+type StrategyParams struct {
+	MaxSurge       string `json:"maxSurge,omitempty"`
+	MaxUnavailable string `json:"maxUnavailable,omitempty"`
+	Partition      *int32 `json:"partition,omitempty"`
 }
 
 // ContainerInfoK8s matches the edge-ingest ContainerInfo.
@@ -138,6 +230,59 @@ type PVCScanResult struct {
 	Status       string   `json:"status"`
 }
 
+// PVScanResult describes a PersistentVolume.
+type PVScanResult struct {
+	Name          string `json:"name"`
+	Capacity      string `json:"capacity"`
+	ReclaimPolicy string `json:"reclaimPolicy"` // Retain, Delete, Recycle
+	Status        string `json:"status"`        // Available, Bound, Released, Failed
+	StorageClass  string `json:"storageClass"`
+	ClaimRef      string `json:"claimRef,omitempty"` // namespace/name of the claim it is (or was) bound to
+}
+
+// StorageClassScanResult describes a StorageClass.
+type StorageClassScanResult struct {
+	Name              string `json:"name"`
+	Provisioner       string `json:"provisioner"`
+	ReclaimPolicy     string `json:"reclaimPolicy"`     // Delete, Retain
+	VolumeBindingMode string `json:"volumeBindingMode"` // Immediate, WaitForFirstConsumer
+	IsDefault         bool   `json:"isDefault"`
+}
+
+// CertificateScanResult describes a cert-manager Certificate. NotAfter and
+// RenewalTime are unset until the certificate has been issued.
+type CertificateScanResult struct {
+	Name        string     `json:"name"`
+	Namespace   string     `json:"namespace"`
+	DNSNames    []string   `json:"dnsNames,omitempty"`
+	SecretName  string     `json:"secretName,omitempty"`
+	IssuerKind  string     `json:"issuerKind"` // Issuer or ClusterIssuer
+	IssuerName  string     `json:"issuerName"`
+	NotAfter    *time.Time `json:"notAfter,omitempty"`
+	RenewalTime *time.Time `json:"renewalTime,omitempty"`
+}
+
+// VPAScanResult describes a VerticalPodAutoscaler and its current
+// recommendation.
+type VPAScanResult struct {
+	Name            string                       `json:"name"`
+	Namespace       string                       `json:"namespace"`
+	TargetKind      string                       `json:"targetKind"`
+	TargetName      string                       `json:"targetName"`
+	UpdateMode      string                       `json:"updateMode"` // Off, Initial, Recreate, InPlaceOrRecreate, Auto
+	Recommendations []VPAContainerRecommendation `json:"recommendations,omitempty"`
+}
+
+// VPAContainerRecommendation is the VPA's suggestion for one container.
+// Target is the recommended request; the bounds are the range the VPA
+// considers acceptable before it would evict the pod.
+type VPAContainerRecommendation struct {
+	Container  string                `json:"container"`
+	Target     *ResourceRequirements `json:"target,omitempty"`
+	LowerBound *ResourceRequirements `json:"lowerBound,omitempty"`
+	UpperBound *ResourceRequirements `json:"upperBound,omitempty"`
+}
+
 // CronJobScanResult matches the edge-ingest CronJobScanResult.
 type CronJobScanResult struct {
 	Name             string  `json:"name"`
@@ -164,6 +309,54 @@ type PDBScanResult struct {
 	Selector       map[string]interface{} `json:"selector"`
 }
 
+// ResourceQuotaScanResult describes a ResourceQuota. Hard and Used map
+// resource names (e.g. "requests.cpu", "pods") to quantities.
+type ResourceQuotaScanResult struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Hard      map[string]string `json:"hard"`
+	Used      map[string]string `json:"used"`
+	Scopes    []string          `json:"scopes,omitempty"`
+}
+
+// LimitRangeScanResult describes a LimitRange.
+type LimitRangeScanResult struct {
+	Name      string           `json:"name"`
+	Namespace string           `json:"namespace"`
+	Limits    []LimitRangeItem `json:"limits"`
+}
+
+// LimitRangeItem is the constraint a LimitRange puts on one kind of
+// object (Container, Pod or PersistentVolumeClaim).
+type LimitRangeItem struct {
+	Type                 string            `json:"type"`
+	Max                  map[string]string `json:"max,omitempty"`
+	Min                  map[string]string `json:"min,omitempty"`
+	Default              map[string]string `json:"default,omitempty"`
+	DefaultRequest       map[string]string `json:"defaultRequest,omitempty"`
+	MaxLimitRequestRatio map[string]string `json:"maxLimitRequestRatio,omitempty"`
+}
+
+// HPAScanResult describes a HorizontalPodAutoscaler.
+type HPAScanResult struct {
+	Name            string      `json:"name"`
+	Namespace       string      `json:"namespace"`
+	TargetKind      string      `json:"targetKind"`
+	TargetName      string      `json:"targetName"`
+	MinReplicas     int32       `json:"minReplicas"`
+	MaxReplicas     int32       `json:"maxReplicas"`
+	CurrentReplicas int32       `json:"currentReplicas"`
+	DesiredReplicas int32       `json:"desiredReplicas"`
+	Metrics         []HPAMetric `json:"metrics,omitempty"`
+}
+
+// HPAMetric is one metric an HPA scales on.
+type HPAMetric struct {
+	Type   string `json:"type"`   // Resource, ContainerResource, Pods, Object, External
+	Name   string `json:"name"`   // e.g. "cpu", "app/memory", "http_requests_per_second"
+	Target string `json:"target"` // "80%" (utilization) or a quantity, e.g. "500m"
+}
+
 // ExternalSecretScanResult matches the edge-ingest ExternalSecretScanResult.
 type ExternalSecretScanResult struct {
 	Name            string                 `json:"name"`
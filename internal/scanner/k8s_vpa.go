@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var vpaGVR = schema.GroupVersionResource{
+	Group:    "autoscaling.k8s.io",
+	Version:  "v1",
+	Resource: "verticalpodautoscalers",
+}
+
+// scanVPA lists VerticalPodAutoscalers in every namespace with their
+// current per-container recommendations. It returns nil when the VPA CRDs
+// are not installed or the agent may not list VPAs.
+func scanVPA(ctx context.Context, dynClient dynamic.Interface, access *accessChecker, log *slog.Logger) []VPAScanResult {
+	list, err := dynClient.Resource(vpaGVR).Namespace("").List(ctx, metav1.ListOptions{})
+	access.recordList(resVPAs, err)
+	if err != nil {
+		// VPA not installed — not an error
+		log.Debug("cannot list VPAs", "error", err)
+		return nil
+	}
+
+	var vpas []VPAScanResult
+	for _, item := range list.Items {
+		v := VPAScanResult{
+			Name:       item.GetName(),
+			Namespace:  item.GetNamespace(),
+			UpdateMode: "Auto", // the API default
+		}
+		v.TargetKind, _, _ = unstructured.NestedString(item.Object, "spec", "targetRef", "kind")
+		v.TargetName, _, _ = unstructured.NestedString(item.Object, "spec", "targetRef", "name")
+		if mode, _, _ := unstructured.NestedString(item.Object, "spec", "updatePolicy", "updateMode"); mode != "" {
+			v.UpdateMode = mode
+		}
+
+		recs, _, _ := unstructured.NestedSlice(item.Object, "status", "recommendation", "containerRecommendations")
+		for _, r := range recs {
+			rec, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(rec, "containerName")
+			v.Recommendations = append(v.Recommendations, VPAContainerRecommendation{
+				Container:  name,
+				Target:     vpaResources(rec, "target"),
+				LowerBound: vpaResources(rec, "lowerBound"),
+				UpperBound: vpaResources(rec, "upperBound"),
+			})
+		}
+		vpas = append(vpas, v)
+	}
+
+	sort.Slice(vpas, func(i, j int) bool {
+		if vpas[i].Namespace != vpas[j].Namespace {
+			return vpas[i].Namespace < vpas[j].Namespace
+		}
+		return vpas[i].Name < vpas[j].Name
+	})
+	return vpas
+}
+
+// vpaResources converts a recommendation's resource list
+// ({cpu: "250m", memory: "262144k"}) to ResourceRequirements, or nil when
+// the field is absent.
+func vpaResources(rec map[string]interface{}, field string) *ResourceRequirements {
+	list, found, _ := unstructured.NestedStringMap(rec, field)
+	if !found {
+		return nil
+	}
+	var r ResourceRequirements
+	if q, err := resource.ParseQuantity(list["cpu"]); err == nil {
+		r.CPUMillicores = q.MilliValue()
+	}
+	if q, err := resource.ParseQuantity(list["memory"]); err == nil {
+		r.MemoryBytes = q.Value()
+	}
+	return &r
+}
+
+// attachVPAs adds each VPA to its namespace's result. VPAs in namespaces
+// that were not scanned (excluded, or not listable) are dropped.
+func attachVPAs(result *ClusterScanResult, vpas []VPAScanResult) {
+	attachByNamespace(result, vpas, func(v VPAScanResult) string { return v.Namespace },
+		func(ns *NamespaceScanResult, v VPAScanResult) { ns.VPAs = append(ns.VPAs, v) })
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestScanVPA(t *testing.T) {
+	vpa := func(ns, name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+		obj := map[string]interface{}{
+			"apiVersion": "autoscaling.k8s.io/v1",
+			"kind":       "VerticalPodAutoscaler",
+			"metadata":   map[string]interface{}{"name": name, "namespace": ns},
+			"spec":       spec,
+		}
+		if status != nil {
+			obj["status"] = status
+		}
+		return &unstructured.Unstructured{Object: obj}
+	}
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{vpaGVR: "VerticalPodAutoscalerList"},
+		vpa("shop", "web", map[string]interface{}{
+			"targetRef":    map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"},
+			"updatePolicy": map[string]interface{}{"updateMode": "Off"},
+		}, map[string]interface{}{
+			"recommendation": map[string]interface{}{
+				"containerRecommendations": []interface{}{
+					map[string]interface{}{
+						"containerName": "app",
+						"lowerBound":    map[string]interface{}{"cpu": "100m", "memory": "128Mi"},
+						"target":        map[string]interface{}{"cpu": "250m", "memory": "256Mi"},
+						"upperBound":    map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+					},
+				},
+			},
+		}),
+		// No recommendation yet, default update mode
+		vpa("shop", "db", map[string]interface{}{
+			"targetRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": "StatefulSet", "name": "db"},
+		}, nil),
+		vpa("excluded", "tool", map[string]interface{}{
+			"targetRef": map[string]interface{}{"kind": "Deployment", "name": "tool"},
+		}, nil),
+	)
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	vpas := scanVPA(context.Background(), dynClient, newAccessChecker(nil, log), log)
+	result := ClusterScanResult{Namespaces: []NamespaceScanResult{{Name: "shop"}}}
+	attachVPAs(&result, vpas)
+
+	want := []VPAScanResult{
+		{Name: "db", Namespace: "shop", TargetKind: "StatefulSet", TargetName: "db", UpdateMode: "Auto"},
+		{
+			Name: "web", Namespace: "shop", TargetKind: "Deployment", TargetName: "web", UpdateMode: "Off",
+			Recommendations: []VPAContainerRecommendation{{
+				Container:  "app",
+				Target:     &ResourceRequirements{CPUMillicores: 250, MemoryBytes: 256 << 20},
+				LowerBound: &ResourceRequirements{CPUMillicores: 100, MemoryBytes: 128 << 20},
+				UpperBound: &ResourceRequirements{CPUMillicores: 1000, MemoryBytes: 1 << 30},
+			}},
+		},
+	}
+	if got := result.Namespaces[0].VPAs; !reflect.DeepEqual(got, want) {
+		t.Errorf("VPAs =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestScanVPAForbiddenIsReportedAsDenied(t *testing.T) {
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{vpaGVR: "VerticalPodAutoscalerList"})
+	dynClient.PrependReactor("list", "verticalpodautoscalers", func(ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(vpaGVR.GroupResource(), "", errors.New("no RBAC"))
+	})
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	access := newAccessChecker(nil, log)
+	if vpas := scanVPA(context.Background(), dynClient, access, log); vpas != nil {
+		t.Errorf("VPAs = %+v, want none", vpas)
+	}
+	want := []AccessDenial{{Resource: "autoscaling.k8s.io/verticalpodautoscalers"}}
+	if got := access.summary().Denied; !reflect.DeepEqual(got, want) {
+		t.Errorf("denied = %+v, want %+v", got, want)
+	}
+}
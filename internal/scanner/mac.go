@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+)
+
+// MACCollision is a MAC address seen on more than one host.
+type MACCollision struct {
+	MAC   string   `json:"mac"`
+	Hosts []string `json:"hosts"`
+}
+
+// normalizeMAC lowercases a MAC and returns "" for missing or all-zero
+// addresses (loopback, tunnels), which are never meaningful duplicates.
+func normalizeMAC(mac string) string {
+	mac = strings.ToLower(strings.TrimSpace(mac))
+	if strings.Trim(mac, "0:-.") == "" {
+		return ""
+	}
+	return mac
+}
+
+// AnnotateSharedMACs sets SharedMAC on every interface whose MAC is also
+// carried by another interface on the same host, e.g. a bridge or bond
+// inheriting a member's MAC, or a cloned VM NIC.
+func AnnotateSharedMACs(ifaces []InterfaceInfo) {
+	byMAC := make(map[string][]int)
+	for i, iface := range ifaces {
+		if mac := normalizeMAC(iface.MAC); mac != "" {
+			byMAC[mac] = append(byMAC[mac], i)
+		}
+	}
+
+	for _, idx := range byMAC {
+		if len(idx) < 2 {
+			continue
+		}
+		for _, i := range idx {
+			var others []string
+			for _, j := range idx {
+				if j != i {
+					others = append(others, ifaces[j].Name)
+				}
+			}
+			ifaces[i].SharedMAC = others
+		}
+	}
+}
+
+// FindMACCollisions returns MAC addresses present on more than one host,
+// keyed by host name. Sharing within a single host is not a collision.
+func FindMACCollisions(hosts map[string][]InterfaceInfo) []MACCollision {
+	seen := make(map[string]map[string]bool)
+	for host, ifaces := range hosts {
+		for _, iface := range ifaces {
+			mac := normalizeMAC(iface.MAC)
+			if mac == "" {
+				continue
+			}
+			if seen[mac] == nil {
+				seen[mac] = make(map[string]bool)
+			}
+			seen[mac][host] = true
+		}
+	}
+
+	var collisions []MACCollision
+	for mac, hostSet := range seen {
+		if len(hostSet) < 2 {
+			continue
+		}
+		c := MACCollision{MAC: mac}
+		for h := range hostSet {
+			c.Hosts = append(c.Hosts, h)
+		}
+		sort.Strings(c.Hosts)
+		collisions = append(collisions, c)
+	}
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].MAC < collisions[j].MAC })
+	return collisions
+}
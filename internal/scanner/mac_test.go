@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnotateSharedMACs(t *testing.T) {
+	ifaces := []InterfaceInfo{
+		{Name: "eth0", MAC: "52:54:00:12:34:56"},
+		{Name: "br0", MAC: "52:54:00:12:34:56"},
+		{Name: "eth1", MAC: "52:54:00:ab:cd:ef"},
+		{Name: "lo", MAC: "00:00:00:00:00:00"},
+		{Name: "tun0"},
+		{Name: "tun1"},
+	}
+	AnnotateSharedMACs(ifaces)
+
+	if !reflect.DeepEqual(ifaces[0].SharedMAC, []string{"br0"}) {
+		t.Errorf("eth0 shared = %v, want [br0]", ifaces[0].SharedMAC)
+	}
+	if !reflect.DeepEqual(ifaces[1].SharedMAC, []string{"eth0"}) {
+		t.Errorf("br0 shared = %v, want [eth0]", ifaces[1].SharedMAC)
+	}
+	for _, i := range []int{2, 3, 4, 5} {
+		if ifaces[i].SharedMAC != nil {
+			t.Errorf("%s should not be annotated, got %v", ifaces[i].Name, ifaces[i].SharedMAC)
+		}
+	}
+}
+
+func TestFindMACCollisions(t *testing.T) {
+	hosts := map[string][]InterfaceInfo{
+		"node-a": {
+			{Name: "eth0", MAC: "52:54:00:12:34:56"},
+			{Name: "br0", MAC: "52:54:00:12:34:56"}, // same host only, not a collision
+			{Name: "eth1", MAC: "52:54:00:00:00:01"},
+		},
+		"node-b": {
+			{Name: "eth0", MAC: "52:54:00:12:34:56"},
+			{Name: "lo", MAC: "00:00:00:00:00:00"},
+		},
+		"node-c": {
+			{Name: "ens3", MAC: "52:54:00:12:34:56"},
+			{Name: "lo", MAC: "00:00:00:00:00:00"},
+		},
+	}
+
+	got := FindMACCollisions(hosts)
+	want := []MACCollision{{MAC: "52:54:00:12:34:56", Hosts: []string{"node-a", "node-b", "node-c"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collisions = %+v, want %+v", got, want)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
 )
 
 // CloudMetadata holds cloud instance metadata from IMDS.
@@ -26,6 +29,24 @@ type NetworkInfo struct {
 	Cloud         *CloudMetadata  `json:"cloud,omitempty"`
 	Interfaces    []InterfaceInfo `json:"interfaces"`
 	Routes        []RouteInfo     `json:"routes,omitempty"`
+	Services      []HostService   `json:"services,omitempty"`
+
+	// IPv6Enabled is set when an interface has a global IPv6 address.
+	// IPv6Egress is the result of the opt-in egress check; nil when the
+	// check did not run
+	IPv6Enabled bool  `json:"ipv6_enabled"`
+	IPv6Egress  *bool `json:"ipv6_egress,omitempty"`
+}
+
+// HostService is a listening TCP port and the process that owns it.
+// Process and PID are empty when the scan could not see the owner:
+// ss hides other users' sockets' owners unless run as root.
+type HostService struct {
+	Protocol string `json:"protocol"` // tcp
+	Address  string `json:"address"`  // bind address; "*", "0.0.0.0" or "::" for all
+	Port     int    `json:"port"`
+	Process  string `json:"process,omitempty"`
+	PID      int    `json:"pid,omitempty"`
 }
 
 // InterfaceInfo represents a single network interface.
@@ -37,6 +58,18 @@ type InterfaceInfo struct {
 	MTU   int    `json:"mtu,omitempty"`
 	State string `json:"state,omitempty"` // up, down
 	Type  string `json:"type,omitempty"`  // physical, cni, bridge, virtual, tunnel, wireless
+
+	// Other interfaces on this host with the same MAC (see AnnotateSharedMACs)
+	SharedMAC []string `json:"shared_mac_with,omitempty"`
+
+	// Cumulative counters since boot (or interface creation). The SaaS
+	// derives rates from deltas between consecutive scans.
+	RxBytes   uint64 `json:"rx_bytes,omitempty"`
+	TxBytes   uint64 `json:"tx_bytes,omitempty"`
+	RxPackets uint64 `json:"rx_packets,omitempty"`
+	TxPackets uint64 `json:"tx_packets,omitempty"`
+	RxErrors  uint64 `json:"rx_errors,omitempty"`
+	TxErrors  uint64 `json:"tx_errors,omitempty"`
 }
 
 // RouteInfo represents a network route.
@@ -47,8 +80,27 @@ type RouteInfo struct {
 	Metric      int    `json:"metric,omitempty"`
 }
 
+// servicesFromListeners converts parsed listening sockets.
+func servicesFromListeners(listeners []parser.Listener) []HostService {
+	var services []HostService
+	for _, l := range listeners {
+		services = append(services, HostService{
+			Protocol: "tcp",
+			Address:  l.Address,
+			Port:     l.Port,
+			Process:  l.Process,
+			PID:      l.PID,
+		})
+	}
+	return services
+}
+
 // NetworkScanner collects network interface and routing information.
-type NetworkScanner struct{}
+type NetworkScanner struct {
+	// IPv6EgressCheck dials an Internet host over IPv6 on hosts with a
+	// global IPv6 address to confirm it is actually reachable
+	IPv6EgressCheck bool
+}
 
 // NewNetworkScanner creates a new NetworkScanner.
 func NewNetworkScanner() *NetworkScanner {
@@ -71,6 +123,15 @@ func (s *NetworkScanner) Scan(ctx context.Context, runner CommandRunner) (json.R
 		return nil, err
 	}
 
+	sort.SliceStable(info.Interfaces, func(i, j int) bool { return info.Interfaces[i].Name < info.Interfaces[j].Name })
+	AnnotateSharedMACs(info.Interfaces)
+
+	info.IPv6Enabled = hasGlobalIPv6(info.Interfaces)
+	if s.IPv6EgressCheck {
+		egress := info.IPv6Enabled && checkIPv6Egress(ctx)
+		info.IPv6Egress = &egress
+	}
+
 	// Detect public IP and cloud provider via metadata services
 	info.PublicIP, info.CloudProvider, info.Cloud = detectCloudMetadata(ctx)
 
@@ -13,11 +13,21 @@ func collectNetworkInfo(ctx context.Context, runner CommandRunner, info *Network
 		info.Interfaces = convertParserInterfaces(parser.ParseIfconfig(string(out)))
 	}
 
+	// Per-interface traffic counters
+	if out, err := runner.Run(ctx, "netstat -ibn"); err == nil {
+		applyInterfaceCounters(info.Interfaces, parser.ParseNetstatIbn(string(out)))
+	}
+
 	// Parse routes
 	if out, err := runner.Run(ctx, "netstat -rn -f inet"); err == nil {
 		info.Routes = parseNetstatRoutes(string(out))
 	}
 
+	// Listening TCP ports. Without root lsof lists only the caller's own.
+	if out, err := runner.Run(ctx, "lsof -iTCP -sTCP:LISTEN -P -n"); err == nil {
+		info.Services = servicesFromListeners(parser.ParseLsofListeners(string(out)))
+	}
+
 	return nil
 }
 
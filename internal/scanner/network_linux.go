@@ -8,6 +8,12 @@ import (
 	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
 )
 
+// sysfsStatisticsCmd prints "path:value" for each traffic counter file.
+const sysfsStatisticsCmd = "grep -H . " +
+	"/sys/class/net/*/statistics/rx_bytes /sys/class/net/*/statistics/tx_bytes " +
+	"/sys/class/net/*/statistics/rx_packets /sys/class/net/*/statistics/tx_packets " +
+	"/sys/class/net/*/statistics/rx_errors /sys/class/net/*/statistics/tx_errors 2>/dev/null"
+
 func collectNetworkInfo(ctx context.Context, runner CommandRunner, info *NetworkInfo) error {
 	// Prefer host's /proc/1/net/dev when running in a container with hostPID.
 	// PID 1 is the host's init, so /proc/1/net/dev shows the host's real
@@ -36,6 +42,13 @@ func collectNetworkInfo(ctx context.Context, runner CommandRunner, info *Network
 		}
 	}
 
+	// Per-interface traffic counters (host /proc/1/net/dev already carries them)
+	if !hasInterfaceCounters(info.Interfaces) {
+		if out, err := runner.Run(ctx, sysfsStatisticsCmd); err == nil {
+			applyInterfaceCounters(info.Interfaces, parser.ParseSysfsStatistics(string(out)))
+		}
+	}
+
 	// Routes
 	if out, err := runner.Run(ctx, "ip -j route show 2>/dev/null"); err == nil {
 		var ipRoutes []parser.IPRouteJSON
@@ -50,9 +63,23 @@ func collectNetworkInfo(ctx context.Context, runner CommandRunner, info *Network
 		}
 	}
 
+	// Listening TCP ports; owners are visible only to root
+	if out, err := runner.Run(ctx, "ss -tlnp"); err == nil {
+		info.Services = servicesFromListeners(parser.ParseSsListeners(string(out)))
+	}
+
 	return nil
 }
 
+func hasInterfaceCounters(ifaces []InterfaceInfo) bool {
+	for _, iface := range ifaces {
+		if iface.RxBytes > 0 || iface.TxBytes > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func parseIPRouteJSON(routes []parser.IPRouteJSON) []RouteInfo {
 	var result []RouteInfo
 	for _, r := range routes {
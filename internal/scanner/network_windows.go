@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"context"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+const (
+	windowsAdaptersScript = `ConvertTo-Json -Compress -InputObject @(Get-NetAdapter | ` +
+		`Select-Object Name, InterfaceDescription, MacAddress, Status, MtuSize)`
+	windowsAddressesScript = `ConvertTo-Json -Compress -InputObject @(Get-NetIPAddress | ` +
+		`Select-Object InterfaceAlias, IPAddress, @{n='AddressFamily';e={"$($_.AddressFamily)"}}, PrefixLength)`
+	windowsRoutesScript = `ConvertTo-Json -Compress -InputObject @(Get-NetRoute -AddressFamily IPv4 | ` +
+		`Select-Object DestinationPrefix, NextHop, InterfaceAlias, RouteMetric)`
+)
+
+func collectNetworkInfo(ctx context.Context, runner CommandRunner, info *NetworkInfo) error {
+	adapters, err := runner.Run(ctx, PowerShellCommand(windowsAdaptersScript))
+	if err == nil {
+		addresses, _ := runner.Run(ctx, PowerShellCommand(windowsAddressesScript))
+		info.Interfaces = convertParserInterfaces(parser.ParsePSInterfaces(adapters, addresses))
+	}
+
+	if out, err := runner.Run(ctx, PowerShellCommand(windowsRoutesScript)); err == nil {
+		for _, r := range parser.ParsePSRoutes(out) {
+			info.Routes = append(info.Routes, RouteInfo{
+				Destination: r.DestinationPrefix,
+				Gateway:     r.NextHop,
+				Interface:   r.InterfaceAlias,
+				Metric:      r.RouteMetric,
+			})
+		}
+	}
+
+	return nil
+}
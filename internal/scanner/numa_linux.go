@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+const (
+	numaLscpuCmd   = `lscpu -J 2>/dev/null`
+	numaMeminfoCmd = `grep -h MemTotal /sys/devices/system/node/node*/meminfo 2>/dev/null; true`
+)
+
+// collectNUMA reads the NUMA layout from lscpu and per-node memory from
+// sysfs. Hosts where lscpu lists no nodes (most VMs, kernels without NUMA
+// support) are reported as a single node holding every CPU and all memory.
+// Must run after CPUCores and MemoryGB are set.
+func collectNUMA(ctx context.Context, runner CommandRunner, info *HostInfo) {
+	var nodes []parser.NUMANode
+	if out, err := runner.Run(ctx, numaLscpuCmd); err == nil {
+		nodes = parser.ParseLscpuNUMA(out)
+	}
+	var mem map[int]int64
+	if out, err := runner.Run(ctx, numaMeminfoCmd); err == nil {
+		mem = parser.ParseNodeMeminfo(string(out))
+	}
+
+	if len(nodes) == 0 {
+		node := parser.NUMANode{ID: 0, MemoryBytes: int64(info.System.MemoryGB * (1 << 30))}
+		if info.System.CPUCores > 0 {
+			node.CPUs = fmt.Sprintf("0-%d", info.System.CPUCores-1)
+		}
+		nodes = []parser.NUMANode{node}
+	}
+
+	numa := &NUMATopology{NodeCount: len(nodes)}
+	for _, n := range nodes {
+		if b, ok := mem[n.ID]; ok {
+			n.MemoryBytes = b
+		}
+		numa.Nodes = append(numa.Nodes, NUMANode{ID: n.ID, CPUs: n.CPUs, MemoryBytes: n.MemoryBytes})
+	}
+	info.System.NUMA = numa
+}
@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCollectNUMA(t *testing.T) {
+	lscpu, err := os.ReadFile("../../testdata/lscpu_numa.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info HostInfo
+	collectNUMA(context.Background(), cannedRunner{
+		numaLscpuCmd:   string(lscpu),
+		numaMeminfoCmd: "Node 0 MemTotal:       65789012 kB\nNode 1 MemTotal:       66060288 kB\n",
+	}, &info)
+
+	want := &NUMATopology{NodeCount: 2, Nodes: []NUMANode{
+		{ID: 0, CPUs: "0-15,32-47", MemoryBytes: 65789012 * 1024},
+		{ID: 1, CPUs: "16-31,48-63", MemoryBytes: 66060288 * 1024},
+	}}
+	if !reflect.DeepEqual(info.System.NUMA, want) {
+		t.Errorf("NUMA = %+v, want %+v", info.System.NUMA, want)
+	}
+}
+
+func TestCollectNUMASingleNodeFallback(t *testing.T) {
+	info := HostInfo{System: SystemInfo{CPUCores: 4, MemoryGB: 8}}
+	collectNUMA(context.Background(), cannedRunner{
+		numaLscpuCmd:   `{"lscpu": [{"field": "CPU(s):", "data": "4"}]}`,
+		numaMeminfoCmd: "",
+	}, &info)
+
+	want := &NUMATopology{NodeCount: 1, Nodes: []NUMANode{{ID: 0, CPUs: "0-3", MemoryBytes: 8 << 30}}}
+	if !reflect.DeepEqual(info.System.NUMA, want) {
+		t.Errorf("NUMA = %+v, want %+v", info.System.NUMA, want)
+	}
+}
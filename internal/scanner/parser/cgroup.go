@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// cgroupV1Unlimited is the threshold above which a cgroup v1
+// memory.limit_in_bytes means "no limit" (the kernel reports
+// PAGE_COUNTER_MAX rounded to the page size, e.g. 9223372036854771712).
+const cgroupV1Unlimited = 1 << 62
+
+// CgroupLimits holds the memory and CPU limits of a cgroup.
+// Zero values mean no limit is set.
+type CgroupLimits struct {
+	MemoryBytes int64
+	CPUs        float64 // CFS quota / period, e.g. 1.5
+}
+
+// ParseProcCgroup parses /proc/<pid>/cgroup and returns the cgroup version
+// and the cgroup path per controller. On a pure v2 (unified) system the
+// single path is stored under the "" key. Hybrid systems with any v1
+// controller report version 1.
+//
+//	v2:  0::/system.slice/tb-manage.service
+//	v1:  4:memory:/docker/abc123
+//	     3:cpu,cpuacct:/docker/abc123
+func ParseProcCgroup(output string) (version int, paths map[string]string) {
+	paths = make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			paths[""] = parts[2]
+			if version == 0 {
+				version = 2
+			}
+			continue
+		}
+		for _, ctrl := range strings.Split(parts[1], ",") {
+			if ctrl == "" || strings.HasPrefix(ctrl, "name=") {
+				continue
+			}
+			paths[ctrl] = parts[2]
+			version = 1
+		}
+	}
+	return version, paths
+}
+
+// ParseCgroupV2Limits parses the contents of memory.max ("max" or bytes)
+// and cpu.max ("$MAX $PERIOD", where $MAX may be "max").
+func ParseCgroupV2Limits(memoryMax, cpuMax string) CgroupLimits {
+	var limits CgroupLimits
+	if v, err := strconv.ParseInt(strings.TrimSpace(memoryMax), 10, 64); err == nil && v > 0 {
+		limits.MemoryBytes = v
+	}
+	if fields := strings.Fields(cpuMax); len(fields) == 2 {
+		limits.CPUs = cpuQuota(fields[0], fields[1])
+	}
+	return limits
+}
+
+// ParseCgroupV1Limits parses memory.limit_in_bytes, cpu.cfs_quota_us and
+// cpu.cfs_period_us. A quota of -1 means no CPU limit.
+func ParseCgroupV1Limits(memoryLimit, cfsQuota, cfsPeriod string) CgroupLimits {
+	var limits CgroupLimits
+	if v, err := strconv.ParseInt(strings.TrimSpace(memoryLimit), 10, 64); err == nil && v > 0 && v < cgroupV1Unlimited {
+		limits.MemoryBytes = v
+	}
+	limits.CPUs = cpuQuota(strings.TrimSpace(cfsQuota), strings.TrimSpace(cfsPeriod))
+	return limits
+}
+
+func cpuQuota(quota, period string) float64 {
+	q, err := strconv.ParseInt(quota, 10, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseInt(period, 10, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return float64(q) / float64(p)
+}
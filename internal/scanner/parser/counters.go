@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// InterfaceCounters holds cumulative per-interface traffic counters.
+type InterfaceCounters struct {
+	RxBytes   uint64
+	TxBytes   uint64
+	RxPackets uint64
+	TxPackets uint64
+	RxErrors  uint64
+	TxErrors  uint64
+}
+
+// ParseNetstatIbn parses macOS/BSD `netstat -ibn` output. Only the <Link#N>
+// row of each interface is used; per-address rows repeat the same counters
+// (or show "-") and are skipped.
+//
+//	Name  Mtu   Network     Address            Ipkts Ierrs     Ibytes    Opkts Oerrs     Obytes  Coll
+//	en0   1500  <Link#6>    a4:83:e7:12:34:56  98765     0  123456789    54321     0   98765432     0
+func ParseNetstatIbn(output string) map[string]InterfaceCounters {
+	counters := make(map[string]InterfaceCounters)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		// Name Mtu Network [Address] + 7 counter columns
+		if len(fields) < 10 || !strings.HasPrefix(fields[2], "<Link#") {
+			continue
+		}
+		name := strings.TrimSuffix(fields[0], "*") // "*" marks down interfaces
+		if _, seen := counters[name]; seen {
+			continue
+		}
+
+		// Counters are the last 7 columns: Ipkts Ierrs Ibytes Opkts Oerrs Obytes Coll
+		c := fields[len(fields)-7:]
+		counters[name] = InterfaceCounters{
+			RxPackets: parseCounter(c[0]),
+			RxErrors:  parseCounter(c[1]),
+			RxBytes:   parseCounter(c[2]),
+			TxPackets: parseCounter(c[3]),
+			TxErrors:  parseCounter(c[4]),
+			TxBytes:   parseCounter(c[5]),
+		}
+	}
+	return counters
+}
+
+// ParseSysfsStatistics parses `grep -H . /sys/class/net/*/statistics/<stat>`
+// output, one "path:value" line per counter file.
+//
+//	/sys/class/net/eth0/statistics/rx_bytes:452698464934
+func ParseSysfsStatistics(output string) map[string]InterfaceCounters {
+	counters := make(map[string]InterfaceCounters)
+	for _, line := range strings.Split(output, "\n") {
+		path, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		parts := strings.Split(path, "/")
+		// "", sys, class, net, <iface>, statistics, <stat>
+		if len(parts) < 3 || parts[len(parts)-2] != "statistics" {
+			continue
+		}
+		name, stat := parts[len(parts)-3], parts[len(parts)-1]
+		n := parseCounter(value)
+
+		c := counters[name]
+		switch stat {
+		case "rx_bytes":
+			c.RxBytes = n
+		case "tx_bytes":
+			c.TxBytes = n
+		case "rx_packets":
+			c.RxPackets = n
+		case "tx_packets":
+			c.TxPackets = n
+		case "rx_errors":
+			c.RxErrors = n
+		case "tx_errors":
+			c.TxErrors = n
+		default:
+			continue
+		}
+		counters[name] = c
+	}
+	return counters
+}
+
+// parseCounter parses an unsigned counter; "-" and garbage read as 0.
+func parseCounter(s string) uint64 {
+	n, _ := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	return n
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ParseCPUFreq parses `grep -H .` over a CPU's cpufreq scaling_max_freq
+// (kHz) and scaling_governor files:
+//
+//	/sys/devices/system/cpu/cpu0/cpufreq/scaling_max_freq:3400000
+//	/sys/devices/system/cpu/cpu0/cpufreq/scaling_governor:powersave
+//
+// Missing files leave the corresponding result zero or empty.
+func ParseCPUFreq(output string) (maxMHz float64, governor string) {
+	for _, line := range strings.Split(output, "\n") {
+		file, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch path.Base(file) {
+		case "scaling_max_freq":
+			if khz, err := strconv.ParseFloat(value, 64); err == nil {
+				maxMHz = khz / 1000
+			}
+		case "scaling_governor":
+			governor = value
+		}
+	}
+	return maxMHz, governor
+}
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GPU is one graphics/compute adapter.
+type GPU struct {
+	Vendor      string // "NVIDIA", "AMD", "Intel", or the PCI vendor string
+	Model       string
+	MemoryBytes int64  // 0 when unknown
+	Driver      string // driver version, when reported
+}
+
+// ParseNvidiaSmiCSV parses
+// `nvidia-smi --query-gpu=name,memory.total,driver_version --format=csv,noheader`:
+//
+//	NVIDIA A100-SXM4-80GB, 81920 MiB, 535.129.03
+func ParseNvidiaSmiCSV(output string) []GPU {
+	var gpus []GPU
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 {
+			continue
+		}
+		model := strings.TrimSpace(fields[0])
+		if model == "" {
+			continue
+		}
+		gpus = append(gpus, GPU{
+			Vendor:      "NVIDIA",
+			Model:       model,
+			MemoryBytes: parseSizeWithUnit(strings.TrimSpace(fields[1])),
+			Driver:      strings.TrimSpace(fields[2]),
+		})
+	}
+	return gpus
+}
+
+// ParseNvidiaProcGPUs parses the concatenated
+// /proc/driver/nvidia/gpus/*/information files, one block per GPU:
+//
+//	Model: 		 Tesla T4
+//	IRQ:   		 38
+//	GPU UUID: 	 GPU-5a1b...
+func ParseNvidiaProcGPUs(output string) []GPU {
+	var gpus []GPU
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "Model" {
+			continue
+		}
+		if model := strings.TrimSpace(value); model != "" {
+			gpus = append(gpus, GPU{Vendor: "NVIDIA", Model: model})
+		}
+	}
+	return gpus
+}
+
+// ParseLspciGPUs parses `lspci -mm` output, keeping display controllers:
+//
+//	03:00.0 "VGA compatible controller" "Advanced Micro Devices, Inc. [AMD/ATI]" "Navi 21 [Radeon RX 6800/6800 XT / 6900 XT]" -rc1 "Sapphire" "Nitro+"
+func ParseLspciGPUs(output string) []GPU {
+	var gpus []GPU
+	for _, line := range strings.Split(output, "\n") {
+		fields := quotedFields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		switch fields[0] {
+		case "VGA compatible controller", "3D controller", "Display controller":
+		default:
+			continue
+		}
+		gpus = append(gpus, GPU{Vendor: gpuVendor(fields[1]), Model: fields[2]})
+	}
+	return gpus
+}
+
+// gpuVendor shortens common PCI vendor names.
+func gpuVendor(pciVendor string) string {
+	lower := strings.ToLower(pciVendor)
+	switch {
+	case strings.Contains(lower, "nvidia"):
+		return "NVIDIA"
+	case strings.Contains(lower, "advanced micro devices"), strings.Contains(lower, "amd"):
+		return "AMD"
+	case strings.Contains(lower, "intel"):
+		return "Intel"
+	}
+	return pciVendor
+}
+
+// quotedFields returns the double-quoted strings in line, in order.
+func quotedFields(line string) []string {
+	var fields []string
+	for {
+		start := strings.IndexByte(line, '"')
+		if start < 0 {
+			return fields
+		}
+		end := strings.IndexByte(line[start+1:], '"')
+		if end < 0 {
+			return fields
+		}
+		fields = append(fields, line[start+1:start+1+end])
+		line = line[start+end+2:]
+	}
+}
+
+// parseSizeWithUnit parses sizes like "81920 MiB" or "16 GiB" into bytes.
+func parseSizeWithUnit(s string) int64 {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0
+	}
+	n, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	mult := float64(1)
+	if len(fields) > 1 {
+		switch strings.ToLower(fields[1]) {
+		case "kib", "kb":
+			mult = 1 << 10
+		case "mib", "mb":
+			mult = 1 << 20
+		case "gib", "gb":
+			mult = 1 << 30
+		}
+	}
+	return int64(n * mult)
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// LVMVolumeGroup is one volume group from `vgs`.
+type LVMVolumeGroup struct {
+	Name      string
+	SizeBytes int64
+	FreeBytes int64
+	PVs       []string // physical volume paths, e.g. /dev/sda3
+}
+
+// LVMLogicalVolume is one logical volume from `lvs`.
+type LVMLogicalVolume struct {
+	Name      string
+	VG        string
+	SizeBytes int64
+	Path      string // /dev/<vg>/<lv>
+	DMPath    string // /dev/mapper/<vg>-<lv>, as df reports it
+}
+
+// ParseVgs parses
+// `vgs --reportformat json --units b --nosuffix -o vg_name,vg_size,vg_free,pv_name`,
+// which lists one row per physical volume:
+//
+//	{"report": [{"vg": [
+//	  {"vg_name":"data", "vg_size":"3999956729856", "vg_free":"999989182464", "pv_name":"/dev/sdb"},
+//	  {"vg_name":"data", "vg_size":"3999956729856", "vg_free":"999989182464", "pv_name":"/dev/sdc"}
+//	]}]}
+func ParseVgs(data []byte) []LVMVolumeGroup {
+	var doc struct {
+		Report []struct {
+			VG []struct {
+				Name string `json:"vg_name"`
+				Size string `json:"vg_size"`
+				Free string `json:"vg_free"`
+				PV   string `json:"pv_name"`
+			} `json:"vg"`
+		} `json:"report"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	var vgs []LVMVolumeGroup
+	index := make(map[string]int)
+	for _, report := range doc.Report {
+		for _, row := range report.VG {
+			if row.Name == "" {
+				continue
+			}
+			i, ok := index[row.Name]
+			if !ok {
+				i = len(vgs)
+				index[row.Name] = i
+				vgs = append(vgs, LVMVolumeGroup{
+					Name:      row.Name,
+					SizeBytes: parseLVMBytes(row.Size),
+					FreeBytes: parseLVMBytes(row.Free),
+				})
+			}
+			if row.PV != "" {
+				vgs[i].PVs = append(vgs[i].PVs, row.PV)
+			}
+		}
+	}
+	return vgs
+}
+
+// ParseLvs parses
+// `lvs --reportformat json --units b --nosuffix -o lv_name,vg_name,lv_size,lv_path,lv_dm_path`:
+//
+//	{"report": [{"lv": [
+//	  {"lv_name":"root", "vg_name":"ubuntu-vg", "lv_size":"107374182400",
+//	   "lv_path":"/dev/ubuntu-vg/root", "lv_dm_path":"/dev/mapper/ubuntu--vg-root"}
+//	]}]}
+func ParseLvs(data []byte) []LVMLogicalVolume {
+	var doc struct {
+		Report []struct {
+			LV []struct {
+				Name   string `json:"lv_name"`
+				VG     string `json:"vg_name"`
+				Size   string `json:"lv_size"`
+				Path   string `json:"lv_path"`
+				DMPath string `json:"lv_dm_path"`
+			} `json:"lv"`
+		} `json:"report"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	var lvs []LVMLogicalVolume
+	for _, report := range doc.Report {
+		for _, row := range report.LV {
+			if row.Name == "" {
+				continue
+			}
+			lvs = append(lvs, LVMLogicalVolume{
+				Name:      row.Name,
+				VG:        row.VG,
+				SizeBytes: parseLVMBytes(row.Size),
+				Path:      row.Path,
+				DMPath:    row.DMPath,
+			})
+		}
+	}
+	return lvs
+}
+
+// parseLVMBytes parses an LVM size in bytes; a trailing "B" unit is
+// accepted in case --nosuffix was ignored.
+func parseLVMBytes(s string) int64 {
+	n, _ := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(s), "B"), 10, 64)
+	return n
+}
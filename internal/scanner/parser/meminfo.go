@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseMeminfo parses /proc/meminfo into byte counts keyed by field name.
+// Fields without a unit (the HugePages_* counts) are returned as-is.
+//
+//	MemTotal:       16318412 kB
+//	SwapTotal:       2097148 kB
+//	SwapFree:        1572860 kB
+func ParseMeminfo(output string) map[string]int64 {
+	values := make(map[string]int64)
+	for _, line := range strings.Split(output, "\n") {
+		key, rest, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		n, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && strings.EqualFold(fields[1], "kB") {
+			n *= 1024
+		}
+		values[strings.TrimSpace(key)] = n
+	}
+	return values
+}
+
+var swapUsageRe = regexp.MustCompile(`(total|used|free)\s*=\s*([\d.]+)([KMGT]?)`)
+
+// ParseSwapUsage parses macOS `sysctl -n vm.swapusage` into bytes:
+//
+//	total = 2048.00M  used = 1126.25M  free = 921.75M  (encrypted)
+func ParseSwapUsage(output string) (total, used, free int64) {
+	for _, m := range swapUsageRe.FindAllStringSubmatch(output, -1) {
+		n, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			continue
+		}
+		switch m[3] {
+		case "K":
+			n *= 1 << 10
+		case "M":
+			n *= 1 << 20
+		case "G":
+			n *= 1 << 30
+		case "T":
+			n *= 1 << 40
+		}
+		switch m[1] {
+		case "total":
+			total = int64(n)
+		case "used":
+			used = int64(n)
+		case "free":
+			free = int64(n)
+		}
+	}
+	return total, used, free
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NUMANode is one NUMA node's CPUs and memory.
+type NUMANode struct {
+	ID          int
+	CPUs        string // kernel cpulist format, e.g. "0-15,32-47"
+	MemoryBytes int64  // 0 when unknown
+}
+
+// lscpuEntry is one field of `lscpu -J`. util-linux 2.38 and later nest
+// related fields under "children".
+type lscpuEntry struct {
+	Field    string       `json:"field"`
+	Data     *string      `json:"data"`
+	Children []lscpuEntry `json:"children"`
+}
+
+var lscpuNUMANodeRe = regexp.MustCompile(`^NUMA node(\d+) CPU\(s\):$`)
+
+// ParseLscpuNUMA returns the NUMA nodes listed by `lscpu -J`, ordered by
+// node ID, or nil when lscpu reports none:
+//
+//	{"lscpu": [
+//	  {"field": "NUMA node(s):", "data": "2"},
+//	  {"field": "NUMA node0 CPU(s):", "data": "0-15,32-47"},
+//	  {"field": "NUMA node1 CPU(s):", "data": "16-31,48-63"}
+//	]}
+func ParseLscpuNUMA(data []byte) []NUMANode {
+	var doc struct {
+		Lscpu []lscpuEntry `json:"lscpu"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	var nodes []NUMANode
+	var walk func(entries []lscpuEntry)
+	walk = func(entries []lscpuEntry) {
+		for _, e := range entries {
+			if m := lscpuNUMANodeRe.FindStringSubmatch(strings.TrimSpace(e.Field)); m != nil && e.Data != nil {
+				id, _ := strconv.Atoi(m[1])
+				nodes = append(nodes, NUMANode{ID: id, CPUs: strings.TrimSpace(*e.Data)})
+			}
+			walk(e.Children)
+		}
+	}
+	walk(doc.Lscpu)
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// ParseNodeMeminfo parses the MemTotal lines of the per-node
+// /sys/devices/system/node/node*/meminfo files into bytes by node ID:
+//
+//	Node 0 MemTotal:       65789012 kB
+func ParseNodeMeminfo(output string) map[int]int64 {
+	mem := make(map[int]int64)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] != "Node" || fields[2] != "MemTotal:" {
+			continue
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		mem[id] = kb * 1024
+	}
+	return mem
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -215,3 +216,596 @@ func TestParseIPAddrJSON(t *testing.T) {
 		t.Errorf("MAC = %q, want aa:bb:cc:dd:ee:ff", eth.MAC)
 	}
 }
+
+func TestParseNetstatIbn(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/netstat_ibn_macos.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	counters := ParseNetstatIbn(string(data))
+
+	if len(counters) != 4 {
+		t.Errorf("expected 4 interfaces (lo0, gif0, en0, utun0), got %d: %v", len(counters), counters)
+	}
+
+	en0, ok := counters["en0"]
+	if !ok {
+		t.Fatal("missing en0")
+	}
+	want := InterfaceCounters{
+		RxPackets: 9876543, RxErrors: 3, RxBytes: 12345678901,
+		TxPackets: 5432109, TxErrors: 1, TxBytes: 987654321,
+	}
+	if en0 != want {
+		t.Errorf("en0 = %+v, want %+v", en0, want)
+	}
+
+	// Link row without an address column
+	if utun := counters["utun0"]; utun.RxBytes != 388 || utun.TxBytes != 812 || utun.TxPackets != 10 {
+		t.Errorf("utun0 = %+v", utun)
+	}
+
+	// Down interface marker is stripped
+	if _, ok := counters["gif0"]; !ok {
+		t.Error("expected gif0 (without trailing *)")
+	}
+}
+
+func TestParseSysfsStatistics(t *testing.T) {
+	output := `/sys/class/net/eth0/statistics/rx_bytes:452698464934
+/sys/class/net/eth0/statistics/tx_bytes:983787744722
+/sys/class/net/eth0/statistics/rx_packets:709126800
+/sys/class/net/eth0/statistics/tx_packets:955845802
+/sys/class/net/eth0/statistics/rx_errors:146203
+/sys/class/net/eth0/statistics/tx_errors:0
+/sys/class/net/lo/statistics/rx_bytes:232655232168
+`
+	counters := ParseSysfsStatistics(output)
+
+	eth0 := counters["eth0"]
+	if eth0.RxBytes != 452698464934 || eth0.TxBytes != 983787744722 || eth0.RxErrors != 146203 || eth0.TxPackets != 955845802 {
+		t.Errorf("eth0 = %+v", eth0)
+	}
+	if counters["lo"].RxBytes != 232655232168 {
+		t.Errorf("lo = %+v", counters["lo"])
+	}
+}
+
+func TestParseProcCgroup(t *testing.T) {
+	version, paths := ParseProcCgroup("0::/system.slice/tb-manage.service\n")
+	if version != 2 || paths[""] != "/system.slice/tb-manage.service" {
+		t.Errorf("v2: version=%d paths=%v", version, paths)
+	}
+
+	// cgroup v1 (hybrid: also has a 0:: unified entry)
+	v1 := `12:pids:/docker/abc123
+5:memory:/docker/abc123
+4:cpu,cpuacct:/docker/abc123
+1:name=systemd:/docker/abc123
+0::/docker/abc123
+`
+	version, paths = ParseProcCgroup(v1)
+	if version != 1 {
+		t.Errorf("v1: version = %d, want 1", version)
+	}
+	if paths["memory"] != "/docker/abc123" || paths["cpu"] != "/docker/abc123" || paths["cpuacct"] != "/docker/abc123" {
+		t.Errorf("v1: paths = %v", paths)
+	}
+	if _, ok := paths["name=systemd"]; ok {
+		t.Error("named hierarchies should be skipped")
+	}
+}
+
+func TestParseCgroupV2Limits(t *testing.T) {
+	limits := ParseCgroupV2Limits("536870912\n", "150000 100000\n")
+	if limits.MemoryBytes != 536870912 || limits.CPUs != 1.5 {
+		t.Errorf("limited = %+v", limits)
+	}
+
+	limits = ParseCgroupV2Limits("max\n", "max 100000\n")
+	if limits.MemoryBytes != 0 || limits.CPUs != 0 {
+		t.Errorf("unlimited = %+v", limits)
+	}
+}
+
+func TestParseCgroupV1Limits(t *testing.T) {
+	limits := ParseCgroupV1Limits("1073741824\n", "200000\n", "100000\n")
+	if limits.MemoryBytes != 1073741824 || limits.CPUs != 2 {
+		t.Errorf("limited = %+v", limits)
+	}
+
+	limits = ParseCgroupV1Limits("9223372036854771712\n", "-1\n", "100000\n")
+	if limits.MemoryBytes != 0 || limits.CPUs != 0 {
+		t.Errorf("unlimited = %+v", limits)
+	}
+}
+
+func TestParsePmsetBatt(t *testing.T) {
+	laptop := "Now drawing from 'Battery Power'\n" +
+		" -InternalBattery-0 (id=4653155)\t87%; discharging; 5:12 remaining present: true\n"
+	batt := ParsePmsetBatt(laptop)
+	if batt.Source != "Battery Power" || !batt.Present || batt.Percent != 87 || batt.Charging != "discharging" {
+		t.Errorf("laptop = %+v", batt)
+	}
+
+	charged := "Now drawing from 'AC Power'\n" +
+		" -InternalBattery-0 (id=4653155)\t100%; charged; 0:00 remaining present: true\n"
+	batt = ParsePmsetBatt(charged)
+	if batt.Source != "AC Power" || batt.Percent != 100 || batt.Charging != "charged" {
+		t.Errorf("charged = %+v", batt)
+	}
+
+	// Mac mini: no battery, only the power source
+	batt = ParsePmsetBatt("Now drawing from 'AC Power'\n")
+	if batt.Source != "AC Power" || batt.Present || batt.Percent != 0 {
+		t.Errorf("mini = %+v", batt)
+	}
+}
+
+func TestParsePsZombies(t *testing.T) {
+	output := `STAT  PPID
+Ss       0
+S        2
+Z     4182
+Ss+   4182
+Z+    4182
+Zs    4182
+R+     911
+Z      911
+I<       2
+`
+	z := ParsePsZombies(output)
+	if z.Count != 4 {
+		t.Errorf("Count = %d, want 4", z.Count)
+	}
+	if z.Parents[4182] != 3 || z.Parents[911] != 1 {
+		t.Errorf("Parents = %v, want 4182:3 911:1", z.Parents)
+	}
+	if got := z.WorstParent(); got != 4182 {
+		t.Errorf("WorstParent = %d, want 4182", got)
+	}
+
+	none := ParsePsZombies("STAT  PPID\nSs       0\nR+     911\n")
+	if none.Count != 0 || none.WorstParent() != 0 {
+		t.Errorf("no zombies: %+v", none)
+	}
+}
+
+func TestParseSsListeners(t *testing.T) {
+	output := `State  Recv-Q Send-Q Local Address:Port  Peer Address:Port Process
+LISTEN 0      4096   127.0.0.53%lo:53      0.0.0.0:*     users:(("systemd-resolve",pid=612,fd=14))
+LISTEN 0      128    0.0.0.0:22            0.0.0.0:*     users:(("sshd",pid=1001,fd=3),("sshd",pid=1002,fd=3))
+LISTEN 0      4096   [::]:6443             [::]:*
+LISTEN 0      511    *:80                  *:*           users:(("nginx: master pr",pid=2201,fd=6))
+`
+	got := ParseSsListeners(output)
+	want := []Listener{
+		{Address: "127.0.0.53", Port: 53, Process: "systemd-resolve", PID: 612},
+		{Address: "0.0.0.0", Port: 22, Process: "sshd", PID: 1001},
+		{Address: "::", Port: 6443},
+		{Address: "*", Port: 80, Process: "nginx: master pr", PID: 2201},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSsListeners =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseLsofListeners(t *testing.T) {
+	output := `COMMAND     PID  USER   FD   TYPE             DEVICE SIZE/OFF NODE NAME
+launchd       1  root   11u  IPv6 0x8e1f2a3b4c5d6e7f      0t0  TCP *:22 (LISTEN)
+launchd       1  root   12u  IPv4 0x8e1f2a3b4c5d6e80      0t0  TCP *:22 (LISTEN)
+Code\x20Helper 4411  dev   40u  IPv4 0x8e1f2a3b4c5d6e81      0t0  TCP 127.0.0.1:52011 (LISTEN)
+ControlCe   602   dev    9u  IPv6 0x8e1f2a3b4c5d6e82      0t0  TCP [::1]:7000 (LISTEN)
+`
+	got := ParseLsofListeners(output)
+	want := []Listener{
+		{Address: "*", Port: 22, Process: "launchd", PID: 1},
+		{Address: "127.0.0.1", Port: 52011, Process: "Code Helper", PID: 4411},
+		{Address: "::1", Port: 7000, Process: "ControlCe", PID: 602},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLsofListeners =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseNvidiaSmiCSV(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/nvidia_smi_gpus.csv")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	gpus := ParseNvidiaSmiCSV(string(data))
+	if len(gpus) != 3 {
+		t.Fatalf("expected 3 GPUs, got %d: %+v", len(gpus), gpus)
+	}
+	want := GPU{Vendor: "NVIDIA", Model: "NVIDIA A100-SXM4-80GB", MemoryBytes: 81920 << 20, Driver: "535.129.03"}
+	if gpus[0] != want {
+		t.Errorf("gpus[0] = %+v, want %+v", gpus[0], want)
+	}
+	if gpus[2].Model != "Tesla T4" || gpus[2].MemoryBytes != 15360<<20 {
+		t.Errorf("gpus[2] = %+v", gpus[2])
+	}
+	if got := ParseNvidiaSmiCSV("NVIDIA-SMI has failed because it couldn't communicate with the NVIDIA driver.\n"); len(got) != 0 {
+		t.Errorf("expected no GPUs from error output, got %+v", got)
+	}
+}
+
+func TestParseLspciGPUs(t *testing.T) {
+	output := `00:00.0 "Host bridge" "Intel Corporation" "8th Gen Core Processor Host Bridge/DRAM Registers" -r07 "Dell" "Device 0869"
+03:00.0 "VGA compatible controller" "Advanced Micro Devices, Inc. [AMD/ATI]" "Navi 21 [Radeon RX 6800/6800 XT / 6900 XT]" -rc1 "Sapphire Technology Limited" "Nitro+ Radeon RX 6800 XT"
+00:02.0 "Display controller" "Intel Corporation" "UHD Graphics 770" -r0c "ASUSTeK Computer Inc." "Device 8882"
+`
+	got := ParseLspciGPUs(output)
+	want := []GPU{
+		{Vendor: "AMD", Model: "Navi 21 [Radeon RX 6800/6800 XT / 6900 XT]"},
+		{Vendor: "Intel", Model: "UHD Graphics 770"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLspciGPUs =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseLspciDevices(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/lspci_mm.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	devices := ParseLspciDevices(string(data))
+	if len(devices) != 8 {
+		t.Fatalf("expected 8 devices, got %d: %+v", len(devices), devices)
+	}
+	want := PCIDevice{Slot: "3b:00.0", Class: "Ethernet controller", Vendor: "Intel Corporation", Device: "Ethernet Controller X710 for 10GbE SFP+"}
+	if devices[4] != want {
+		t.Errorf("devices[4] = %+v, want %+v", devices[4], want)
+	}
+	// Vendor with an embedded comma and empty subsystem fields
+	want = PCIDevice{Slot: "d8:00.0", Class: "Processing accelerators", Vendor: "Advanced Micro Devices, Inc. [AMD]", Device: "Aldebaran/MI200 [Instinct MI210]"}
+	if devices[7] != want {
+		t.Errorf("devices[7] = %+v, want %+v", devices[7], want)
+	}
+	if got := ParseLspciDevices("bash: lspci: command not found\n"); len(got) != 0 {
+		t.Errorf("expected no devices from error output, got %+v", got)
+	}
+}
+
+func TestParseSysThermal(t *testing.T) {
+	output := `/sys/class/thermal/thermal_zone0/type:acpitz
+/sys/class/thermal/thermal_zone0/temp:27800
+/sys/class/thermal/thermal_zone1/type:x86_pkg_temp
+/sys/class/thermal/thermal_zone1/temp:52000
+/sys/class/thermal/thermal_zone2/type:iwlwifi_1
+/sys/class/hwmon/hwmon2/name:coretemp
+/sys/class/hwmon/hwmon2/temp1_label:Package id 0
+/sys/class/hwmon/hwmon2/temp1_input:53000
+/sys/class/hwmon/hwmon2/temp2_input:49500
+/sys/class/hwmon/hwmon3/name:nvme
+/sys/class/hwmon/hwmon3/temp1_input:-273200
+`
+	got := ParseSysThermal(output)
+	want := []ThermalSensor{
+		{Name: "acpitz", Celsius: 27.8},
+		{Name: "coretemp/Package id 0", Celsius: 53},
+		{Name: "coretemp/temp2", Celsius: 49.5},
+		{Name: "x86_pkg_temp", Celsius: 52},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSysThermal =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := ParseSysThermal(""); len(got) != 0 {
+		t.Errorf("expected no sensors from empty output, got %+v", got)
+	}
+}
+
+func TestParseSysPowerSupply(t *testing.T) {
+	output := `/sys/class/power_supply/AC/type:Mains
+/sys/class/power_supply/AC/online:0
+/sys/class/power_supply/BAT0/type:Battery
+/sys/class/power_supply/BAT0/scope:System
+/sys/class/power_supply/BAT0/capacity:64
+/sys/class/power_supply/BAT0/status:Discharging
+/sys/class/power_supply/hidpp_battery_0/type:Battery
+/sys/class/power_supply/hidpp_battery_0/scope:Device
+/sys/class/power_supply/hidpp_battery_0/capacity:90
+`
+	got := ParseSysPowerSupply(output)
+	want := []PowerSupply{
+		{Name: "AC", Type: "Mains", Capacity: -1},
+		{Name: "BAT0", Type: "Battery", Capacity: 64, Status: "Discharging"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSysPowerSupply =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := ParseSysPowerSupply(""); len(got) != 0 {
+		t.Errorf("expected no supplies from empty output, got %+v", got)
+	}
+}
+
+func TestParsePowermetricsTemps(t *testing.T) {
+	output := `*** Sampled system activity (Wed Oct 16 10:00:00 2026 -0700) (1003.21ms elapsed) ***
+
+**** SMC sensors ****
+
+CPU Thermal level: 0
+GPU Thermal level: 0
+Fan: 1200.52 rpm
+CPU die temperature: 45.67 C
+GPU die temperature: 41.02 C
+`
+	got := ParsePowermetricsTemps(output)
+	want := []ThermalSensor{
+		{Name: "CPU die", Celsius: 45.67},
+		{Name: "GPU die", Celsius: 41.02},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePowermetricsTemps = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsePSPhysicalDisks(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/ps_physicaldisk.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	disks := ParsePSPhysicalDisks(data)
+	if len(disks) != 3 {
+		t.Fatalf("expected 3 disks, got %d: %+v", len(disks), disks)
+	}
+	want := PSPhysicalDisk{
+		DeviceID:     "1",
+		FriendlyName: "ST4000DM004-2CV104",
+		SerialNumber: "ZFN0ABCD",
+		Size:         4000787030016,
+		MediaType:    "HDD",
+		BusType:      "SATA",
+		HealthStatus: "Warning",
+	}
+	if disks[1] != want {
+		t.Errorf("disk 1 = %+v, want %+v", disks[1], want)
+	}
+
+	// A single disk is serialized as a bare object
+	one := ParsePSPhysicalDisks([]byte(`{"DeviceId":"0","FriendlyName":"Msft Virtual Disk","Size":136365211648,"MediaType":"Unspecified","BusType":"SAS"}`))
+	if len(one) != 1 || one[0].FriendlyName != "Msft Virtual Disk" {
+		t.Errorf("single object not parsed: %+v", one)
+	}
+}
+
+func TestParsePSVolumes(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/ps_volume.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	volumes := ParsePSVolumes(data)
+	if len(volumes) != 2 {
+		t.Fatalf("expected C and D only, got %+v", volumes)
+	}
+	if volumes[0].DriveLetter != "C" || volumes[0].FileSystem != "NTFS" || volumes[0].SizeRemaining != 412391718912 {
+		t.Errorf("unexpected C volume: %+v", volumes[0])
+	}
+	if volumes[1].DriveLetter != "D" || volumes[1].FileSystemLabel != "Data" {
+		t.Errorf("unexpected D volume: %+v", volumes[1])
+	}
+}
+
+func TestParsePSInterfaces(t *testing.T) {
+	adapters, err := os.ReadFile("../../../testdata/ps_netadapter.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	addresses, err := os.ReadFile("../../../testdata/ps_netipaddress.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	got := ParsePSInterfaces(adapters, addresses)
+	want := []InterfaceInfo{
+		{Name: "Ethernet", IP: "192.168.1.20", IPv6: "2001:db8::15", MAC: "00:15:5d:01:02:03", MTU: 1500, State: "up", Type: "physical"},
+		{Name: "Wi-Fi", MAC: "a4:b1:c1:22:33:44", MTU: 1500, State: "down", Type: "wireless"},
+		{Name: "vEthernet (Default Switch)", IP: "172.29.64.1", MAC: "00:15:5d:aa:bb:cc", MTU: 1500, State: "up", Type: "virtual"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePSInterfaces =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParsePSSystem(t *testing.T) {
+	sys, ok := ParsePSSystem([]byte(`{"Caption":"Microsoft Windows Server 2022 Standard","Version":"10.0.20348","CPUModel":"Intel(R) Xeon(R) Silver 4214R CPU @ 2.40GHz ","Cores":24,"MemoryBytes":137307746304,"SerialNumber":"CZ2D1A0B3C","UUID":"4C4C4544-0042-3510-8052-B4C04F4D4E32"}`))
+	if !ok {
+		t.Fatal("expected system to parse")
+	}
+	if sys.Cores != 24 || sys.CPUModel != "Intel(R) Xeon(R) Silver 4214R CPU @ 2.40GHz" || sys.MemoryBytes != 137307746304 {
+		t.Errorf("unexpected system: %+v", sys)
+	}
+	if _, ok := ParsePSSystem([]byte("")); ok {
+		t.Error("empty output should not parse")
+	}
+}
+
+func TestParseZpoolList(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/zpool_list.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	pools := ParseZpoolList(string(data))
+	want := []ZPool{
+		{Name: "tank", SizeBytes: 3985729650688, AllocBytes: 1204521369600, FreeBytes: 2781208281088, Health: "ONLINE"},
+		{Name: "backup", SizeBytes: 11987721109504, AllocBytes: 9831072645120, FreeBytes: 2156648464384, Health: "DEGRADED"},
+	}
+	if !reflect.DeepEqual(pools, want) {
+		t.Errorf("ParseZpoolList =\n%+v\nwant\n%+v", pools, want)
+	}
+
+	if got := ParseZpoolList("no pools available\n"); len(got) != 0 {
+		t.Errorf("expected no pools, got %+v", got)
+	}
+}
+
+func TestParseZpoolStatus(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/zpool_status.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	got := ParseZpoolStatus(string(data))
+	want := map[string]ZPoolLayout{
+		"backup": {RaidLevel: "raidz2", Members: []string{"/dev/sdd1", "/dev/sde1", "/dev/sdf1", "/dev/sdg1"}},
+		"tank":   {RaidLevel: "mirror", Members: []string{"/dev/sda1", "/dev/sdb1"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseZpoolStatus =\n%+v\nwant\n%+v", got, want)
+	}
+
+	stripe := "  pool: scratch\n state: ONLINE\nconfig:\n\n\tNAME         STATE     READ WRITE CKSUM\n\tscratch      ONLINE       0     0     0\n\t  /dev/sdj   ONLINE       0     0     0\n\t  /dev/sdk   ONLINE       0     0     0\n\nerrors: No known data errors\n"
+	if l := ParseZpoolStatus(stripe)["scratch"]; l.RaidLevel != "stripe" || len(l.Members) != 2 {
+		t.Errorf("unexpected stripe layout: %+v", l)
+	}
+}
+
+func TestParseBtrfsShow(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/btrfs_show.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	got := ParseBtrfsShow(string(data))
+	want := []BtrfsFilesystem{
+		{Label: "data", UUID: "6f3b0a6e-4d2c-4b7e-9a51-2f0c8e1d7b3a", UsedBytes: 1351079936000, TotalBytes: 8001574060032, Devices: []string{"/dev/sdh", "/dev/sdi"}},
+		{UUID: "0c1d2e3f-5a6b-4c7d-8e9f-a0b1c2d3e4f5", UsedBytes: 8912896000, TotalBytes: 107374182400, Devices: []string{"/dev/nvme0n1p3"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseBtrfsShow =\n%+v\nwant\n%+v", got, want)
+	}
+
+	df := "Data, RAID1: total=1.23TiB, used=1.22TiB\nSystem, RAID1: total=32.00MiB, used=192.00KiB\nMetadata, RAID1C3: total=3.00GiB, used=1.91GiB\n"
+	if p := ParseBtrfsDataProfile(df); p != "raid1" {
+		t.Errorf("data profile = %q, want raid1", p)
+	}
+}
+
+func TestParseVgs(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/vgs_report.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	got := ParseVgs(data)
+	want := []LVMVolumeGroup{
+		{Name: "data", SizeBytes: 3999956729856, FreeBytes: 999989182464, PVs: []string{"/dev/sdb", "/dev/sdc"}},
+		{Name: "ubuntu-vg", SizeBytes: 498921046016, FreeBytes: 284172681216, PVs: []string{"/dev/nvme0n1p3"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseVgs =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := ParseVgs([]byte(`{"report":[{"vg":[]}]}`)); len(got) != 0 {
+		t.Errorf("expected no volume groups, got %+v", got)
+	}
+}
+
+func TestParseLvs(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/lvs_report.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	got := ParseLvs(data)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 logical volumes, got %d: %+v", len(got), got)
+	}
+	want := LVMLogicalVolume{Name: "root", VG: "ubuntu-vg", SizeBytes: 107374182400, Path: "/dev/ubuntu-vg/root", DMPath: "/dev/mapper/ubuntu--vg-root"}
+	if got[1] != want {
+		t.Errorf("got[1] = %+v, want %+v", got[1], want)
+	}
+	if lv := ParseLvs([]byte(`{"report":[{"lv":[{"lv_name":"x","vg_name":"v","lv_size":"4194304B"}]}]}`)); len(lv) != 1 || lv[0].SizeBytes != 4194304 {
+		t.Errorf("unexpected suffixed size parse: %+v", lv)
+	}
+}
+
+func TestParseMeminfo(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/proc_meminfo.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	mem := ParseMeminfo(string(data))
+	if got := mem["MemTotal"]; got != 16318412*1024 {
+		t.Errorf("MemTotal = %d", got)
+	}
+	if got := mem["SwapTotal"]; got != 2097148*1024 {
+		t.Errorf("SwapTotal = %d", got)
+	}
+	if got := mem["SwapFree"]; got != 1572860*1024 {
+		t.Errorf("SwapFree = %d", got)
+	}
+	if got, ok := mem["HugePages_Total"]; !ok || got != 0 {
+		t.Errorf("HugePages_Total = %d, %v", got, ok)
+	}
+}
+
+func TestParseSwapUsage(t *testing.T) {
+	total, used, free := ParseSwapUsage("total = 2048.00M  used = 1126.25M  free = 921.75M  (encrypted)\n")
+	if total != 2048<<20 || used != 1126.25*(1<<20) || free != 921.75*(1<<20) {
+		t.Errorf("ParseSwapUsage = %d, %d, %d", total, used, free)
+	}
+	total, used, free = ParseSwapUsage("total = 0.00M  used = 0.00M  free = 0.00M  (encrypted)")
+	if total != 0 || used != 0 || free != 0 {
+		t.Errorf("expected zero swap, got %d, %d, %d", total, used, free)
+	}
+}
+
+func TestParseCPUFreq(t *testing.T) {
+	output := `/sys/devices/system/cpu/cpu0/cpufreq/scaling_max_freq:3400000
+/sys/devices/system/cpu/cpu0/cpufreq/scaling_governor:powersave
+`
+	mhz, gov := ParseCPUFreq(output)
+	if mhz != 3400 || gov != "powersave" {
+		t.Errorf("ParseCPUFreq = %v, %q, want 3400, powersave", mhz, gov)
+	}
+
+	// VMs without cpufreq: grep prints nothing
+	if mhz, gov := ParseCPUFreq(""); mhz != 0 || gov != "" {
+		t.Errorf("expected zero values, got %v, %q", mhz, gov)
+	}
+}
+
+func TestParseLscpuNUMA(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/lscpu_numa.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	got := ParseLscpuNUMA(data)
+	want := []NUMANode{
+		{ID: 0, CPUs: "0-15,32-47"},
+		{ID: 1, CPUs: "16-31,48-63"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLscpuNUMA = %+v, want %+v", got, want)
+	}
+
+	// util-linux 2.38+ nests sections under "children"
+	nested := `{"lscpu": [{"field": "NUMA:", "data": null, "children": [
+		{"field": "NUMA node(s):", "data": "1"},
+		{"field": "NUMA node0 CPU(s):", "data": "0-3"}]}]}`
+	if got := ParseLscpuNUMA([]byte(nested)); !reflect.DeepEqual(got, []NUMANode{{ID: 0, CPUs: "0-3"}}) {
+		t.Errorf("nested ParseLscpuNUMA = %+v", got)
+	}
+
+	if got := ParseLscpuNUMA([]byte(`{"lscpu": [{"field": "CPU(s):", "data": "4"}]}`)); got != nil {
+		t.Errorf("expected no nodes, got %+v", got)
+	}
+}
+
+func TestParseNodeMeminfo(t *testing.T) {
+	output := `Node 0 MemTotal:       65789012 kB
+Node 1 MemTotal:       66060288 kB
+`
+	got := ParseNodeMeminfo(output)
+	want := map[int]int64{0: 65789012 * 1024, 1: 66060288 * 1024}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseNodeMeminfo = %v, want %v", got, want)
+	}
+}
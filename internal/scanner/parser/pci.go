@@ -0,0 +1,36 @@
+package parser
+
+import "strings"
+
+// PCIDevice is one device from `lspci -mm`.
+type PCIDevice struct {
+	Slot   string // bus address, e.g. "3b:00.0" or "0000:3b:00.0"
+	Class  string
+	Vendor string
+	Device string
+}
+
+// ParseLspciDevices parses `lspci -mm` output. Fields are quoted, so
+// vendor names with commas stay intact:
+//
+//	3b:00.0 "Ethernet controller" "Intel Corporation" "Ethernet Controller X710 for 10GbE SFP+" -r02 "Intel Corporation" "Ethernet 10G 2P X710 Adapter"
+func ParseLspciDevices(output string) []PCIDevice {
+	var devices []PCIDevice
+	for _, line := range strings.Split(output, "\n") {
+		slot, rest, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		fields := quotedFields(rest)
+		if len(fields) < 3 {
+			continue
+		}
+		devices = append(devices, PCIDevice{
+			Slot:   slot,
+			Class:  fields[0],
+			Vendor: fields[1],
+			Device: fields[2],
+		})
+	}
+	return devices
+}
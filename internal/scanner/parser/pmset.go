@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PmsetBattery is the power state reported by `pmset -g batt`.
+type PmsetBattery struct {
+	Source   string // "AC Power", "Battery Power" or "UPS Power"
+	Present  bool   // an internal battery or UPS reports a charge
+	Percent  int
+	Charging string // "charging", "discharging", "charged", "AC attached", ...
+}
+
+var (
+	pmsetSourceRe = regexp.MustCompile(`drawing from '([^']+)'`)
+	pmsetChargeRe = regexp.MustCompile(`(\d+)%;\s*([^;]+);`)
+)
+
+// ParsePmsetBatt parses `pmset -g batt` output. Machines without a battery
+// (e.g. Mac minis) only report the power source.
+//
+//	Now drawing from 'Battery Power'
+//	 -InternalBattery-0 (id=4653155)	87%; discharging; 5:12 remaining present: true
+func ParsePmsetBatt(output string) PmsetBattery {
+	var batt PmsetBattery
+	if m := pmsetSourceRe.FindStringSubmatch(output); m != nil {
+		batt.Source = m[1]
+	}
+	if m := pmsetChargeRe.FindStringSubmatch(output); m != nil {
+		if pct, err := strconv.Atoi(m[1]); err == nil {
+			batt.Present = true
+			batt.Percent = pct
+			batt.Charging = strings.TrimSpace(m[2])
+		}
+	}
+	return batt
+}
@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ZPool is one ZFS pool from `zpool list -Hp`.
+type ZPool struct {
+	Name       string
+	SizeBytes  int64
+	AllocBytes int64
+	FreeBytes  int64
+	Health     string // ONLINE, DEGRADED, FAULTED, ...
+}
+
+// ParseZpoolList parses `zpool list -Hp` (tab-separated, exact byte
+// counts, default columns):
+//
+//	NAME SIZE ALLOC FREE CKPOINT EXPANDSZ FRAG CAP DEDUP HEALTH ALTROOT
+//	tank	3985729650688	1204521369600	2781208281088	-	-	3	30	1.00	ONLINE	-
+func ParseZpoolList(output string) []ZPool {
+	var pools []ZPool
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 10 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		alloc, _ := strconv.ParseInt(fields[2], 10, 64)
+		free, _ := strconv.ParseInt(fields[3], 10, 64)
+		pools = append(pools, ZPool{
+			Name:       fields[0],
+			SizeBytes:  size,
+			AllocBytes: alloc,
+			FreeBytes:  free,
+			Health:     fields[9],
+		})
+	}
+	return pools
+}
+
+// ZPoolLayout is the vdev layout of one pool from `zpool status -PL`.
+type ZPoolLayout struct {
+	RaidLevel string   // mirror, raidz1, raidz2, raidz3, draid, stripe; mixed layouts are joined with "+"
+	Members   []string // data device paths, e.g. /dev/sda1; log, cache and spare devices excluded
+}
+
+// ParseZpoolStatus parses `zpool status -PL` into per-pool layouts. The
+// config section is indented by nesting depth:
+//
+//	NAME           STATE     READ WRITE CKSUM
+//	tank           ONLINE       0     0     0
+//	  mirror-0     ONLINE       0     0     0
+//	    /dev/sda1  ONLINE       0     0     0
+//	    /dev/sdb1  ONLINE       0     0     0
+//	logs
+//	  /dev/nvme0n1p1  ONLINE    0     0     0
+func ParseZpoolStatus(output string) map[string]ZPoolLayout {
+	layouts := make(map[string]ZPoolLayout)
+	var (
+		pool      string
+		inConfig  bool
+		poolDepth int
+		inData    bool
+		levels    map[string]bool
+		layout    ZPoolLayout
+	)
+	flush := func() {
+		if pool == "" {
+			return
+		}
+		var names []string
+		for l := range levels {
+			names = append(names, l)
+		}
+		sort.Strings(names)
+		layout.RaidLevel = strings.Join(names, "+")
+		layouts[pool] = layout
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "pool:") {
+			flush()
+			pool = strings.TrimSpace(strings.TrimPrefix(trimmed, "pool:"))
+			inConfig = false
+			levels = make(map[string]bool)
+			layout = ZPoolLayout{}
+			continue
+		}
+		if pool == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "config:") {
+			inConfig = true
+			continue
+		}
+		if strings.HasPrefix(trimmed, "errors:") {
+			inConfig = false
+			continue
+		}
+		if !inConfig || trimmed == "" {
+			continue
+		}
+
+		fields := strings.Fields(trimmed)
+		name := fields[0]
+		depth := len(strings.ReplaceAll(line, "\t", "")) - len(strings.TrimLeft(strings.ReplaceAll(line, "\t", ""), " "))
+		switch {
+		case name == "NAME":
+			continue
+		case name == pool:
+			poolDepth, inData = depth, true
+			continue
+		case depth <= poolDepth:
+			// logs, cache, spares, special and dedup sections
+			inData = false
+			continue
+		case !inData:
+			continue
+		}
+
+		if !strings.HasPrefix(name, "/") {
+			// Grouping vdevs; replacing-N and spare-N wrap a disk mid-resilver
+			if level := zfsVdevLevel(name); level != "" || strings.HasPrefix(name, "replacing-") || strings.HasPrefix(name, "spare-") {
+				if level != "" && depth == poolDepth+2 {
+					levels[level] = true
+				}
+				continue
+			}
+		}
+		if depth == poolDepth+2 {
+			levels["stripe"] = true // disk directly under the pool
+		}
+		layout.Members = append(layout.Members, name)
+	}
+	flush()
+	return layouts
+}
+
+// zfsVdevLevel returns the redundancy of a vdev name like "raidz2-0", or
+// "" for a leaf device.
+func zfsVdevLevel(name string) string {
+	base, _, _ := strings.Cut(name, "-")
+	switch base {
+	case "mirror", "raidz1", "raidz2", "raidz3":
+		return base
+	case "raidz":
+		return "raidz1"
+	case "draid", "draid1", "draid2", "draid3":
+		return "draid"
+	}
+	return ""
+}
+
+// BtrfsFilesystem is one filesystem from `btrfs filesystem show --raw`.
+type BtrfsFilesystem struct {
+	Label      string
+	UUID       string
+	UsedBytes  int64
+	TotalBytes int64    // sum of member device sizes
+	Devices    []string // device paths
+}
+
+// ParseBtrfsShow parses `btrfs filesystem show --raw`:
+//
+//	Label: 'data'  uuid: 6f3b0a6e-...
+//		Total devices 2 FS bytes used 1351079936000
+//		devid    1 size 4000787030016 used 1352663859200 path /dev/sdb
+//		devid    2 size 4000787030016 used 1352663859200 path /dev/sdc
+func ParseBtrfsShow(output string) []BtrfsFilesystem {
+	var filesystems []BtrfsFilesystem
+	var cur *BtrfsFilesystem
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 4 && fields[0] == "Label:":
+			filesystems = append(filesystems, BtrfsFilesystem{})
+			cur = &filesystems[len(filesystems)-1]
+			if fields[1] != "none" {
+				cur.Label = strings.Trim(fields[1], "'")
+			}
+			for i := 2; i < len(fields)-1; i++ {
+				if fields[i] == "uuid:" {
+					cur.UUID = fields[i+1]
+				}
+			}
+		case cur == nil:
+			continue
+		case len(fields) >= 7 && fields[0] == "Total" && fields[5] == "used":
+			cur.UsedBytes, _ = strconv.ParseInt(fields[6], 10, 64)
+		case len(fields) >= 8 && fields[0] == "devid":
+			for i := 2; i < len(fields)-1; i++ {
+				switch fields[i] {
+				case "size":
+					n, _ := strconv.ParseInt(fields[i+1], 10, 64)
+					cur.TotalBytes += n
+				case "path":
+					cur.Devices = append(cur.Devices, fields[i+1])
+				}
+			}
+		}
+	}
+	return filesystems
+}
+
+// ParseBtrfsDataProfile returns the data profile ("raid1", "single", ...)
+// from `btrfs filesystem df` output:
+//
+//	Data, RAID1: total=1.23TiB, used=1.22TiB
+func ParseBtrfsDataProfile(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		kind, rest, ok := strings.Cut(strings.TrimSpace(line), ",")
+		if !ok || kind != "Data" {
+			continue
+		}
+		profile, _, _ := strings.Cut(strings.TrimSpace(rest), ":")
+		return strings.ToLower(profile)
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Listener is a listening TCP socket and, when visible, its owner.
+type Listener struct {
+	Address string // bind address without port; "*" for all
+	Port    int
+	Process string // empty when the owner is not visible (needs root)
+	PID     int
+}
+
+var ssUsersRe = regexp.MustCompile(`\(\("([^"]+)",pid=(\d+)`)
+
+// ParseSsListeners parses `ss -tlnp` output. Process info appears only for
+// sockets the caller may inspect; other users' sockets (all of them, when
+// not root) have no users:(...) column.
+//
+//	State  Recv-Q Send-Q Local Address:Port Peer Address:Port Process
+//	LISTEN 0      128    0.0.0.0:22         0.0.0.0:*         users:(("sshd",pid=1001,fd=3))
+//	LISTEN 0      4096   [::]:443           [::]:*
+func ParseSsListeners(output string) []Listener {
+	var listeners []Listener
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != "LISTEN" {
+			continue
+		}
+		addr, port, ok := splitHostPort(fields[3])
+		if !ok {
+			continue
+		}
+		l := Listener{Address: addr, Port: port}
+		// Only the first owner is kept; forked workers share the socket
+		if m := ssUsersRe.FindStringSubmatch(line); m != nil {
+			l.Process = m[1]
+			l.PID, _ = strconv.Atoi(m[2])
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners
+}
+
+// ParseLsofListeners parses `lsof -iTCP -sTCP:LISTEN -P -n` output. Unlike
+// ss, lsof omits sockets it cannot attribute, so without root only the
+// caller's own listeners are reported.
+//
+//	COMMAND   PID USER   FD   TYPE DEVICE             SIZE/OFF NODE NAME
+//	sshd      812 root    3u  IPv4 0x1a2b3c4d5e6f7a8b      0t0  TCP *:22 (LISTEN)
+func ParseLsofListeners(output string) []Listener {
+	var listeners []Listener
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 10 || fields[len(fields)-1] != "(LISTEN)" {
+			continue
+		}
+		addr, port, ok := splitHostPort(fields[len(fields)-2])
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		// One line per file descriptor; collapse duplicates
+		key := fields[1] + " " + fields[len(fields)-2]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		// lsof escapes spaces in command names as \x20
+		listeners = append(listeners, Listener{Address: addr, Port: port, Process: strings.ReplaceAll(fields[0], `\x20`, " "), PID: pid})
+	}
+	return listeners
+}
+
+// splitHostPort splits "0.0.0.0:22", "[::]:22", "*:22" or
+// "127.0.0.53%lo:53", dropping the zone and brackets.
+func splitHostPort(s string) (string, int, bool) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return "", 0, false
+	}
+	port, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	host := strings.Trim(s[:i], "[]")
+	if zone := strings.IndexByte(host, '%'); zone >= 0 {
+		host = host[:zone]
+	}
+	return host, port, true
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PowerSupply is one entry under /sys/class/power_supply.
+type PowerSupply struct {
+	Name     string // BAT0, AC, ADP1, ...
+	Type     string // "Battery", "Mains", "USB", ...
+	Online   bool   // Mains/USB: the adapter is plugged in
+	Capacity int    // Battery: charge in percent, -1 when unknown
+	Status   string // Battery: "Charging", "Discharging", "Full", ...
+}
+
+// ParseSysPowerSupply parses `grep -H . <files>` output over
+// /sys/class/power_supply/*/{type,scope,online,capacity,status}. Supplies
+// with scope "Device" (wireless mice, keyboards, ...) are not the host's
+// own power and are skipped.
+//
+//	/sys/class/power_supply/AC/type:Mains
+//	/sys/class/power_supply/AC/online:0
+//	/sys/class/power_supply/BAT0/type:Battery
+//	/sys/class/power_supply/BAT0/capacity:64
+//	/sys/class/power_supply/BAT0/status:Discharging
+func ParseSysPowerSupply(output string) []PowerSupply {
+	attrs := make(map[string]map[string]string) // supply -> attribute -> value
+	for _, line := range strings.Split(output, "\n") {
+		file, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		dir, attr := path.Split(file)
+		name := path.Base(dir)
+		if attrs[name] == nil {
+			attrs[name] = make(map[string]string)
+		}
+		attrs[name][attr] = strings.TrimSpace(value)
+	}
+
+	var supplies []PowerSupply
+	for name, a := range attrs {
+		if a["scope"] == "Device" {
+			continue
+		}
+		ps := PowerSupply{Name: name, Type: a["type"], Online: a["online"] == "1", Capacity: -1, Status: a["status"]}
+		if n, err := strconv.Atoi(a["capacity"]); err == nil {
+			ps.Capacity = n
+		}
+		supplies = append(supplies, ps)
+	}
+	sort.Slice(supplies, func(i, j int) bool { return supplies[i].Name < supplies[j].Name })
+	return supplies
+}
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// PowerShell's ConvertTo-Json writes a single object instead of a
+// one-element array unless the caller wraps the input, and older
+// PowerShell versions ignore the wrapping in pipelines. psArray accepts
+// both shapes.
+func psArray[T any](data []byte) []T {
+	data = []byte(strings.TrimSpace(string(data)))
+	if len(data) == 0 {
+		return nil
+	}
+	if data[0] == '{' {
+		var one T
+		if err := json.Unmarshal(data, &one); err != nil {
+			return nil
+		}
+		return []T{one}
+	}
+	var many []T
+	if err := json.Unmarshal(data, &many); err != nil {
+		return nil
+	}
+	return many
+}
+
+// PSSystem is the object printed by the Windows host script, assembled
+// from Win32_OperatingSystem, Win32_Processor, Win32_ComputerSystem,
+// Win32_BIOS and Win32_ComputerSystemProduct.
+type PSSystem struct {
+	Caption      string `json:"Caption"` // "Microsoft Windows Server 2022 Standard"
+	Version      string `json:"Version"` // "10.0.20348"
+	CPUModel     string `json:"CPUModel"`
+	Cores        int    `json:"Cores"` // physical cores across sockets
+	MemoryBytes  int64  `json:"MemoryBytes"`
+	SerialNumber string `json:"SerialNumber"`
+	UUID         string `json:"UUID"`
+}
+
+// ParsePSSystem parses the Windows host script's JSON object.
+func ParsePSSystem(data []byte) (PSSystem, bool) {
+	systems := psArray[PSSystem](data)
+	if len(systems) == 0 {
+		return PSSystem{}, false
+	}
+	s := systems[0]
+	s.CPUModel = strings.TrimSpace(s.CPUModel)
+	s.SerialNumber = strings.TrimSpace(s.SerialNumber)
+	return s, true
+}
+
+// PSPhysicalDisk is one Get-PhysicalDisk entry. The script stringifies
+// the enum properties so they read "SSD", "NVMe", "Healthy".
+type PSPhysicalDisk struct {
+	DeviceID     string `json:"DeviceId"`
+	FriendlyName string `json:"FriendlyName"`
+	SerialNumber string `json:"SerialNumber"`
+	Size         int64  `json:"Size"`
+	MediaType    string `json:"MediaType"`    // HDD, SSD, SCM, Unspecified
+	BusType      string `json:"BusType"`      // NVMe, SATA, SAS, USB, ...
+	HealthStatus string `json:"HealthStatus"` // Healthy, Warning, Unhealthy
+}
+
+// ParsePSPhysicalDisks parses `Get-PhysicalDisk | ConvertTo-Json` output.
+func ParsePSPhysicalDisks(data []byte) []PSPhysicalDisk {
+	disks := psArray[PSPhysicalDisk](data)
+	for i := range disks {
+		disks[i].FriendlyName = strings.TrimSpace(disks[i].FriendlyName)
+		disks[i].SerialNumber = strings.TrimSpace(disks[i].SerialNumber)
+	}
+	return disks
+}
+
+// PSVolume is one Get-Volume entry with a drive letter.
+type PSVolume struct {
+	DriveLetter     string `json:"DriveLetter"`
+	FileSystemLabel string `json:"FileSystemLabel"`
+	FileSystem      string `json:"FileSystem"` // NTFS, ReFS, FAT32
+	Size            int64  `json:"Size"`
+	SizeRemaining   int64  `json:"SizeRemaining"`
+}
+
+// ParsePSVolumes parses `Get-Volume | ConvertTo-Json` output, skipping
+// volumes without a drive letter or size (recovery partitions, empty
+// optical drives).
+func ParsePSVolumes(data []byte) []PSVolume {
+	var volumes []PSVolume
+	for _, v := range psArray[PSVolume](data) {
+		if v.DriveLetter == "" || v.Size == 0 {
+			continue
+		}
+		volumes = append(volumes, v)
+	}
+	return volumes
+}
+
+// PSNetAdapter is one Get-NetAdapter entry.
+type PSNetAdapter struct {
+	Name                 string `json:"Name"`
+	InterfaceDescription string `json:"InterfaceDescription"`
+	MacAddress           string `json:"MacAddress"` // "00-15-5D-01-02-03"
+	Status               string `json:"Status"`     // Up, Disconnected, Disabled
+	MtuSize              int    `json:"MtuSize"`
+}
+
+// PSNetIPAddress is one Get-NetIPAddress entry.
+type PSNetIPAddress struct {
+	InterfaceAlias string `json:"InterfaceAlias"`
+	IPAddress      string `json:"IPAddress"`
+	AddressFamily  string `json:"AddressFamily"` // IPv4, IPv6
+	PrefixLength   int    `json:"PrefixLength"`
+}
+
+// ParsePSInterfaces joins Get-NetAdapter and Get-NetIPAddress output into
+// interfaces, keyed by adapter name (InterfaceAlias). Addresses on
+// interfaces without an adapter (loopback) are ignored, as are IPv6
+// link-local addresses.
+func ParsePSInterfaces(adapterData, addressData []byte) []InterfaceInfo {
+	adapters := psArray[PSNetAdapter](adapterData)
+	byName := make(map[string]*InterfaceInfo, len(adapters))
+	interfaces := make([]InterfaceInfo, len(adapters))
+	for i, a := range adapters {
+		interfaces[i] = InterfaceInfo{
+			Name:  a.Name,
+			MAC:   strings.ToLower(strings.ReplaceAll(a.MacAddress, "-", ":")),
+			MTU:   a.MtuSize,
+			State: psAdapterState(a.Status),
+			Type:  psAdapterType(a.InterfaceDescription),
+		}
+		byName[a.Name] = &interfaces[i]
+	}
+
+	for _, addr := range psArray[PSNetIPAddress](addressData) {
+		iface, ok := byName[addr.InterfaceAlias]
+		if !ok {
+			continue
+		}
+		ip, _, _ := strings.Cut(addr.IPAddress, "%") // drop IPv6 zone
+		switch addr.AddressFamily {
+		case "IPv4":
+			if iface.IP == "" {
+				iface.IP = ip
+			}
+		case "IPv6":
+			if iface.IPv6 == "" && !strings.HasPrefix(strings.ToLower(ip), "fe80:") {
+				iface.IPv6 = ip
+			}
+		}
+	}
+	return interfaces
+}
+
+func psAdapterState(status string) string {
+	if strings.EqualFold(status, "Up") {
+		return "up"
+	}
+	return "down"
+}
+
+// psAdapterType classifies an adapter by its driver description.
+func psAdapterType(description string) string {
+	d := strings.ToLower(description)
+	switch {
+	case strings.Contains(d, "wi-fi"), strings.Contains(d, "wireless"), strings.Contains(d, "802.11"):
+		return "wireless"
+	case strings.Contains(d, "vpn"), strings.Contains(d, "tap-windows"), strings.Contains(d, "wireguard"), strings.Contains(d, "wintun"):
+		return "tunnel"
+	case strings.Contains(d, "hyper-v"), strings.Contains(d, "virtual"), strings.Contains(d, "vmware"), strings.Contains(d, "virtio"):
+		return "virtual"
+	}
+	return "physical"
+}
+
+// PSNetRoute is one Get-NetRoute entry.
+type PSNetRoute struct {
+	DestinationPrefix string `json:"DestinationPrefix"`
+	NextHop           string `json:"NextHop"`
+	InterfaceAlias    string `json:"InterfaceAlias"`
+	RouteMetric       int    `json:"RouteMetric"`
+}
+
+// ParsePSRoutes parses `Get-NetRoute | ConvertTo-Json` output. An on-link
+// next hop (0.0.0.0 or ::) is reported without a gateway.
+func ParsePSRoutes(data []byte) []PSNetRoute {
+	routes := psArray[PSNetRoute](data)
+	for i := range routes {
+		if routes[i].NextHop == "0.0.0.0" || routes[i].NextHop == "::" {
+			routes[i].NextHop = ""
+		}
+	}
+	return routes
+}
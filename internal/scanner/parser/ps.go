@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PsZombies summarizes zombie (defunct) processes from `ps -eo stat,ppid`.
+type PsZombies struct {
+	Count int
+	// Parents maps each parent PID to how many zombie children it has not
+	// reaped.
+	Parents map[int]int
+}
+
+// ParsePsZombies counts processes whose state starts with Z in
+// `ps -eo stat,ppid` output (procps, BSD/macOS and BusyBox all accept it).
+//
+//	STAT  PPID
+//	Ss       0
+//	Z+    4182
+func ParsePsZombies(output string) PsZombies {
+	var z PsZombies
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], "Z") {
+			continue
+		}
+		ppid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		z.Count++
+		if z.Parents == nil {
+			z.Parents = make(map[int]int)
+		}
+		z.Parents[ppid]++
+	}
+	return z
+}
+
+// WorstParent returns the parent PID with the most zombie children, the
+// lowest PID on ties, or 0 when there are none.
+func (z PsZombies) WorstParent() int {
+	worst, most := 0, 0
+	for ppid, n := range z.Parents {
+		if n > most || (n == most && ppid < worst) {
+			worst, most = ppid, n
+		}
+	}
+	return worst
+}
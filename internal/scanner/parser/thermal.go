@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ThermalSensor is one temperature reading.
+type ThermalSensor struct {
+	Name    string // e.g. "x86_pkg_temp", "coretemp/Package id 0"
+	Celsius float64
+}
+
+// ParseSysThermal parses `grep -H . <files>` output over
+// /sys/class/thermal/thermal_zone*/{type,temp} and
+// /sys/class/hwmon/hwmon*/{name,temp*_input,temp*_label}. Temperatures are
+// in millidegrees Celsius. Zones without a readable temperature are
+// skipped, as are readings outside a plausible range (disconnected
+// sensors report values like -273.2 or 127).
+//
+//	/sys/class/thermal/thermal_zone0/type:x86_pkg_temp
+//	/sys/class/thermal/thermal_zone0/temp:52000
+//	/sys/class/hwmon/hwmon2/name:coretemp
+//	/sys/class/hwmon/hwmon2/temp1_label:Package id 0
+//	/sys/class/hwmon/hwmon2/temp1_input:53000
+func ParseSysThermal(output string) []ThermalSensor {
+	values := make(map[string]string) // file path -> first line
+	for _, line := range strings.Split(output, "\n") {
+		file, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if _, seen := values[file]; !seen {
+			values[file] = strings.TrimSpace(value)
+		}
+	}
+
+	var sensors []ThermalSensor
+	for file, value := range values {
+		dir, base := path.Split(file)
+		dir = strings.TrimSuffix(dir, "/")
+
+		var name string
+		switch {
+		case base == "temp" && strings.Contains(dir, "/thermal_zone"):
+			name = values[dir+"/type"]
+			if name == "" {
+				name = path.Base(dir)
+			}
+		case hwmonInputRe.MatchString(base):
+			chip := values[dir+"/name"]
+			if chip == "" {
+				chip = path.Base(dir)
+			}
+			label := values[dir+"/"+strings.TrimSuffix(base, "_input")+"_label"]
+			if label == "" {
+				label = strings.TrimSuffix(base, "_input")
+			}
+			name = chip + "/" + label
+		default:
+			continue
+		}
+
+		milli, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		celsius := milli / 1000
+		if celsius <= -40 || celsius >= 125 {
+			continue
+		}
+		sensors = append(sensors, ThermalSensor{Name: name, Celsius: celsius})
+	}
+
+	sort.Slice(sensors, func(i, j int) bool { return sensors[i].Name < sensors[j].Name })
+	return sensors
+}
+
+var (
+	hwmonInputRe     = regexp.MustCompile(`^temp\d+_input$`)
+	powermetricsTemp = regexp.MustCompile(`(?m)^(.+?) temperature:\s*([\d.]+)\s*C\s*$`)
+)
+
+// ParsePowermetricsTemps parses `powermetrics --samplers smc` output
+// (macOS, root only):
+//
+//	CPU die temperature: 45.67 C
+//	GPU die temperature: 41.02 C
+func ParsePowermetricsTemps(output string) []ThermalSensor {
+	var sensors []ThermalSensor
+	for _, m := range powermetricsTemp.FindAllStringSubmatch(output, -1) {
+		c, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			continue
+		}
+		sensors = append(sensors, ThermalSensor{Name: strings.TrimSpace(m[1]), Celsius: c})
+	}
+	return sensors
+}
@@ -0,0 +1,25 @@
+package scanner
+
+import (
+	"context"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+// collectPCI inventories PCI devices (NICs, HBAs, accelerators) from
+// lspci. Hosts without lspci (pciutils not installed, most containers)
+// are left without a device list.
+func collectPCI(ctx context.Context, runner CommandRunner, info *HostInfo) {
+	out, err := runner.Run(ctx, "lspci -mm 2>/dev/null")
+	if err != nil {
+		return
+	}
+	for _, d := range parser.ParseLspciDevices(string(out)) {
+		info.PCI = append(info.PCI, PCIDevice{
+			Slot:   d.Slot,
+			Class:  d.Class,
+			Vendor: d.Vendor,
+			Device: d.Device,
+		})
+	}
+}
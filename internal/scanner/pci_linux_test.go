@@ -0,0 +1,32 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCollectPCI(t *testing.T) {
+	var info HostInfo
+	collectPCI(context.Background(), cannedRunner{
+		"lspci -mm 2>/dev/null": `18:00.0 "RAID bus controller" "Broadcom / LSI" "MegaRAID SAS-3 3108 [Invader]" -r02 "Dell" "PERC H730P Mini"
+d8:00.0 "Processing accelerators" "Advanced Micro Devices, Inc. [AMD]" "Aldebaran/MI200 [Instinct MI210]" -r02 "" ""
+`,
+	}, &info)
+
+	want := []PCIDevice{
+		{Slot: "18:00.0", Class: "RAID bus controller", Vendor: "Broadcom / LSI", Device: "MegaRAID SAS-3 3108 [Invader]"},
+		{Slot: "d8:00.0", Class: "Processing accelerators", Vendor: "Advanced Micro Devices, Inc. [AMD]", Device: "Aldebaran/MI200 [Instinct MI210]"},
+	}
+	if !reflect.DeepEqual(info.PCI, want) {
+		t.Errorf("PCI =\n%+v\nwant\n%+v", info.PCI, want)
+	}
+}
+
+func TestCollectPCINoLspci(t *testing.T) {
+	var info HostInfo
+	collectPCI(context.Background(), cannedRunner{}, &info)
+	if info.PCI != nil {
+		t.Errorf("expected no PCI devices without lspci, got %+v", info.PCI)
+	}
+}
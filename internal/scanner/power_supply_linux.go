@@ -0,0 +1,22 @@
+package scanner
+
+import (
+	"context"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+// powerSupplyCmd dumps the power_supply class attributes as "path:value"
+// lines. Not every supply has every attribute; "true" keeps grep's exit
+// status from failing the command when some are missing.
+const powerSupplyCmd = `grep -H . /sys/class/power_supply/*/type /sys/class/power_supply/*/scope ` +
+	`/sys/class/power_supply/*/online /sys/class/power_supply/*/capacity /sys/class/power_supply/*/status 2>/dev/null; true`
+
+// collectPowerSupply reads AC adapter and battery state from sysfs.
+func collectPowerSupply(ctx context.Context, runner CommandRunner, info *HostInfo) {
+	out, err := runner.Run(ctx, powerSupplyCmd)
+	if err != nil {
+		return
+	}
+	applyPowerSupplies(&info.System, parser.ParseSysPowerSupply(string(out)))
+}
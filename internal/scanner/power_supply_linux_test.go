@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCollectPowerSupplyOnBattery(t *testing.T) {
+	var info HostInfo
+	collectPowerSupply(context.Background(), cannedRunner{
+		powerSupplyCmd: `/sys/class/power_supply/AC/type:Mains
+/sys/class/power_supply/AC/online:0
+/sys/class/power_supply/BAT0/type:Battery
+/sys/class/power_supply/BAT0/capacity:70
+/sys/class/power_supply/BAT0/status:Discharging
+/sys/class/power_supply/BAT1/type:Battery
+/sys/class/power_supply/BAT1/capacity:50
+/sys/class/power_supply/BAT1/status:Unknown
+`,
+	}, &info)
+
+	sys := info.System
+	if sys.PowerSource != "Battery Power" || sys.BatteryPercent != 60 || sys.BatteryStatus != "discharging" {
+		t.Errorf("power = %q %d%% %q, want Battery Power at 60%% discharging", sys.PowerSource, sys.BatteryPercent, sys.BatteryStatus)
+	}
+}
+
+func TestCollectPowerSupplyPluggedIn(t *testing.T) {
+	var info HostInfo
+	collectPowerSupply(context.Background(), cannedRunner{
+		powerSupplyCmd: `/sys/class/power_supply/ADP1/type:Mains
+/sys/class/power_supply/ADP1/online:1
+/sys/class/power_supply/BAT0/type:Battery
+/sys/class/power_supply/BAT0/capacity:100
+/sys/class/power_supply/BAT0/status:Full
+`,
+	}, &info)
+
+	// sysfs "Full" uses the same term as pmset's "charged"
+	sys := info.System
+	if sys.PowerSource != "AC Power" || sys.BatteryPercent != 100 || sys.BatteryStatus != "charged" {
+		t.Errorf("power = %q %d%% %q, want AC Power at 100%% charged", sys.PowerSource, sys.BatteryPercent, sys.BatteryStatus)
+	}
+}
+
+func TestCollectPowerSupplyNoBattery(t *testing.T) {
+	var info HostInfo
+	// Servers often expose only their PSUs, or nothing at all
+	collectPowerSupply(context.Background(), cannedRunner{
+		powerSupplyCmd: "/sys/class/power_supply/AC/type:Mains\n/sys/class/power_supply/AC/online:1\n",
+	}, &info)
+	if sys := info.System; sys.PowerSource != "" || sys.BatteryPercent != 0 || sys.BatteryStatus != "" {
+		t.Errorf("expected no power fields, got %q %d%% %q", sys.PowerSource, sys.BatteryPercent, sys.BatteryStatus)
+	}
+}
@@ -0,0 +1,22 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"unicode/utf16"
+)
+
+// PowerShellCommand wraps a PowerShell script as a command line for
+// CommandRunner.Run. The script is passed with -EncodedCommand so quoting
+// survives any outer shell (cmd.exe locally, sh or cmd over SSH). Errors
+// are silenced so stderr can't corrupt JSON on runners that combine
+// output streams; failed cmdlets simply produce no objects.
+func PowerShellCommand(script string) string {
+	script = "$ErrorActionPreference = 'SilentlyContinue'; $ProgressPreference = 'SilentlyContinue'; " + script
+	units := utf16.Encode([]rune(script))
+	buf := make([]byte, 2*len(units))
+	for i, u := range units {
+		buf[2*i] = byte(u)
+		buf[2*i+1] = byte(u >> 8)
+	}
+	return "powershell -NoProfile -NonInteractive -EncodedCommand " + base64.StdEncoding.EncodeToString(buf)
+}
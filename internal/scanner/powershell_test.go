@@ -0,0 +1,33 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestPowerShellCommandEncodesScript(t *testing.T) {
+	script := `Get-Volume | Select-Object @{n='Size';e={"$($_.Size)"}}`
+	cmd := PowerShellCommand(script)
+
+	const prefix = "powershell -NoProfile -NonInteractive -EncodedCommand "
+	if !strings.HasPrefix(cmd, prefix) {
+		t.Fatalf("unexpected command: %s", cmd)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(cmd, prefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = uint16(raw[2*i]) | uint16(raw[2*i+1])<<8
+	}
+	decoded := string(utf16.Decode(units))
+	if !strings.HasSuffix(decoded, script) {
+		t.Errorf("decoded script = %q, want suffix %q", decoded, script)
+	}
+	if !strings.Contains(decoded, "$ErrorActionPreference = 'SilentlyContinue'") {
+		t.Errorf("errors should be silenced: %q", decoded)
+	}
+}
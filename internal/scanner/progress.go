@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress writes plain-text, per-step scan progress lines for humans
+// debugging a slow or failing scan. It is separate from the structured
+// logs. A nil *Progress discards everything, so callers need not check.
+type Progress struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+}
+
+// NewProgress returns a Progress writing to w.
+func NewProgress(w io.Writer) *Progress {
+	return &Progress{w: w, start: time.Now()}
+}
+
+// Printf writes one progress line prefixed with the time since start.
+func (p *Progress) Printf(format string, args ...any) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, "[%6.2fs] %s\n", time.Since(p.start).Seconds(), fmt.Sprintf(format, args...))
+}
+
+// Runner wraps r so every command it runs is reported. Returns r unchanged
+// for a nil Progress.
+func (p *Progress) Runner(r CommandRunner) CommandRunner {
+	if p == nil {
+		return r
+	}
+	return &progressRunner{runner: r, progress: p}
+}
+
+type progressRunner struct {
+	runner   CommandRunner
+	progress *Progress
+}
+
+func (r *progressRunner) Run(ctx context.Context, cmd string) ([]byte, error) {
+	start := time.Now()
+	out, err := r.runner.Run(ctx, cmd)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	name, _, _ := strings.Cut(strings.TrimSpace(cmd), " ")
+	if err != nil {
+		r.progress.Printf("  %s failed after %s", name, elapsed)
+		return out, err
+	}
+	lines := bytes.Count(bytes.TrimSpace(out), []byte("\n"))
+	if len(bytes.TrimSpace(out)) > 0 {
+		lines++
+	}
+	r.progress.Printf("  %s returned %d line(s) in %s", name, lines, elapsed)
+	return out, err
+}
+
+// isLocalRunner reports whether r runs commands on this host, looking
+// through a progress wrapper.
+func isLocalRunner(r CommandRunner) bool {
+	if pr, ok := r.(*progressRunner); ok {
+		r = pr.runner
+	}
+	switch r.(type) {
+	case LocalRunner, *LocalRunner:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// collectRebootRequired reports whether the host needs a reboot to finish
+// applying updates, from (in order) the Debian/Ubuntu flag file, RHEL's
+// needs-restarting, and a comparison of the running kernel with the newest
+// kernel image in /boot.
+func collectRebootRequired(ctx context.Context, runner CommandRunner, info *HostInfo) {
+	var reasons []string
+
+	if out, err := runner.Run(ctx, "test -f /var/run/reboot-required && echo yes"); err == nil && strings.TrimSpace(string(out)) == "yes" {
+		reasons = append(reasons, "/var/run/reboot-required present")
+	}
+
+	// needs-restarting -r exits 1 when a reboot is needed, so the output is
+	// checked even on error
+	if out, _ := runner.Run(ctx, "needs-restarting -r"); strings.Contains(string(out), "Reboot is required") {
+		reasons = append(reasons, "needs-restarting reports updated core packages")
+	}
+
+	if running, err := runner.Run(ctx, "uname -r"); err == nil {
+		if boot, err := runner.Run(ctx, "ls /boot"); err == nil {
+			runningKernel := strings.TrimSpace(string(running))
+			newest := newestKernel(strings.Fields(string(boot)))
+			if newest != "" && compareKernelVersions(newest, runningKernel) > 0 {
+				reasons = append(reasons, fmt.Sprintf("running kernel %s, newest installed %s", runningKernel, newest))
+			}
+		}
+	}
+
+	if len(reasons) > 0 {
+		info.System.RebootRequired = true
+		info.System.RebootRequiredReason = strings.Join(reasons, "; ")
+	}
+}
+
+// newestKernel returns the highest kernel version among /boot entries named
+// vmlinuz-<version>, ignoring rescue images.
+func newestKernel(bootFiles []string) string {
+	var newest string
+	for _, f := range bootFiles {
+		version, ok := strings.CutPrefix(f, "vmlinuz-")
+		if !ok || version == "" || strings.Contains(version, "rescue") {
+			continue
+		}
+		if newest == "" || compareKernelVersions(version, newest) > 0 {
+			newest = version
+		}
+	}
+	return newest
+}
+
+// compareKernelVersions compares kernel release strings such as
+// "5.15.0-91-generic" or "4.18.0-513.el8.x86_64" by their numeric
+// components in order, so 5.15.0-101 sorts after 5.15.0-91.
+func compareKernelVersions(a, b string) int {
+	pa, pb := kernelVersionParts(a), kernelVersionParts(b)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(pa) - len(pb)
+}
+
+func kernelVersionParts(v string) []int {
+	var parts []int
+	for _, f := range strings.FieldsFunc(v, func(r rune) bool { return r < '0' || r > '9' }) {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCollectRebootRequiredFlagFile(t *testing.T) {
+	var info HostInfo
+	collectRebootRequired(context.Background(), cannedRunner{
+		"test -f /var/run/reboot-required && echo yes": "yes\n",
+		"uname -r": "6.8.0-40-generic\n",
+		"ls /boot": "config-6.8.0-40-generic\nvmlinuz\nvmlinuz-6.8.0-40-generic\n",
+	}, &info)
+
+	if !info.System.RebootRequired {
+		t.Fatal("expected reboot required from flag file")
+	}
+	if info.System.RebootRequiredReason != "/var/run/reboot-required present" {
+		t.Errorf("reason = %q", info.System.RebootRequiredReason)
+	}
+}
+
+func TestCollectRebootRequiredKernel(t *testing.T) {
+	var info HostInfo
+	collectRebootRequired(context.Background(), cannedRunner{
+		"uname -r": "5.15.0-91-generic\n",
+		"ls /boot": "vmlinuz-5.15.0-91-generic\nvmlinuz-5.15.0-101-generic\nvmlinuz-0-rescue-abc123\n",
+	}, &info)
+
+	if !info.System.RebootRequired {
+		t.Fatal("expected reboot required for newer installed kernel")
+	}
+	if !strings.Contains(info.System.RebootRequiredReason, "running kernel 5.15.0-91-generic, newest installed 5.15.0-101-generic") {
+		t.Errorf("reason = %q", info.System.RebootRequiredReason)
+	}
+
+	// Already on the newest kernel, no flag file
+	info = HostInfo{}
+	collectRebootRequired(context.Background(), cannedRunner{
+		"uname -r": "5.15.0-101-generic\n",
+		"ls /boot": "vmlinuz-5.15.0-91-generic\nvmlinuz-5.15.0-101-generic\n",
+	}, &info)
+	if info.System.RebootRequired {
+		t.Errorf("unexpected reboot required: %q", info.System.RebootRequiredReason)
+	}
+}
+
+func TestCompareKernelVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"5.15.0-101-generic", "5.15.0-91-generic", 1},
+		{"4.18.0-513.el8.x86_64", "4.18.0-553.el8.x86_64", -1},
+		{"6.8.0-40-generic", "6.8.0-40-generic", 0},
+		{"6.1.0", "5.19.17", 1},
+	}
+	for _, tt := range tests {
+		got := compareKernelVersions(tt.a, tt.b)
+		if (got > 0) != (tt.want > 0) || (got < 0) != (tt.want < 0) {
+			t.Errorf("compareKernelVersions(%q, %q) = %d, want sign %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,40 @@
 package scanner
 
+import "time"
+
 // RegistryOptions configures scanner construction.
 type RegistryOptions struct {
-	ExcludeNamespaces []string
+	ExcludeNamespaces []string      // replaces DefaultExcludeNamespaces; names or globs
+	SkipNamespaces    []string      // added to the exclusions in effect; names or globs
+	IncludeNamespaces []string      // scan only these namespaces; names or globs
+	ClusterNameLabel  string        // overrides DefaultClusterNameLabel
+	IoTCacheTTL       time.Duration // 0 = iot.DefaultCacheTTL, negative = no caching
+	IPv6EgressCheck   bool          // dial out over IPv6 during network scans
+
+	// Kubernetes API rate limit (0 = DefaultK8sQPS/DefaultK8sBurst) and
+	// namespaces scanned at once (0 = DefaultNamespaceConcurrency)
+	K8sQPS               float32
+	K8sBurst             int
+	NamespaceConcurrency int
+
+	// List distinct running images in cluster scans (K8sScanner.ImageInventory)
+	ImageInventory bool
+}
+
+// newNamespacedK8sScanner returns a K8sScanner with the namespace
+// selection in opts applied.
+func newNamespacedK8sScanner(opts RegistryOptions) *K8sScanner {
+	var k8s *K8sScanner
+	if len(opts.ExcludeNamespaces) > 0 {
+		k8s = NewK8sScannerWithExclusions(opts.ExcludeNamespaces)
+	} else {
+		k8s = NewK8sScanner()
+	}
+	for _, ns := range opts.SkipNamespaces {
+		k8s.ExcludeNamespaces[ns] = true
+	}
+	k8s.IncludeNamespaces = opts.IncludeNamespaces
+	return k8s
 }
 
 // Registry maps profiles to their scanners.
@@ -22,12 +54,22 @@ func NewRegistryWithOptions(opts RegistryOptions) *Registry {
 	}
 
 	// Build k8s scanner with exclusion config
-	var k8s *K8sScanner
-	if len(opts.ExcludeNamespaces) > 0 {
-		k8s = NewK8sScannerWithExclusions(opts.ExcludeNamespaces)
-	} else {
-		k8s = NewK8sScanner()
+	k8s := newNamespacedK8sScanner(opts)
+	if opts.ClusterNameLabel != "" {
+		k8s.ClusterNameLabel = opts.ClusterNameLabel
 	}
+	k8s.QPS = opts.K8sQPS
+	k8s.Burst = opts.K8sBurst
+	k8s.NamespaceConcurrency = opts.NamespaceConcurrency
+	k8s.ImageInventory = opts.ImageInventory
+
+	iotScanner := NewIoTScanner()
+	if opts.IoTCacheTTL != 0 {
+		iotScanner.registry.SetCacheTTL(opts.IoTCacheTTL)
+	}
+
+	network := NewNetworkScanner()
+	network.IPv6EgressCheck = opts.IPv6EgressCheck
 
 	// Minimal: just host info
 	minimal := []Scanner{
@@ -36,7 +78,7 @@ func NewRegistryWithOptions(opts RegistryOptions) *Registry {
 
 	// Standard: host + network + storage + topology
 	standard := append(minimal,
-		NewNetworkScanner(),
+		network,
 		NewStorageScanner(),
 	)
 
@@ -45,7 +87,7 @@ func NewRegistryWithOptions(opts RegistryOptions) *Registry {
 		NewContainerScanner(),
 		k8s,
 		NewPowerScanner(),
-		NewIoTScanner(),
+		iotScanner,
 	)
 
 	r.scanners[ProfileMinimal] = minimal
@@ -11,6 +11,7 @@ type Result struct {
 	Cluster    json.RawMessage            `json:"cluster,omitempty"`
 	Power      json.RawMessage            `json:"power,omitempty"`
 	IoT        json.RawMessage            `json:"iot,omitempty"`
+	Health     *HealthReport              `json:"health,omitempty"`
 	Phases     map[string]json.RawMessage `json:"-"`
 	Meta       ResultMeta                 `json:"meta"`
 }
@@ -23,6 +24,12 @@ type ResultMeta struct {
 	Phases       []string `json:"phases"`
 	SourceHost   string   `json:"source_host"`
 	InferredRole string   `json:"inferred_role,omitempty"`
+	Partial      bool     `json:"partial,omitempty"` // scan hit its timeout; some phases are missing
+
+	// Agent scans only: a per-host counter that increases with every scan
+	// (across restarts) and when the scan started, RFC 3339
+	Sequence    uint64 `json:"sequence,omitempty"`
+	GeneratedAt string `json:"generated_at,omitempty"`
 }
 
 // NewResult creates an empty Result.
@@ -54,3 +61,36 @@ func (r *Result) Set(name string, data json.RawMessage) {
 		r.IoT = data
 	}
 }
+
+// ForProfile returns a copy of r holding only the phases a scan at profile
+// p would produce. Phases p includes that r lacks are not added.
+func (r *Result) ForProfile(p Profile) *Result {
+	keep := make(map[string]bool)
+	for _, s := range NewRegistry().ForProfile(p) {
+		keep[s.Name()] = true
+	}
+
+	out := NewResult()
+	out.Meta = r.Meta
+	// Scores go only where their phase does
+	if r.Health != nil {
+		health := &HealthReport{}
+		if keep["host"] {
+			health.Host = r.Health.Host
+		}
+		if keep["cluster"] {
+			health.Cluster = r.Health.Cluster
+		}
+		if health.Host != nil || health.Cluster != nil {
+			out.Health = health
+		}
+	}
+	out.Meta.Profile = p.String()
+	out.Meta.Phases = nil
+	for _, name := range r.Meta.Phases {
+		if keep[name] {
+			out.Set(name, r.Phases[name])
+		}
+	}
+	return out
+}
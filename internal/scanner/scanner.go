@@ -28,9 +28,13 @@ type CommandRunner interface {
 // LocalRunner executes commands on the local host.
 type LocalRunner struct{}
 
-// Run executes a command locally via /bin/sh.
+// Run executes a command locally via /bin/sh (cmd.exe on Windows, where
+// scanners pass PowerShellCommand lines).
 func (r LocalRunner) Run(ctx context.Context, command string) ([]byte, error) {
 	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	}
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return out, fmt.Errorf("command %q failed: %w (output: %s)", command, err, strings.TrimSpace(string(out)))
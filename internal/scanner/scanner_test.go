@@ -0,0 +1,17 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+)
+
+// cannedRunner returns fixed output per command; unknown commands fail.
+type cannedRunner map[string]string
+
+func (r cannedRunner) Run(_ context.Context, cmd string) ([]byte, error) {
+	out, ok := r[cmd]
+	if !ok {
+		return nil, errors.New("command failed")
+	}
+	return []byte(out), nil
+}
@@ -3,12 +3,46 @@ package scanner
 import (
 	"context"
 	"encoding/json"
+	"sort"
 )
 
 // StorageInfo holds storage scan results.
 type StorageInfo struct {
 	Filesystems []FilesystemInfo `json:"filesystems"`
 	Disks       []DiskInfo       `json:"disks,omitempty"`
+	Pools       []StoragePool    `json:"pools,omitempty"` // ZFS pools and Btrfs filesystems (Linux only)
+
+	// LVM volume groups; Linux hosts with the lvm2 tools only
+	VolumeGroups []VolumeGroup `json:"volume_groups,omitempty"`
+}
+
+// VolumeGroup is an LVM volume group and the logical volumes carved from it.
+type VolumeGroup struct {
+	Name           string          `json:"name"`
+	SizeBytes      int64           `json:"size_bytes"`
+	FreeBytes      int64           `json:"free_bytes"`
+	PVs            []string        `json:"pvs,omitempty"` // physical volume paths, e.g. /dev/sda3
+	LogicalVolumes []LogicalVolume `json:"logical_volumes,omitempty"`
+}
+
+// LogicalVolume is one LVM logical volume.
+type LogicalVolume struct {
+	Name       string `json:"name"`
+	SizeBytes  int64  `json:"size_bytes"`
+	MountPoint string `json:"mount_point,omitempty"` // per df; empty for swap and unmounted volumes
+}
+
+// StoragePool is a ZFS pool or multi-device Btrfs filesystem. Its member
+// devices also appear in Disks, marked with the pool name, so capacity
+// should be counted from the pool rather than from its members.
+type StoragePool struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`                 // zfs, btrfs
+	RaidLevel  string   `json:"raid_level,omitempty"` // mirror, raidz2, raid1, single, ...
+	Health     string   `json:"health,omitempty"`     // ZFS pool state: ONLINE, DEGRADED, ...
+	TotalBytes int64    `json:"total_bytes"`
+	UsedBytes  int64    `json:"used_bytes"`
+	Members    []string `json:"members,omitempty"` // device names, e.g. sda1
 }
 
 // FilesystemInfo represents a mounted filesystem.
@@ -20,6 +54,53 @@ type FilesystemInfo struct {
 	UsedGB     float64 `json:"used_gb"`
 	AvailGB    float64 `json:"avail_gb"`
 	UsePct     float64 `json:"use_pct"`
+
+	// Inode usage; zero when df -i has no figures for the filesystem
+	InodesTotal  int64   `json:"inodes_total,omitempty"`
+	InodesUsed   int64   `json:"inodes_used,omitempty"`
+	InodesUsePct float64 `json:"inodes_use_pct,omitempty"`
+
+	// Mount options, e.g. ["rw", "noatime"]; from /proc/mounts on Linux
+	// and mount(8) on macOS
+	Options []string `json:"options,omitempty"`
+}
+
+// inodeUsage is one filesystem's inode figures from df -i.
+type inodeUsage struct {
+	Total  int64
+	Used   int64
+	UsePct float64
+}
+
+// mergeInodes copies inode figures onto filesystems by mount point.
+func mergeInodes(filesystems []FilesystemInfo, usage map[string]inodeUsage) {
+	for i := range filesystems {
+		if u, ok := usage[filesystems[i].MountPoint]; ok {
+			filesystems[i].InodesTotal = u.Total
+			filesystems[i].InodesUsed = u.Used
+			filesystems[i].InodesUsePct = u.UsePct
+		}
+	}
+}
+
+// mountEntry is one mount's filesystem type and options.
+type mountEntry struct {
+	Type    string
+	Options []string
+}
+
+// mergeMounts copies filesystem types and mount options onto filesystems
+// by mount point. Mounts df did not list are ignored, so df's filtering
+// of pseudo-filesystems still applies.
+func mergeMounts(filesystems []FilesystemInfo, mounts map[string]mountEntry) {
+	for i := range filesystems {
+		if m, ok := mounts[filesystems[i].MountPoint]; ok {
+			if filesystems[i].Type == "" {
+				filesystems[i].Type = m.Type
+			}
+			filesystems[i].Options = m.Options
+		}
+	}
 }
 
 // DiskInfo represents a physical or virtual disk.
@@ -30,6 +111,35 @@ type DiskInfo struct {
 	Model    string `json:"model,omitempty"`
 	Serial   string `json:"serial,omitempty"`
 	ReadOnly bool   `json:"read_only,omitempty"`
+	// Set only by the /sys/block fallback used when lsblk is unavailable
+	Rotational  bool     `json:"rotational,omitempty"`
+	Removable   bool     `json:"removable,omitempty"`
+	MountPoints []string `json:"mount_points,omitempty"`
+	// Firmware and wear data; Linux NVMe devices with nvme-cli only
+	Firmware string      `json:"firmware,omitempty"`
+	Health   *DiskHealth `json:"health,omitempty"`
+	// smartctl overall health and key attributes; Linux only
+	SMART *SMARTInfo `json:"smart,omitempty"`
+	// Name of the StoragePool this device (or one of its partitions) belongs to
+	Pool string `json:"pool,omitempty"`
+}
+
+// SMARTInfo is the subset of smartctl's report used to spot failing disks.
+type SMARTInfo struct {
+	Health             string `json:"health"` // PASSED or FAILED
+	PowerOnHours       int64  `json:"power_on_hours,omitempty"`
+	ReallocatedSectors int64  `json:"reallocated_sectors"` // ATA attribute 5; 0 for drives without it
+	TemperatureC       int    `json:"temperature_c,omitempty"`
+}
+
+// DiskHealth is a drive's self-reported health.
+type DiskHealth struct {
+	Source          string `json:"source"`           // tool that reported it, e.g. "nvme-cli"
+	CriticalWarning int    `json:"critical_warning"` // NVMe critical warning bitmap; 0 is healthy
+	PercentageUsed  int    `json:"percentage_used"`  // vendor estimate of endurance used; may exceed 100
+	AvailableSpare  int    `json:"available_spare"`  // percent of spare capacity remaining
+	MediaErrors     int64  `json:"media_errors"`
+	TemperatureC    int    `json:"temperature_c,omitempty"`
 }
 
 // StorageScanner collects disk and filesystem information.
@@ -49,6 +159,21 @@ func (s *StorageScanner) Scan(ctx context.Context, runner CommandRunner) (json.R
 	if err := collectStorageInfo(ctx, runner, &info); err != nil {
 		return nil, err
 	}
+	sortStorageInfo(&info)
 
 	return json.Marshal(info)
 }
+
+// sortStorageInfo orders filesystems by mount point and disks, pools and
+// volume groups by name, so that unchanged hosts produce identical scans.
+func sortStorageInfo(info *StorageInfo) {
+	sort.SliceStable(info.Filesystems, func(i, j int) bool {
+		return info.Filesystems[i].MountPoint < info.Filesystems[j].MountPoint
+	})
+	sort.SliceStable(info.Disks, func(i, j int) bool { return info.Disks[i].Name < info.Disks[j].Name })
+	sort.SliceStable(info.Pools, func(i, j int) bool { return info.Pools[i].Name < info.Pools[j].Name })
+	sort.SliceStable(info.VolumeGroups, func(i, j int) bool { return info.VolumeGroups[i].Name < info.VolumeGroups[j].Name })
+	for _, vg := range info.VolumeGroups {
+		sort.SliceStable(vg.LogicalVolumes, func(i, j int) bool { return vg.LogicalVolumes[i].Name < vg.LogicalVolumes[j].Name })
+	}
+}
@@ -7,11 +7,22 @@ import (
 )
 
 func collectStorageInfo(ctx context.Context, runner CommandRunner, info *StorageInfo) error {
-	// Use df -Pk for POSIX output (6 columns, consistent across platforms)
-	if out, err := runner.Run(ctx, "df -Pk 2>/dev/null"); err == nil {
+	// Use df -Pk for POSIX output (6 columns, consistent across platforms).
+	// LC_ALL=C keeps the header and number formatting locale-independent.
+	if out, err := runner.Run(ctx, "LC_ALL=C df -Pk 2>/dev/null"); err == nil {
 		info.Filesystems = parseDfOutput(string(out))
 	}
 
+	// Inode usage; -i appends iused/ifree/%iused to the block columns
+	if out, err := runner.Run(ctx, "LC_ALL=C df -ki 2>/dev/null"); err == nil {
+		mergeInodes(info.Filesystems, parseDfInodes(string(out)))
+	}
+
+	// Filesystem types and mount options (read-only, noatime, ...)
+	if out, err := runner.Run(ctx, "mount 2>/dev/null"); err == nil {
+		mergeMounts(info.Filesystems, parseMountOutput(string(out)))
+	}
+
 	return nil
 }
 
@@ -66,3 +77,54 @@ func parseDfOutput(output string) []FilesystemInfo {
 
 	return filesystems
 }
+
+// parseMountOutput parses mount(8) output, keyed by mount point. The first
+// parenthesized item is the filesystem type, the rest are options:
+//
+//	/dev/disk3s1s1 on / (apfs, sealed, local, read-only, journaled)
+func parseMountOutput(output string) map[string]mountEntry {
+	mounts := make(map[string]mountEntry)
+	for _, line := range strings.Split(output, "\n") {
+		_, rest, ok := strings.Cut(line, " on ")
+		open := strings.LastIndex(rest, " (")
+		if !ok || open < 0 || !strings.HasSuffix(rest, ")") {
+			continue
+		}
+		var entry mountEntry
+		for i, item := range strings.Split(rest[open+2:len(rest)-1], ",") {
+			item = strings.TrimSpace(item)
+			if i == 0 {
+				entry.Type = item
+			} else if item != "" {
+				entry.Options = append(entry.Options, item)
+			}
+		}
+		mounts[rest[:open]] = entry
+	}
+	return mounts
+}
+
+// parseDfInodes parses macOS `df -ki` output, keyed by mount point.
+// Columns: Filesystem 1024-blocks Used Available Capacity iused ifree
+// %iused Mounted-on. Like parseDfOutput, the mount point is the remainder
+// of the line so paths with spaces survive.
+func parseDfInodes(output string) map[string]inodeUsage {
+	usage := make(map[string]inodeUsage)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 9 || fields[0] == "Filesystem" {
+			continue
+		}
+		used, err := strconv.ParseInt(fields[5], 10, 64)
+		if err != nil {
+			continue
+		}
+		free, _ := strconv.ParseInt(fields[6], 10, 64)
+		if used+free == 0 {
+			continue
+		}
+		pct, _ := strconv.ParseFloat(strings.TrimSuffix(fields[7], "%"), 64)
+		usage[strings.Join(fields[8:], " ")] = inodeUsage{Total: used + free, Used: used, UsePct: pct}
+	}
+	return usage
+}
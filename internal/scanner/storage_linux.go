@@ -8,64 +8,145 @@ import (
 )
 
 func collectStorageInfo(ctx context.Context, runner CommandRunner, info *StorageInfo) error {
-	// Filesystem info from df
-	if out, err := runner.Run(ctx, "df -Pk 2>/dev/null"); err == nil {
+	// Filesystem info from df. LC_ALL=C keeps the header and number formatting
+	// stable; BusyBox builds without -P support fall back to plain -k output.
+	if out, err := runner.Run(ctx, "LC_ALL=C df -Pk 2>/dev/null"); err == nil {
 		info.Filesystems = parseDfOutput(string(out))
+	} else if out, err := runner.Run(ctx, "LC_ALL=C df -k 2>/dev/null"); err == nil {
+		info.Filesystems = parseDfOutput(string(out))
+	}
+
+	// Inode usage; a filesystem can be "full" with free blocks left
+	if out, err := runner.Run(ctx, "LC_ALL=C df -Pi 2>/dev/null"); err == nil {
+		mergeInodes(info.Filesystems, parseDfInodes(string(out)))
+	} else if out, err := runner.Run(ctx, "LC_ALL=C df -i 2>/dev/null"); err == nil {
+		mergeInodes(info.Filesystems, parseDfInodes(string(out)))
+	}
+
+	// Filesystem types and mount options (ro, noatime, ...)
+	if out, err := runner.Run(ctx, "cat /proc/mounts 2>/dev/null"); err == nil {
+		mergeMounts(info.Filesystems, parseProcMounts(string(out)).byMountPoint)
 	}
 
 	// Disk info from lsblk (Linux only)
 	if out, err := runner.Run(ctx, "lsblk -J -b -o NAME,SIZE,TYPE,MODEL,SERIAL,RO 2>/dev/null"); err == nil {
 		info.Disks = parseLsblkJSON(out)
+	} else if isLocalRunner(runner) {
+		// Minimal containers often lack lsblk; sysfs has the same data.
+		// Only meaningful when the runner is this host, not an SSH target.
+		info.Disks = collectSysBlock("")
 	}
 
+	// Firmware and wear for NVMe drives
+	collectNVMeHealth(ctx, runner, info.Disks)
+
+	// SMART health for the remaining disks
+	collectSMART(ctx, runner, info.Disks)
+
+	// ZFS pools and Btrfs filesystems spanning the disks above
+	collectPools(ctx, runner, info)
+
+	// LVM volume groups and logical volumes
+	collectLVM(ctx, runner, info)
+
 	return nil
 }
 
-// parseDfOutput parses `df -k` output (sizes in 1K blocks).
-func parseDfOutput(output string) []FilesystemInfo {
-	var filesystems []FilesystemInfo
-	lines := strings.Split(output, "\n")
+// dfRow is one filesystem line of POSIX df output: the device, the four
+// numeric columns and the mount point.
+type dfRow struct {
+	Filesystem string
+	Cols       [4]string
+	MountPoint string
+}
 
-	for _, line := range lines {
+// parseDfRows splits `df -P` style output (-k or -i) into rows. The header
+// is identified by its non-numeric second column rather than by name, so
+// localized headers are handled. Device names that don't fit their column
+// (GNU and BusyBox without -P) wrap the numbers onto the next line. Mount
+// points may contain spaces. Pseudo-filesystems are skipped.
+func parseDfRows(output string) []dfRow {
+	var rows []dfRow
+	var pending string
+
+	for _, line := range strings.Split(output, "\n") {
 		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		// Wrapped device name: remember it and join with the next line
+		if len(fields) == 1 {
+			pending = fields[0]
+			continue
+		}
+		if pending != "" {
+			if _, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+				fields = append([]string{pending}, fields...)
+			}
+			pending = ""
+		}
 		if len(fields) < 6 {
 			continue
 		}
-		// Skip header
-		if fields[0] == "Filesystem" {
+
+		// Header (any locale) has a non-numeric second column
+		if _, err := strconv.ParseInt(fields[1], 10, 64); err != nil {
 			continue
 		}
+
 		// Skip pseudo-filesystems
 		fs := fields[0]
 		if !strings.HasPrefix(fs, "/") && !strings.Contains(fs, ":") {
 			continue
 		}
 
-		sizeKB, err := strconv.ParseInt(fields[1], 10, 64)
-		if err != nil {
-			continue
-		}
-		usedKB, _ := strconv.ParseInt(fields[2], 10, 64)
-		availKB, _ := strconv.ParseInt(fields[3], 10, 64)
-
-		pctStr := strings.TrimSuffix(fields[4], "%")
-		pct, _ := strconv.ParseFloat(pctStr, 64)
+		rows = append(rows, dfRow{
+			Filesystem: fs,
+			Cols:       [4]string{fields[1], fields[2], fields[3], fields[4]},
+			MountPoint: strings.Join(fields[5:], " "),
+		})
+	}
+	return rows
+}
 
-		mountPoint := strings.Join(fields[5:], " ")
+// parseDfOutput parses `df -Pk` or `df -k` output (sizes in 1K blocks).
+func parseDfOutput(output string) []FilesystemInfo {
+	var filesystems []FilesystemInfo
+	for _, row := range parseDfRows(output) {
+		sizeKB, _ := strconv.ParseInt(row.Cols[0], 10, 64)
+		usedKB, _ := strconv.ParseInt(row.Cols[1], 10, 64)
+		availKB, _ := strconv.ParseInt(row.Cols[2], 10, 64)
+		pct, _ := strconv.ParseFloat(strings.TrimSuffix(row.Cols[3], "%"), 64)
 
 		filesystems = append(filesystems, FilesystemInfo{
-			Filesystem: fs,
-			MountPoint: mountPoint,
+			Filesystem: row.Filesystem,
+			MountPoint: row.MountPoint,
 			SizeGB:     float64(sizeKB) / (1024 * 1024),
 			UsedGB:     float64(usedKB) / (1024 * 1024),
 			AvailGB:    float64(availKB) / (1024 * 1024),
 			UsePct:     pct,
 		})
 	}
-
 	return filesystems
 }
 
+// parseDfInodes parses `df -Pi` or `df -i` output, keyed by mount point.
+// Filesystems without a fixed inode table (btrfs, some FUSE mounts)
+// report 0 inodes and are left out.
+func parseDfInodes(output string) map[string]inodeUsage {
+	usage := make(map[string]inodeUsage)
+	for _, row := range parseDfRows(output) {
+		total, _ := strconv.ParseInt(row.Cols[0], 10, 64)
+		if total == 0 {
+			continue
+		}
+		used, _ := strconv.ParseInt(row.Cols[1], 10, 64)
+		pct, _ := strconv.ParseFloat(strings.TrimSuffix(row.Cols[3], "%"), 64)
+		usage[row.MountPoint] = inodeUsage{Total: total, Used: used, UsePct: pct}
+	}
+	return usage
+}
+
 type lsblkOutput struct {
 	Blockdevices []lsblkDevice `json:"blockdevices"`
 }
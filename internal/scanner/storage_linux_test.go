@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseDfOutputBusyBoxWrapped(t *testing.T) {
+	// BusyBox `df -k` without -P wraps long device names onto their own line
+	content := `Filesystem           1K-blocks      Used Available Use% Mounted on
+/dev/root             30497732   5236640  23997032  18% /
+devtmpfs                443152         0    443152   0% /dev
+tmpfs                   476816         0    476816   0% /dev/shm
+/dev/mapper/very-long-volume-group-name-root
+                      51475068  12345678  36485614  26% /data
+/dev/mmcblk0p1          258095     50387    207708  20% /boot
+`
+	fs := parseDfOutput(content)
+	if len(fs) != 3 {
+		t.Fatalf("expected 3 filesystems, got %d: %+v", len(fs), fs)
+	}
+	if fs[1].Filesystem != "/dev/mapper/very-long-volume-group-name-root" || fs[1].MountPoint != "/data" {
+		t.Errorf("wrapped line not joined: %+v", fs[1])
+	}
+	if fs[1].UsePct != 26 {
+		t.Errorf("expected 26%% use, got %v", fs[1].UsePct)
+	}
+	if fs[2].MountPoint != "/boot" {
+		t.Errorf("expected /boot after wrapped line, got %+v", fs[2])
+	}
+}
+
+func TestParseDfOutputLocalizedHeader(t *testing.T) {
+	// de_DE header; mount point with a space
+	content := `Dateisystem    1K-Blöcke   Benutzt Verfügbar Verw% Eingehängt auf
+/dev/sda1       102687672  45678912  51767884   47% /
+nas:/export     976284600 123456789 803167811   14% /mnt/daten sicherung
+`
+	fs := parseDfOutput(content)
+	if len(fs) != 2 {
+		t.Fatalf("expected 2 filesystems, got %d: %+v", len(fs), fs)
+	}
+	if fs[0].MountPoint != "/" || fs[0].UsePct != 47 {
+		t.Errorf("unexpected root filesystem: %+v", fs[0])
+	}
+	if fs[1].MountPoint != "/mnt/daten sicherung" {
+		t.Errorf("expected mount point with space, got %q", fs[1].MountPoint)
+	}
+}
+
+func TestParseDfInodesMergesByMount(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/df_inodes.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	filesystems := parseDfOutput(`Filesystem     1024-blocks      Used Available Capacity Mounted on
+/dev/nvme0n1p2   102687672  45678912  51767884      47% /
+/dev/mapper/vg0-build 205375344 98765432 106609912 49% /var/lib/build cache
+/dev/nvme0n1p1      523248      6220    517028       2% /boot/efi
+`)
+	mergeInodes(filesystems, parseDfInodes(string(data)))
+
+	root := filesystems[0]
+	if root.InodesTotal != 6553600 || root.InodesUsed != 6553211 || root.InodesUsePct != 100 {
+		t.Errorf("unexpected root inodes: %+v", root)
+	}
+	build := filesystems[1]
+	if build.MountPoint != "/var/lib/build cache" || build.InodesUsePct != 17 || build.InodesTotal != 13107200 {
+		t.Errorf("mount point with space not merged: %+v", build)
+	}
+	if efi := filesystems[2]; efi.InodesTotal != 0 || efi.InodesUsePct != 0 {
+		t.Errorf("vfat reports no inodes and should stay empty: %+v", efi)
+	}
+}
+
+func TestParseProcMountsMergesByMount(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/proc_mounts.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	filesystems := parseDfOutput(`Filesystem     1024-blocks      Used Available Capacity Mounted on
+/dev/nvme0n1p2   102687672  45678912  51767884      47% /
+/dev/mapper/vg0-build 205375344 98765432 106609912 49% /var/lib/build cache
+/dev/nvme0n1p1      523248      6220    517028       2% /boot/efi
+`)
+	mergeMounts(filesystems, parseProcMounts(string(data)).byMountPoint)
+
+	if len(filesystems) != 3 {
+		t.Fatalf("mounts df skipped should not be added, got %d filesystems", len(filesystems))
+	}
+	// The later, visible mount of / wins
+	root := filesystems[0]
+	if root.Type != "ext4" || !reflect.DeepEqual(root.Options, []string{"rw", "noatime", "errors=remount-ro"}) {
+		t.Errorf("unexpected root mount: %+v", root)
+	}
+	build := filesystems[1]
+	if build.MountPoint != "/var/lib/build cache" || build.Type != "xfs" || len(build.Options) == 0 || build.Options[1] != "noatime" {
+		t.Errorf("escaped mount point not merged: %+v", build)
+	}
+	if efi := filesystems[2]; efi.Type != "vfat" || efi.Options[0] != "rw" {
+		t.Errorf("unexpected efi mount: %+v", efi)
+	}
+}
@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"context"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+const (
+	lvmVgsCmd = "vgs --reportformat json --units b --nosuffix -o vg_name,vg_size,vg_free,pv_name 2>/dev/null"
+	lvmLvsCmd = "lvs --reportformat json --units b --nosuffix -o lv_name,vg_name,lv_size,lv_path,lv_dm_path 2>/dev/null"
+)
+
+// collectLVM adds LVM volume groups with their logical volumes when the
+// lvm2 tools are installed. Logical volumes get their mount point from df,
+// which lists them by /dev/mapper or /dev/<vg>/<lv> path. Must run after
+// the filesystems are collected.
+func collectLVM(ctx context.Context, runner CommandRunner, info *StorageInfo) {
+	out, err := runner.Run(ctx, lvmVgsCmd)
+	if err != nil {
+		return
+	}
+	vgs := parser.ParseVgs(out)
+	if len(vgs) == 0 {
+		return
+	}
+
+	var lvs []parser.LVMLogicalVolume
+	if out, err := runner.Run(ctx, lvmLvsCmd); err == nil {
+		lvs = parser.ParseLvs(out)
+	}
+
+	for _, vg := range vgs {
+		group := VolumeGroup{
+			Name:      vg.Name,
+			SizeBytes: vg.SizeBytes,
+			FreeBytes: vg.FreeBytes,
+			PVs:       vg.PVs,
+		}
+		for _, lv := range lvs {
+			if lv.VG != vg.Name {
+				continue
+			}
+			group.LogicalVolumes = append(group.LogicalVolumes, LogicalVolume{
+				Name:       lv.Name,
+				SizeBytes:  lv.SizeBytes,
+				MountPoint: mountPointOf(info.Filesystems, []string{lv.DMPath, lv.Path}),
+			})
+		}
+		info.VolumeGroups = append(info.VolumeGroups, group)
+	}
+}
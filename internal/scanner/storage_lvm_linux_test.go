@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCollectLVM(t *testing.T) {
+	vgs, err := os.ReadFile("../../testdata/vgs_report.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	lvs, err := os.ReadFile("../../testdata/lvs_report.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	info := StorageInfo{
+		Filesystems: []FilesystemInfo{
+			{Filesystem: "/dev/mapper/ubuntu--vg-root", MountPoint: "/"},
+			{Filesystem: "/dev/data/backups", MountPoint: "/srv/backups"},
+		},
+	}
+	collectLVM(context.Background(), cannedRunner{
+		lvmVgsCmd: string(vgs),
+		lvmLvsCmd: string(lvs),
+	}, &info)
+
+	want := []VolumeGroup{
+		{
+			Name: "data", SizeBytes: 3999956729856, FreeBytes: 999989182464,
+			PVs:            []string{"/dev/sdb", "/dev/sdc"},
+			LogicalVolumes: []LogicalVolume{{Name: "backups", SizeBytes: 2999967547392, MountPoint: "/srv/backups"}},
+		},
+		{
+			Name: "ubuntu-vg", SizeBytes: 498921046016, FreeBytes: 284172681216,
+			PVs: []string{"/dev/nvme0n1p3"},
+			LogicalVolumes: []LogicalVolume{
+				{Name: "root", SizeBytes: 107374182400, MountPoint: "/"},
+				{Name: "swap", SizeBytes: 107374182400},
+			},
+		},
+	}
+	if !reflect.DeepEqual(info.VolumeGroups, want) {
+		t.Errorf("VolumeGroups =\n%+v\nwant\n%+v", info.VolumeGroups, want)
+	}
+}
+
+func TestCollectLVMWithoutTools(t *testing.T) {
+	var info StorageInfo
+	collectLVM(context.Background(), cannedRunner{}, &info)
+	if info.VolumeGroups != nil {
+		t.Errorf("expected no volume groups without lvm2, got %+v", info.VolumeGroups)
+	}
+}
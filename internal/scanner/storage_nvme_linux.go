@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// collectNVMeHealth fills Firmware and Health on NVMe disks using nvme-cli.
+// nvme-cli reads the drive's own SMART/health log, which is richer than
+// what smartctl exposes for NVMe. The commands need root; when nvme-cli is
+// missing or a device can't be queried the fields are left empty.
+func collectNVMeHealth(ctx context.Context, runner CommandRunner, disks []DiskInfo) {
+	if _, err := runner.Run(ctx, "command -v nvme"); err != nil {
+		return
+	}
+	for i := range disks {
+		d := &disks[i]
+		if d.Type != "disk" || !strings.HasPrefix(d.Name, "nvme") {
+			continue
+		}
+		dev := "/dev/" + d.Name
+		if out, err := runner.Run(ctx, "nvme smart-log "+dev+" -o json 2>/dev/null"); err == nil {
+			d.Health = parseNvmeSmartLog(out)
+		}
+		if out, err := runner.Run(ctx, "nvme id-ctrl "+dev+" -o json 2>/dev/null"); err == nil {
+			d.Firmware = parseNvmeIDCtrlFirmware(out)
+		}
+	}
+}
+
+// nvmeSmartLog is the subset of `nvme smart-log -o json` we report. Field
+// types vary between nvme-cli releases (2.x emits some counters as
+// strings), so numbers are decoded leniently.
+type nvmeSmartLog struct {
+	CriticalWarning nvmeNumber `json:"critical_warning"`
+	Temperature     nvmeNumber `json:"temperature"` // Kelvin
+	AvailSpare      nvmeNumber `json:"avail_spare"`
+	PercentUsed     nvmeNumber `json:"percent_used"`
+	MediaErrors     nvmeNumber `json:"media_errors"`
+}
+
+// nvmeNumber accepts a JSON number or a numeric string. Anything else
+// decodes to 0 rather than failing the whole log.
+type nvmeNumber int64
+
+func (n *nvmeNumber) UnmarshalJSON(data []byte) error {
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return nil
+	}
+	if v, err := num.Int64(); err == nil {
+		*n = nvmeNumber(v)
+	} else if f, err := num.Float64(); err == nil {
+		*n = nvmeNumber(f)
+	}
+	return nil
+}
+
+// parseNvmeSmartLog parses `nvme smart-log <dev> -o json`. Returns nil when
+// the output isn't a smart log.
+func parseNvmeSmartLog(data []byte) *DiskHealth {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	if _, ok := raw["percent_used"]; !ok {
+		return nil
+	}
+	var log nvmeSmartLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil
+	}
+
+	h := &DiskHealth{
+		Source:          "nvme-cli",
+		CriticalWarning: int(log.CriticalWarning),
+		PercentageUsed:  int(log.PercentUsed),
+		AvailableSpare:  int(log.AvailSpare),
+		MediaErrors:     int64(log.MediaErrors),
+	}
+	if log.Temperature > 0 {
+		h.TemperatureC = int(log.Temperature) - 273
+	}
+	return h
+}
+
+// parseNvmeIDCtrlFirmware returns the firmware revision ("fr") from
+// `nvme id-ctrl <dev> -o json`.
+func parseNvmeIDCtrlFirmware(data []byte) string {
+	var ctrl struct {
+		FR string `json:"fr"`
+	}
+	if err := json.Unmarshal(data, &ctrl); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(ctrl.FR)
+}
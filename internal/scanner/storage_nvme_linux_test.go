@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseNvmeSmartLog(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/nvme_smart_log.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	got := parseNvmeSmartLog(data)
+	want := &DiskHealth{
+		Source:         "nvme-cli",
+		PercentageUsed: 7,
+		AvailableSpare: 100,
+		MediaErrors:    2,
+		TemperatureC:   38,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNvmeSmartLog = %+v, want %+v", got, want)
+	}
+
+	// nvme-cli 2.x emits large counters as strings
+	got = parseNvmeSmartLog([]byte(`{"critical_warning":4,"temperature":300,"avail_spare":9,"percent_used":104,"media_errors":"18446744073709551615"}`))
+	if got == nil || got.CriticalWarning != 4 || got.PercentageUsed != 104 || got.AvailableSpare != 9 {
+		t.Errorf("unexpected health from string counters: %+v", got)
+	}
+
+	if got := parseNvmeSmartLog([]byte(`{"error":"permission denied"}`)); got != nil {
+		t.Errorf("expected nil for non-smart-log output, got %+v", got)
+	}
+}
+
+func TestCollectNVMeHealth(t *testing.T) {
+	smartLog, err := os.ReadFile("../../testdata/nvme_smart_log.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	disks := []DiskInfo{
+		{Name: "nvme0n1", Type: "disk"},
+		{Name: "nvme0n1p1", Type: "part"},
+		{Name: "sda", Type: "disk"},
+	}
+	collectNVMeHealth(context.Background(), cannedRunner{
+		"command -v nvme": "/usr/sbin/nvme\n",
+		"nvme smart-log /dev/nvme0n1 -o json 2>/dev/null": string(smartLog),
+		"nvme id-ctrl /dev/nvme0n1 -o json 2>/dev/null":   `{"vid":5197,"sn":"S4EWNX0R123456  ","mn":"Samsung SSD 980 PRO 1TB","fr":"5B2QGXA7"}`,
+	}, disks)
+
+	if disks[0].Health == nil || disks[0].Health.PercentageUsed != 7 {
+		t.Errorf("nvme0n1 health = %+v", disks[0].Health)
+	}
+	if disks[0].Firmware != "5B2QGXA7" {
+		t.Errorf("nvme0n1 firmware = %q", disks[0].Firmware)
+	}
+	if disks[1].Health != nil || disks[2].Health != nil {
+		t.Errorf("only the NVMe disk should have health: %+v", disks)
+	}
+}
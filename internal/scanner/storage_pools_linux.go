@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+// collectPools adds ZFS pools and Btrfs filesystems when their tools are
+// installed, and marks member devices in info.Disks with the pool name.
+func collectPools(ctx context.Context, runner CommandRunner, info *StorageInfo) {
+	if out, err := runner.Run(ctx, "zpool list -Hp 2>/dev/null"); err == nil {
+		var layouts map[string]parser.ZPoolLayout
+		if status, err := runner.Run(ctx, "zpool status -PL 2>/dev/null"); err == nil {
+			layouts = parser.ParseZpoolStatus(string(status))
+		}
+		for _, zp := range parser.ParseZpoolList(string(out)) {
+			layout := layouts[zp.Name]
+			info.Pools = append(info.Pools, StoragePool{
+				Name:       zp.Name,
+				Type:       "zfs",
+				RaidLevel:  layout.RaidLevel,
+				Health:     zp.Health,
+				TotalBytes: zp.SizeBytes,
+				UsedBytes:  zp.AllocBytes,
+				Members:    devNames(layout.Members),
+			})
+		}
+	}
+
+	if out, err := runner.Run(ctx, "btrfs filesystem show --raw 2>/dev/null"); err == nil {
+		for _, fs := range parser.ParseBtrfsShow(string(out)) {
+			members := devNames(fs.Devices)
+			name := fs.Label
+			if name == "" {
+				name = fs.UUID
+			}
+			pool := StoragePool{
+				Name:       name,
+				Type:       "btrfs",
+				TotalBytes: fs.TotalBytes,
+				UsedBytes:  fs.UsedBytes,
+				Members:    members,
+			}
+			// The data profile is only reported for a mounted filesystem
+			if mount := mountPointOf(info.Filesystems, fs.Devices); mount != "" {
+				if df, err := runner.Run(ctx, "btrfs filesystem df "+shellQuote(mount)+" 2>/dev/null"); err == nil {
+					pool.RaidLevel = parser.ParseBtrfsDataProfile(string(df))
+				}
+			}
+			info.Pools = append(info.Pools, pool)
+		}
+	}
+
+	markPoolMembers(info.Disks, info.Pools)
+}
+
+// devNames strips /dev/ from device paths.
+func devNames(paths []string) []string {
+	var names []string
+	for _, p := range paths {
+		names = append(names, strings.TrimPrefix(p, "/dev/"))
+	}
+	return names
+}
+
+// mountPointOf returns where any of devices is mounted, per df.
+func mountPointOf(filesystems []FilesystemInfo, devices []string) string {
+	for _, fs := range filesystems {
+		for _, d := range devices {
+			if fs.Filesystem == d {
+				return fs.MountPoint
+			}
+		}
+	}
+	return ""
+}
+
+// markPoolMembers sets Pool on each member device and on the whole disk a
+// member partition lives on, so the disk's capacity isn't counted twice.
+func markPoolMembers(disks []DiskInfo, pools []StoragePool) {
+	for _, pool := range pools {
+		for _, member := range pool.Members {
+			for i := range disks {
+				if disks[i].Name == member || (disks[i].Type == "disk" && isPartitionOf(member, disks[i].Name)) {
+					disks[i].Pool = pool.Name
+				}
+			}
+		}
+	}
+}
+
+// isPartitionOf reports whether part names a partition of disk: sda1 of
+// sda, nvme0n1p2 of nvme0n1, mmcblk0p1 of mmcblk0.
+func isPartitionOf(part, disk string) bool {
+	suffix, ok := strings.CutPrefix(part, disk)
+	if disk == "" || !ok || suffix == "" {
+		return false
+	}
+	// Disks whose names end in a digit separate the partition number with "p"
+	if last := disk[len(disk)-1]; last >= '0' && last <= '9' {
+		if suffix, ok = strings.CutPrefix(suffix, "p"); !ok {
+			return false
+		}
+	}
+	for _, r := range suffix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return suffix != ""
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestCollectPoolsMarksMembers(t *testing.T) {
+	zpoolList, err := os.ReadFile("../../testdata/zpool_list.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	zpoolStatus, err := os.ReadFile("../../testdata/zpool_status.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	btrfsShow, err := os.ReadFile("../../testdata/btrfs_show.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	info := StorageInfo{
+		Filesystems: []FilesystemInfo{{Filesystem: "/dev/sdh", MountPoint: "/srv/data"}},
+		Disks: []DiskInfo{
+			{Name: "sda", Type: "disk"},
+			{Name: "sda1", Type: "part"},
+			{Name: "sda9", Type: "part"},
+			{Name: "sdh", Type: "disk"},
+			{Name: "sdz", Type: "disk"},
+		},
+	}
+	collectPools(context.Background(), cannedRunner{
+		"zpool list -Hp 2>/dev/null":                  string(zpoolList),
+		"zpool status -PL 2>/dev/null":                string(zpoolStatus),
+		"btrfs filesystem show --raw 2>/dev/null":     string(btrfsShow),
+		"btrfs filesystem df '/srv/data' 2>/dev/null": "Data, RAID1: total=1.23TiB, used=1.22TiB\n",
+	}, &info)
+
+	if len(info.Pools) != 4 {
+		t.Fatalf("expected 2 zfs + 2 btrfs pools, got %d: %+v", len(info.Pools), info.Pools)
+	}
+	tank := info.Pools[0]
+	if tank.Name != "tank" || tank.Type != "zfs" || tank.RaidLevel != "mirror" || tank.UsedBytes != 1204521369600 {
+		t.Errorf("unexpected tank pool: %+v", tank)
+	}
+	if data := info.Pools[2]; data.Name != "data" || data.RaidLevel != "raid1" || len(data.Members) != 2 {
+		t.Errorf("unexpected btrfs pool: %+v", data)
+	}
+
+	pools := map[string]string{}
+	for _, d := range info.Disks {
+		pools[d.Name] = d.Pool
+	}
+	want := map[string]string{"sda": "tank", "sda1": "tank", "sda9": "", "sdh": "data", "sdz": ""}
+	for name, pool := range want {
+		if pools[name] != pool {
+			t.Errorf("%s pool = %q, want %q", name, pools[name], pool)
+		}
+	}
+}
+
+func TestIsPartitionOf(t *testing.T) {
+	for _, tc := range []struct {
+		part, disk string
+		want       bool
+	}{
+		{"sda1", "sda", true},
+		{"sdaa1", "sda", false},
+		{"nvme0n1p2", "nvme0n1", true},
+		{"nvme0n10", "nvme0n1", false},
+		{"mmcblk0p1", "mmcblk0", true},
+		{"sda", "sda", false},
+	} {
+		if got := isPartitionOf(tc.part, tc.disk); got != tc.want {
+			t.Errorf("isPartitionOf(%q, %q) = %v, want %v", tc.part, tc.disk, got, tc.want)
+		}
+	}
+}
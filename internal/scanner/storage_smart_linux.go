@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// collectSMART fills SMART on whole disks using smartctl. NVMe drives that
+// already have nvme-cli health are skipped, as are loop, RAM, zram and
+// optical devices. smartctl encodes findings in its exit status, so the
+// output is parsed regardless of it; devices without SMART support (most
+// virtual disks) produce no smart_status and are left empty.
+func collectSMART(ctx context.Context, runner CommandRunner, disks []DiskInfo) {
+	if _, err := runner.Run(ctx, "command -v smartctl"); err != nil {
+		return
+	}
+	for i := range disks {
+		d := &disks[i]
+		if d.Type != "disk" || d.Health != nil || !smartCapable(d.Name) {
+			continue
+		}
+		out, err := runner.Run(ctx, "smartctl -H -A -j /dev/"+d.Name+" 2>/dev/null; true")
+		if err != nil {
+			continue
+		}
+		d.SMART = parseSmartctlJSON(out)
+	}
+}
+
+// smartCapable rules out device types that never carry SMART data.
+func smartCapable(name string) bool {
+	for _, prefix := range []string{"loop", "ram", "zram", "sr", "fd", "dm-", "md"} {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+type smartctlOutput struct {
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	PowerOnTime struct {
+		Hours int64 `json:"hours"`
+	} `json:"power_on_time"`
+	Temperature struct {
+		Current int `json:"current"`
+	} `json:"temperature"`
+	ATASmartAttributes struct {
+		Table []struct {
+			ID  int `json:"id"`
+			Raw struct {
+				Value int64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+}
+
+// parseSmartctlJSON parses `smartctl -H -A -j <dev>`. Returns nil when the
+// device reported no overall health (no SMART support or no permission).
+func parseSmartctlJSON(data []byte) *SMARTInfo {
+	var out smartctlOutput
+	if err := json.Unmarshal(data, &out); err != nil || out.SmartStatus == nil {
+		return nil
+	}
+
+	info := &SMARTInfo{
+		Health:       "FAILED",
+		PowerOnHours: out.PowerOnTime.Hours,
+		TemperatureC: out.Temperature.Current,
+	}
+	if out.SmartStatus.Passed {
+		info.Health = "PASSED"
+	}
+	for _, attr := range out.ATASmartAttributes.Table {
+		if attr.ID == 5 { // Reallocated_Sector_Ct
+			info.ReallocatedSectors = attr.Raw.Value
+		}
+	}
+	return info
+}
@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseSmartctlJSON(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/smartctl_sda.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	got := parseSmartctlJSON(data)
+	want := &SMARTInfo{
+		Health:             "PASSED",
+		PowerOnHours:       43512,
+		ReallocatedSectors: 24,
+		TemperatureC:       37,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSmartctlJSON = %+v, want %+v", got, want)
+	}
+
+	if got := parseSmartctlJSON([]byte(`{"smart_status":{"passed":false}}`)); got == nil || got.Health != "FAILED" {
+		t.Errorf("expected FAILED, got %+v", got)
+	}
+
+	// Virtual disk: smartctl answers but has no SMART data
+	virtio := `{"smartctl":{"exit_status":4,"messages":[{"string":"/dev/vda: Unable to detect device type","severity":"error"}]}}`
+	if got := parseSmartctlJSON([]byte(virtio)); got != nil {
+		t.Errorf("expected nil without smart_status, got %+v", got)
+	}
+}
+
+func TestCollectSMARTSkipsNVMeAndVirtual(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/smartctl_sda.json")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	disks := []DiskInfo{
+		{Name: "sda", Type: "disk"},
+		{Name: "nvme0n1", Type: "disk", Health: &DiskHealth{Source: "nvme-cli"}},
+		{Name: "loop0", Type: "loop"},
+		{Name: "zram0", Type: "disk"},
+	}
+	collectSMART(context.Background(), cannedRunner{
+		"command -v smartctl":                          "/usr/sbin/smartctl\n",
+		"smartctl -H -A -j /dev/sda 2>/dev/null; true": string(data),
+	}, disks)
+
+	if disks[0].SMART == nil || disks[0].SMART.Health != "PASSED" {
+		t.Errorf("sda SMART = %+v", disks[0].SMART)
+	}
+	for _, d := range disks[1:] {
+		if d.SMART != nil {
+			t.Errorf("%s should be skipped, got %+v", d.Name, d.SMART)
+		}
+	}
+}
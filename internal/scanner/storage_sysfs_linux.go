@@ -0,0 +1,156 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// collectSysBlock builds the disk list from /sys/block without shelling
+// out, for minimal containers that lack lsblk. root prefixes the /sys and
+// /proc paths (empty for the live system).
+//
+//	/sys/block/sda/size               size in 512-byte sectors
+//	/sys/block/sda/removable          1 for removable media
+//	/sys/block/sda/ro                 1 for read-only
+//	/sys/block/sda/queue/rotational   1 for spinning disks
+//	/sys/block/sda/device/model       model string (SCSI/SATA/virtio-scsi)
+//	/sys/block/sda/sda1/partition     present on partitions
+func collectSysBlock(root string) []DiskInfo {
+	blockDir := filepath.Join(root, "sys/block")
+	entries, err := os.ReadDir(blockDir)
+	if err != nil {
+		return nil
+	}
+	mounts := readProcMounts(filepath.Join(root, "proc/mounts")).byDevice
+
+	var disks []DiskInfo
+	for _, e := range entries {
+		name := e.Name()
+		// RAM disks and loop devices are not storage hardware
+		if strings.HasPrefix(name, "ram") || strings.HasPrefix(name, "loop") {
+			continue
+		}
+		dir := filepath.Join(blockDir, name)
+		sectors := readSysInt(filepath.Join(dir, "size"))
+		if sectors == 0 {
+			continue
+		}
+
+		disks = append(disks, DiskInfo{
+			Name:        name,
+			SizeGB:      sectorsToGB(sectors),
+			Type:        "disk",
+			Model:       readSysString(filepath.Join(dir, "device/model")),
+			ReadOnly:    readSysInt(filepath.Join(dir, "ro")) == 1,
+			Rotational:  readSysInt(filepath.Join(dir, "queue/rotational")) == 1,
+			Removable:   readSysInt(filepath.Join(dir, "removable")) == 1,
+			MountPoints: mounts[name],
+		})
+
+		// Partitions are subdirectories carrying a "partition" file
+		parts, _ := os.ReadDir(dir)
+		for _, p := range parts {
+			pdir := filepath.Join(dir, p.Name())
+			if _, err := os.Stat(filepath.Join(pdir, "partition")); err != nil {
+				continue
+			}
+			disks = append(disks, DiskInfo{
+				Name:        p.Name(),
+				SizeGB:      sectorsToGB(readSysInt(filepath.Join(pdir, "size"))),
+				Type:        "part",
+				ReadOnly:    readSysInt(filepath.Join(pdir, "ro")) == 1,
+				MountPoints: mounts[p.Name()],
+			})
+		}
+	}
+	return disks
+}
+
+// procMounts is /proc/mounts, indexed both ways the storage scan needs.
+type procMounts struct {
+	// Block device names (sda1, nvme0n1p2) to their sorted mount points
+	byDevice map[string][]string
+	// Mount points to their filesystem type and options
+	byMountPoint map[string]mountEntry
+}
+
+// parseProcMounts parses /proc/mounts:
+//
+//	/dev/nvme0n1p2 / ext4 rw,noatime,errors=remount-ro 0 0
+//
+// Spaces and other special characters in paths are octal-escaped
+// ("/mnt/my\040disk"). When a mount point is mounted over, the last entry
+// is the visible one.
+func parseProcMounts(output string) procMounts {
+	mounts := procMounts{
+		byDevice:     make(map[string][]string),
+		byMountPoint: make(map[string]mountEntry),
+	}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		mountPoint := unescapeMountPath(fields[1])
+		mounts.byMountPoint[mountPoint] = mountEntry{
+			Type:    fields[2],
+			Options: strings.Split(fields[3], ","),
+		}
+		if strings.HasPrefix(fields[0], "/dev/") {
+			dev := filepath.Base(fields[0])
+			mounts.byDevice[dev] = append(mounts.byDevice[dev], mountPoint)
+		}
+	}
+	for _, mps := range mounts.byDevice {
+		sort.Strings(mps)
+	}
+	return mounts
+}
+
+// readProcMounts reads and parses the /proc/mounts file at path. An
+// unreadable file yields no mounts.
+func readProcMounts(path string) procMounts {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return procMounts{}
+	}
+	return parseProcMounts(string(data))
+}
+
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func sectorsToGB(sectors int64) float64 {
+	return float64(sectors*512) / (1024 * 1024 * 1024)
+}
+
+func readSysString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readSysInt(path string) int64 {
+	n, _ := strconv.ParseInt(readSysString(path), 10, 64)
+	return n
+}
@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollectSysBlock(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		// 100 GiB SATA SSD with two partitions
+		"sys/block/sda/size":              "209715200\n",
+		"sys/block/sda/ro":                "0\n",
+		"sys/block/sda/removable":         "0\n",
+		"sys/block/sda/queue/rotational":  "0\n",
+		"sys/block/sda/device/model":      "Samsung SSD 870 \n",
+		"sys/block/sda/sda1/partition":    "1\n",
+		"sys/block/sda/sda1/size":         "1048576\n",
+		"sys/block/sda/sda1/ro":           "0\n",
+		"sys/block/sda/sda2/partition":    "2\n",
+		"sys/block/sda/sda2/size":         "208666624\n",
+		"sys/block/sda/sda2/ro":           "0\n",
+		"sys/block/sda/queue/nr_requests": "64\n",
+		// 2 GiB removable, read-only spinning media without partitions
+		"sys/block/sdb/size":             "4194304\n",
+		"sys/block/sdb/ro":               "1\n",
+		"sys/block/sdb/removable":        "1\n",
+		"sys/block/sdb/queue/rotational": "1\n",
+		// Skipped: loop device, RAM disk, empty drive
+		"sys/block/loop0/size": "2048\n",
+		"sys/block/ram0/size":  "8192\n",
+		"sys/block/sr0/size":   "0\n",
+
+		"proc/mounts": "proc /proc proc rw 0 0\n" +
+			"/dev/sda2 / ext4 rw,relatime 0 0\n" +
+			"/dev/sda1 /boot/efi vfat rw 0 0\n" +
+			"/dev/sdb /mnt/usb\\040stick iso9660 ro 0 0\n" +
+			"/dev/sda2 /var/lib/kubelet ext4 rw 0 0\n",
+	})
+
+	got := collectSysBlock(root)
+	want := []DiskInfo{
+		{Name: "sda", SizeGB: 100, Type: "disk", Model: "Samsung SSD 870"},
+		{Name: "sda1", SizeGB: 0.5, Type: "part", MountPoints: []string{"/boot/efi"}},
+		{Name: "sda2", SizeGB: 99.5, Type: "part", MountPoints: []string{"/", "/var/lib/kubelet"}},
+		{Name: "sdb", SizeGB: 2, Type: "disk", ReadOnly: true, Rotational: true, Removable: true, MountPoints: []string{"/mnt/usb stick"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectSysBlock =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCollectSysBlockMissing(t *testing.T) {
+	if got := collectSysBlock(t.TempDir()); got != nil {
+		t.Errorf("collectSysBlock on empty root = %+v, want nil", got)
+	}
+}
+
+func TestIsLocalRunner(t *testing.T) {
+	if !isLocalRunner(LocalRunner{}) {
+		t.Error("LocalRunner not detected as local")
+	}
+	if !isLocalRunner(NewProgress(nil).Runner(LocalRunner{})) {
+		t.Error("progress-wrapped LocalRunner not detected as local")
+	}
+	if isLocalRunner(cannedRunner{}) {
+		t.Error("canned runner detected as local")
+	}
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+// The enum properties are stringified so the JSON carries their names.
+const (
+	windowsVolumesScript = `ConvertTo-Json -Compress -InputObject @(Get-Volume | ` +
+		`Select-Object DriveLetter, FileSystemLabel, FileSystem, Size, SizeRemaining)`
+	windowsDisksScript = `ConvertTo-Json -Compress -InputObject @(Get-PhysicalDisk | ` +
+		`Select-Object DeviceId, FriendlyName, SerialNumber, Size, ` +
+		`@{n='MediaType';e={"$($_.MediaType)"}}, @{n='BusType';e={"$($_.BusType)"}}, @{n='HealthStatus';e={"$($_.HealthStatus)"}})`
+)
+
+func collectStorageInfo(ctx context.Context, runner CommandRunner, info *StorageInfo) error {
+	if out, err := runner.Run(ctx, PowerShellCommand(windowsVolumesScript)); err == nil {
+		info.Filesystems = convertPSVolumes(parser.ParsePSVolumes(out))
+	}
+	if out, err := runner.Run(ctx, PowerShellCommand(windowsDisksScript)); err == nil {
+		info.Disks = convertPSPhysicalDisks(parser.ParsePSPhysicalDisks(out))
+	}
+	return nil
+}
+
+// convertPSVolumes maps drive-letter volumes to filesystems mounted at
+// their drive root ("C:\").
+func convertPSVolumes(volumes []parser.PSVolume) []FilesystemInfo {
+	const gb = 1024 * 1024 * 1024
+	var filesystems []FilesystemInfo
+	for _, v := range volumes {
+		used := v.Size - v.SizeRemaining
+		filesystems = append(filesystems, FilesystemInfo{
+			Filesystem: v.DriveLetter + ":",
+			MountPoint: v.DriveLetter + `:\`,
+			Type:       v.FileSystem,
+			SizeGB:     float64(v.Size) / gb,
+			UsedGB:     float64(used) / gb,
+			AvailGB:    float64(v.SizeRemaining) / gb,
+			UsePct:     math.Round(float64(used) / float64(v.Size) * 100),
+		})
+	}
+	return filesystems
+}
+
+// convertPSPhysicalDisks names disks the way Windows addresses them
+// (PhysicalDrive0 is \\.\PhysicalDrive0).
+func convertPSPhysicalDisks(disks []parser.PSPhysicalDisk) []DiskInfo {
+	var out []DiskInfo
+	for _, d := range disks {
+		name := "PhysicalDrive" + d.DeviceID
+		if _, err := strconv.Atoi(d.DeviceID); err != nil {
+			name = d.FriendlyName
+		}
+		disk := DiskInfo{
+			Name:       name,
+			SizeGB:     float64(d.Size) / (1024 * 1024 * 1024),
+			Type:       "disk",
+			Model:      d.FriendlyName,
+			Rotational: strings.EqualFold(d.MediaType, "HDD"),
+			Removable:  strings.EqualFold(d.BusType, "USB") || strings.EqualFold(d.BusType, "SD"),
+		}
+		if !IsJunkSerial(d.SerialNumber) {
+			disk.Serial = d.SerialNumber
+		}
+		out = append(out, disk)
+	}
+	return out
+}
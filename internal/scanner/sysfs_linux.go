@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -19,7 +20,8 @@ func hostProcNetDev() string {
 	return ""
 }
 
-// collectHostInterfaces reads network interface names from /proc/1/net/dev.
+// collectHostInterfaces reads network interface names and traffic counters
+// from /proc/1/net/dev.
 // Returns nil if the file doesn't exist or can't be read.
 //
 // Format of /proc/net/dev:
@@ -58,10 +60,24 @@ func collectHostInterfaces(procNetDevPath string) []InterfaceInfo {
 			continue
 		}
 
-		interfaces = append(interfaces, InterfaceInfo{
-			Name: name,
-		})
+		iface := InterfaceInfo{Name: name}
+		// Receive: bytes packets errs drop fifo frame compressed multicast
+		// Transmit: bytes packets errs drop fifo colls carrier compressed
+		if f := strings.Fields(parts[1]); len(f) >= 11 {
+			iface.RxBytes = parseUint(f[0])
+			iface.RxPackets = parseUint(f[1])
+			iface.RxErrors = parseUint(f[2])
+			iface.TxBytes = parseUint(f[8])
+			iface.TxPackets = parseUint(f[9])
+			iface.TxErrors = parseUint(f[10])
+		}
+		interfaces = append(interfaces, iface)
 	}
 
 	return interfaces
 }
+
+func parseUint(s string) uint64 {
+	n, _ := strconv.ParseUint(s, 10, 64)
+	return n
+}
@@ -45,6 +45,14 @@ veth75825756: 72475154  298752    0    0    0     0          0         0 4195207
 			t.Errorf("missing interface %q", expected)
 		}
 	}
+	// Traffic counters come from the same line
+	eth0 := byName["eth0"]
+	if eth0.RxBytes != 452698464934 || eth0.RxPackets != 709126800 || eth0.RxErrors != 146203 {
+		t.Errorf("eth0 rx counters = %d/%d/%d", eth0.RxBytes, eth0.RxPackets, eth0.RxErrors)
+	}
+	if eth0.TxBytes != 983787744722 || eth0.TxPackets != 955845802 || eth0.TxErrors != 0 {
+		t.Errorf("eth0 tx counters = %d/%d/%d", eth0.TxBytes, eth0.TxPackets, eth0.TxErrors)
+	}
 }
 
 func TestCollectHostInterfacesNonexistentPath(t *testing.T) {
@@ -0,0 +1,23 @@
+package scanner
+
+import (
+	"context"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner/parser"
+)
+
+// thermalCmd dumps every thermal zone and hwmon temperature file as
+// "path:value" lines. Files the agent cannot read (or sensors that error
+// on read) are dropped silently; "true" keeps grep's exit status from
+// failing the command when some are missing.
+const thermalCmd = `grep -H . /sys/class/thermal/thermal_zone*/type /sys/class/thermal/thermal_zone*/temp ` +
+	`/sys/class/hwmon/hwmon*/name /sys/class/hwmon/hwmon*/temp*_input /sys/class/hwmon/hwmon*/temp*_label 2>/dev/null; true`
+
+// collectThermal reads temperature sensors from sysfs.
+func collectThermal(ctx context.Context, runner CommandRunner, info *HostInfo) {
+	out, err := runner.Run(ctx, thermalCmd)
+	if err != nil {
+		return
+	}
+	info.System.Thermal = newThermalInfo(parser.ParseSysThermal(string(out)))
+}
@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCollectThermal(t *testing.T) {
+	var info HostInfo
+	collectThermal(context.Background(), cannedRunner{
+		thermalCmd: `/sys/class/thermal/thermal_zone0/type:x86_pkg_temp
+/sys/class/thermal/thermal_zone0/temp:61000
+/sys/class/hwmon/hwmon1/name:nvme
+/sys/class/hwmon/hwmon1/temp1_label:Composite
+/sys/class/hwmon/hwmon1/temp1_input:38850
+`,
+	}, &info)
+
+	th := info.System.Thermal
+	if th == nil {
+		t.Fatal("expected thermal info")
+	}
+	if len(th.Sensors) != 2 {
+		t.Fatalf("sensors = %+v, want 2", th.Sensors)
+	}
+	if th.MaxCelsius != 61 {
+		t.Errorf("MaxCelsius = %v, want 61", th.MaxCelsius)
+	}
+}
+
+func TestCollectThermalNoSensors(t *testing.T) {
+	var info HostInfo
+	collectThermal(context.Background(), cannedRunner{thermalCmd: ""}, &info)
+	if info.System.Thermal != nil {
+		t.Errorf("expected nil thermal, got %+v", info.System.Thermal)
+	}
+}
@@ -6,3 +6,8 @@ import "strings"
 func trimOutput(out []byte) string {
 	return strings.TrimSpace(string(out))
 }
+
+// shellQuote quotes s as a single sh word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
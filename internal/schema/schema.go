@@ -0,0 +1,226 @@
+// Package schema generates JSON Schema documents for the agent's payload
+// structs by reflection, so upload consumers can validate what they
+// receive without reading the Go source.
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect emitted by Generate.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema produced by Generate.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"` // string, or []string when nullable
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+	timeType       = reflect.TypeOf(time.Time{})
+)
+
+// Option adjusts the schema Generate produces.
+type Option func(*generator)
+
+// RawField documents the json.RawMessage field of struct owner whose JSON
+// name is field as holding v (a value or pointer of the field's real type),
+// instead of accepting any value.
+func RawField(owner any, field string, v any) Option {
+	return func(g *generator) {
+		g.raw[rawKey{structType(owner), field}] = reflect.TypeOf(v)
+	}
+}
+
+// Generate returns the schema for v, which must be a struct or a pointer
+// to one. Nested named structs are emitted once under $defs and referenced.
+// Fields follow encoding/json: omitempty fields are optional, nil slices,
+// maps and pointers without omitempty may be null, and json.RawMessage
+// accepts any value unless described with RawField.
+func Generate(title string, v any, opts ...Option) *Schema {
+	g := &generator{
+		defs:  make(map[string]*Schema),
+		names: make(map[reflect.Type]string),
+		raw:   make(map[rawKey]reflect.Type),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	t := structType(v)
+	root := g.structSchema(t)
+	root.Schema = Draft
+	root.Title = title
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+type generator struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+	raw   map[rawKey]reflect.Type // set by RawField
+}
+
+// rawKey names a struct field by its JSON name.
+type rawKey struct {
+	owner reflect.Type
+	field string
+}
+
+// structType returns the type of v with pointers dereferenced.
+func structType(v any) reflect.Type {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func (g *generator) typeSchema(t reflect.Type) *Schema {
+	switch t {
+	case rawMessageType:
+		return &Schema{}
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.typeSchema(t.Elem())
+	case reflect.Struct:
+		return g.ref(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"} // base64
+		}
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	default:
+		// Interfaces and anything else: unconstrained
+		return &Schema{}
+	}
+}
+
+// ref registers t under $defs on first use and returns a reference to it.
+func (g *generator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		if name == "" {
+			return g.structSchema(t) // anonymous struct: inline
+		}
+		if _, taken := g.defs[name]; taken {
+			name = pkgName(t) + "." + name
+		}
+		g.names[t] = name
+		g.defs[name] = nil // reserve the name before recursing
+		g.defs[name] = g.structSchema(t)
+	}
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+	return s
+}
+
+// addFields adds t's JSON fields to s, flattening embedded structs the way
+// encoding/json does.
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.typeSchema(f.Type)
+		if raw, ok := g.raw[rawKey{t, name}]; ok && f.Type == rawMessageType {
+			fs = g.typeSchema(raw)
+		}
+		omitempty := hasOption(opts, "omitempty") || hasOption(opts, "omitzero")
+		if !omitempty {
+			s.Required = append(s.Required, name)
+			switch f.Type.Kind() {
+			case reflect.Pointer, reflect.Slice, reflect.Map:
+				if f.Type != rawMessageType {
+					fs = nullable(fs)
+				}
+			}
+		}
+		s.Properties[name] = fs
+	}
+}
+
+// nullable widens s to also accept null.
+func nullable(s *Schema) *Schema {
+	switch typ := s.Type.(type) {
+	case string:
+		s.Type = []string{typ, "null"}
+		return s
+	case nil:
+		if s.Ref == "" {
+			return s // already unconstrained
+		}
+	}
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+func hasOption(opts, want string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == want {
+			return true
+		}
+	}
+	return false
+}
+
+func pkgName(t reflect.Type) string {
+	p := t.PkgPath()
+	if i := strings.LastIndex(p, "/"); i >= 0 {
+		p = p[i+1:]
+	}
+	return p
+}
@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type sampleChild struct {
+	Name string `json:"name"`
+}
+
+type sampleBase struct {
+	ID int `json:"id"`
+}
+
+type sample struct {
+	sampleBase
+	Title    string            `json:"title"`
+	Count    *int32            `json:"count,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Child    *sampleChild      `json:"child,omitempty"`
+	Children []sampleChild     `json:"children,omitempty"`
+	Extra    json.RawMessage   `json:"extra,omitempty"`
+	Self     *sample           `json:"self,omitempty"`
+	Ignored  string            `json:"-"`
+	private  string
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate("Sample", &sample{})
+
+	if s.Schema != Draft || s.Title != "Sample" || s.Type != "object" {
+		t.Errorf("unexpected root: %+v", s)
+	}
+	if want := []string{"id", "title", "tags"}; !reflect.DeepEqual(s.Required, want) {
+		t.Errorf("required = %v, want %v", s.Required, want)
+	}
+	for _, name := range []string{"Ignored", "private"} {
+		if _, ok := s.Properties[name]; ok {
+			t.Errorf("unexpected property %q", name)
+		}
+	}
+	if got := s.Properties["count"].Type; got != "integer" {
+		t.Errorf("count type = %v, want integer", got)
+	}
+	if got := s.Properties["tags"].Type; !reflect.DeepEqual(got, []string{"array", "null"}) {
+		t.Errorf("tags type = %v, want nullable array", got)
+	}
+	if got := s.Properties["child"].Ref; got != "#/$defs/sampleChild" {
+		t.Errorf("child ref = %q", got)
+	}
+	if got := s.Properties["children"].Items.Ref; got != "#/$defs/sampleChild" {
+		t.Errorf("children items ref = %q", got)
+	}
+	if got := s.Properties["extra"]; got.Type != nil || got.Ref != "" {
+		t.Errorf("extra should be unconstrained, got %+v", got)
+	}
+	if _, ok := s.Defs["sample"]; !ok {
+		t.Error("recursive type should be emitted under $defs")
+	}
+}
+
+func TestGenerateRawField(t *testing.T) {
+	s := Generate("Sample", sample{}, RawField(sample{}, "extra", &sampleChild{}))
+
+	if got := s.Properties["extra"].Ref; got != "#/$defs/sampleChild" {
+		t.Errorf("extra ref = %q, want sampleChild", got)
+	}
+	if err := Validate(s, []byte(`{"id":1,"title":"a","tags":[],"extra":{"name":"x"}}`)); err != nil {
+		t.Errorf("valid extra rejected: %v", err)
+	}
+	if err := Validate(s, []byte(`{"id":1,"title":"a","tags":[],"extra":[1]}`)); err == nil {
+		t.Error("expected extra of the wrong type to fail validation")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := Generate("Sample", sample{})
+
+	good := `{"id":1,"title":"a","tags":null,"count":3,"child":{"name":"c"},
+		"labels":{"k":"v"},"extra":[1,"x"],"self":{"id":2,"title":"b","tags":[]}}`
+	if err := Validate(s, []byte(good)); err != nil {
+		t.Errorf("valid document rejected: %v", err)
+	}
+
+	bad := map[string]string{
+		"missing required": `{"id":1,"tags":[]}`,
+		"wrong type":       `{"id":1,"title":2,"tags":[]}`,
+		"fractional int":   `{"id":1.5,"title":"a","tags":[]}`,
+		"bad nested":       `{"id":1,"title":"a","tags":[],"child":{}}`,
+		"bad map value":    `{"id":1,"title":"a","tags":[],"labels":{"k":1}}`,
+		"bad recursion":    `{"id":1,"title":"a","tags":[],"self":{"id":"x"}}`,
+	}
+	for name, doc := range bad {
+		if err := Validate(s, []byte(doc)); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+
+	// A schema round-tripped through JSON still validates
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Schema
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(&decoded, []byte(`{"id":1,"title":"a","tags":"x"}`)); err == nil {
+		t.Error("decoded schema: expected nullable array to reject a string")
+	}
+}
@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Validate checks a JSON document against a schema from Generate. It
+// understands only the keywords Generate emits.
+func Validate(root *Schema, data []byte) error {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	v := validator{defs: root.Defs}
+	return v.validate(root, doc, "$")
+}
+
+type validator struct {
+	defs map[string]*Schema
+}
+
+func (v validator) validate(s *Schema, doc any, path string) error {
+	if s.Ref != "" {
+		def, ok := v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok || def == nil {
+			return fmt.Errorf("%s: unresolved $ref %q", path, s.Ref)
+		}
+		return v.validate(def, doc, path)
+	}
+
+	if len(s.AnyOf) > 0 {
+		var errs []string
+		for _, alt := range s.AnyOf {
+			err := v.validate(alt, doc, path)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s: no anyOf alternative matched: %s", path, strings.Join(errs, "; "))
+	}
+
+	if s.Type != nil && !typeMatches(s.Type, doc) {
+		return fmt.Errorf("%s: expected %v, got %s", path, s.Type, jsonType(doc))
+	}
+
+	switch d := doc.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := d[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ps, ok := s.Properties[k]
+			if !ok {
+				ps = s.AdditionalProperties
+			}
+			if ps == nil {
+				continue
+			}
+			if err := v.validate(ps, d[k], path+"."+k); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range d {
+				if err := v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func typeMatches(typ any, doc any) bool {
+	switch t := typ.(type) {
+	case string:
+		return typeIs(t, doc)
+	case []string:
+		for _, one := range t {
+			if typeIs(one, doc) {
+				return true
+			}
+		}
+		return false
+	case []any: // decoded from JSON
+		for _, one := range t {
+			if s, ok := one.(string); ok && typeIs(s, doc) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func typeIs(typ string, doc any) bool {
+	switch typ {
+	case "integer":
+		n, ok := doc.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := doc.(float64)
+		return ok
+	default:
+		return jsonType(doc) == typ
+	}
+}
+
+func jsonType(doc any) string {
+	switch doc.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", doc)
+}
@@ -0,0 +1,73 @@
+package signing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AnyAction and AnyOrigin are wildcards in an OriginPolicy.
+const (
+	AnyAction = "*"
+	AnyOrigin = "*"
+)
+
+// OriginPolicy maps a signing origin to the actions (protocol message
+// types, e.g. "session.open") it may issue. Origins without an entry fall
+// back to the AnyOrigin entry, if any; otherwise they may issue nothing.
+// A nil policy allows every origin every action.
+type OriginPolicy map[string][]string
+
+// Allows reports whether origin may issue action.
+func (p OriginPolicy) Allows(origin, action string) bool {
+	if p == nil {
+		return true
+	}
+	actions, ok := p[origin]
+	if !ok {
+		actions = p[AnyOrigin]
+	}
+	for _, a := range actions {
+		if a == action || a == AnyAction {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseOriginPolicy parses "origin=action|action" entries, e.g.
+// {"saas-api": "*", "automation": "pty.resize|session.close"}.
+func ParseOriginPolicy(entries map[string]string) (OriginPolicy, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	p := make(OriginPolicy, len(entries))
+	for origin, spec := range entries {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			return nil, fmt.Errorf("origin policy: empty origin")
+		}
+		var actions []string
+		for _, a := range strings.Split(spec, "|") {
+			if a = strings.TrimSpace(a); a != "" {
+				actions = append(actions, a)
+			}
+		}
+		if len(actions) == 0 {
+			return nil, fmt.Errorf("origin policy: no actions for origin %q", origin)
+		}
+		p[origin] = actions
+	}
+	return p, nil
+}
+
+// commandAction returns the "type" field of a command.
+func commandAction(command []byte) string {
+	var msg struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(command, &msg); err != nil {
+		return ""
+	}
+	return msg.Type
+}
@@ -0,0 +1,111 @@
+package signing
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// CertExpiryWarning is how far ahead of expiry a certificate is reported.
+const CertExpiryWarning = 14 * 24 * time.Hour
+
+// KeyMaterial describes the agent's configured signing/verification keys.
+// Empty fields are not checked.
+type KeyMaterial struct {
+	PublicKey      string // Ed25519 command verification key (hex or base64)
+	PrivateKeyPath string // PEM or OpenSSH private key used to sign requests
+	CertPath       string // optional X.509 (PEM) or SSH certificate for the private key
+}
+
+// SelfCheckResult is the outcome of a key material self-check.
+type SelfCheckResult struct {
+	Healthy  bool
+	Warnings []string
+	// CertExpiry is the certificate expiry time, zero if none was checked or it never expires.
+	CertExpiry time.Time
+}
+
+// CheckKeyMaterial validates that configured key material is present and
+// parseable, and that any certificate is not expired or close to expiry.
+func CheckKeyMaterial(km KeyMaterial, now time.Time) SelfCheckResult {
+	var result SelfCheckResult
+
+	if km.PublicKey != "" {
+		if _, err := ParsePublicKey(km.PublicKey); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("public key: %v", err))
+		}
+	}
+
+	if km.PrivateKeyPath != "" {
+		if err := checkPrivateKey(km.PrivateKeyPath); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("private key %s: %v", km.PrivateKeyPath, err))
+		}
+	}
+
+	if km.CertPath != "" {
+		expiry, err := certExpiry(km.CertPath)
+		result.CertExpiry = expiry
+		switch {
+		case err != nil:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("certificate %s: %v", km.CertPath, err))
+		case expiry.IsZero():
+			// Certificate never expires
+		case !now.Before(expiry):
+			result.Warnings = append(result.Warnings, fmt.Sprintf("certificate %s expired at %s", km.CertPath, expiry.UTC().Format(time.RFC3339)))
+		case expiry.Sub(now) < CertExpiryWarning:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("certificate %s expires at %s — rotation needed", km.CertPath, expiry.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	result.Healthy = len(result.Warnings) == 0
+	return result
+}
+
+func checkPrivateKey(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := ssh.ParseRawPrivateKey(data); err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+	return nil
+}
+
+// certExpiry returns the expiry of a PEM X.509 certificate or an
+// OpenSSH certificate (authorized_keys format, e.g. *-cert.pub).
+// A zero time means the certificate does not expire.
+func certExpiry(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return time.Time{}, fmt.Errorf("unexpected PEM block %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse: %w", err)
+		}
+		return cert.NotAfter, nil
+	}
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse: %w", err)
+	}
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		return time.Time{}, fmt.Errorf("not a certificate")
+	}
+	if cert.ValidBefore == ssh.CertTimeInfinity {
+		return time.Time{}, nil
+	}
+	return time.Unix(int64(cert.ValidBefore), 0), nil
+}
@@ -38,6 +38,7 @@ type SignedPayload struct {
 type Verifier struct {
 	pubKey     ed25519.PublicKey
 	nonceStore *NonceStore
+	policy     OriginPolicy
 }
 
 // NewVerifier creates a Verifier with the given Ed25519 public key.
@@ -48,6 +49,12 @@ func NewVerifier(pubKey ed25519.PublicKey) *Verifier {
 	}
 }
 
+// SetOriginPolicy restricts which actions each verified origin may issue.
+// Pass nil to allow all origins everything.
+func (v *Verifier) SetOriginPolicy(p OriginPolicy) {
+	v.policy = p
+}
+
 // ParsePublicKey decodes a hex or base64-encoded Ed25519 public key.
 func ParsePublicKey(s string) (ed25519.PublicKey, error) {
 	s = strings.TrimSpace(s)
@@ -161,6 +168,16 @@ func (v *Verifier) Verify(raw []byte) (command []byte, result VerificationResult
 		}
 	}
 
+	// The origin is authentic only now; apply its action policy
+	if action := commandAction(command); !v.policy.Allows(env.Origin, action) {
+		return nil, VerificationResult{
+			Reason:    fmt.Sprintf("origin %q not permitted to issue %q", env.Origin, action),
+			UserID:    env.UserID,
+			Origin:    env.Origin,
+			Timestamp: env.Timestamp,
+		}
+	}
+
 	result.Valid = true
 	return command, result
 }
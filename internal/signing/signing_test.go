@@ -272,3 +272,77 @@ func TestSignAndVerifyRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifyOriginPolicy(t *testing.T) {
+	pub, priv := generateKeyPair(t)
+	v := NewVerifier(pub)
+	policy, err := ParseOriginPolicy(map[string]string{
+		"saas-api":   "*",
+		"automation": "pty.resize|session.close",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.SetOriginPolicy(policy)
+	now := time.Now().Unix()
+
+	// automation may not open a shell
+	open := []byte(`{"type":"session.open","sessionId":"s1"}`)
+	_, result := v.Verify(signCommand(t, priv, open, now, "n1", "bot", "automation"))
+	if result.Valid {
+		t.Fatal("expected session.open from automation to be rejected")
+	}
+	if result.Origin != "automation" {
+		t.Errorf("expected origin automation, got %q", result.Origin)
+	}
+
+	// ...but may resize one
+	resize := []byte(`{"type":"pty.resize","sessionId":"s1","cols":80,"rows":24}`)
+	if _, result := v.Verify(signCommand(t, priv, resize, now, "n2", "bot", "automation")); !result.Valid {
+		t.Errorf("expected pty.resize from automation to be accepted, got: %s", result.Reason)
+	}
+
+	// saas-api may issue anything
+	if _, result := v.Verify(signCommand(t, priv, open, now, "n3", "user1", "saas-api")); !result.Valid {
+		t.Errorf("expected session.open from saas-api to be accepted, got: %s", result.Reason)
+	}
+
+	// Origins without an entry are denied
+	if _, result := v.Verify(signCommand(t, priv, resize, now, "n4", "user1", "unknown")); result.Valid {
+		t.Error("expected unlisted origin to be rejected")
+	}
+}
+
+func TestVerifyOriginPolicyAfterSignature(t *testing.T) {
+	pub, _ := generateKeyPair(t)
+	_, otherPriv := generateKeyPair(t)
+	v := NewVerifier(pub)
+	v.SetOriginPolicy(OriginPolicy{"automation": {"pty.resize"}})
+
+	// A forged message is rejected for its signature, not its policy
+	cmd := []byte(`{"type":"session.open","sessionId":"s1"}`)
+	_, result := v.Verify(signCommand(t, otherPriv, cmd, time.Now().Unix(), "n1", "bot", "automation"))
+	if result.Valid || result.Reason != "signature verification failed" {
+		t.Errorf("expected signature failure, got valid=%v reason=%q", result.Valid, result.Reason)
+	}
+}
+
+func TestParseOriginPolicy(t *testing.T) {
+	p, err := ParseOriginPolicy(map[string]string{"automation": " pty.resize | session.close ", "*": "pty.resize"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Allows("automation", "session.close") || p.Allows("automation", "session.open") {
+		t.Errorf("unexpected automation policy: %v", p["automation"])
+	}
+	if !p.Allows("other", "pty.resize") || p.Allows("other", "session.open") {
+		t.Error("expected wildcard origin entry to apply to unlisted origins")
+	}
+
+	if p, err := ParseOriginPolicy(nil); err != nil || p != nil || !p.Allows("any", "session.open") {
+		t.Errorf("empty policy should allow everything, got %v, %v", p, err)
+	}
+	if _, err := ParseOriginPolicy(map[string]string{"automation": " | "}); err == nil {
+		t.Error("expected error for origin without actions")
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"strings"
 )
 
-// allowedPrefixes are read-only command prefixes that can be executed remotely.
+// allowedPrefixes are read-only commands that can be executed remotely,
+// given as their leading argv words: "pmset -g" allows "pmset -g batt" but
+// not "pmset -gx". A path word also covers paths below it.
 var allowedPrefixes = []string{
 	// System info
 	"uname", "hostname", "whoami", "id", "arch",
 	"sysctl", "sw_vers", "uptime", "vm_stat", "top -l 1",
 
+	// Power / battery (pmset -g only reads settings; bare pmset changes them)
+	"pmset -g", "ioreg -rn AppleSmartBattery",
+
 	// Hardware / resources
 	"cat /proc/cpuinfo", "cat /proc/meminfo",
 	"cat /etc/os-release", "cat /etc/machine-id", "cat /etc/hostname",
@@ -28,6 +33,7 @@ var allowedPrefixes = []string{
 	"networksetup -listallhardwareports", "networksetup -getinfo",
 	"netstat -rn", "netstat -tlnp", "netstat -ulnp",
 	"ss -tlnp", "ss -ulnp",
+	"lsof -iTCP -sTCP:LISTEN",
 
 	// USB enumeration
 	"system_profiler SPUSBDataType",
@@ -35,6 +41,7 @@ var allowedPrefixes = []string{
 
 	// Services
 	"systemctl list-units", "systemctl status", "systemctl is-active",
+	"needs-restarting -r",
 	"brew services list",
 	"launchctl list",
 	"rc-status",
@@ -87,10 +94,13 @@ var blockedPatterns = []*regexp.Regexp{
 	regexp.MustCompile(`\bcurl\b.*-X\s*(POST|PUT|DELETE|PATCH)`),
 	regexp.MustCompile(`\bwget\b`),
 	regexp.MustCompile(`[|&;` + "`" + `$].*\brm\b`),
+	// Command chaining or substitution would run more than the allowed command
+	regexp.MustCompile(`[;&|` + "`" + `\n]|\$\(`),
 }
 
 // IsCommandAllowed checks if a command is safe to execute remotely.
-// It must match an allowed prefix AND not contain any blocked patterns.
+// Its leading words must match an allowed prefix AND it must not contain any
+// blocked patterns.
 func IsCommandAllowed(cmd string) bool {
 	trimmed := strings.TrimSpace(cmd)
 
@@ -101,12 +111,30 @@ func IsCommandAllowed(cmd string) bool {
 		}
 	}
 
-	// Check allowed prefixes
+	// Check allowed prefixes word by word
+	args := strings.Fields(trimmed)
 	for _, prefix := range allowedPrefixes {
-		if strings.HasPrefix(trimmed, prefix) {
+		if hasArgPrefix(args, strings.Fields(prefix)) {
 			return true
 		}
 	}
 
 	return false
 }
+
+// hasArgPrefix reports whether args starts with the words of prefix.
+func hasArgPrefix(args, prefix []string) bool {
+	if len(args) < len(prefix) {
+		return false
+	}
+	for i, word := range prefix {
+		if args[i] == word {
+			continue
+		}
+		isPath := strings.HasPrefix(word, "/") || strings.HasPrefix(word, "~/")
+		if !isPath || !strings.HasPrefix(args[i], strings.TrimSuffix(word, "/")+"/") {
+			return false
+		}
+	}
+	return true
+}
@@ -28,7 +28,9 @@ type Target struct {
 	Port string
 }
 
-// ParseTarget parses a string like "user@host" or "user@host:2222".
+// ParseTarget parses a string like "user@host" or "user@host:2222". IPv6
+// hosts need brackets only with a port: "user@2001:db8::1",
+// "user@[2001:db8::1]" and "user@[2001:db8::1]:2222" are all accepted.
 func ParseTarget(s string) (Target, error) {
 	t := Target{Port: "22"}
 
@@ -40,17 +42,36 @@ func ParseTarget(s string) (Target, error) {
 	t.User = parts[0]
 	hostPort := parts[1]
 
-	// Check for port
-	if h, p, err := net.SplitHostPort(hostPort); err == nil {
-		t.Host = h
-		t.Port = p
-	} else {
+	switch {
+	case isIPv6Literal(hostPort):
+		// Bare IPv6 address: its colons are not a port separator
 		t.Host = hostPort
+	case strings.HasPrefix(hostPort, "[") && strings.HasSuffix(hostPort, "]"):
+		// Bracketed IPv6 address without a port
+		t.Host = hostPort[1 : len(hostPort)-1]
+	default:
+		if h, p, err := net.SplitHostPort(hostPort); err == nil {
+			t.Host = h
+			t.Port = p
+		} else {
+			t.Host = hostPort
+		}
+	}
+	if t.Host == "" || t.Port == "" {
+		return t, fmt.Errorf("invalid SSH target %q (expected user@host[:port])", s)
 	}
 
 	return t, nil
 }
 
+// isIPv6Literal reports whether s is an unbracketed IPv6 address,
+// optionally with a zone ("fe80::1%eth0").
+func isIPv6Literal(s string) bool {
+	addr, _, _ := strings.Cut(s, "%")
+	ip := net.ParseIP(addr)
+	return ip != nil && strings.Contains(addr, ":")
+}
+
 // ParseTargets splits a comma-separated list of SSH targets.
 func ParseTargets(s string) ([]Target, error) {
 	var targets []Target
@@ -80,7 +101,7 @@ func (t Target) String() string {
 	if t.Port == "22" {
 		return t.User + "@" + t.Host
 	}
-	return fmt.Sprintf("%s@%s:%s", t.User, t.Host, t.Port)
+	return t.User + "@" + t.Addr()
 }
 
 // NewRunner establishes an SSH connection and returns a Runner.
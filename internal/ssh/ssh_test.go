@@ -34,11 +34,17 @@ func TestIsCommandAllowed(t *testing.T) {
 		{"free -b", "free memory"},
 		{"sysctl -n hw.memsize", "sysctl"},
 		{"sw_vers -productVersion", "sw_vers"},
+		{"pmset -g batt", "pmset battery"},
+		{"ioreg -rn AppleSmartBattery", "ioreg battery"},
 		{"diskutil list", "diskutil"},
 		{"ps aux", "process list"},
+		{"ss -tlnp", "listening ports"},
+		{"lsof -iTCP -sTCP:LISTEN -P -n", "lsof listening ports"},
 		{"which kubectl", "which"},
 		{"test -f /usr/local/bin/k3s", "test file"},
 		{"find /etc/rancher -name config.yaml", "find file"},
+		{"cat /etc/rancher/k3s/config.yaml", "file under allowed dir"},
+		{"pmset -g", "pmset settings"},
 	}
 
 	for _, tc := range allowed {
@@ -68,6 +74,7 @@ func TestIsCommandBlocked(t *testing.T) {
 		{"sudo rm -rf /", "sudo"},
 		{"systemctl start nginx", "systemctl start"},
 		{"systemctl restart kubelet", "systemctl restart"},
+		{"pmset sleep 0", "pmset set"},
 		{"kubectl delete pod foo", "kubectl delete"},
 		{"kubectl apply -f foo.yaml", "kubectl apply"},
 		{"kubectl exec -it pod -- sh", "kubectl exec"},
@@ -77,6 +84,14 @@ func TestIsCommandBlocked(t *testing.T) {
 		{"ls; rm -rf /", "semicolon rm"},
 		{"python3 -c 'import os'", "arbitrary code"},
 		{"bash -c 'echo pwned'", "bash exec"},
+		{"pmset -g; pmset sleep 0", "semicolon after allowed prefix"},
+		{"pmset -g ; shutdown -h now", "separate semicolon"},
+		{"pmset -gfoo", "prefix without word boundary"},
+		{"uname -a && reboot", "and chain"},
+		{"hostname `reboot`", "backtick substitution"},
+		{"hostname $(reboot)", "dollar substitution"},
+		{"uname\nreboot", "newline"},
+		{"cat /etc/ranchers", "sibling of allowed path"},
 	}
 
 	for _, tc := range blocked {
@@ -100,6 +115,13 @@ func TestParseTarget(t *testing.T) {
 		{"ubuntu@myhost.local", "ubuntu", "myhost.local", "22", false},
 		{"user@host:2222", "user", "host", "2222", false},
 		{"deploy@[::1]:22", "deploy", "::1", "22", false},
+		{"deploy@[2001:db8::1]:2222", "deploy", "2001:db8::1", "2222", false},
+		{"deploy@[2001:db8::1]", "deploy", "2001:db8::1", "22", false},
+		{"deploy@::1", "deploy", "::1", "22", false},
+		{"deploy@2001:db8::1", "deploy", "2001:db8::1", "22", false},
+		{"deploy@fe80::1%eth0", "deploy", "fe80::1%eth0", "22", false},
+		{"deploy@[]", "", "", "", true},
+		{"deploy@host:", "", "", "", true},
 		{"noatsign", "", "", "", true},
 		{"@nouser", "", "", "", true},
 		{"nohost@", "", "", "", true},
@@ -130,6 +152,21 @@ func TestParseTarget(t *testing.T) {
 	}
 }
 
+func TestTargetStringIPv6RoundTrip(t *testing.T) {
+	tests := map[Target]string{
+		{User: "root", Host: "2001:db8::1", Port: "22"}:   "root@2001:db8::1",
+		{User: "root", Host: "2001:db8::1", Port: "2222"}: "root@[2001:db8::1]:2222",
+	}
+	for target, want := range tests {
+		if got := target.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+		if parsed, err := ParseTarget(want); err != nil || parsed != target {
+			t.Errorf("ParseTarget(%q) = %+v, %v; want %+v", want, parsed, err, target)
+		}
+	}
+}
+
 func TestParseTargets(t *testing.T) {
 	tests := []struct {
 		input string
@@ -0,0 +1,88 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// DefaultEnvDenyPatterns match environment variables that usually hold
+// credentials. They keep the agent's own token and cloud keys out of
+// terminal sessions.
+var DefaultEnvDenyPatterns = []string{
+	"TB_TOKEN", "TB_SECRET",
+	"*_TOKEN", "*_SECRET", "*_PASSWORD", "*_KEY",
+}
+
+// EnvFilter decides which of the agent's environment variables a PTY
+// session inherits. Variables matching a deny pattern are dropped unless
+// they also match an allow pattern. Patterns are shell globs (path.Match
+// syntax) over the variable name, compared case-insensitively.
+type EnvFilter struct {
+	deny  []string
+	allow []string
+}
+
+// NewEnvFilter checks and stores deny and allow patterns.
+func NewEnvFilter(deny, allow []string) (*EnvFilter, error) {
+	f := &EnvFilter{}
+	for _, p := range deny {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("env deny pattern %q: %w", p, err)
+		}
+		f.deny = append(f.deny, strings.ToUpper(p))
+	}
+	for _, p := range allow {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("env allow pattern %q: %w", p, err)
+		}
+		f.allow = append(f.allow, strings.ToUpper(p))
+	}
+	return f, nil
+}
+
+// defaultEnvFilter is used by sessions started without a filter.
+var defaultEnvFilter, _ = NewEnvFilter(DefaultEnvDenyPatterns, nil)
+
+// Filter returns environ ("KEY=value" entries) without the denied
+// variables. A nil EnvFilter applies DefaultEnvDenyPatterns.
+func (f *EnvFilter) Filter(environ []string) []string {
+	if f == nil {
+		f = defaultEnvFilter
+	}
+	var filtered []string
+	for _, env := range environ {
+		key, _, _ := strings.Cut(env, "=")
+		if f.Allowed(key) {
+			filtered = append(filtered, env)
+		}
+	}
+	return filtered
+}
+
+// Allowed reports whether a session may inherit the variable key.
+func (f *EnvFilter) Allowed(key string) bool {
+	if f == nil {
+		f = defaultEnvFilter
+	}
+	key = strings.ToUpper(key)
+	if matchAny(f.allow, key) {
+		return true
+	}
+	return !matchAny(f.deny, key)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// filteredEnv returns os.Environ() with the variables f denies removed.
+func filteredEnv(f *EnvFilter) []string {
+	return f.Filter(os.Environ())
+}
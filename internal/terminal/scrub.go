@@ -0,0 +1,163 @@
+package terminal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScrubMask replaces secrets in scrubbed output.
+const ScrubMask = "***"
+
+// DefaultScrubPatterns match common secrets that show up when someone
+// cats a credentials file or env dump. Patterns with a capture group mask
+// only the group (the value), keeping the key visible for context.
+var DefaultScrubPatterns = []string{
+	// AWS access key IDs (long-term and STS)
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	// AWS secret access keys next to their usual key names
+	`(?i)aws_secret_access_key["']?\s*[=:]\s*["']?([A-Za-z0-9/+=]{40})`,
+	// JWTs: base64url header.payload.signature, header starting {"
+	`\beyJ[A-Za-z0-9_-]{4,}\.eyJ[A-Za-z0-9_-]{4,}\.[A-Za-z0-9_-]+`,
+	// password=..., passwd: ..., secret=..., token=...
+	`(?i)(?:password|passwd|secret|token)["']?\s*[=:]\s*["']?([^\s"']+)`,
+}
+
+// Scrubber masks secrets in terminal output before it leaves the agent.
+type Scrubber struct {
+	patterns []*regexp.Regexp
+}
+
+// NewScrubber compiles patterns (Go regexp syntax).
+func NewScrubber(patterns []string) (*Scrubber, error) {
+	s := &Scrubber{}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("scrub pattern %q: %w", p, err)
+		}
+		s.patterns = append(s.patterns, re)
+	}
+	return s, nil
+}
+
+// Scrub returns text with every match replaced by ScrubMask. A nil
+// Scrubber returns text unchanged.
+func (s *Scrubber) Scrub(text string) string {
+	if s == nil {
+		return text
+	}
+	for _, re := range s.patterns {
+		text = maskMatches(re, text)
+	}
+	return text
+}
+
+// maskMatches masks each match of re, or only its first capture group
+// when the pattern has one.
+func maskMatches(re *regexp.Regexp, text string) string {
+	matches := re.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(ScrubMask)
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// scrubHoldback is the most output held back waiting for the rest of a
+// line, bounding the added latency and memory.
+const scrubHoldback = 4096
+
+// scrubIdleFlush is how long a held partial line waits for the rest of its
+// line. A prompt such as "Password:" that ends a full read is then emitted
+// instead of stalling until the user types.
+const scrubIdleFlush = 50 * time.Millisecond
+
+// splitScrubbable splits PTY output into the part safe to scrub and emit
+// now and a trailing partial line to hold until the next read, so a secret
+// cut by a read boundary is still matched whole. Output is held only when
+// more is known to follow (the read filled the buffer); otherwise prompts
+// and partial lines would stall.
+func splitScrubbable(data string, more bool) (emit, hold string) {
+	if !more {
+		return data, ""
+	}
+	i := strings.LastIndexByte(data, '\n')
+	if len(data)-(i+1) > scrubHoldback {
+		return data, ""
+	}
+	return data[:i+1], data[i+1:]
+}
+
+// scrubBuffer scrubs PTY output read by read, holding back trailing
+// partial lines with splitScrubbable and emitting a held line on its own
+// once no output has followed it for idle.
+type scrubBuffer struct {
+	scrubber *Scrubber
+	emit     func(string)
+	idle     time.Duration
+
+	mu      sync.Mutex // serializes emit and guards the fields below
+	pending string
+	timer   *time.Timer
+	gen     int // bumped by each write, so a stale timer flushes nothing
+}
+
+// write scrubs and emits data, which is held back in part when more is
+// true (see splitScrubbable).
+func (b *scrubBuffer) write(data string, more bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.gen++
+	var out string
+	out, b.pending = splitScrubbable(b.pending+data, more)
+	if out != "" {
+		b.emit(b.scrubber.Scrub(out))
+	}
+	if b.pending != "" {
+		gen := b.gen
+		b.timer = time.AfterFunc(b.idle, func() { b.flushIf(gen) })
+	}
+}
+
+// flush scrubs and emits any held partial line.
+func (b *scrubBuffer) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.flushLocked()
+}
+
+// flushIf flushes when no write has happened since generation gen.
+func (b *scrubBuffer) flushIf(gen int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.gen == gen {
+		b.flushLocked()
+	}
+}
+
+func (b *scrubBuffer) flushLocked() {
+	if b.pending != "" {
+		b.emit(b.scrubber.Scrub(b.pending))
+		b.pending = ""
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"io"
 	"os"
 	"os/exec"
-	"strings"
 	"sync"
 	"time"
 
@@ -22,13 +21,17 @@ type PTYSession struct {
 	rows      uint16
 	onOutput  func(sessionID, data string)
 	onError   func(sessionID, errMsg string)
+	scrubber  *Scrubber
 	done      chan struct{}
 	closeOnce sync.Once
 }
 
 // NewPTYSession spawns a new shell and starts relaying output.
 // shellCmd overrides the default shell if non-empty (e.g., ["nsenter", "-t", "1", "-m", "-u", "-i", "-n", "--", "/bin/bash"]).
-func NewPTYSession(id string, cols, rows int, shellCmd []string, onOutput func(string, string), onError func(string, string)) (*PTYSession, error) {
+// scrubber, if non-nil, masks secrets in output before onOutput sees it.
+// envFilter decides which environment variables the shell inherits; nil
+// applies DefaultEnvDenyPatterns.
+func NewPTYSession(id string, cols, rows int, shellCmd []string, onOutput func(string, string), onError func(string, string), scrubber *Scrubber, envFilter *EnvFilter) (*PTYSession, error) {
 	if cols <= 0 {
 		cols = 80
 	}
@@ -54,7 +57,7 @@ func NewPTYSession(id string, cols, rows int, shellCmd []string, onOutput func(s
 		}
 		cmd = exec.Command(shell)
 	}
-	cmd.Env = append(filteredEnv(), "TERM=xterm-256color")
+	cmd.Env = append(filteredEnv(envFilter), "TERM=xterm-256color")
 
 	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{
 		Cols: uint16(cols),
@@ -73,6 +76,7 @@ func NewPTYSession(id string, cols, rows int, shellCmd []string, onOutput func(s
 		rows:      uint16(rows),
 		onOutput:  onOutput,
 		onError:   onError,
+		scrubber:  scrubber,
 		done:      make(chan struct{}),
 	}
 
@@ -116,12 +120,22 @@ func (s *PTYSession) Done() <-chan struct{} {
 
 func (s *PTYSession) readLoop() {
 	buf := make([]byte, 4096)
+	scrubbed := &scrubBuffer{
+		scrubber: s.scrubber,
+		emit:     func(data string) { s.onOutput(s.ID, data) },
+		idle:     scrubIdleFlush,
+	}
 	for {
 		n, err := s.ptmx.Read(buf)
 		if n > 0 {
-			s.onOutput(s.ID, string(buf[:n]))
+			if s.scrubber == nil {
+				s.onOutput(s.ID, string(buf[:n]))
+			} else {
+				scrubbed.write(string(buf[:n]), n == len(buf) && err == nil)
+			}
 		}
 		if err != nil {
+			scrubbed.flush()
 			if err != io.EOF {
 				select {
 				case <-s.done:
@@ -135,34 +149,3 @@ func (s *PTYSession) readLoop() {
 		}
 	}
 }
-
-// filteredEnv returns os.Environ() with sensitive variables removed.
-func filteredEnv() []string {
-	sensitiveKeys := []string{"TB_TOKEN", "TB_SECRET"}
-	sensitiveSuffixes := []string{"_KEY", "_SECRET", "_PASSWORD", "_TOKEN"}
-
-	var filtered []string
-	for _, env := range os.Environ() {
-		key, _, _ := strings.Cut(env, "=")
-		skip := false
-		for _, k := range sensitiveKeys {
-			if key == k {
-				skip = true
-				break
-			}
-		}
-		if !skip {
-			upper := strings.ToUpper(key)
-			for _, suffix := range sensitiveSuffixes {
-				if strings.HasSuffix(upper, suffix) {
-					skip = true
-					break
-				}
-			}
-		}
-		if !skip {
-			filtered = append(filtered, env)
-		}
-	}
-	return filtered
-}
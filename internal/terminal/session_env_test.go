@@ -19,7 +19,7 @@ func TestFilteredEnvRemovesSensitiveVars(t *testing.T) {
 		os.Unsetenv("SAFE_VAR")
 	}()
 
-	env := filteredEnv()
+	env := filteredEnv(nil)
 	envMap := make(map[string]string)
 	for _, e := range env {
 		k, v, _ := strings.Cut(e, "=")
@@ -39,3 +39,26 @@ func TestFilteredEnvRemovesSensitiveVars(t *testing.T) {
 		t.Error("SAFE_VAR should be preserved")
 	}
 }
+
+func TestEnvFilterCustomRules(t *testing.T) {
+	f, err := NewEnvFilter(append(DefaultEnvDenyPatterns, "INTERNAL_*"), []string{"SSH_AUTH_KEY", "kubeconfig_*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := f.Filter([]string{
+		"SSH_AUTH_KEY=/run/agent.sock", // denied by *_KEY, allowlisted
+		"KUBECONFIG_TOKEN=abc",         // denied by *_TOKEN, allowlisted (case-insensitive)
+		"DB_PASSWORD=secret",
+		"INTERNAL_ENDPOINT=http://10.0.0.1",
+		"PATH=/usr/bin",
+	})
+
+	want := []string{"SSH_AUTH_KEY=/run/agent.sock", "KUBECONFIG_TOKEN=abc", "PATH=/usr/bin"}
+	if strings.Join(env, " ") != strings.Join(want, " ") {
+		t.Errorf("Filter = %v, want %v", env, want)
+	}
+
+	if _, err := NewEnvFilter([]string{"[invalid"}, nil); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
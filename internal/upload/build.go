@@ -2,10 +2,31 @@ package upload
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
 )
 
+// PrepareRequest builds the upload request for result, merges the
+// enrichment fields into its host section and then applies the redaction
+// rules, so enrichment can be redacted like any other field. Finally the
+// payload is truncated to maxBytes (0 = no limit).
+func PrepareRequest(result *scanner.Result, extra map[string]json.RawMessage, rules RedactRules, maxBytes int) (*EdgeIngestRequest, error) {
+	req := BuildRequest(result)
+	if err := Enrich(req, extra); err != nil {
+		return nil, fmt.Errorf("enrich payload: %w", err)
+	}
+	req, err := Redact(req, rules)
+	if err != nil {
+		return nil, fmt.Errorf("redact payload: %w", err)
+	}
+	req, err = Truncate(req, maxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("truncate payload: %w", err)
+	}
+	return req, nil
+}
+
 // BuildRequest converts scan results to an EdgeIngestRequest.
 func BuildRequest(result *scanner.Result) *EdgeIngestRequest {
 	req := &EdgeIngestRequest{
@@ -14,6 +35,10 @@ func BuildRequest(result *scanner.Result) *EdgeIngestRequest {
 			DurationMS: result.Meta.DurationMS,
 			Phases:     result.Meta.Phases,
 			SourceHost: result.Meta.SourceHost,
+			Partial:    result.Meta.Partial,
+
+			Sequence:    result.Meta.Sequence,
+			GeneratedAt: result.Meta.GeneratedAt,
 		},
 	}
 
@@ -28,11 +53,42 @@ func BuildRequest(result *scanner.Result) *EdgeIngestRequest {
 					Arch:     hostInfo.System.Arch,
 					CPUCores: hostInfo.System.CPUCores,
 					MemoryGB: hostInfo.System.MemoryGB,
+
+					CPUMaxMHz:   hostInfo.System.CPUMaxMHz,
+					CPUGovernor: hostInfo.System.CPUGovernor,
+
+					NUMA: hostInfo.System.NUMA,
+					Swap: hostInfo.System.Swap,
+
+					CgroupVersion:  hostInfo.System.CgroupVersion,
+					CgroupMemoryGB: hostInfo.System.CgroupMemoryGB,
+					CgroupCPUs:     hostInfo.System.CgroupCPUs,
+
+					LoadAvg:        hostInfo.System.LoadAvg,
+					FailedServices: hostInfo.System.FailedServices,
+
+					UptimeSeconds: hostInfo.System.UptimeSeconds,
+
+					ZombieProcesses:  hostInfo.System.ZombieProcesses,
+					ZombieParentPID:  hostInfo.System.ZombieParentPID,
+					ZombiesExcessive: hostInfo.System.ZombiesExcessive,
+
+					RebootRequired:       hostInfo.System.RebootRequired,
+					RebootRequiredReason: hostInfo.System.RebootRequiredReason,
+
+					GPUs:    hostInfo.System.GPUs,
+					Thermal: hostInfo.System.Thermal,
+
+					PowerSource:       hostInfo.System.PowerSource,
+					BatteryPercent:    hostInfo.System.BatteryPercent,
+					BatteryStatus:     hostInfo.System.BatteryStatus,
+					BatteryCycleCount: hostInfo.System.BatteryCycleCount,
 				},
 				Network: HostNetwork{
 					Hostname:   hostInfo.Name,
 					Interfaces: []HostInterface{},
 				},
+				PCI: hostInfo.PCI,
 			}
 
 			if result.Network != nil {
@@ -69,5 +125,7 @@ func BuildRequest(result *scanner.Result) *EdgeIngestRequest {
 		req.Cluster = result.Cluster
 	}
 
+	req.Health = result.Health
+
 	return req
 }
@@ -17,7 +17,12 @@ func TestBuildRequestHostMapping(t *testing.T) {
 			"arch": "amd64",
 			"cpu_model": "Intel Xeon",
 			"cpu_cores": 8,
-			"memory_gb": 32
+			"memory_gb": 32,
+			"cgroup_version": 2,
+			"cgroup_memory_gb": 0.5,
+			"cgroup_cpus": 1.5,
+			"load_avg": [0.5, 0.4, 0.3],
+			"failed_services": ["nginx.service"]
 		}
 	}`
 
@@ -38,8 +43,14 @@ func TestBuildRequestHostMapping(t *testing.T) {
 	result.Meta.Phases = []string{"host", "network"}
 	result.Meta.SourceHost = "test-host.local"
 
+	scanner.ApplyHealth(result)
+
 	req := BuildRequest(result)
 
+	if req.Health == nil || req.Health.Host == nil {
+		t.Error("expected host health in request")
+	}
+
 	// Check host mapping
 	if req.Host == nil {
 		t.Fatal("expected host in request")
@@ -56,6 +67,12 @@ func TestBuildRequestHostMapping(t *testing.T) {
 	if req.Host.System.MemoryGB != 32 {
 		t.Errorf("memory_gb = %f, want 32", req.Host.System.MemoryGB)
 	}
+	if req.Host.System.CgroupVersion != 2 || req.Host.System.CgroupMemoryGB != 0.5 || req.Host.System.CgroupCPUs != 1.5 {
+		t.Errorf("cgroup fields = %+v", req.Host.System)
+	}
+	if len(req.Host.System.LoadAvg) != 3 || len(req.Host.System.FailedServices) != 1 {
+		t.Errorf("load/services fields = %+v", req.Host.System)
+	}
 
 	// Check interfaces — all interfaces included (sysfs may lack IPs)
 	if len(req.Host.Network.Interfaces) != 3 {
@@ -78,7 +95,7 @@ func TestBuildRequestHostMapping(t *testing.T) {
 
 func TestBuildRequestJSONCompatibility(t *testing.T) {
 	// Verify the output JSON matches the edge-ingest contract
-	hostJSON := `{"name":"h1","type":"vm","system":{"os":"linux","arch":"arm64","cpu_cores":4,"memory_gb":8}}`
+	hostJSON := `{"name":"h1","type":"vm","system":{"os":"linux","arch":"arm64","cpu_cores":4,"memory_gb":8,"gpus":[]}}`
 	netJSON := `{"hostname":"h1","interfaces":[{"name":"enp0s1","ip":"10.0.0.5","mac":"aa:bb:cc:dd:ee:ff"}]}`
 
 	result := scanner.NewResult()
@@ -133,6 +150,10 @@ func TestBuildRequestJSONCompatibility(t *testing.T) {
 			t.Errorf("missing host.system.%s", field)
 		}
 	}
+	// An empty GPU list is sent, not dropped
+	if gpus, ok := system["gpus"].([]interface{}); !ok || len(gpus) != 0 {
+		t.Errorf("host.system.gpus = %#v, want []", system["gpus"])
+	}
 
 	network, ok := host["network"].(map[string]interface{})
 	if !ok {
@@ -57,6 +57,23 @@ func NewHostKeyClient(baseURL, anonKey, token string, identity *auth.HostIdentit
 	}
 }
 
+// SetMaxRetries sets how many times Upload retries a network error or a 5xx
+// response. 0 disables retries, so the first response is the one returned.
+func (c *Client) SetMaxRetries(n int) {
+	c.maxRetries = n
+}
+
+// StatusError is returned by Upload when edge-ingest answers with a status
+// other than 200.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
 // Upload sends scan results to edge-ingest.
 func (c *Client) Upload(ctx context.Context, req *EdgeIngestRequest) (*EdgeIngestResponse, error) {
 	if c.token != "" {
@@ -110,12 +127,12 @@ func (c *Client) Upload(ctx context.Context, req *EdgeIngestRequest) (*EdgeInges
 		resp.Body.Close()
 
 		if resp.StatusCode >= 500 {
-			lastErr = fmt.Errorf("server error %d: %s", resp.StatusCode, string(respBody))
+			lastErr = fmt.Errorf("server error: %w", &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)})
 			continue
 		}
 
 		if resp.StatusCode != 200 {
-			return nil, fmt.Errorf("upload failed (HTTP %d): %s", resp.StatusCode, string(respBody))
+			return nil, fmt.Errorf("upload failed: %w", &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)})
 		}
 
 		var result EdgeIngestResponse
@@ -126,5 +143,8 @@ func (c *Client) Upload(ctx context.Context, req *EdgeIngestRequest) (*EdgeInges
 		return &result, nil
 	}
 
+	if c.maxRetries == 0 {
+		return nil, fmt.Errorf("upload failed: %w", lastErr)
+	}
 	return nil, fmt.Errorf("upload failed after %d retries: %w", c.maxRetries, lastErr)
 }
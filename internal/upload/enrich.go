@@ -0,0 +1,176 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// enrichCommandTimeout bounds how long an enrichment command may run.
+const enrichCommandTimeout = 10 * time.Second
+
+// Enrichment adds site-specific fields (site code, rack, owner, ...) from a
+// local source to the host object of every upload. edge-ingest keeps
+// unknown host keys in scan_data, so no server change is needed.
+type Enrichment struct {
+	Static  map[string]any // fixed fields, e.g. from the config file
+	Command string         // run with sh -c; stdout must be a JSON object
+}
+
+// Empty reports whether no enrichment is configured.
+func (e Enrichment) Empty() bool {
+	return len(e.Static) == 0 && e.Command == ""
+}
+
+// Fields resolves the enrichment to the JSON fields to merge. Command
+// output is read fresh on every call and wins over static fields with the
+// same name. Fields that would overwrite a core host field are rejected.
+func (e Enrichment) Fields(ctx context.Context) (map[string]json.RawMessage, error) {
+	if e.Empty() {
+		return nil, nil
+	}
+
+	fields := make(map[string]json.RawMessage, len(e.Static))
+	for k, v := range e.Static {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("enrichment field %q: %w", k, err)
+		}
+		fields[k] = data
+	}
+
+	if e.Command != "" {
+		ctx, cancel := context.WithTimeout(ctx, enrichCommandTimeout)
+		defer cancel()
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "sh", "-c", e.Command)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("enrichment command: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(out, &obj); err != nil {
+			return nil, fmt.Errorf("enrichment command must print a JSON object: %w", err)
+		}
+		for k, v := range obj {
+			fields[k] = v
+		}
+	}
+
+	for k := range fields {
+		if err := checkEnrichmentKey(k); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// hostCoreFields are the JSON names HostScanResult defines itself.
+var hostCoreFields = func() map[string]bool {
+	core := make(map[string]bool)
+	t := reflect.TypeOf(HostScanResult{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			core[name] = true
+		}
+	}
+	return core
+}()
+
+func checkEnrichmentKey(k string) error {
+	switch {
+	case strings.TrimSpace(k) == "":
+		return fmt.Errorf("enrichment field name is empty")
+	case hostCoreFields[k]:
+		return fmt.Errorf("enrichment field %q would overwrite a core host field", k)
+	}
+	return nil
+}
+
+// Enrich merges fields into req's host object. Requests without a host
+// section (cluster-only scans) are left unchanged.
+func Enrich(req *EdgeIngestRequest, fields map[string]json.RawMessage) error {
+	if len(fields) == 0 || req.Host == nil {
+		return nil
+	}
+	for k, v := range fields {
+		if err := checkEnrichmentKey(k); err != nil {
+			return err
+		}
+		if !json.Valid(v) {
+			return fmt.Errorf("enrichment field %q is not valid JSON", k)
+		}
+		if req.Host.Extra == nil {
+			req.Host.Extra = make(map[string]json.RawMessage, len(fields))
+		}
+		req.Host.Extra[k] = v
+	}
+	return nil
+}
+
+// hostScanResultFields has HostScanResult's fields without its methods,
+// so the custom (un)marshalers below can defer to encoding/json.
+type hostScanResultFields HostScanResult
+
+// MarshalJSON writes the core fields followed by any enrichment fields.
+func (h HostScanResult) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(hostScanResultFields(h))
+	if err != nil || len(h.Extra) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(h.Extra))
+	for k := range h.Extra {
+		if !hostCoreFields[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1]) // drop the closing brace
+	for i, k := range keys {
+		name, _ := json.Marshal(k)
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(h.Extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON reads the core fields and keeps any others in Extra, so
+// enrichment survives the JSON round trip in Redact.
+func (h *HostScanResult) UnmarshalJSON(data []byte) error {
+	var core hostScanResultFields
+	if err := json.Unmarshal(data, &core); err != nil {
+		return err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	*h = HostScanResult(core)
+	h.Extra = nil
+	for k, v := range all {
+		if hostCoreFields[k] {
+			continue
+		}
+		if h.Extra == nil {
+			h.Extra = make(map[string]json.RawMessage)
+		}
+		h.Extra[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+)
+
+func TestEnrichStaticFieldsInPayload(t *testing.T) {
+	result := scanner.NewResult()
+	result.Host = json.RawMessage(`{"name": "edge-1", "type": "baremetal", "system": {"os": "linux"}}`)
+
+	enrich := Enrichment{Static: map[string]any{
+		"site":  "ams3",
+		"rack":  "r12",
+		"owner": map[string]any{"team": "platform"},
+	}}
+	extra, err := enrich.Fields(context.Background())
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	req, err := PrepareRequest(result, extra, RedactRules{Remove: []string{"host.rack"}}, 0)
+	if err != nil {
+		t.Fatalf("PrepareRequest: %v", err)
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Host map[string]json.RawMessage `json:"host"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if string(doc.Host["site"]) != `"ams3"` {
+		t.Errorf("site = %s, want \"ams3\": %s", doc.Host["site"], data)
+	}
+	if string(doc.Host["owner"]) != `{"team":"platform"}` {
+		t.Errorf("owner = %s: %s", doc.Host["owner"], data)
+	}
+	if _, ok := doc.Host["rack"]; ok {
+		t.Errorf("redaction should apply to enrichment fields: %s", data)
+	}
+	if string(doc.Host["name"]) != `"edge-1"` {
+		t.Errorf("core fields should be untouched: %s", data)
+	}
+}
+
+func TestEnrichRejectsCoreFields(t *testing.T) {
+	_, err := Enrichment{Static: map[string]any{"name": "spoofed"}}.Fields(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `"name"`) {
+		t.Errorf("expected core field error, got %v", err)
+	}
+
+	req := &EdgeIngestRequest{Host: &HostScanResult{Name: "edge-1"}}
+	if err := Enrich(req, map[string]json.RawMessage{"system": json.RawMessage(`{}`)}); err == nil {
+		t.Error("Enrich should reject core fields")
+	}
+}
+
+func TestEnrichCommand(t *testing.T) {
+	extra, err := Enrichment{
+		Static:  map[string]any{"site": "static", "rack": "r1"},
+		Command: `echo '{"site": "ams3", "asset_tag": 4411}'`,
+	}.Fields(context.Background())
+	if err != nil {
+		t.Fatalf("Fields: %v", err)
+	}
+	if string(extra["site"]) != `"ams3"` || string(extra["rack"]) != `"r1"` || string(extra["asset_tag"]) != "4411" {
+		t.Errorf("unexpected fields: %s %s %s", extra["site"], extra["rack"], extra["asset_tag"])
+	}
+
+	if _, err := (Enrichment{Command: "echo not-json"}).Fields(context.Background()); err == nil {
+		t.Error("expected error for non-JSON command output")
+	}
+	if _, err := (Enrichment{Command: "echo '[1,2]'"}).Fields(context.Background()); err == nil {
+		t.Error("expected error for non-object command output")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
 )
 
 // Uploader is the interface for uploading scan results.
@@ -19,9 +21,10 @@ type MultiClient struct {
 }
 
 type namedClient struct {
-	name   string
-	client *Client
-	token  string
+	name    string
+	client  *Client
+	token   string
+	profile string
 }
 
 // NewMultiClient creates an uploader from upstream configs.
@@ -31,9 +34,10 @@ func NewMultiClient(upstreams []Upstream) *MultiClient {
 	}
 	for _, u := range upstreams {
 		mc.upstreams = append(mc.upstreams, namedClient{
-			name:   u.Name,
-			client: NewClient(u.URL, u.Token, u.AnonKey),
-			token:  u.Token,
+			name:    u.Name,
+			client:  NewClient(u.URL, u.Token, u.AnonKey),
+			token:   u.Token,
+			profile: u.Profile,
 		})
 	}
 	return mc
@@ -42,16 +46,50 @@ func NewMultiClient(upstreams []Upstream) *MultiClient {
 // Upload sends scan results to all upstreams. Returns the first successful
 // response. Logs errors for individual upstreams but only fails if all fail.
 func (mc *MultiClient) Upload(ctx context.Context, req *EdgeIngestRequest) (*EdgeIngestResponse, error) {
-	if len(mc.upstreams) == 0 {
-		return nil, fmt.Errorf("no upstreams configured")
-	}
-
 	// Marshal once, each upstream gets its own copy with the right token
 	baseBody, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
+	return mc.uploadAll(ctx, func(u namedClient) (*EdgeIngestRequest, error) {
+		var upstreamReq EdgeIngestRequest
+		if err := json.Unmarshal(baseBody, &upstreamReq); err != nil {
+			return nil, err
+		}
+		return &upstreamReq, nil
+	})
+}
+
+// UploadResult builds a request per upstream from result, trimmed to the
+// upstream's profile, enriched with extra, redacted with rules and
+// truncated to maxBytes, and uploads it. Upstreams without a profile
+// receive every scanned phase.
+func (mc *MultiClient) UploadResult(ctx context.Context, result *scanner.Result, extra map[string]json.RawMessage, rules RedactRules, maxBytes int) (*EdgeIngestResponse, error) {
+	return mc.uploadAll(ctx, func(u namedClient) (*EdgeIngestRequest, error) {
+		r := result
+		if u.profile != "" {
+			profile, err := scanner.ParseProfile(u.profile)
+			if err != nil {
+				return nil, err
+			}
+			r = result.ForProfile(profile)
+		}
+		req, err := PrepareRequest(r, extra, rules, maxBytes)
+		if err == nil && req.Meta.Truncated {
+			mc.log.Warn("payload truncated to fit size limit", "upstream", u.name, "max_bytes", maxBytes, "sections", req.Meta.TruncatedSections)
+		}
+		return req, err
+	})
+}
+
+// uploadAll uploads the request built by reqFor to each upstream
+// concurrently, with the upstream's token set.
+func (mc *MultiClient) uploadAll(ctx context.Context, reqFor func(u namedClient) (*EdgeIngestRequest, error)) (*EdgeIngestResponse, error) {
+	if len(mc.upstreams) == 0 {
+		return nil, fmt.Errorf("no upstreams configured")
+	}
+
 	type result struct {
 		name string
 		resp *EdgeIngestResponse
@@ -63,11 +101,14 @@ func (mc *MultiClient) Upload(ctx context.Context, req *EdgeIngestRequest) (*Edg
 	for _, u := range mc.upstreams {
 		go func(u namedClient) {
 			// Each upstream gets its own request copy with the correct token
-			var upstreamReq EdgeIngestRequest
-			json.Unmarshal(baseBody, &upstreamReq)
+			upstreamReq, err := reqFor(u)
+			if err != nil {
+				ch <- result{name: u.name, err: err}
+				return
+			}
 			upstreamReq.AgentToken = u.token
 
-			resp, err := u.client.Upload(ctx, &upstreamReq)
+			resp, err := u.client.Upload(ctx, upstreamReq)
 			ch <- result{name: u.name, resp: resp, err: err}
 		}(u)
 	}
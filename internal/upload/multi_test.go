@@ -0,0 +1,103 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+)
+
+func TestLoadUpstreamsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upstreams.json")
+	data := `[
+		{"name": "prod", "url": "https://prod.example", "token": "t1", "anonKey": "a1", "profile": "minimal"},
+		{"name": "lab", "url": "https://lab.example", "token": "t2", "anonKey": "a2", "permissions": ["scan"]}
+	]`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	upstreams, err := LoadUpstreamsFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(upstreams) != 2 {
+		t.Fatalf("expected 2 upstreams, got %d", len(upstreams))
+	}
+	if upstreams[0].Profile != "minimal" || upstreams[1].Profile != "" {
+		t.Errorf("profiles = %q, %q", upstreams[0].Profile, upstreams[1].Profile)
+	}
+
+	if _, err := LoadUpstreamsFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestParseUpstreamsInvalidProfile(t *testing.T) {
+	_, err := ParseUpstreams(`[{"name": "prod", "url": "https://prod.example", "profile": "huge"}]`)
+	if err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
+
+func TestMultiClientUploadResultPerProfile(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]EdgeIngestRequest)
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var req EdgeIngestRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			received[name] = req
+			mu.Unlock()
+			json.NewEncoder(w).Encode(EdgeIngestResponse{SessionID: name})
+		}
+	}
+	minimal := httptest.NewServer(handler("minimal"))
+	defer minimal.Close()
+	full := httptest.NewServer(handler("full"))
+	defer full.Close()
+
+	result := scanner.NewResult()
+	result.Set("host", json.RawMessage(`{"name": "node-1", "system": {"os": "linux", "cpu_cores": 4}}`))
+	result.Set("network", json.RawMessage(`{"hostname": "node-1", "interfaces": []}`))
+	result.Meta.Profile = "full"
+
+	mc := NewMultiClient([]Upstream{
+		{Name: "minimal", URL: minimal.URL, Token: "t-min", Profile: "minimal"},
+		{Name: "full", URL: full.URL, Token: "t-full", Profile: "full"},
+	})
+	if _, err := mc.UploadResult(context.Background(), result, nil, RedactRules{}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	minReq, ok := received["minimal"]
+	if !ok {
+		t.Fatal("minimal upstream received nothing")
+	}
+	if minReq.AgentToken != "t-min" {
+		t.Errorf("minimal token = %q", minReq.AgentToken)
+	}
+	if minReq.Network != nil {
+		t.Error("minimal upstream should not receive network data")
+	}
+	if len(minReq.Meta.Phases) != 1 || minReq.Meta.Phases[0] != "host" {
+		t.Errorf("minimal phases = %v, want [host]", minReq.Meta.Phases)
+	}
+
+	fullReq := received["full"]
+	if fullReq.AgentToken != "t-full" || fullReq.Network == nil {
+		t.Errorf("full upstream should receive network data with its own token, got token %q", fullReq.AgentToken)
+	}
+
+	// The caller's result is not modified by trimming
+	if result.Network == nil || len(result.Meta.Phases) != 2 {
+		t.Error("UploadResult must not mutate the scan result")
+	}
+}
@@ -0,0 +1,143 @@
+package upload
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RedactRules lists payload fields to strip or hash before upload.
+// Paths are dot-separated JSON keys relative to the request root; a
+// trailing "[]" on a segment walks every element of an array and "*"
+// matches any key. The last segment names the field itself, so it cannot
+// end in "[]". Examples:
+//
+//	host.network.public_ip
+//	host.network.interfaces[].mac
+//	network.interfaces[].mac
+//
+// Hashed fields are replaced by an HMAC-SHA256 keyed with HashKey, a
+// secret kept per install. The same value hashes the same way across
+// uploads from one install, so it can still be correlated there, but a
+// plain digest of an IP or MAC address could be reversed by hashing every
+// candidate; without the key it cannot.
+type RedactRules struct {
+	Remove  []string // fields dropped from the payload
+	Hash    []string // string fields replaced by a keyed digest
+	HashKey string   // HMAC key for Hash; required when Hash is set
+}
+
+// Validate rejects malformed paths and Hash rules without a key.
+func (r RedactRules) Validate() error {
+	for _, path := range append(append([]string(nil), r.Remove...), r.Hash...) {
+		parts := strings.Split(path, ".")
+		for _, seg := range parts {
+			if strings.TrimSuffix(seg, "[]") == "" {
+				return fmt.Errorf("redact path %q: empty segment", path)
+			}
+		}
+		if strings.HasSuffix(parts[len(parts)-1], "[]") {
+			return fmt.Errorf("redact path %q: last segment must name a field, not an array", path)
+		}
+	}
+	if len(r.Hash) > 0 && r.HashKey == "" {
+		return errors.New("redact: hash_key is required to hash fields")
+	}
+	return nil
+}
+
+// Empty reports whether no rules are configured.
+func (r RedactRules) Empty() bool {
+	return len(r.Remove) == 0 && len(r.Hash) == 0
+}
+
+// Redact returns a copy of req with the rules applied. The request is
+// round-tripped through JSON so paths also reach raw sections such as
+// network and cluster.
+func Redact(req *EdgeIngestRequest, rules RedactRules) (*EdgeIngestRequest, error) {
+	if rules.Empty() {
+		return req, nil
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal request: %w", err)
+	}
+
+	for _, path := range rules.Remove {
+		walkPath(doc, strings.Split(path, "."), func(m map[string]any, key string) {
+			delete(m, key)
+		})
+	}
+	for _, path := range rules.Hash {
+		walkPath(doc, strings.Split(path, "."), func(m map[string]any, key string) {
+			if s, ok := m[key].(string); ok && s != "" {
+				m[key] = hashValue(rules.HashKey, s)
+			}
+		})
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal redacted request: %w", err)
+	}
+	var out EdgeIngestRequest
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal redacted request: %w", err)
+	}
+	return &out, nil
+}
+
+// walkPath calls fn for every map/key pair matched by parts.
+func walkPath(node any, parts []string, fn func(m map[string]any, key string)) {
+	m, ok := node.(map[string]any)
+	if !ok || len(parts) == 0 {
+		return
+	}
+
+	seg := parts[0]
+	isArray := strings.HasSuffix(seg, "[]")
+	seg = strings.TrimSuffix(seg, "[]")
+
+	var keys []string
+	if seg == "*" {
+		for k := range m {
+			keys = append(keys, k)
+		}
+	} else if _, ok := m[seg]; ok {
+		keys = []string{seg}
+	}
+
+	for _, k := range keys {
+		if len(parts) == 1 && !isArray {
+			fn(m, k)
+			continue
+		}
+		if isArray {
+			items, _ := m[k].([]any)
+			for _, item := range items {
+				walkPath(item, parts[1:], fn)
+			}
+			continue
+		}
+		walkPath(m[k], parts[1:], fn)
+	}
+}
+
+// hashValue returns a truncated HMAC-SHA256 of s under key.
+func hashValue(key, s string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(s))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
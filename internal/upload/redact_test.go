@@ -0,0 +1,111 @@
+package upload
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+)
+
+func TestRedactMACAndPublicIP(t *testing.T) {
+	result := scanner.NewResult()
+	result.Host = json.RawMessage(`{"name": "edge-1", "type": "baremetal", "system": {"os": "linux"}}`)
+	result.Network = json.RawMessage(`{
+		"public_ip": "203.0.113.7",
+		"interfaces": [
+			{"name": "eth0", "ip": "10.0.0.1", "mac": "aa:bb:cc:dd:ee:ff"},
+			{"name": "wlan0", "ip": "10.0.0.2", "mac": "11:22:33:44:55:66"}
+		]
+	}`)
+
+	req, err := Redact(BuildRequest(result), RedactRules{
+		Remove:  []string{"host.network.interfaces[].mac", "network.interfaces[].mac"},
+		Hash:    []string{"host.network.public_ip", "network.public_ip"},
+		HashKey: "install-secret",
+	})
+	if err != nil {
+		t.Fatalf("Redact: %v", err)
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := string(data)
+
+	for _, secret := range []string{"aa:bb:cc:dd:ee:ff", "11:22:33:44:55:66", "203.0.113.7"} {
+		if strings.Contains(payload, secret) {
+			t.Errorf("payload still contains %q: %s", secret, payload)
+		}
+	}
+	if strings.Contains(payload, `"mac"`) {
+		t.Errorf("mac fields should be removed: %s", payload)
+	}
+
+	want := hashValue("install-secret", "203.0.113.7")
+	if req.Host.Network.PublicIP != want {
+		t.Errorf("host public_ip = %q, want %q", req.Host.Network.PublicIP, want)
+	}
+	if !strings.Contains(string(req.Network), want) {
+		t.Errorf("raw network section should carry hashed public_ip: %s", req.Network)
+	}
+
+	// Unrelated fields survive
+	if req.Host.Name != "edge-1" || len(req.Host.Network.Interfaces) != 2 || req.Host.Network.Interfaces[0].IP != "10.0.0.1" {
+		t.Errorf("redaction changed unrelated fields: %+v", req.Host)
+	}
+}
+
+func TestRedactNoRules(t *testing.T) {
+	req := &EdgeIngestRequest{AgentToken: "tok"}
+	out, err := Redact(req, RedactRules{})
+	if err != nil || out != req {
+		t.Errorf("expected request returned unchanged, got %v, %v", out, err)
+	}
+}
+
+func TestRedactWildcard(t *testing.T) {
+	req := &EdgeIngestRequest{Cluster: json.RawMessage(`{"nodes": [{"name": "n1", "labels": {"serial": "X1"}}, {"name": "n2", "labels": {"serial": "X2"}}]}`)}
+	out, err := Redact(req, RedactRules{Remove: []string{"cluster.nodes[].*.serial"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out.Cluster), "serial") {
+		t.Errorf("wildcard path not applied: %s", out.Cluster)
+	}
+}
+
+func TestHashValueKeyed(t *testing.T) {
+	a, b := hashValue("key-a", "203.0.113.7"), hashValue("key-b", "203.0.113.7")
+	if a == b {
+		t.Error("different keys should give different digests")
+	}
+	if a != hashValue("key-a", "203.0.113.7") {
+		t.Error("the same key should give a stable digest")
+	}
+	if !strings.HasPrefix(a, "hmac-sha256:") {
+		t.Errorf("digest = %q, want hmac-sha256: prefix", a)
+	}
+}
+
+func TestRedactRulesValidate(t *testing.T) {
+	for _, tc := range []struct {
+		rules RedactRules
+		ok    bool
+	}{
+		{RedactRules{Remove: []string{"network.interfaces[].mac"}}, true},
+		{RedactRules{Hash: []string{"network.public_ip"}, HashKey: "k"}, true},
+		{RedactRules{Hash: []string{"network.public_ip"}}, false},
+		{RedactRules{Remove: []string{"host.tags[]"}}, false},
+		{RedactRules{Remove: []string{"host..mac"}}, false},
+	} {
+		if err := tc.rules.Validate(); (err == nil) != tc.ok {
+			t.Errorf("Validate(%+v) = %v, want ok=%v", tc.rules, err, tc.ok)
+		}
+	}
+
+	if _, err := Redact(&EdgeIngestRequest{}, RedactRules{Remove: []string{"host.tags[]"}}); err == nil {
+		t.Error("Redact should reject a path ending in []")
+	}
+}
@@ -0,0 +1,181 @@
+package upload
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// namespaceObjectKeys are the per-namespace object arrays in the cluster
+// section that Truncate may cap.
+var namespaceObjectKeys = []string{
+	"workloads", "services", "ingresses", "configMaps", "secrets", "pvcs",
+	"cronJobs", "networkPolicies", "pdbs", "externalSecrets", "hpas",
+	"resourceQuotas", "limitRanges", "vpas", "certificates",
+}
+
+// clusterArraySteps are the cluster-level arrays Truncate may cap, in
+// order, around the per-namespace objects (the "" entry).
+var clusterArraySteps = []string{"podPlacements", "images", "helmReleases", "", "crds", "storageClasses", "persistentVolumes"}
+
+// Truncate returns a copy of req trimmed to at most maxBytes of JSON, or
+// req itself when it already fits or maxBytes is not positive. Sections
+// are trimmed least important first, stopping as soon as the payload fits:
+//
+//  1. insights[].error_sample: log samples attached to insights
+//  2. insights[].description: insight details; titles are kept
+//  3. cluster.podPlacements, cluster.images, then cluster.helmReleases:
+//     pod-to-node placements, the image inventory and Helm releases
+//  4. cluster.namespaces[]: per-namespace object arrays
+//  5. cluster.crds, cluster.storageClasses, then cluster.persistentVolumes
+//  6. host.containers: the host's container list
+//
+// Arrays in steps 3-5 are capped to a shrinking length rather than dropped.
+//
+// The trimmed sections are listed in meta.truncated_sections and
+// meta.truncated is set. A payload that still does not fit is returned
+// with everything above trimmed; edge-ingest may still reject it.
+func Truncate(req *EdgeIngestRequest, maxBytes int) (*EdgeIngestRequest, error) {
+	if maxBytes <= 0 {
+		return req, nil
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	if len(data) <= maxBytes {
+		return req, nil
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal request: %w", err)
+	}
+	if err := truncateDoc(doc, maxBytes); err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal truncated request: %w", err)
+	}
+	var out EdgeIngestRequest
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal truncated request: %w", err)
+	}
+	return &out, nil
+}
+
+// truncateDoc applies the Truncate steps to a decoded request.
+func truncateDoc(doc map[string]any, maxBytes int) error {
+	meta, _ := doc["meta"].(map[string]any)
+	if meta == nil {
+		meta = make(map[string]any)
+		doc["meta"] = meta
+	}
+	var sections []string
+	mark := func(section string) {
+		sections = append(sections, section)
+		meta["truncated"] = true
+		meta["truncated_sections"] = sections
+	}
+	fits := func() (bool, error) {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return false, fmt.Errorf("marshal truncated request: %w", err)
+		}
+		return len(data) <= maxBytes, nil
+	}
+
+	insights, _ := doc["insights"].([]any)
+	for _, field := range []string{"error_sample", "description"} {
+		if !dropInsightField(insights, field) {
+			continue
+		}
+		if field == "error_sample" {
+			dropInsightField(insights, "error_sample_count")
+		}
+		mark("insights." + field)
+		if ok, err := fits(); ok || err != nil {
+			return err
+		}
+	}
+
+	if cluster, ok := doc["cluster"].(map[string]any); ok {
+		for _, key := range clusterArraySteps {
+			// Each step caps objs[*][keys] to a halving length
+			section, objs, keys := "cluster."+key, []any{cluster}, []string{key}
+			if key == "" {
+				section, keys = "cluster.namespaces", namespaceObjectKeys
+				objs, _ = cluster["namespaces"].([]any)
+			}
+			capped := false
+			for limit := longestArray(objs, keys) / 2; ; limit /= 2 {
+				if capArrays(objs, keys, limit) {
+					if !capped {
+						mark(section)
+						capped = true
+					}
+					if ok, err := fits(); ok || err != nil {
+						return err
+					}
+				}
+				if limit == 0 {
+					break
+				}
+			}
+		}
+	}
+
+	if host, ok := doc["host"].(map[string]any); ok {
+		if _, ok := host["containers"]; ok {
+			delete(host, "containers")
+			mark("host.containers")
+		}
+	}
+	return nil
+}
+
+// dropInsightField removes field from every insight, reporting whether
+// any insight had it.
+func dropInsightField(insights []any, field string) bool {
+	dropped := false
+	for _, item := range insights {
+		if m, ok := item.(map[string]any); ok {
+			if _, ok := m[field]; ok {
+				delete(m, field)
+				dropped = true
+			}
+		}
+	}
+	return dropped
+}
+
+// longestArray returns the length of the longest keys array across objs.
+func longestArray(objs []any, keys []string) int {
+	longest := 0
+	for _, item := range objs {
+		obj, _ := item.(map[string]any)
+		for _, key := range keys {
+			if arr, ok := obj[key].([]any); ok && len(arr) > longest {
+				longest = len(arr)
+			}
+		}
+	}
+	return longest
+}
+
+// capArrays shortens every keys array across objs to at most limit
+// entries, reporting whether any was shortened.
+func capArrays(objs []any, keys []string, limit int) bool {
+	capped := false
+	for _, item := range objs {
+		obj, _ := item.(map[string]any)
+		for _, key := range keys {
+			if arr, ok := obj[key].([]any); ok && len(arr) > limit {
+				obj[key] = arr[:limit]
+				capped = true
+			}
+		}
+	}
+	return capped
+}
@@ -0,0 +1,181 @@
+package upload
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+)
+
+// oversizedResult returns a cluster scan with 5 namespaces of 400
+// configmaps each and insights carrying log samples.
+func oversizedResult(t *testing.T) *scanner.Result {
+	t.Helper()
+	var namespaces []scanner.NamespaceScanResult
+	for n := 0; n < 5; n++ {
+		ns := scanner.NamespaceScanResult{Name: fmt.Sprintf("ns-%d", n)}
+		for i := 0; i < 400; i++ {
+			ns.ConfigMaps = append(ns.ConfigMaps, scanner.ConfigMapScanResult{
+				Name:      fmt.Sprintf("config-%03d", i),
+				Namespace: ns.Name,
+				DataKeys:  []string{"application.yaml", "logging.properties"},
+			})
+		}
+		ns.Workloads = []scanner.WorkloadScanResult{{Name: "web", Namespace: ns.Name, Kind: "Deployment"}}
+		namespaces = append(namespaces, ns)
+	}
+	cluster, err := json.Marshal(scanner.ClusterScanResult{Name: "big", Namespaces: namespaces})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := scanner.NewResult()
+	result.Cluster = cluster
+	return result
+}
+
+func TestTruncateOversizedPayload(t *testing.T) {
+	req := BuildRequest(oversizedResult(t))
+	for i := 0; i < 20; i++ {
+		req.Insights = append(req.Insights, json.RawMessage(fmt.Sprintf(
+			`{"title":"pod %d crashlooping","description":"%s","error_sample":"%s","error_sample_count":3}`,
+			i, strings.Repeat("d", 200), strings.Repeat("e", 2000))))
+	}
+	full, _ := json.Marshal(req)
+
+	const limit = 64 << 10
+	if len(full) <= limit {
+		t.Fatalf("test payload is only %d bytes", len(full))
+	}
+	out, err := Truncate(req, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(out)
+	if len(data) > limit {
+		t.Fatalf("truncated payload is %d bytes, limit %d", len(data), limit)
+	}
+	if !out.Meta.Truncated {
+		t.Error("meta.truncated not set")
+	}
+	want := []string{"insights.error_sample", "insights.description", "cluster.namespaces"}
+	if !reflect.DeepEqual(out.Meta.TruncatedSections, want) {
+		t.Errorf("truncated sections = %v, want %v", out.Meta.TruncatedSections, want)
+	}
+
+	var cluster scanner.ClusterScanResult
+	if err := json.Unmarshal(out.Cluster, &cluster); err != nil {
+		t.Fatal(err)
+	}
+	if len(cluster.Namespaces) != 5 {
+		t.Fatalf("namespaces should be kept, got %d", len(cluster.Namespaces))
+	}
+	ns := cluster.Namespaces[0]
+	if n := len(ns.ConfigMaps); n == 0 || n >= 400 {
+		t.Errorf("configmaps should be capped, got %d", n)
+	}
+	if len(ns.Workloads) != 1 {
+		t.Errorf("arrays under the cap should be untouched, got %d workloads", len(ns.Workloads))
+	}
+	if !strings.Contains(string(out.Insights[0]), `"title":"pod 0 crashlooping"`) || strings.Contains(string(out.Insights[0]), "error_sample") {
+		t.Errorf("insight = %s", out.Insights[0])
+	}
+}
+
+func TestTruncatePlacementsBeforeNamespaces(t *testing.T) {
+	result := oversizedResult(t)
+	var cluster scanner.ClusterScanResult
+	if err := json.Unmarshal(result.Cluster, &cluster); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5000; i++ {
+		cluster.PodPlacements = append(cluster.PodPlacements, scanner.PodPlacement{
+			Name: fmt.Sprintf("web-%04d", i), Namespace: "ns-0", Node: "node-1", Phase: "Running", Drainable: true,
+		})
+	}
+	data, err := json.Marshal(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.Cluster = data
+	req := BuildRequest(result)
+	full, _ := json.Marshal(req)
+
+	// Room for every namespace object but only some placements
+	limit := len(full) - 200_000
+	out, err := Truncate(req, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := json.Marshal(out); len(data) > limit {
+		t.Fatalf("truncated payload is %d bytes, limit %d", len(data), limit)
+	}
+	if want := []string{"cluster.podPlacements"}; !reflect.DeepEqual(out.Meta.TruncatedSections, want) {
+		t.Errorf("truncated sections = %v, want %v", out.Meta.TruncatedSections, want)
+	}
+
+	var got scanner.ClusterScanResult
+	if err := json.Unmarshal(out.Cluster, &got); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(got.PodPlacements); n == 0 || n >= 5000 {
+		t.Errorf("placements should be capped, got %d", n)
+	}
+	if n := len(got.Namespaces[0].ConfigMaps); n != 400 {
+		t.Errorf("namespace objects should be kept while placements can be capped, got %d configmaps", n)
+	}
+}
+
+func TestTruncateCapsHelmReleasesAndStorageClasses(t *testing.T) {
+	var cluster scanner.ClusterScanResult
+	for i := 0; i < 2000; i++ {
+		cluster.HelmReleases = append(cluster.HelmReleases, scanner.HelmReleaseScanResult{
+			Name: fmt.Sprintf("release-%04d", i), Namespace: "apps", Chart: "web", Version: "1.2.3", Status: "deployed", Revision: 1,
+		})
+		cluster.StorageClasses = append(cluster.StorageClasses, scanner.StorageClassScanResult{
+			Name: fmt.Sprintf("class-%04d", i), Provisioner: "csi.example.com", ReclaimPolicy: "Delete", VolumeBindingMode: "Immediate",
+		})
+	}
+	data, err := json.Marshal(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := scanner.NewResult()
+	result.Cluster = data
+
+	const limit = 32 << 10
+	out, err := Truncate(BuildRequest(result), limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := json.Marshal(out); len(data) > limit {
+		t.Fatalf("truncated payload is %d bytes, limit %d", len(data), limit)
+	}
+	want := []string{"cluster.helmReleases", "cluster.storageClasses"}
+	if !reflect.DeepEqual(out.Meta.TruncatedSections, want) {
+		t.Errorf("truncated sections = %v, want %v", out.Meta.TruncatedSections, want)
+	}
+
+	var got scanner.ClusterScanResult
+	if err := json.Unmarshal(out.Cluster, &got); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(got.StorageClasses); n == 0 || n >= 2000 {
+		t.Errorf("storage classes should be capped, got %d", n)
+	}
+}
+
+func TestTruncateWithinLimit(t *testing.T) {
+	req := BuildRequest(oversizedResult(t))
+	for _, limit := range []int{0, 10 << 20} {
+		out, err := Truncate(req, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != req || out.Meta.Truncated {
+			t.Errorf("limit %d: payload should be returned unchanged", limit)
+		}
+	}
+}
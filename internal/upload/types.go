@@ -1,18 +1,25 @@
 package upload
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/tinkerbelle-io/tb-manage/internal/scanner"
+)
 
 // EdgeIngestRequest is the top-level request to the edge-ingest function.
 // Must match the TypeScript interface in edge-ingest/index.ts exactly.
 type EdgeIngestRequest struct {
-	AgentToken    string              `json:"agent_token"`
-	Host          *HostScanResult     `json:"host,omitempty"`
-	Cluster       json.RawMessage     `json:"cluster,omitempty"`
-	Observability json.RawMessage     `json:"observability,omitempty"`
-	Network       json.RawMessage     `json:"network,omitempty"`
-	Exposure      json.RawMessage     `json:"exposure,omitempty"`
-	Insights      []json.RawMessage   `json:"insights,omitempty"`
-	Meta          EdgeIngestMeta      `json:"meta"`
+	AgentToken    string                `json:"agent_token"`
+	Host          *HostScanResult       `json:"host,omitempty"`
+	Cluster       json.RawMessage       `json:"cluster,omitempty"`
+	Observability json.RawMessage       `json:"observability,omitempty"`
+	Network       json.RawMessage       `json:"network,omitempty"`
+	Exposure      json.RawMessage       `json:"exposure,omitempty"`
+	Insights      []json.RawMessage     `json:"insights,omitempty"`
+	Health        *scanner.HealthReport `json:"health,omitempty"`
+	Meta          EdgeIngestMeta        `json:"meta"`
 }
 
 // EdgeIngestMeta holds scan metadata.
@@ -21,27 +28,75 @@ type EdgeIngestMeta struct {
 	DurationMS int      `json:"duration_ms"`
 	Phases     []string `json:"phases"`
 	SourceHost string   `json:"source_host"`
+	Partial    bool     `json:"partial,omitempty"`
+	// Set when sections were trimmed to fit the payload size limit; see Truncate
+	Truncated         bool     `json:"truncated,omitempty"`
+	TruncatedSections []string `json:"truncated_sections,omitempty"`
+	// Orders uploads that arrive late or out of order; see ResultMeta
+	Sequence    uint64 `json:"sequence,omitempty"`
+	GeneratedAt string `json:"generated_at,omitempty"`
 }
 
 // HostScanResult matches the edge-ingest HostScanResult interface.
 type HostScanResult struct {
-	Name       string            `json:"name"`
-	Type       string            `json:"type"` // baremetal, vm, cloud
-	System     HostSystem        `json:"system"`
-	Network    HostNetwork       `json:"network"`
-	Kubernetes *HostKubernetes   `json:"kubernetes,omitempty"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type"` // baremetal, vm, cloud
+	System     HostSystem      `json:"system"`
+	Network    HostNetwork     `json:"network"`
+	Kubernetes *HostKubernetes `json:"kubernetes,omitempty"`
 
 	// Extra fields go into scan_data via [key: string]: unknown
-	Storage    json.RawMessage   `json:"storage,omitempty"`
-	Containers json.RawMessage   `json:"containers,omitempty"`
+	Storage    json.RawMessage `json:"storage,omitempty"`
+	Containers json.RawMessage `json:"containers,omitempty"`
+
+	// PCI device inventory (Linux only)
+	PCI []scanner.PCIDevice `json:"pci,omitempty"`
+
+	// Site-specific enrichment fields, written at the top level of the
+	// host object; see Enrich
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // HostSystem matches the system field in HostScanResult.
+// CPUCores/MemoryGB are host totals; the Cgroup* fields carry the limits
+// the agent runs under when containerized.
 type HostSystem struct {
 	OS       string  `json:"os"`
 	Arch     string  `json:"arch"`
 	CPUCores int     `json:"cpu_cores"`
 	MemoryGB float64 `json:"memory_gb"`
+
+	CPUMaxMHz   float64 `json:"cpu_max_mhz,omitempty"`
+	CPUGovernor string  `json:"cpu_governor,omitempty"`
+
+	NUMA *scanner.NUMATopology `json:"numa,omitempty"`
+	Swap *scanner.SwapInfo     `json:"swap,omitempty"`
+
+	CgroupVersion  int     `json:"cgroup_version,omitempty"`
+	CgroupMemoryGB float64 `json:"cgroup_memory_gb,omitempty"`
+	CgroupCPUs     float64 `json:"cgroup_cpus,omitempty"`
+
+	LoadAvg        []float64 `json:"load_avg,omitempty"`
+	FailedServices []string  `json:"failed_services,omitempty"`
+
+	UptimeSeconds int64 `json:"uptime_seconds,omitempty"`
+
+	ZombieProcesses  int  `json:"zombie_processes,omitempty"`
+	ZombieParentPID  int  `json:"zombie_parent_pid,omitempty"`
+	ZombiesExcessive bool `json:"zombies_excessive,omitempty"`
+
+	RebootRequired       bool   `json:"reboot_required,omitempty"`
+	RebootRequiredReason string `json:"reboot_required_reason,omitempty"`
+
+	// Kept when empty: [] reports a host without GPUs, so ones removed
+	// since the last scan are cleared
+	GPUs    []scanner.GPUInfo    `json:"gpus"`
+	Thermal *scanner.ThermalInfo `json:"thermal,omitempty"`
+
+	PowerSource       string `json:"power_source,omitempty"`
+	BatteryPercent    int    `json:"battery_percent,omitempty"`
+	BatteryStatus     string `json:"battery_status,omitempty"`
+	BatteryCycleCount int    `json:"battery_cycle_count,omitempty"`
 }
 
 // HostNetwork matches the network field in HostScanResult.
@@ -81,6 +136,9 @@ type Upstream struct {
 	Token       string   `json:"token"`
 	AnonKey     string   `json:"anonKey"`
 	Permissions []string `json:"permissions,omitempty"`
+	// Profile limits the scan phases sent to this upstream (minimal,
+	// standard, full). Empty sends everything that was scanned.
+	Profile string `json:"profile,omitempty"`
 }
 
 // ParseUpstreams parses a JSON array of upstream configs.
@@ -89,5 +147,26 @@ func ParseUpstreams(data string) ([]Upstream, error) {
 	if err := json.Unmarshal([]byte(data), &upstreams); err != nil {
 		return nil, err
 	}
+	for _, u := range upstreams {
+		if u.Profile == "" {
+			continue
+		}
+		if _, err := scanner.ParseProfile(u.Profile); err != nil {
+			return nil, fmt.Errorf("upstream %q: %w", u.Name, err)
+		}
+	}
+	return upstreams, nil
+}
+
+// LoadUpstreamsFile reads a JSON array of upstream configs from path.
+func LoadUpstreamsFile(path string) ([]Upstream, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read upstreams file: %w", err)
+	}
+	upstreams, err := ParseUpstreams(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse upstreams file %s: %w", path, err)
+	}
 	return upstreams, nil
 }